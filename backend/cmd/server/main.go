@@ -17,6 +17,7 @@ package main
 
 import (
 	"context"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"os"
@@ -26,8 +27,10 @@ import (
 
 	_ "github.com/editor-pdf/backend/cmd/server/docs" // Importa docs para registrar Swagger
 	"github.com/editor-pdf/backend/internal/config"
+	"github.com/editor-pdf/backend/internal/domain"
 	"github.com/editor-pdf/backend/internal/handler"
 	"github.com/editor-pdf/backend/internal/infrastructure/pdf"
+	"github.com/editor-pdf/backend/internal/infrastructure/signing"
 	"github.com/editor-pdf/backend/internal/infrastructure/storage"
 	appMiddleware "github.com/editor-pdf/backend/internal/middleware"
 	"github.com/editor-pdf/backend/internal/repository"
@@ -139,21 +142,28 @@ func setupRoutes(e *echo.Echo, db *sqlx.DB, cfg *config.Config) {
 	}
 
 	// Inicializa PDFProcessor
-	pdfProcessor, err := pdf.NewPDFCPUProcessor()
+	pdfProcessor, err := pdf.NewPDFCPUProcessor(cfg.Fonts.Path)
 	if err != nil {
 		logger.Logger.Fatal("Erro ao inicializar PDFProcessor", zap.Error(err))
 	}
 
+	// Inicializa autoridade de carimbo do tempo e certificados de assinatura
+	timestampAuthority, certificateStore := setupSigning(e, cfg)
+
 	// Inicializa Repositories
 	documentRepo := repository.NewDocumentRepository(db)
 	auditLogRepo := repository.NewAuditLogRepository(db)
+	annotationRepo := repository.NewAnnotationRepository(db)
 
 	// Inicializa UseCases
 	documentUseCase := usecase.NewDocumentUseCase(
 		documentRepo,
 		auditLogRepo,
+		annotationRepo,
 		fileStorage,
 		pdfProcessor,
+		certificateStore,
+		timestampAuthority,
 		cfg.Storage.Path,
 	)
 	previewUseCase := usecase.NewPDFPreviewUseCase(
@@ -178,10 +188,82 @@ func setupRoutes(e *echo.Echo, db *sqlx.DB, cfg *config.Config) {
 		{
 			documents.POST("", documentHandler.UploadDocument)
 			documents.GET("", documentHandler.ListDocuments)
+			documents.POST("/merge", documentHandler.MergeDocuments)
+			documents.POST("/header-footer", documentHandler.ApplyHeaderFooterBatch)
 			documents.GET("/:id", documentHandler.GetDocument)
+			documents.GET("/:id/download", documentHandler.DownloadDocument)
 			documents.POST("/:id/process", documentHandler.ProcessDocument)
+			documents.POST("/:id/split", documentHandler.SplitDocument)
+			documents.GET("/:id/text", documentHandler.ExtractText)
+			documents.GET("/:id/search", documentHandler.SearchText)
+			documents.GET("/:id/form", documentHandler.ListFormFields)
+			documents.GET("/:id/form/export", documentHandler.ExportFormData)
+			documents.POST("/:id/form/import", documentHandler.ImportFormData)
+			documents.POST("/:id/sign", documentHandler.SignDocument)
+			documents.GET("/:id/signatures", documentHandler.VerifySignatures)
+			documents.POST("/:id/encrypt", documentHandler.EncryptDocument)
+			documents.POST("/:id/decrypt", documentHandler.DecryptDocument)
+			documents.POST("/:id/optimize", documentHandler.OptimizeDocument)
+			documents.POST("/:id/linearize", documentHandler.LinearizeDocument)
+			documents.POST("/:id/pdfa", documentHandler.ConvertToPDFA)
+			documents.GET("/:id/pdfa/validate", documentHandler.ValidatePDFA)
+			documents.POST("/:id/header-footer", documentHandler.ApplyHeaderFooter)
+			documents.GET("/:id/annotations", documentHandler.ListAnnotations)
+			documents.POST("/:id/annotations", documentHandler.CreateAnnotation)
+			documents.POST("/:id/annotations/apply", documentHandler.ApplyAnnotations)
+			documents.GET("/:id/annotations/export", documentHandler.ExportAnnotations)
+			documents.POST("/:id/annotations/import", documentHandler.ImportAnnotations)
+			documents.PUT("/:id/annotations/:annotationId", documentHandler.UpdateAnnotation)
+			documents.DELETE("/:id/annotations/:annotationId", documentHandler.DeleteAnnotation)
+			documents.GET("/:id/metadata", documentHandler.GetMetadata)
+			documents.PATCH("/:id/metadata", documentHandler.UpdateMetadata)
+			documents.GET("/:id/bookmarks", documentHandler.ListBookmarks)
+			documents.PUT("/:id/bookmarks", documentHandler.ReplaceBookmarks)
+			documents.PATCH("/:id/bookmarks", documentHandler.EditBookmarks)
+			documents.POST("/:id/bookmarks/generate", documentHandler.GenerateBookmarks)
 			documents.GET("/:id/preview/:page", documentHandler.GeneratePreview)
+			documents.POST("/:id/export/images", documentHandler.ExportImages)
 			documents.DELETE("/:id", documentHandler.DeleteDocument)
 		}
 	}
 }
+
+// setupSigning configura o carimbo do tempo (RFC 3161) e o repositório de certificados de assinatura
+// Com SIGNATURE_TSA_URL=local a autoridade roda em processo, exposta em POST /tsa, e seu certificado
+// passa a ser confiável; apenas para desenvolvimento
+func setupSigning(e *echo.Echo, cfg *config.Config) (domain.TimestampAuthority, domain.CertificateStore) {
+	var timestampAuthority domain.TimestampAuthority
+	var additionalRoots []*x509.Certificate
+
+	switch cfg.Signature.TSAURL {
+	case "":
+		logger.Logger.Info("Carimbo do tempo não configurado")
+	case "local":
+		localTSA, err := signing.NewLocalTimestampAuthority()
+		if err != nil {
+			logger.Logger.Fatal("Erro ao inicializar autoridade de carimbo do tempo local", zap.Error(err))
+		}
+		e.POST("/tsa", echo.WrapHandler(localTSA))
+		additionalRoots = append(additionalRoots, localTSA.Certificate())
+		timestampAuthority = localTSA
+		logger.Logger.Warn("Usando autoridade de carimbo do tempo local (apenas desenvolvimento)")
+	default:
+		timeout, err := time.ParseDuration(cfg.Signature.TSATimeout)
+		if err != nil {
+			logger.Logger.Fatal("SIGNATURE_TSA_TIMEOUT inválido", zap.Error(err))
+		}
+		timestampAuthority = signing.NewRFC3161Client(cfg.Signature.TSAURL, timeout)
+	}
+
+	certificateStore, err := signing.NewFileCertificateStore(
+		cfg.Signature.CertsPath,
+		cfg.Signature.OrgCertPassword,
+		cfg.Signature.TrustStore,
+		additionalRoots...,
+	)
+	if err != nil {
+		logger.Logger.Fatal("Erro ao inicializar repositório de certificados", zap.Error(err))
+	}
+
+	return timestampAuthority, certificateStore
+}
@@ -9,12 +9,14 @@ import (
 
 // Config contém todas as configurações da aplicação
 type Config struct {
-	Server  ServerConfig  `mapstructure:"server"`
-	DB      DBConfig      `mapstructure:"db"`
-	JWT     JWTConfig     `mapstructure:"jwt"`
-	CORS    CORSConfig    `mapstructure:"cors"`
-	Storage StorageConfig `mapstructure:"storage"`
-	Env     string        `mapstructure:"env"`
+	Server    ServerConfig    `mapstructure:"server"`
+	DB        DBConfig        `mapstructure:"db"`
+	JWT       JWTConfig       `mapstructure:"jwt"`
+	CORS      CORSConfig      `mapstructure:"cors"`
+	Storage   StorageConfig   `mapstructure:"storage"`
+	Fonts     FontsConfig     `mapstructure:"fonts"`
+	Signature SignatureConfig `mapstructure:"signature"`
+	Env       string          `mapstructure:"env"`
 }
 
 // ServerConfig contém configurações do servidor
@@ -50,6 +52,20 @@ type StorageConfig struct {
 	MaxUploadSize int64  `mapstructure:"max_upload_size"` // em bytes
 }
 
+// FontsConfig contém configurações de fontes usadas na inserção de texto
+type FontsConfig struct {
+	Path string `mapstructure:"path"` // Diretório com fontes TrueType/OpenType adicionais
+}
+
+// SignatureConfig contém configurações de assinatura digital e carimbo do tempo
+type SignatureConfig struct {
+	CertsPath       string `mapstructure:"certs_path"`        // Diretório com organization.p12 e users/<user_id>.p12
+	OrgCertPassword string `mapstructure:"org_cert_password"` // Senha do certificado da organização
+	TrustStore      string `mapstructure:"trust_store"`       // Arquivo ou diretório com as raízes confiáveis
+	TSAURL          string `mapstructure:"tsa_url"`           // URL RFC 3161; "local" usa a autoridade em processo
+	TSATimeout      string `mapstructure:"tsa_timeout"`       // Tempo máximo da requisição ao TSA
+}
+
 // DSN retorna a string de conexão do PostgreSQL
 func (c *DBConfig) DSN() string {
 	return fmt.Sprintf(
@@ -84,6 +100,12 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("CORS_ALLOWED_ORIGINS", "http://localhost:3000")
 	viper.SetDefault("STORAGE_PATH", "./storage")
 	viper.SetDefault("STORAGE_MAX_UPLOAD_SIZE", 104857600) // 100MB em bytes
+	viper.SetDefault("FONTS_PATH", "./fonts")
+	viper.SetDefault("SIGNATURE_CERTS_PATH", "./certs")
+	viper.SetDefault("SIGNATURE_ORG_CERT_PASSWORD", "")
+	viper.SetDefault("SIGNATURE_TRUST_STORE", "./certs/trusted")
+	viper.SetDefault("SIGNATURE_TSA_URL", "")
+	viper.SetDefault("SIGNATURE_TSA_TIMEOUT", "15s")
 	viper.SetDefault("ENV", "development")
 
 	// Tenta ler primeiro o arquivo .env.local (prioridade maior)
@@ -118,6 +140,12 @@ func LoadConfig() (*Config, error) {
 	config.JWT.Expiration = viper.GetString("JWT_EXPIRATION")
 	config.Storage.Path = viper.GetString("STORAGE_PATH")
 	config.Storage.MaxUploadSize = viper.GetInt64("STORAGE_MAX_UPLOAD_SIZE")
+	config.Fonts.Path = viper.GetString("FONTS_PATH")
+	config.Signature.CertsPath = viper.GetString("SIGNATURE_CERTS_PATH")
+	config.Signature.OrgCertPassword = viper.GetString("SIGNATURE_ORG_CERT_PASSWORD")
+	config.Signature.TrustStore = viper.GetString("SIGNATURE_TRUST_STORE")
+	config.Signature.TSAURL = viper.GetString("SIGNATURE_TSA_URL")
+	config.Signature.TSATimeout = viper.GetString("SIGNATURE_TSA_TIMEOUT")
 	config.Env = viper.GetString("ENV")
 
 	// Parse CORS allowed origins
@@ -159,6 +187,9 @@ func validateConfig(cfg *Config) error {
 	if cfg.JWT.Secret == "" {
 		return fmt.Errorf("JWT_SECRET é obrigatório")
 	}
+	if cfg.IsProduction() && cfg.Signature.TSAURL == "local" {
+		return fmt.Errorf("SIGNATURE_TSA_URL=local não é permitido em produção")
+	}
 	return nil
 }
 
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // FileStorage define a interface para armazenamento de arquivos
 type FileStorage interface {
@@ -10,6 +13,9 @@ type FileStorage interface {
 	// Read lê um arquivo do storage
 	Read(ctx context.Context, filePath string) ([]byte, error)
 
+	// Open abre um arquivo do storage para leitura com posicionamento (ex.: requisições de faixas de bytes)
+	Open(ctx context.Context, filePath string) (io.ReadSeekCloser, error)
+
 	// Delete remove um arquivo do storage
 	Delete(ctx context.Context, filePath string) error
 
@@ -2,29 +2,185 @@ package domain
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
 
 	"github.com/editor-pdf/backend/internal/model"
 )
 
+var (
+	// ErrRenderLimit indica que a imagem pedida excede o tamanho máximo de renderização de uma página
+	ErrRenderLimit = errors.New("imagem excede o limite de renderização")
+
+	// ErrClipOutsidePage indica que a região de recorte pedida não intercepta a área visível da página
+	ErrClipOutsidePage = errors.New("região de recorte fora da página")
+
+	// ErrInvalidImage indica que uma imagem enviada não pode ser convertida (formato não suportado,
+	// arquivo corrompido ou dimensões acima do limite)
+	ErrInvalidImage = errors.New("imagem inválida")
+
+	// ErrUnsupportedCharacters indica que o texto contém caracteres sem glifo em nenhuma das fontes
+	// disponíveis no servidor (seriam desenhados como caixas vazias)
+	ErrUnsupportedCharacters = errors.New("caracteres sem glifo nas fontes disponíveis")
+)
+
 // PDFProcessor define a interface para processamento de arquivos PDF
 type PDFProcessor interface {
 	// ExtractPages extrai informações sobre as páginas de um PDF
 	ExtractPages(ctx context.Context, filePath string) ([]model.Page, error)
 
-	// AddText adiciona texto a uma página específica do PDF
-	// Coordenadas em PDF points (72 DPI)
-	AddText(ctx context.Context, filePath string, pageNum int, x, y float64, text string, fontSize float64) error
+	// AddText adiciona texto a uma página específica do PDF usando fontes Unicode embutidas
+	// Coordenadas em PDF points (72 DPI). Com opts.Width > 0 o texto é quebrado dentro da caixa
+	AddText(ctx context.Context, filePath string, pageNum int, x, y float64, text string, opts model.TextOptions) error
 
 	// AddImage adiciona uma imagem a uma página específica do PDF
 	// Coordenadas e dimensões em PDF points (72 DPI)
 	AddImage(ctx context.Context, filePath string, pageNum int, x, y, width, height float64, imagePath string) error
 
+	// AddDrawing adiciona uma forma vetorial (linha, retângulo, elipse, polígono ou traço livre)
+	// a uma página específica do PDF. Coordenadas em PDF points (72 DPI)
+	AddDrawing(ctx context.Context, filePath string, pageNum int, drawing model.Drawing) error
+
+	// Redact remove definitivamente glifos, pixels de imagens, caminhos e anotações que interceptam
+	// a região e pinta o preenchimento e o texto sobreposto opcionais. Coordenadas em PDF points (72 DPI)
+	Redact(ctx context.Context, filePath string, pageNum int, redaction model.Redaction) (*model.RedactionReport, error)
+
+	// RotatePage rotaciona uma página em múltiplos de 90 graus (sentido horário)
+	RotatePage(ctx context.Context, filePath string, pageNum, degrees int) error
+
+	// DeletePage remove uma página do PDF
+	DeletePage(ctx context.Context, filePath string, pageNum int) error
+
+	// MovePage move uma página para a posição informada (1 = primeira página)
+	MovePage(ctx context.Context, filePath string, pageNum, targetIndex int) error
+
+	// DuplicatePage insere uma cópia da página logo após a original
+	DuplicatePage(ctx context.Context, filePath string, pageNum int) error
+
+	// InsertBlankPage insere uma página em branco na posição informada (numPages+1 = final)
+	// Dimensões em PDF points (72 DPI)
+	InsertBlankPage(ctx context.Context, filePath string, position int, width, height float64) error
+
+	// SelectPages reescreve o PDF mantendo apenas as páginas informadas, na ordem informada
+	// (páginas repetidas são duplicadas)
+	SelectPages(ctx context.Context, filePath string, pages []int) error
+
+	// AddWatermark aplica uma marca d'água de texto ou imagem às páginas informadas (todas quando vazio),
+	// atrás ou sobre o conteúdo, com posição, rotação, opacidade e escala relativa à página
+	AddWatermark(ctx context.Context, filePath string, pages []int, watermark model.Watermark) error
+
+	// AddHeaderFooter desenha cabeçalhos e rodapés (esquerda, centro e direita) com os textos já resolvidos
+	// de cada página, posicionados pelas margens do estilo
+	AddHeaderFooter(ctx context.Context, filePath string, pages []model.PageHeaderFooter, style model.HeaderFooterStyle) error
+
+	// ExtractBookmarks lê a hierarquia de marcadores (outline) do PDF com destino, visualização,
+	// estado aberto/fechado e estilo de cada item
+	ExtractBookmarks(ctx context.Context, filePath string) ([]model.Bookmark, error)
+
+	// WriteBookmarks substitui os marcadores do PDF pela árvore informada (vazia remove o outline)
+	WriteBookmarks(ctx context.Context, filePath string, bookmarks []model.Bookmark) error
+
+	// DetectHeadings detecta títulos no texto pelo tamanho da fonte, candidatos a marcadores
+	DetectHeadings(ctx context.Context, filePath string, options model.HeadingOptions) ([]model.Heading, error)
+
+	// ExtractAnnotations lê as anotações de destaque, sublinhado, tachado, nota, texto livre e tinta do PDF,
+	// com coordenadas em PDF points e origem no topo esquerdo da página
+	ExtractAnnotations(ctx context.Context, filePath string) ([]model.Annotation, error)
+
+	// WriteAnnotations grava as anotações como anotações nativas com aparições, substituindo as anotações
+	// dos tipos suportados já existentes no PDF
+	WriteAnnotations(ctx context.Context, filePath string, annotations []model.Annotation) error
+
+	// ExportAnnotationsXFDF gera um XFDF com as anotações, nas coordenadas das páginas do PDF
+	ExportAnnotationsXFDF(ctx context.Context, filePath string, annotations []model.Annotation) ([]byte, error)
+
+	// ParseAnnotationsXFDF lê as anotações de um XFDF para as páginas do PDF
+	ParseAnnotationsXFDF(ctx context.Context, filePath string, data []byte) ([]model.Annotation, error)
+
+	// ExtractText extrai o texto das páginas informadas (todas quando vazio), com linhas e palavras
+	// posicionadas em PDF points e origem no topo esquerdo da página (mesmo sistema de AddText)
+	ExtractText(ctx context.Context, filePath string, pages []int) ([]model.PageText, error)
+
+	// SearchText busca uma frase (ou expressão regular) ignorando maiúsculas/minúsculas e acentos
+	// Cada ocorrência traz os quadriláteros e retângulos do trecho, no mesmo sistema de ExtractText
+	SearchText(ctx context.Context, filePath string, search model.TextSearch) ([]model.SearchHit, error)
+
+	// ListFormFields lista os campos do formulário (AcroForm) com valores, opções e widgets
+	ListFormFields(ctx context.Context, filePath string) ([]model.FormField, error)
+
+	// AddFormField cria um campo de formulário com widget e aparição na página
+	// Coordenadas em PDF points, com (x, y) no canto superior esquerdo do widget
+	AddFormField(ctx context.Context, filePath string, definition model.FormFieldDefinition) error
+
+	// FillForm preenche campos do formulário pelo nome completo e regenera as aparições
+	FillForm(ctx context.Context, filePath string, values map[string]interface{}) error
+
+	// FlattenForm converte as aparições dos campos em conteúdo estático e remove o formulário
+	FlattenForm(ctx context.Context, filePath string) error
+
+	// ExportFormData exporta os valores dos campos do formulário em FDF ou XFDF
+	ExportFormData(ctx context.Context, filePath string, format model.FormDataFormat) ([]byte, error)
+
+	// ParseFormData lê valores de campos de um arquivo FDF ou XFDF, detectando o formato
+	ParseFormData(ctx context.Context, data []byte) (map[string]interface{}, model.FormDataFormat, error)
+
+	// SignPDF assina o documento digitalmente (PAdES, ETSI.CAdES.detached) em uma atualização incremental,
+	// preservando os bytes e assinaturas anteriores; com tsa não nulo inclui carimbo do tempo na assinatura
+	// Retorna o nome do campo de assinatura utilizado
+	SignPDF(ctx context.Context, filePath string, identity *model.SigningIdentity, options model.SignatureOptions, tsa TimestampAuthority) (string, error)
+
+	// VerifySignatures verifica as assinaturas do documento contra as raízes confiáveis informadas
+	VerifySignatures(ctx context.Context, filePath string, roots *x509.CertPool) ([]model.SignatureVerification, error)
+
+	// EncryptPDF protege o documento com criptografia AES-256, senhas de usuário e proprietário e permissões
+	EncryptPDF(ctx context.Context, filePath string, options model.EncryptionOptions) error
+
+	// DecryptPDF remove a proteção do documento com a senha de usuário ou de proprietário
+	// Retorna false quando o documento não está protegido (o arquivo não é alterado)
+	DecryptPDF(ctx context.Context, filePath, password string) (bool, error)
+
+	// ReadMetadata lê os metadados do dicionário Info e do pacote XMP do PDF
+	ReadMetadata(ctx context.Context, filePath string) (*model.PDFMetadata, error)
+
+	// WriteMetadata grava título, autor, assunto, palavras-chave, criador e propriedades personalizadas
+	// no dicionário Info e em um pacote XMP sincronizado, em uma atualização incremental
+	WriteMetadata(ctx context.Context, filePath string, metadata model.DocumentMetadata) error
+
+	// OptimizePDF reduz o tamanho do PDF: reamostra imagens acima da resolução limite, remove recursos
+	// duplicados e objetos sem uso e comprime os fluxos em object streams. O arquivo só é substituído
+	// quando o resultado é menor
+	OptimizePDF(ctx context.Context, filePath string, options model.OptimizeOptions) (*model.OptimizeReport, error)
+
+	// LinearizePDF reescreve o PDF linearizado (visualização rápida na web): a primeira página fica no início
+	// do arquivo e um fluxo de dicas permite carregar as demais por requisições de faixas de bytes
+	LinearizePDF(ctx context.Context, filePath string) error
+
+	// ConvertToPDFA converte o PDF para o nível PDF/A informado (1b, 2b ou 3b): remove a criptografia e os
+	// recursos proibidos, embute as fontes, adiciona um OutputIntent sRGB e grava o XMP com a identificação
+	// PDF/A. Retorna as violações corrigidas e as que permaneceram
+	ConvertToPDFA(ctx context.Context, filePath string, conformance model.PDFAConformance, password string) (*model.PDFAConversionReport, error)
+
+	// ValidatePDFA verifica o PDF contra as regras do nível PDF/A informado, listando cada violação com a
+	// cláusula da norma
+	ValidatePDFA(ctx context.Context, filePath string, conformance model.PDFAConformance) (*model.PDFAValidation, error)
+
+	// ImagesToPDF cria um PDF com uma página por imagem (PNG, JPEG ou cada página de um TIFF), com o tamanho
+	// de página, margens e orientação informados; JPEGs podem ser embutidos sem recompressão
+	ImagesToPDF(ctx context.Context, outputPath string, images []model.ImageFile, options model.ImageConversionOptions) error
+
 	// MergePDFs mescla múltiplos PDFs em um único arquivo
 	MergePDFs(ctx context.Context, outputPath string, inputPaths []string) error
 
-	// GeneratePreview gera uma preview (imagem) de uma página específica do PDF
-	GeneratePreview(ctx context.Context, filePath string, pageNum int) ([]byte, error)
+	// ExportPageImages renderiza as páginas informadas em PNG, JPEG ou TIFF na resolução ou largura escolhida,
+	// opcionalmente em tons de cinza e com fundo transparente; uma imagem por página ou, com options.MultiPage
+	// em TIFF, um único arquivo com todas as páginas
+	ExportPageImages(ctx context.Context, filePath string, pages []int, options model.ImageExportOptions) ([]model.PageImage, error)
+
+	// GeneratePreview gera uma preview (imagem) de uma página específica do PDF em PNG, JPEG ou WebP, na resolução
+	// ou largura informada, opcionalmente apenas de uma região (clip) ou como miniatura
+	GeneratePreview(ctx context.Context, filePath string, pageNum int, options model.PreviewOptions) ([]byte, error)
 
 	// ValidatePDF valida se um arquivo é um PDF válido usando magic bytes
+	// Documentos que exigem senha para abrir resultam no erro "PDF protegido por senha"
 	ValidatePDF(ctx context.Context, data []byte) error
 }
@@ -34,7 +34,8 @@ type DocumentRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*model.Document, error)
 
 	// FindByUserID busca todos os documentos de um usuário
-	FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.Document, int, error)
+	// filter restringe por palavras do nome original e dos metadados e pelo nível PDF/A
+	FindByUserID(ctx context.Context, userID uuid.UUID, filter model.DocumentFilter, limit, offset int) ([]*model.Document, int, error)
 
 	// Update atualiza um documento
 	Update(ctx context.Context, document *model.Document) error
@@ -54,3 +55,21 @@ type AuditLogRepository interface {
 	// FindByDocumentID busca logs de auditoria de um documento
 	FindByDocumentID(ctx context.Context, documentID uuid.UUID, limit, offset int) ([]*model.AuditLog, int, error)
 }
+
+// AnnotationRepository define a interface para operações de anotações de PDF no banco de dados
+type AnnotationRepository interface {
+	// Create cria uma nova anotação
+	Create(ctx context.Context, annotation *model.Annotation) error
+
+	// FindByID busca uma anotação por ID
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Annotation, error)
+
+	// FindByDocumentID busca as anotações de um documento, ordenadas por página (page 0 = todas as páginas)
+	FindByDocumentID(ctx context.Context, documentID uuid.UUID, page int) ([]*model.Annotation, error)
+
+	// Update atualiza uma anotação
+	Update(ctx context.Context, annotation *model.Annotation) error
+
+	// Delete remove uma anotação
+	Delete(ctx context.Context, id uuid.UUID) error
+}
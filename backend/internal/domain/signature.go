@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"crypto/x509"
+
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/google/uuid"
+)
+
+// CertificateStore define a interface de acesso aos certificados de assinatura mantidos no servidor
+type CertificateStore interface {
+	// Identity carrega o certificado PKCS#12 do usuário ou, quando ele não possui um, o da organização
+	// A senha informada é usada apenas para o certificado do usuário
+	Identity(ctx context.Context, userID uuid.UUID, password string) (*model.SigningIdentity, error)
+
+	// TrustedRoots retorna as autoridades certificadoras do repositório confiável
+	TrustedRoots() *x509.CertPool
+}
+
+// TimestampAuthority define a interface de uma autoridade de carimbo do tempo (RFC 3161)
+type TimestampAuthority interface {
+	// Timestamp obtém um TimeStampToken (CMS em DER) sobre o resumo SHA-256 informado
+	Timestamp(ctx context.Context, digest []byte) ([]byte, error)
+}
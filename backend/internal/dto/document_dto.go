@@ -1,20 +1,30 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/editor-pdf/backend/internal/model"
+)
 
 // DocumentResponse representa a resposta de um documento
 // @Description Informações completas de um documento PDF
 type DocumentResponse struct {
-	ID        string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	UserID    string    `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	FilePath  string    `json:"file_path" example:"/storage/documents/550e8400-e29b-41d4-a716-446655440000.pdf"`
-	FileURL   string    `json:"file_url" example:"/api/v1/documents/550e8400-e29b-41d4-a716-446655440000/file"`
-	Checksum  string    `json:"checksum" example:"a1b2c3d4e5f6..."`
-	Version   int       `json:"version" example:"1"`
-	Status    string    `json:"status" example:"processed" enums:"uploaded,processing,processed,error"`
-	PageCount int       `json:"page_count" example:"10"`
-	CreatedAt time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
-	UpdatedAt time.Time `json:"updated_at" example:"2024-01-15T10:30:00Z"`
+	ID               string    `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID           string    `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	FilePath         string    `json:"file_path" example:"/storage/documents/550e8400-e29b-41d4-a716-446655440000.pdf"`
+	FileURL          string    `json:"file_url" example:"/api/v1/documents/550e8400-e29b-41d4-a716-446655440000/file"`
+	OriginalFilename string    `json:"original_filename" example:"contrato.pdf"`
+	Title            string    `json:"title,omitempty" example:"Contrato de prestação de serviços"`
+	Author           string    `json:"author,omitempty" example:"Maria Silva"`
+	Subject          string    `json:"subject,omitempty" example:"Prestação de serviços de consultoria"`
+	Keywords         string    `json:"keywords,omitempty" example:"contrato, consultoria"`
+	Checksum         string    `json:"checksum" example:"a1b2c3d4e5f6..."`
+	Version          int       `json:"version" example:"1"`
+	Status           string    `json:"status" example:"processed" enums:"uploaded,processing,processed,error"`
+	PageCount        int       `json:"page_count" example:"10"`
+	PDFAConformance  string    `json:"pdfa_conformance,omitempty" example:"2b" enums:"1b,2b,3b"`
+	CreatedAt        time.Time `json:"created_at" example:"2024-01-15T10:30:00Z"`
+	UpdatedAt        time.Time `json:"updated_at" example:"2024-01-15T10:30:00Z"`
 }
 
 // DocumentListResponse representa a resposta de uma lista de documentos
@@ -32,3 +42,373 @@ type UploadDocumentResponse struct {
 	Document DocumentResponse `json:"document"`
 	Message  string           `json:"message" example:"Documento enviado com sucesso"`
 }
+
+// ImageUploadOptions representa as opções de conversão de imagens enviadas no upload
+// @Description pageSize: fit (tamanho da imagem), a4 ou letter; orientation vale apenas para a4 e letter; margin em PDF points
+type ImageUploadOptions struct {
+	PageSize        string  `json:"pageSize" validate:"oneof=fit a4 letter" example:"a4" enums:"fit,a4,letter"`
+	Orientation     string  `json:"orientation" validate:"oneof=auto portrait landscape" example:"auto" enums:"auto,portrait,landscape"`
+	Margin          float64 `json:"margin" validate:"min=0,max=144" example:"18"`
+	AutoRotate      bool    `json:"autoRotate" example:"true"`
+	JPEGPassthrough bool    `json:"jpegPassthrough" example:"true"`
+}
+
+// MergeSource representa um documento de origem de uma mesclagem
+// @Description Documento de origem e, opcionalmente, as páginas a serem incluídas
+type MergeSource struct {
+	DocumentID string `json:"documentId" validate:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Pages      string `json:"pages,omitempty" example:"1-3,5,8-"`
+}
+
+// MergeDocumentsRequest representa a requisição para mesclar documentos
+// @Description Lista ordenada de documentos a serem combinados em um novo documento
+type MergeDocumentsRequest struct {
+	Documents []MergeSource `json:"documents" validate:"required,min=2,dive"`
+}
+
+// MergeDocumentsResponse representa a resposta após mesclar documentos
+// @Description Resposta com o novo documento gerado pela mesclagem
+type MergeDocumentsResponse struct {
+	Document DocumentResponse `json:"document"`
+	Message  string           `json:"message" example:"Documentos mesclados com sucesso"`
+}
+
+// SplitDocumentRequest representa a requisição para dividir um documento
+// @Description Divide um documento por intervalos explícitos, a cada N páginas ou por marcador de primeiro nível
+type SplitDocumentRequest struct {
+	Mode   string `json:"mode" validate:"required,oneof=ranges every bookmarks" example:"ranges" enums:"ranges,every,bookmarks"`
+	Ranges string `json:"ranges,omitempty" example:"1-3,4-10"`
+	Every  int    `json:"every,omitempty" validate:"omitempty,min=1" example:"2"`
+}
+
+// SplitDocumentResponse representa a resposta após dividir um documento
+// @Description Documentos gerados pela divisão, na ordem das páginas do original
+type SplitDocumentResponse struct {
+	Documents []DocumentResponse `json:"documents"`
+	Message   string             `json:"message" example:"Documento dividido com sucesso"`
+}
+
+// DocumentTextResponse representa o texto extraído de um documento
+// @Description Texto de cada página e, opcionalmente, linhas e palavras com caixas em PDF points (origem no topo esquerdo)
+type DocumentTextResponse struct {
+	DocumentID string           `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version    int              `json:"version" example:"1"`
+	Pages      []model.PageText `json:"pages"`
+}
+
+// SearchResponse representa o resultado de uma busca de texto em um documento
+// @Description Ocorrências encontradas com quadriláteros e retângulos em PDF points (origem no topo esquerdo)
+type SearchResponse struct {
+	DocumentID string            `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version    int               `json:"version" example:"1"`
+	Query      string            `json:"query" example:"contrato de prestação"`
+	Regex      bool              `json:"regex" example:"false"`
+	Hits       []model.SearchHit `json:"hits"`
+	Total      int               `json:"total" example:"3"`
+	Truncated  bool              `json:"truncated" example:"false"` // Há mais ocorrências além do limite retornado
+}
+
+// FormFieldsResponse representa os campos de formulário de um documento
+// @Description Campos do formulário (AcroForm) com valores, opções e widgets em PDF points (origem no topo esquerdo)
+type FormFieldsResponse struct {
+	DocumentID string            `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version    int               `json:"version" example:"1"`
+	Fields     []model.FormField `json:"fields"`
+}
+
+// SignDocumentRequest representa a requisição para assinar digitalmente um documento
+// @Description Assina com o certificado PKCS#12 do usuário (ou o da organização); com width e height a assinatura é visível, com (x, y) no canto superior esquerdo em PDF points
+type SignDocumentRequest struct {
+	CertificatePassword string   `json:"certificatePassword,omitempty" example:"senha-do-certificado"`
+	FieldName           string   `json:"fieldName,omitempty" validate:"omitempty,max=255,excludesall=." example:"Assinatura1"`
+	Reason              string   `json:"reason,omitempty" validate:"omitempty,max=255" example:"Aprovação do contrato"`
+	Location            string   `json:"location,omitempty" validate:"omitempty,max=255" example:"São Paulo"`
+	ContactInfo         string   `json:"contactInfo,omitempty" validate:"omitempty,max=255" example:"financeiro@empresa.com"`
+	Page                int      `json:"page,omitempty" validate:"omitempty,min=1" example:"1"`
+	X                   float64  `json:"x,omitempty" example:"72"`
+	Y                   float64  `json:"y,omitempty" example:"700"`
+	Width               *float64 `json:"width,omitempty" validate:"omitempty,gt=0" example:"200"`
+	Height              *float64 `json:"height,omitempty" validate:"omitempty,gt=0" example:"60"`
+	Timestamp           *bool    `json:"timestamp,omitempty" example:"true"` // Padrão: usa o carimbo do tempo quando configurado
+}
+
+// SignatureVerificationResponse representa a verificação das assinaturas de um documento
+// @Description Integridade, cadeia de certificados, carimbo do tempo e alterações posteriores de cada assinatura
+type SignatureVerificationResponse struct {
+	DocumentID string                        `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version    int                           `json:"version" example:"2"`
+	Signatures []model.SignatureVerification `json:"signatures"`
+}
+
+// EncryptDocumentRequest representa a requisição para proteger um documento com senha
+// @Description Criptografia AES-256; sem userPassword o documento abre sem senha, mas apenas com as permissões informadas
+type EncryptDocumentRequest struct {
+	UserPassword  string               `json:"userPassword,omitempty" validate:"omitempty,max=127" example:"senha-de-abertura"`
+	OwnerPassword string               `json:"ownerPassword" validate:"required,max=127,nefield=UserPassword" example:"senha-do-proprietario"`
+	Permissions   model.PDFPermissions `json:"permissions"`
+}
+
+// DecryptDocumentRequest representa a requisição para remover a proteção por senha de um documento
+// @Description Senha de usuário ou de proprietário do documento
+type DecryptDocumentRequest struct {
+	Password string `json:"password" validate:"required,max=127" example:"senha-do-proprietario"`
+}
+
+// BatesOptions representa a numeração Bates de um conjunto de documentos
+// @Description Número sequencial com prefixo e zeros à esquerda (ex.: ACME-000123), contínuo entre os documentos
+type BatesOptions struct {
+	Prefix string `json:"prefix,omitempty" validate:"omitempty,max=50" example:"ACME-"`
+	Start  *int   `json:"start,omitempty" validate:"omitempty,min=0" example:"123"`       // Padrão: 1
+	Digits int    `json:"digits,omitempty" validate:"omitempty,min=1,max=12" example:"6"` // Padrão: 6
+}
+
+// HeaderFooterOptions representa os cabeçalhos e rodapés a serem aplicados
+// @Description Modelos das posições esquerda, central e direita com os marcadores {page}, {total}, {date}, {name} e {bates}; fontSize, marginX e marginY em PDF points
+type HeaderFooterOptions struct {
+	Header     model.HeaderFooterSlots `json:"header"`
+	Footer     model.HeaderFooterSlots `json:"footer"`
+	Pages      string                  `json:"pages,omitempty" example:"2-"`                                                                // Vazio = todas
+	Parity     string                  `json:"parity,omitempty" validate:"omitempty,oneof=all odd even" example:"all" enums:"all,odd,even"` // Filtra as páginas selecionadas pelo número
+	FontFamily string                  `json:"fontFamily,omitempty" validate:"omitempty,oneof=helvetica times courier" example:"helvetica" enums:"helvetica,times,courier"`
+	FontWeight string                  `json:"fontWeight,omitempty" validate:"omitempty,oneof=normal bold" example:"normal" enums:"normal,bold"`
+	FontSize   *float64                `json:"fontSize,omitempty" validate:"omitempty,gte=4,lte=72" example:"10"`
+	Color      string                  `json:"color,omitempty" validate:"omitempty,hexcolor" example:"#000000"`
+	MarginX    *float64                `json:"marginX,omitempty" validate:"omitempty,gte=0" example:"36"`
+	MarginY    *float64                `json:"marginY,omitempty" validate:"omitempty,gte=0" example:"24"`
+	Bates      *BatesOptions           `json:"bates,omitempty"`
+}
+
+// HeaderFooterRequest representa a requisição para aplicar cabeçalhos e rodapés em um documento
+// @Description Cabeçalhos e rodapés de um documento; name substitui o marcador {name} (padrão: nome original do arquivo, sem extensão)
+type HeaderFooterRequest struct {
+	HeaderFooterOptions
+	Name string `json:"name,omitempty" validate:"omitempty,max=255" example:"Contrato de prestação de serviços"`
+}
+
+// HeaderFooterSource representa um documento de um conjunto numerado
+// @Description Documento do conjunto e, opcionalmente, o nome usado no marcador {name}
+type HeaderFooterSource struct {
+	DocumentID string `json:"documentId" validate:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name       string `json:"name,omitempty" validate:"omitempty,max=255" example:"Contrato"`
+}
+
+// BatchHeaderFooterRequest representa a requisição para aplicar cabeçalhos e rodapés em um conjunto de documentos
+// @Description Documentos na ordem da produção; a numeração Bates continua de um documento para o seguinte
+type BatchHeaderFooterRequest struct {
+	HeaderFooterOptions
+	Documents []HeaderFooterSource `json:"documents" validate:"required,min=1,dive"`
+}
+
+// HeaderFooterDocument representa um documento com cabeçalhos e rodapés aplicados
+// @Description Nova versão do documento e o intervalo de números Bates atribuídos a ele
+type HeaderFooterDocument struct {
+	Document   DocumentResponse `json:"document"`
+	BatesFirst string           `json:"bates_first,omitempty" example:"ACME-000123"`
+	BatesLast  string           `json:"bates_last,omitempty" example:"ACME-000134"`
+}
+
+// HeaderFooterResponse representa a resposta após aplicar cabeçalhos e rodapés
+// @Description Documentos atualizados e o próximo número Bates, para continuar a produção em outra requisição
+type HeaderFooterResponse struct {
+	Documents       []HeaderFooterDocument `json:"documents"`
+	NextBatesNumber *int                   `json:"next_bates_number,omitempty" example:"135"`
+	Message         string                 `json:"message" example:"Cabeçalhos e rodapés aplicados com sucesso"`
+}
+
+// AnnotationRequest representa a requisição para criar ou substituir uma anotação
+// @Description Coordenadas em PDF points com origem no topo esquerdo. highlight, underline e strikeout usam quads (ex.: os de uma busca); note usa (x, y); free_text usa x, y, width e height (texto WinAnsi); ink usa inkList
+type AnnotationRequest struct {
+	Page      int          `json:"page" validate:"required,min=1" example:"1"`
+	Type      string       `json:"type" validate:"required,oneof=highlight underline strikeout note free_text ink" example:"highlight"`
+	X         float64      `json:"x,omitempty" example:"72.0"`
+	Y         float64      `json:"y,omitempty" example:"100.0"`
+	Width     float64      `json:"width,omitempty" validate:"gte=0" example:"200.0"`
+	Height    float64      `json:"height,omitempty" validate:"gte=0" example:"40.0"`
+	Quads     []model.Quad `json:"quads,omitempty"`
+	InkList   [][]Point    `json:"inkList,omitempty" validate:"omitempty,dive,min=2"`
+	Author    string       `json:"author,omitempty" validate:"max=255" example:"Maria Revisora"`
+	Contents  string       `json:"contents,omitempty" example:"Revisar esta cláusula"`
+	Color     string       `json:"color,omitempty" validate:"omitempty,hexcolor" example:"#FFFF00"`
+	Opacity   *float64     `json:"opacity,omitempty" validate:"omitempty,gt=0,lte=1" example:"1"`
+	LineWidth *float64     `json:"lineWidth,omitempty" validate:"omitempty,gt=0,lte=100" example:"2"`
+	FontSize  *float64     `json:"fontSize,omitempty" validate:"omitempty,gt=0,lte=200" example:"12"`
+}
+
+// AnnotationListResponse representa as anotações de um documento
+// @Description Anotações em camada separada do conteúdo, em PDF points (origem no topo esquerdo)
+type AnnotationListResponse struct {
+	DocumentID  string             `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version     int                `json:"version" example:"1"`
+	Annotations []model.Annotation `json:"annotations"`
+	Total       int                `json:"total" example:"4"`
+}
+
+// ImportAnnotationsResponse representa a resposta após importar anotações de um XFDF
+// @Description Quantidade de anotações criadas e atualizadas (pelo nome da anotação no XFDF)
+type ImportAnnotationsResponse struct {
+	Created int    `json:"created" example:"3"`
+	Updated int    `json:"updated" example:"1"`
+	Message string `json:"message" example:"Anotações importadas com sucesso"`
+}
+
+// BookmarkTarget representa o destino de um marcador
+// @Description Página e visualização de destino; left e top em PDF points com origem no topo esquerdo (nulos mantêm a posição atual do leitor). Página 0 cria um marcador sem destino
+type BookmarkTarget struct {
+	Page int      `json:"page" validate:"gte=0" example:"3"`
+	View string   `json:"view,omitempty" validate:"omitempty,oneof=fit fit_width fit_height xyz" example:"xyz"`
+	Left *float64 `json:"left,omitempty" example:"0"`
+	Top  *float64 `json:"top,omitempty" example:"72"`
+	Zoom float64  `json:"zoom,omitempty" validate:"gte=0,lte=64" example:"0"`
+}
+
+// BookmarkRequest representa um marcador (com seus filhos) em uma requisição
+type BookmarkRequest struct {
+	Title string `json:"title" validate:"required,max=512" example:"Capítulo 1"`
+	BookmarkTarget
+	Open     bool              `json:"open,omitempty" example:"true"`
+	Bold     bool              `json:"bold,omitempty" example:"false"`
+	Italic   bool              `json:"italic,omitempty" example:"false"`
+	Color    string            `json:"color,omitempty" validate:"omitempty,hexcolor" example:"#000000"`
+	Children []BookmarkRequest `json:"children,omitempty" validate:"omitempty,dive"`
+}
+
+// ReplaceBookmarksRequest representa a requisição para substituir toda a árvore de marcadores
+// @Description Lista vazia remove todos os marcadores do documento
+type ReplaceBookmarksRequest struct {
+	Bookmarks []BookmarkRequest `json:"bookmarks" validate:"dive"`
+}
+
+// BookmarkOperation representa uma edição pontual na árvore de marcadores
+// @Description Caminhos são as posições na árvore (1 = primeiro item, "2.1" = primeiro filho do segundo item), avaliados após as operações anteriores. add insere bookmark em parent (vazio = raiz) na posição informada (vazio = final); move leva o item de path para parent/position; rename usa title; set_open usa open; set_target usa target
+type BookmarkOperation struct {
+	Type     string           `json:"type" validate:"required,oneof=add rename move delete set_open set_target" example:"rename"`
+	Path     string           `json:"path,omitempty" example:"2.1"`
+	Parent   string           `json:"parent,omitempty" example:"2"`
+	Position *int             `json:"position,omitempty" validate:"omitempty,min=1" example:"1"`
+	Title    string           `json:"title,omitempty" validate:"max=512" example:"Introdução"`
+	Open     *bool            `json:"open,omitempty" example:"true"`
+	Target   *BookmarkTarget  `json:"target,omitempty"`
+	Bookmark *BookmarkRequest `json:"bookmark,omitempty"`
+}
+
+// EditBookmarksRequest representa a requisição para editar a árvore de marcadores
+// @Description As operações são aplicadas em ordem e gravadas em uma única nova versão
+type EditBookmarksRequest struct {
+	Operations []BookmarkOperation `json:"operations" validate:"required,min=1,dive"`
+}
+
+// GenerateBookmarksRequest representa a requisição para gerar marcadores a partir dos títulos do texto
+// @Description Títulos são linhas curtas com fonte maior que a do corpo do texto; cada tamanho de fonte é um nível. Com preview os marcadores são apenas retornados
+type GenerateBookmarksRequest struct {
+	MaxLevel     int     `json:"maxLevel,omitempty" validate:"omitempty,min=1,max=6" example:"3"`
+	MinSizeRatio float64 `json:"minSizeRatio,omitempty" validate:"omitempty,gte=1,lte=5" example:"1.15"`
+	Preview      bool    `json:"preview,omitempty" example:"false"`
+}
+
+// BookmarksResponse representa a árvore de marcadores de um documento
+// @Description Cada marcador traz seu caminho (path), usado nas edições
+type BookmarksResponse struct {
+	DocumentID string           `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version    int              `json:"version" example:"2"`
+	Bookmarks  []model.Bookmark `json:"bookmarks"`
+	Total      int              `json:"total" example:"12"`
+}
+
+// GenerateBookmarksResponse representa a resposta após gerar marcadores a partir dos títulos
+// @Description Em preview, document é omitido e o PDF não é alterado
+type GenerateBookmarksResponse struct {
+	Headings  []model.Heading   `json:"headings"`
+	Bookmarks []model.Bookmark  `json:"bookmarks"`
+	Document  *DocumentResponse `json:"document,omitempty"`
+	Message   string            `json:"message" example:"Marcadores gerados com sucesso"`
+}
+
+// UpdateMetadataRequest representa a requisição para alterar os metadados de um documento
+// @Description Campos omitidos mantêm o valor atual e string vazia remove o valor. Em custom, valor nulo remove a propriedade. Título, autor, assunto, palavras-chave, criador e propriedades são gravados no dicionário Info e no XMP do PDF (nova versão); originalFilename altera apenas o registro
+type UpdateMetadataRequest struct {
+	OriginalFilename *string            `json:"originalFilename,omitempty" validate:"omitempty,max=255" example:"contrato-assinado.pdf"`
+	Title            *string            `json:"title,omitempty" validate:"omitempty,max=1024" example:"Contrato de prestação de serviços"`
+	Author           *string            `json:"author,omitempty" validate:"omitempty,max=1024" example:"Maria Silva"`
+	Subject          *string            `json:"subject,omitempty" validate:"omitempty,max=1024" example:"Prestação de serviços de consultoria"`
+	Keywords         *string            `json:"keywords,omitempty" validate:"omitempty,max=1024" example:"contrato, consultoria"`
+	Creator          *string            `json:"creator,omitempty" validate:"omitempty,max=1024" example:"Editor PDF"`
+	Custom           map[string]*string `json:"custom,omitempty" validate:"omitempty,max=100,dive,omitempty,max=4096"`
+}
+
+// MetadataResponse representa os metadados de um documento
+// @Description metadata consolida o dicionário Info com o XMP (usado quando o Info não tem o valor); in_sync indica se o XMP existe e tem os mesmos valores editáveis do Info (Producer e datas, atualizados pelas edições apenas no Info, não são comparados)
+type MetadataResponse struct {
+	DocumentID       string                  `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version          int                     `json:"version" example:"2"`
+	OriginalFilename string                  `json:"original_filename" example:"contrato.pdf"`
+	Metadata         model.DocumentMetadata  `json:"metadata"`
+	Info             model.DocumentMetadata  `json:"info"`
+	XMP              *model.DocumentMetadata `json:"xmp,omitempty"`
+	InSync           bool                    `json:"in_sync" example:"true"`
+}
+
+// OptimizeDocumentRequest representa a requisição para otimizar (reduzir o tamanho de) um documento
+// @Description Perfis: screen (imagens a 72 DPI), ebook (150 DPI) e print (300 DPI); apenas imagens acima de 1,5x a resolução do perfil são reamostradas
+type OptimizeDocumentRequest struct {
+	Preset string `json:"preset" validate:"required,oneof=screen ebook print" example:"ebook" enums:"screen,ebook,print"`
+}
+
+// OptimizeDocumentResponse representa a resposta após otimizar um documento
+// @Description Sem redução de tamanho (optimized = false) nenhuma versão é criada e document é a versão atual
+type OptimizeDocumentResponse struct {
+	Document     DocumentResponse     `json:"document"`
+	Preset       string               `json:"preset" example:"ebook"`
+	Optimized    bool                 `json:"optimized" example:"true"`
+	Report       model.OptimizeReport `json:"report"`
+	SavedPercent float64              `json:"saved_percent" example:"42.5"`
+	Message      string               `json:"message" example:"Documento otimizado com sucesso"`
+}
+
+// ExportImagesRequest representa a requisição para exportar páginas de um documento como imagens
+// @Description Informe dpi ou width (padrão: 150 DPI). Uma página resulta em uma imagem; várias páginas resultam em um ZIP, exceto em TIFF com multiPage (um único arquivo). zip=true sempre gera um ZIP. transparent não é suportado em JPEG
+type ExportImagesRequest struct {
+	Pages       string  `json:"pages,omitempty" example:"1-3,5,8-"`
+	Format      string  `json:"format" validate:"required,oneof=png jpeg tiff" example:"png" enums:"png,jpeg,tiff"`
+	DPI         float64 `json:"dpi,omitempty" validate:"omitempty,min=18,max=600" example:"150"`
+	Width       int     `json:"width,omitempty" validate:"omitempty,min=16,max=10000" example:"1920"`
+	Quality     int     `json:"quality,omitempty" validate:"omitempty,min=1,max=100" example:"85"`
+	Grayscale   bool    `json:"grayscale" example:"false"`
+	Transparent bool    `json:"transparent" example:"false"`
+	MultiPage   bool    `json:"multiPage" example:"true"`
+	Zip         bool    `json:"zip" example:"false"`
+}
+
+// PreviewRequest representa os parâmetros de renderização da preview de uma página
+// @Description Informe dpi ou width (padrão: 150 DPI). clip recorta a região "x,y,largura,altura" em PDF points, com origem no topo esquerdo da página exibida; dpi e width se aplicam à região. thumbnail gera uma miniatura que cabe em um quadrado de size pixels (padrão: 200), em JPEG quando format não é informado
+type PreviewRequest struct {
+	DPI       float64 `json:"dpi,omitempty" validate:"omitempty,min=18,max=1200" example:"150"`
+	Width     int     `json:"width,omitempty" validate:"omitempty,min=16,max=4096" example:"1024"`
+	Format    string  `json:"format" validate:"oneof=png jpeg webp" example:"png" enums:"png,jpeg,webp"`
+	Quality   int     `json:"quality,omitempty" validate:"omitempty,min=1,max=100" example:"85"`
+	Clip      string  `json:"clip,omitempty" example:"72,100,200,150"`
+	Thumbnail bool    `json:"thumbnail" example:"false"`
+	Size      int     `json:"size,omitempty" validate:"omitempty,min=32,max=512" example:"200"`
+}
+
+// ConvertPDFARequest representa a requisição para converter um documento para PDF/A
+// @Description Níveis: 1b (ISO 19005-1), 2b (ISO 19005-2) e 3b (ISO 19005-3); password é exigida para documentos protegidos
+type ConvertPDFARequest struct {
+	Conformance string `json:"conformance" validate:"required,oneof=1b 2b 3b" example:"2b" enums:"1b,2b,3b"`
+	Password    string `json:"password,omitempty" example:"senha-de-abertura"`
+}
+
+// PDFAConversionResponse representa a resposta após converter um documento para PDF/A
+// @Description Com violações não corrigíveis (converted = false) nenhuma versão é criada e document é a versão atual
+type PDFAConversionResponse struct {
+	Document  DocumentResponse           `json:"document"`
+	Converted bool                       `json:"converted" example:"true"`
+	Report    model.PDFAConversionReport `json:"report"`
+	Message   string                     `json:"message" example:"Documento convertido para PDF/A-2b com sucesso"`
+}
+
+// PDFAValidationResponse representa o resultado da validação PDF/A de um documento
+type PDFAValidationResponse struct {
+	DocumentID string               `json:"document_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Version    int                  `json:"version" example:"3"`
+	Validation model.PDFAValidation `json:"validation"`
+}
@@ -1,23 +1,84 @@
 package dto
 
 // EditInstruction representa uma instrução de edição de PDF
-// @Description Instrução individual para editar um documento PDF (adicionar texto, imagem ou desenho)
+// @Description Instrução individual para editar um documento PDF (adicionar texto, imagem, desenho ou marca d'água, manipular páginas, redigir regiões ou criar e preencher formulários)
 type EditInstruction struct {
-	Type     string                 `json:"type" validate:"required,oneof=text image drawing" example:"text" enums:"text,image,drawing"`
-	Page     int                    `json:"page" validate:"required,min=1" example:"1"`
-	X        float64                `json:"x" validate:"required" example:"100.5"`
-	Y        float64                `json:"y" validate:"required" example:"200.5"`
+	Type     string                 `json:"type" validate:"required,oneof=text image drawing rotate_page delete_page move_page duplicate_page insert_page redact form_field form_fill form_flatten watermark" example:"text" enums:"text,image,drawing,rotate_page,delete_page,move_page,duplicate_page,insert_page,redact,form_field,form_fill,form_flatten,watermark"`
+	Page     int                    `json:"page" validate:"min=0" example:"1"` // Omitido (0) nas instruções que se aplicam ao documento inteiro (form_fill, form_flatten, watermark)
+	X        float64                `json:"x" example:"100.5"`
+	Y        float64                `json:"y" example:"200.5"`
 	Width    *float64               `json:"width,omitempty" example:"150.0"`
 	Height   *float64               `json:"height,omitempty" example:"50.0"`
 	Content  string                 `json:"content,omitempty" example:"Texto a ser inserido"`
 	FontSize *float64               `json:"fontSize,omitempty" example:"12.0"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Campos de texto (type=text)
+	// Com width informado, (x, y) é o canto superior esquerdo da caixa e o texto é quebrado em linhas
+	FontFamily string   `json:"fontFamily,omitempty" example:"go"`
+	FontWeight string   `json:"fontWeight,omitempty" validate:"omitempty,oneof=normal bold" example:"bold" enums:"normal,bold"`
+	Rotation   *float64 `json:"rotation,omitempty" validate:"omitempty,gte=-360,lte=360" example:"45"`
+	LineHeight *float64 `json:"lineHeight,omitempty" validate:"omitempty,gt=0" example:"1.2"`
+	Align      string   `json:"align,omitempty" validate:"omitempty,oneof=left center right" example:"center" enums:"left,center,right"`
+	Overflow   string   `json:"overflow,omitempty" validate:"omitempty,oneof=visible clip shrink" example:"shrink" enums:"visible,clip,shrink"`
+
+	// Campos de páginas (rotate_page usa rotation; insert_page usa page como posição e width/height como tamanho)
+	TargetPage *int `json:"targetPage,omitempty" validate:"omitempty,min=1" example:"3"`
+
+	// Campos de desenho (type=drawing); fillColor e opacity também se aplicam a texto
+	Shape       string    `json:"shape,omitempty" validate:"omitempty,oneof=line rectangle ellipse polygon polyline" example:"ellipse" enums:"line,rectangle,ellipse,polygon,polyline"`
+	Points      []Point   `json:"points,omitempty" validate:"omitempty,dive"`
+	StrokeColor string    `json:"strokeColor,omitempty" validate:"omitempty,hexcolor" example:"#FF0000"`
+	FillColor   string    `json:"fillColor,omitempty" validate:"omitempty,hexcolor" example:"#FFFF00"`
+	LineWidth   *float64  `json:"lineWidth,omitempty" validate:"omitempty,gt=0" example:"2.0"`
+	DashPattern []float64 `json:"dashPattern,omitempty" validate:"omitempty,dive,gte=0" example:"6,3"`
+	Opacity     *float64  `json:"opacity,omitempty" validate:"omitempty,gte=0,lte=1" example:"0.8"`
+
+	// Campos de redação (type=redact): x, y, width e height delimitam a região; content é o texto
+	// sobreposto opcional e fillColor o preenchimento (padrão preto)
+	Fill      *bool  `json:"fill,omitempty" example:"true"`
+	TextColor string `json:"textColor,omitempty" validate:"omitempty,hexcolor" example:"#FFFFFF"`
+
+	// Criação de campo de formulário (type=form_field): x, y, width e height delimitam o widget;
+	// fontFamily (helvetica, times ou courier), fontWeight e fontSize (0 = automático) definem a fonte.
+	// Botões de radio com o mesmo name formam um grupo, cada um com seu exportValue
+	FieldType    string   `json:"fieldType,omitempty" validate:"omitempty,oneof=text checkbox radio combo signature" example:"text" enums:"text,checkbox,radio,combo,signature"`
+	Name         string   `json:"name,omitempty" validate:"omitempty,max=255" example:"nome_completo"`
+	DefaultValue string   `json:"defaultValue,omitempty" example:"Maria"`
+	ExportValue  string   `json:"exportValue,omitempty" example:"Sim"`
+	Options      []string `json:"options,omitempty" example:"São Paulo,Rio de Janeiro"`
+	Editable     bool     `json:"editable,omitempty" example:"false"`
+	Multiline    bool     `json:"multiline,omitempty" example:"false"`
+	MaxLength    *int     `json:"maxLength,omitempty" validate:"omitempty,min=1" example:"50"`
+	ReadOnly     bool     `json:"readOnly,omitempty" example:"false"`
+	Required     bool     `json:"required,omitempty" example:"true"`
+
+	// Marca d'água (type=watermark): content é o texto ou imagePath a imagem; pages seleciona as páginas
+	// (ex.: "1-3,5,8-"; vazio = todas). rotation, opacity (padrão 0.3), fillColor (cor do texto),
+	// fontFamily (helvetica, times ou courier) e fontWeight também se aplicam
+	Pages      string   `json:"pages,omitempty" example:"1-3,5,8-"`
+	ImagePath  string   `json:"imagePath,omitempty" example:"logos/empresa.png"`
+	Position   string   `json:"position,omitempty" validate:"omitempty,oneof=center diagonal top_left top_right bottom_left bottom_right" example:"diagonal" enums:"center,diagonal,top_left,top_right,bottom_left,bottom_right"`
+	Scale      *float64 `json:"scale,omitempty" validate:"omitempty,gt=0,lte=1" example:"0.6"` // Largura relativa à página
+	Background bool     `json:"background,omitempty" example:"false"`                          // Atrás do conteúdo (padrão: sobre o conteúdo)
+
+	// Campos de formulário (type=form_fill): valores pelo nome completo do campo; checkbox usa bool,
+	// list com seleção múltipla usa lista de strings e os demais tipos usam string
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// Point representa um ponto em PDF points, com origem no topo esquerdo da página
+// @Description Ponto de um desenho (linha, polígono ou traço livre)
+type Point struct {
+	X float64 `json:"x" example:"120.0"`
+	Y float64 `json:"y" example:"80.0"`
 }
 
 // ProcessDocumentRequest representa a requisição para processar edições em um documento
 // @Description Requisição contendo lista de instruções de edição a serem aplicadas no documento
 type ProcessDocumentRequest struct {
 	Instructions []EditInstruction `json:"instructions" validate:"required,min=1,dive"`
+	Linearize    bool              `json:"linearize,omitempty" example:"true"` // Lineariza a versão gerada (visualização rápida na web)
 }
 
 // ProcessDocumentResponse representa a resposta após processar edições
@@ -1,11 +1,15 @@
 package handler
 
 import (
+	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
 	"github.com/editor-pdf/backend/internal/usecase"
 	"github.com/editor-pdf/backend/pkg/response"
 	"github.com/google/uuid"
@@ -35,14 +39,20 @@ func NewDocumentHandler(
 	}
 }
 
-// UploadDocument faz upload de um documento PDF
-// @Summary Faz upload de um documento PDF
-// @Description Faz upload de um arquivo PDF e cria um registro no banco
+// UploadDocument faz upload de um documento PDF ou de imagens convertidas em PDF
+// @Summary Faz upload de um documento PDF ou de imagens
+// @Description Faz upload de um arquivo PDF e cria um registro no banco. PDFs protegidos por senha são decifrados com a senha informada. Imagens PNG, JPEG e TIFF (inclusive multipágina), uma ou várias no campo file, são convertidas em um único PDF com uma página por imagem
 // @Tags documents
 // @Security Bearer
 // @Accept multipart/form-data
 // @Produce json
-// @Param file formData file true "Arquivo PDF"
+// @Param file formData file true "Arquivo PDF ou imagens (PNG, JPEG, TIFF); repita o campo para várias imagens"
+// @Param password formData string false "Senha do PDF protegido (a proteção é removida no armazenamento)"
+// @Param pageSize formData string false "Tamanho das páginas geradas a partir de imagens" Enums(fit, a4, letter) default(fit)
+// @Param orientation formData string false "Orientação das páginas a4 e letter" Enums(auto, portrait, landscape) default(auto)
+// @Param margin formData number false "Margem em PDF points" default(0)
+// @Param autoRotate formData bool false "Aplica a orientação EXIF das fotos" default(true)
+// @Param jpegPassthrough formData bool false "Embute JPEGs sem recompressão" default(true)
 // @Success 201 {object} dto.UploadDocumentResponse
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
@@ -52,49 +62,116 @@ func (h *DocumentHandler) UploadDocument(c echo.Context) error {
 	// Usa DefaultUserID quando não há autenticação
 	userUUID := DefaultUserID
 
-	// Obtém o arquivo do form
-	file, err := c.FormFile("file")
-	if err != nil {
+	// Obtém os arquivos do form
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["file"]) == 0 {
 		return response.ErrorBadRequest(c, err, "arquivo não fornecido")
 	}
-
-	// Valida tamanho do arquivo
-	if file.Size > h.maxUploadSize {
-		return response.Error(c, http.StatusRequestEntityTooLarge, nil, "arquivo muito grande")
+	files := form.File["file"]
+	if len(files) > maxUploadImages {
+		return response.ErrorBadRequest(c, nil, fmt.Sprintf("no máximo %d arquivos por envio", maxUploadImages))
 	}
 
-	// Abre o arquivo
-	src, err := file.Open()
-	if err != nil {
-		return response.ErrorBadRequest(c, err, "erro ao abrir arquivo")
-	}
-	defer src.Close()
+	var totalSize int64
+	images := make([]model.ImageFile, 0, len(files))
+	for _, file := range files {
+		// Valida tamanho do arquivo (e do envio inteiro)
+		totalSize += file.Size
+		if file.Size > h.maxUploadSize || totalSize > h.maxUploadSize {
+			return response.Error(c, http.StatusRequestEntityTooLarge, nil, "arquivo muito grande")
+		}
 
-	// Lê o conteúdo do arquivo
-	fileData, err := io.ReadAll(src)
-	if err != nil {
-		return response.ErrorBadRequest(c, err, "erro ao ler arquivo")
-	}
+		// Abre o arquivo
+		src, err := file.Open()
+		if err != nil {
+			return response.ErrorBadRequest(c, err, "erro ao abrir arquivo")
+		}
 
-	// Valida tamanho novamente após ler
-	if int64(len(fileData)) > h.maxUploadSize {
-		return response.Error(c, http.StatusRequestEntityTooLarge, nil, "arquivo muito grande")
+		// Lê o conteúdo do arquivo
+		fileData, err := io.ReadAll(src)
+		src.Close()
+		if err != nil {
+			return response.ErrorBadRequest(c, err, "erro ao ler arquivo")
+		}
+
+		// Valida tamanho novamente após ler
+		if int64(len(fileData)) > h.maxUploadSize {
+			return response.Error(c, http.StatusRequestEntityTooLarge, nil, "arquivo muito grande")
+		}
+
+		// Valida MIME type
+		contentType := file.Header.Get("Content-Type")
+		if contentType != "" && !allowedUploadTypes[contentType] {
+			return response.ErrorBadRequest(c, nil, "tipo de arquivo inválido (esperado: application/pdf, image/png, image/jpeg ou image/tiff)")
+		}
+
+		// Valida magic bytes (PDF deve começar com %PDF)
+		if len(fileData) >= 4 && string(fileData[0:4]) == "%PDF" {
+			if len(files) > 1 {
+				return response.ErrorBadRequest(c, nil, "envie um único PDF ou apenas imagens")
+			}
+			return h.uploadPDF(c, userUUID, fileData, file.Filename)
+		}
+		if !isUploadImage(fileData) {
+			return response.ErrorBadRequest(c, nil, "arquivo não é um PDF ou imagem válida (magic bytes inválidos)")
+		}
+		images = append(images, model.ImageFile{Name: file.Filename, Data: fileData})
 	}
 
-	// Valida MIME type
-	contentType := file.Header.Get("Content-Type")
-	if contentType != "" && contentType != "application/pdf" {
-		return response.ErrorBadRequest(c, nil, "tipo de arquivo inválido (esperado: application/pdf)")
+	options := dto.ImageUploadOptions{
+		PageSize:        formValueOr(c, "pageSize", string(model.ImagePageFit)),
+		Orientation:     formValueOr(c, "orientation", string(model.ImageOrientationAuto)),
+		AutoRotate:      true,
+		JPEGPassthrough: true,
+	}
+	if marginStr := c.FormValue("margin"); marginStr != "" {
+		if options.Margin, err = strconv.ParseFloat(marginStr, 64); err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro margin inválido")
+		}
+	}
+	if autoRotateStr := c.FormValue("autoRotate"); autoRotateStr != "" {
+		if options.AutoRotate, err = strconv.ParseBool(autoRotateStr); err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro autoRotate inválido")
+		}
+	}
+	if passthroughStr := c.FormValue("jpegPassthrough"); passthroughStr != "" {
+		if options.JPEGPassthrough, err = strconv.ParseBool(passthroughStr); err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro jpegPassthrough inválido")
+		}
+	}
+	if err := c.Validate(&options); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
 	}
 
-	// Valida magic bytes (PDF deve começar com %PDF)
-	if len(fileData) < 4 || string(fileData[0:4]) != "%PDF" {
-		return response.ErrorBadRequest(c, nil, "arquivo não é um PDF válido (magic bytes inválidos)")
+	// Converte as imagens em um documento PDF
+	document, err := h.documentUseCase.UploadImages(c.Request().Context(), userUUID, images, model.ImageConversionOptions{
+		PageSize:        model.ImagePageSize(options.PageSize),
+		Orientation:     model.ImageOrientation(options.Orientation),
+		Margin:          options.Margin,
+		AutoRotate:      options.AutoRotate,
+		JPEGPassthrough: options.JPEGPassthrough,
+	})
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "imagens inválidas") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao fazer upload do documento")
 	}
 
+	return response.SuccessCreated(c, dto.UploadDocumentResponse{
+		Document: *document,
+		Message:  "Documento criado a partir das imagens com sucesso",
+	}, "Documento criado a partir das imagens com sucesso")
+}
+
+// uploadPDF faz upload de um único arquivo PDF
+func (h *DocumentHandler) uploadPDF(c echo.Context, userUUID uuid.UUID, fileData []byte, filename string) error {
 	// Faz upload do documento (validação adicional será feita no UseCase)
-	document, err := h.documentUseCase.UploadDocument(c.Request().Context(), userUUID, fileData, file.Filename)
+	document, err := h.documentUseCase.UploadDocument(c.Request().Context(), userUUID, fileData, filename, c.FormValue("password"))
 	if err != nil {
+		if err.Error() == "PDF protegido por senha" || err.Error() == "senha do PDF inválida" {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
 		return response.ErrorInternalServer(c, err, "erro ao fazer upload do documento")
 	}
 
@@ -104,6 +181,40 @@ func (h *DocumentHandler) UploadDocument(c echo.Context) error {
 	}, "Documento enviado com sucesso")
 }
 
+// maxUploadImages limita a quantidade de imagens convertidas em um único envio
+const maxUploadImages = 200
+
+// allowedUploadTypes são os tipos MIME aceitos no upload
+var allowedUploadTypes = map[string]bool{
+	"application/pdf": true,
+	"image/png":       true,
+	"image/jpeg":      true,
+	"image/jpg":       true,
+	"image/tiff":      true,
+	"image/x-tiff":    true,
+}
+
+// isUploadImage verifica os magic bytes de PNG, JPEG e TIFF
+func isUploadImage(data []byte) bool {
+	switch {
+	case len(data) >= 8 && string(data[:8]) == "\x89PNG\r\n\x1a\n":
+		return true
+	case len(data) >= 3 && data[0] == 0xFF && data[1] == 0xD8 && data[2] == 0xFF:
+		return true
+	case len(data) >= 4 && (string(data[:4]) == "II*\x00" || string(data[:4]) == "MM\x00*"):
+		return true
+	}
+	return false
+}
+
+// formValueOr retorna o valor do campo do formulário ou o padrão quando ausente
+func formValueOr(c echo.Context, name, fallback string) string {
+	if value := c.FormValue(name); value != "" {
+		return value
+	}
+	return fallback
+}
+
 // ListDocuments lista documentos do usuário
 // @Summary Lista documentos do usuário
 // @Description Retorna uma lista paginada de documentos do usuário autenticado
@@ -112,7 +223,10 @@ func (h *DocumentHandler) UploadDocument(c echo.Context) error {
 // @Produce json
 // @Param limit query int false "Limite de resultados" default(20)
 // @Param offset query int false "Offset para paginação" default(0)
+// @Param search query string false "Palavras do nome original, título, autor, assunto ou palavras-chave"
+// @Param pdfa query string false "Nível PDF/A (1b, 2b ou 3b), any (qualquer nível) ou none (não conformes)"
 // @Success 200 {object} dto.DocumentListResponse
+// @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
 // @Router /api/v1/documents [get]
 func (h *DocumentHandler) ListDocuments(c echo.Context) error {
@@ -136,8 +250,13 @@ func (h *DocumentHandler) ListDocuments(c echo.Context) error {
 	}
 
 	// Lista documentos
-	documents, err := h.documentUseCase.ListDocuments(c.Request().Context(), userUUID, limit, offset)
+	search := strings.TrimSpace(c.QueryParam("search"))
+	pdfa := strings.ToLower(strings.TrimSpace(c.QueryParam("pdfa")))
+	documents, err := h.documentUseCase.ListDocuments(c.Request().Context(), userUUID, search, pdfa, limit, offset)
 	if err != nil {
+		if strings.HasPrefix(err.Error(), "filtro PDF/A inválido") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
 		return response.ErrorInternalServer(c, err, "erro ao listar documentos")
 	}
 
@@ -182,7 +301,7 @@ func (h *DocumentHandler) GetDocument(c echo.Context) error {
 
 // ProcessDocument processa edições em um documento
 // @Summary Processa edições em um documento
-// @Description Aplica edições (texto, imagens, etc.) em um documento PDF
+// @Description Aplica edições (texto, imagens, etc.) em um documento PDF; com linearize a versão gerada é linearizada (visualização rápida na web)
 // @Tags documents
 // @Security Bearer
 // @Accept json
@@ -213,14 +332,20 @@ func (h *DocumentHandler) ProcessDocument(c echo.Context) error {
 	}
 
 	// Processa documento
-	document, err := h.documentUseCase.ProcessDocument(c.Request().Context(), documentID, userUUID, req.Instructions)
+	document, err := h.documentUseCase.ProcessDocument(c.Request().Context(), documentID, userUUID, req.Instructions, req.Linearize)
 	if err != nil {
+		if err.Error() == "documento protegido por senha; remova a proteção antes de editar" {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
 		if err.Error() == "documento não encontrado" {
 			return response.ErrorNotFound(c, err, "documento não encontrado")
 		}
 		if err.Error() == "acesso negado" {
 			return response.ErrorForbidden(c, err, "acesso negado")
 		}
+		if strings.HasPrefix(err.Error(), "texto inválido") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
 		return response.ErrorInternalServer(c, err, "erro ao processar documento")
 	}
 
@@ -230,20 +355,253 @@ func (h *DocumentHandler) ProcessDocument(c echo.Context) error {
 	})
 }
 
-// GeneratePreview gera preview de uma página do documento
-// @Summary Gera preview de uma página
-// @Description Retorna uma imagem (preview) de uma página específica do PDF
+// MergeDocuments mescla documentos em um novo documento
+// @Summary Mescla documentos
+// @Description Combina documentos (ou intervalos de páginas deles), na ordem informada, em um novo documento
 // @Tags documents
 // @Security Bearer
-// @Produce image/png
+// @Accept json
+// @Produce json
+// @Param request body dto.MergeDocumentsRequest true "Documentos de origem"
+// @Success 201 {object} dto.MergeDocumentsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/merge [post]
+func (h *DocumentHandler) MergeDocuments(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	var req dto.MergeDocumentsRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Mescla documentos
+	document, err := h.documentUseCase.MergeDocuments(c.Request().Context(), userUUID, req.Documents)
+	if err != nil {
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		return response.ErrorInternalServer(c, err, "erro ao mesclar documentos")
+	}
+
+	return response.SuccessCreated(c, dto.MergeDocumentsResponse{
+		Document: *document,
+		Message:  "Documentos mesclados com sucesso",
+	}, "Documentos mesclados com sucesso")
+}
+
+// SplitDocument divide um documento em novos documentos
+// @Summary Divide um documento
+// @Description Divide um documento por intervalos de páginas, a cada N páginas ou por marcador de primeiro nível
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
 // @Param id path string true "ID do documento"
-// @Param page path int true "Número da página"
+// @Param request body dto.SplitDocumentRequest true "Modo de divisão"
+// @Success 201 {object} dto.SplitDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/split [post]
+func (h *DocumentHandler) SplitDocument(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.SplitDocumentRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Divide documento
+	documents, err := h.documentUseCase.SplitDocument(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		return response.ErrorInternalServer(c, err, "erro ao dividir documento")
+	}
+
+	return response.SuccessCreated(c, dto.SplitDocumentResponse{
+		Documents: documents,
+		Message:   "Documento dividido com sucesso",
+	}, "Documento dividido com sucesso")
+}
+
+// ExtractText extrai o texto de um documento
+// @Summary Extrai o texto de um documento
+// @Description Retorna o texto de cada página e, com words=true, as linhas e palavras com caixas em PDF points (origem no topo esquerdo)
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param pages query string false "Páginas (ex.: 1-3,5,8-); vazio = todas"
+// @Param words query bool false "Inclui linhas e palavras com posições"
+// @Success 200 {object} dto.DocumentTextResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/text [get]
+func (h *DocumentHandler) ExtractText(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	withWords := false
+	if wordsStr := c.QueryParam("words"); wordsStr != "" {
+		withWords, err = strconv.ParseBool(wordsStr)
+		if err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro words inválido")
+		}
+	}
+
+	// Extrai texto
+	text, err := h.documentUseCase.ExtractText(c.Request().Context(), documentID, userUUID, c.QueryParam("pages"), withWords)
+	if err != nil {
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		return response.ErrorInternalServer(c, err, "erro ao extrair texto")
+	}
+
+	return response.SuccessOK(c, text)
+}
+
+// SearchText busca texto em um documento
+// @Summary Busca texto em um documento
+// @Description Busca uma frase (ou expressão regular com regex=true) ignorando maiúsculas/minúsculas e acentos. Cada ocorrência traz a página e os quadriláteros/retângulos do trecho em PDF points (origem no topo esquerdo)
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param q query string true "Frase ou expressão regular"
+// @Param regex query bool false "Interpreta q como expressão regular (RE2)"
+// @Param pages query string false "Páginas (ex.: 1-3,5,8-); vazio = todas"
+// @Success 200 {object} dto.SearchResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/search [get]
+func (h *DocumentHandler) SearchText(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	isRegex := false
+	if regexStr := c.QueryParam("regex"); regexStr != "" {
+		isRegex, err = strconv.ParseBool(regexStr)
+		if err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro regex inválido")
+		}
+	}
+
+	// Busca texto
+	result, err := h.documentUseCase.SearchText(c.Request().Context(), documentID, userUUID, c.QueryParam("q"), isRegex, c.QueryParam("pages"))
+	if err != nil {
+		if err.Error() == "consulta de busca vazia" || err.Error() == "expressão regular inválida" {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		return response.ErrorInternalServer(c, err, "erro ao buscar texto")
+	}
+
+	return response.SuccessOK(c, result)
+}
+
+// ListFormFields lista os campos de formulário de um documento
+// @Summary Lista os campos de formulário de um documento
+// @Description Retorna os campos do formulário (AcroForm) com tipo, valor, opções, flags e widgets em PDF points (origem no topo esquerdo). Para preencher, use a instrução form_fill em /process
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Success 200 {object} dto.FormFieldsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/form [get]
+func (h *DocumentHandler) ListFormFields(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	// Lista campos
+	fields, err := h.documentUseCase.ListFormFields(c.Request().Context(), documentID, userUUID)
+	if err != nil {
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		return response.ErrorInternalServer(c, err, "erro ao listar campos do formulário")
+	}
+
+	return response.SuccessOK(c, fields)
+}
+
+// ExportFormData exporta os valores do formulário de um documento
+// @Summary Exporta os valores do formulário
+// @Description Exporta os valores dos campos do formulário como arquivo FDF ou XFDF
+// @Tags documents
+// @Security Bearer
+// @Produce application/vnd.fdf
+// @Produce application/vnd.adobe.xfdf
+// @Param id path string true "ID do documento"
+// @Param format query string false "Formato do arquivo" Enums(xfdf, fdf) default(xfdf)
 // @Success 200 {file} binary
 // @Failure 400 {object} response.ErrorResponse
 // @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
 // @Failure 404 {object} response.ErrorResponse
-// @Router /api/v1/documents/{id}/preview/{page} [get]
-func (h *DocumentHandler) GeneratePreview(c echo.Context) error {
+// @Router /api/v1/documents/{id}/form/export [get]
+func (h *DocumentHandler) ExportFormData(c echo.Context) error {
 	// Usa DefaultUserID quando não há autenticação
 	userUUID := DefaultUserID
 
@@ -252,25 +610,1384 @@ func (h *DocumentHandler) GeneratePreview(c echo.Context) error {
 		return response.ErrorBadRequest(c, err, "ID de documento inválido")
 	}
 
-	pageNum, err := strconv.Atoi(c.Param("page"))
-	if err != nil || pageNum < 1 {
-		return response.ErrorBadRequest(c, err, "número de página inválido")
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "xfdf"
 	}
 
-	// Gera preview
-	preview, err := h.previewUseCase.GeneratePreview(c.Request().Context(), documentID, userUUID, pageNum)
+	// Exporta valores
+	data, filename, err := h.documentUseCase.ExportFormData(c.Request().Context(), documentID, userUUID, format)
 	if err != nil {
+		if err.Error() == "formato de dados de formulário inválido" {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
 		if err.Error() == "documento não encontrado" {
 			return response.ErrorNotFound(c, err, "documento não encontrado")
 		}
 		if err.Error() == "acesso negado" {
 			return response.ErrorForbidden(c, err, "acesso negado")
 		}
-		return response.ErrorInternalServer(c, err, "erro ao gerar preview")
+		return response.ErrorInternalServer(c, err, "erro ao exportar dados do formulário")
+	}
+
+	contentType := "application/vnd.adobe.xfdf"
+	if format == "fdf" {
+		contentType = "application/vnd.fdf"
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, contentType, data)
+}
+
+// ImportFormData importa valores de formulário para um documento
+// @Summary Importa valores de formulário
+// @Description Preenche o formulário do documento com os valores de um arquivo FDF ou XFDF (formato detectado pelo conteúdo), gerando uma nova versão
+// @Tags documents
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param file formData file true "Arquivo FDF ou XFDF"
+// @Success 200 {object} dto.ProcessDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/form/import [post]
+func (h *DocumentHandler) ImportFormData(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	// Obtém o arquivo do form
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "arquivo não fornecido")
+	}
+
+	// Valida tamanho do arquivo
+	if file.Size > h.maxUploadSize {
+		return response.Error(c, http.StatusRequestEntityTooLarge, nil, "arquivo muito grande")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "erro ao abrir arquivo")
+	}
+	defer src.Close()
+
+	fileData, err := io.ReadAll(io.LimitReader(src, h.maxUploadSize+1))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "erro ao ler arquivo")
+	}
+	if int64(len(fileData)) > h.maxUploadSize {
+		return response.Error(c, http.StatusRequestEntityTooLarge, nil, "arquivo muito grande")
+	}
+
+	// Importa valores
+	document, err := h.documentUseCase.ImportFormData(c.Request().Context(), documentID, userUUID, fileData)
+	if err != nil {
+		if err.Error() == "dados de formulário inválidos" {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		return response.ErrorInternalServer(c, err, "erro ao importar dados do formulário")
+	}
+
+	return response.SuccessOK(c, dto.ProcessDocumentResponse{
+		Document: *document,
+		Message:  "Dados de formulário importados com sucesso",
+	})
+}
+
+// SignDocument assina digitalmente um documento
+// @Summary Assina um documento (PAdES)
+// @Description Assina com o certificado PKCS#12 do usuário mantido no servidor (ou o da organização) em uma atualização incremental, gerando uma nova versão. Com width e height a assinatura é visível; com timestamp inclui carimbo do tempo RFC 3161 (padrão quando configurado). Edições posteriores invalidam a cobertura da assinatura
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.SignDocumentRequest true "Opções da assinatura"
+// @Success 200 {object} dto.ProcessDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/sign [post]
+func (h *DocumentHandler) SignDocument(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.SignDocumentRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Assina documento
+	document, err := h.documentUseCase.SignDocument(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		switch err.Error() {
+		case "documento não encontrado":
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		case "acesso negado":
+			return response.ErrorForbidden(c, err, "acesso negado")
+		case "assinatura digital não configurada",
+			"carimbo do tempo não configurado",
+			"certificado de assinatura não encontrado",
+			"senha do certificado inválida",
+			"certificado de assinatura inválido",
+			"certificado de assinatura fora do período de validade",
+			"certificado de assinatura não permite assinatura digital",
+			"documento criptografado não pode ser assinado":
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		if strings.HasPrefix(err.Error(), "página inválida") ||
+			strings.HasPrefix(err.Error(), "já existe um campo com o nome") ||
+			strings.HasPrefix(err.Error(), "o campo ") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao assinar documento")
+	}
+
+	return response.SuccessOK(c, dto.ProcessDocumentResponse{
+		Document: *document,
+		Message:  "Documento assinado com sucesso",
+	})
+}
+
+// VerifySignatures verifica as assinaturas digitais de um documento
+// @Summary Verifica as assinaturas de um documento
+// @Description Para cada assinatura informa integridade, cadeia de certificados até as raízes confiáveis, carimbo do tempo e se o documento foi alterado após a assinatura
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Success 200 {object} dto.SignatureVerificationResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/signatures [get]
+func (h *DocumentHandler) VerifySignatures(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	// Verifica assinaturas
+	result, err := h.documentUseCase.VerifySignatures(c.Request().Context(), documentID, userUUID)
+	if err != nil {
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		return response.ErrorInternalServer(c, err, "erro ao verificar assinaturas")
+	}
+
+	return response.SuccessOK(c, result)
+}
+
+// EncryptDocument protege um documento com senha
+// @Summary Protege um documento com senha
+// @Description Criptografa com AES-256 usando senhas de usuário e proprietário e permissões (imprimir, copiar, modificar, anotar), gerando uma nova versão. As senhas não são registradas na auditoria
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.EncryptDocumentRequest true "Senhas e permissões"
+// @Success 200 {object} dto.ProcessDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/encrypt [post]
+func (h *DocumentHandler) EncryptDocument(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.EncryptDocumentRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Protege documento
+	document, err := h.documentUseCase.EncryptDocument(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		switch err.Error() {
+		case "documento não encontrado":
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		case "acesso negado":
+			return response.ErrorForbidden(c, err, "acesso negado")
+		case "documento já está protegido por senha", "senha de proprietário é obrigatória":
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao proteger documento")
+	}
+
+	return response.SuccessOK(c, dto.ProcessDocumentResponse{
+		Document: *document,
+		Message:  "Documento protegido com sucesso",
+	})
+}
+
+// DecryptDocument remove a proteção por senha de um documento
+// @Summary Remove a proteção por senha
+// @Description Decifra o documento com a senha de usuário ou de proprietário, gerando uma nova versão sem proteção
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.DecryptDocumentRequest true "Senha do documento"
+// @Success 200 {object} dto.ProcessDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/decrypt [post]
+func (h *DocumentHandler) DecryptDocument(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.DecryptDocumentRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Remove proteção
+	document, err := h.documentUseCase.DecryptDocument(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		switch err.Error() {
+		case "documento não encontrado":
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		case "acesso negado":
+			return response.ErrorForbidden(c, err, "acesso negado")
+		case "documento não está protegido por senha", "senha do PDF inválida":
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao remover proteção do documento")
+	}
+
+	return response.SuccessOK(c, dto.ProcessDocumentResponse{
+		Document: *document,
+		Message:  "Proteção removida com sucesso",
+	})
+}
+
+// LinearizeDocument lineariza um documento
+// @Summary Lineariza um documento (visualização rápida na web)
+// @Description Reorganiza o PDF para que a primeira página seja exibida antes do download completo e as demais sejam carregadas por requisições de faixas de bytes (ver /download), gerando uma nova versão
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Success 200 {object} dto.ProcessDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/linearize [post]
+func (h *DocumentHandler) LinearizeDocument(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	// Lineariza documento
+	document, err := h.documentUseCase.LinearizeDocument(c.Request().Context(), documentID, userUUID)
+	if err != nil {
+		switch err.Error() {
+		case "documento não encontrado":
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		case "acesso negado":
+			return response.ErrorForbidden(c, err, "acesso negado")
+		case "documento protegido por senha; remova a proteção antes de linearizar":
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao linearizar documento")
+	}
+
+	return response.SuccessOK(c, dto.ProcessDocumentResponse{
+		Document: *document,
+		Message:  "Documento linearizado com sucesso",
+	})
+}
+
+// OptimizeDocument reduz o tamanho de um documento
+// @Summary Otimiza um documento
+// @Description Reamostra e recomprime imagens acima da resolução do perfil (screen, ebook ou print), unifica recursos duplicados, descarta fontes e objetos sem uso e comprime os fluxos em object streams. Fontes embutidas em uso não são reduzidas a subsets. Gera uma nova versão apenas quando o tamanho diminui e informa os tamanhos antes e depois
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.OptimizeDocumentRequest true "Perfil de otimização"
+// @Success 200 {object} dto.OptimizeDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/optimize [post]
+func (h *DocumentHandler) OptimizeDocument(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.OptimizeDocumentRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Otimiza documento
+	result, err := h.documentUseCase.OptimizeDocument(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		switch err.Error() {
+		case "documento não encontrado":
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		case "acesso negado":
+			return response.ErrorForbidden(c, err, "acesso negado")
+		case "documento protegido por senha; remova a proteção antes de otimizar":
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		if strings.HasPrefix(err.Error(), "perfil de otimização inválido") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao otimizar documento")
+	}
+
+	return response.SuccessOK(c, result)
+}
+
+// ConvertToPDFA converte um documento para PDF/A
+// @Summary Converte um documento para PDF/A
+// @Description Converte para PDF/A-1b, 2b ou 3b: remove a criptografia (com a senha informada), JavaScript, ações e anotações proibidas, arquivos anexos (exceto no 3b) e, no 1b, transparência e camadas; embute substitutas para as fontes simples não embutidas, adiciona um OutputIntent sRGB e grava o XMP com a identificação PDF/A. Gera uma nova versão apenas quando o resultado é conforme; caso contrário, lista as violações restantes
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.ConvertPDFARequest true "Nível PDF/A"
+// @Success 200 {object} dto.PDFAConversionResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/pdfa [post]
+func (h *DocumentHandler) ConvertToPDFA(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.ConvertPDFARequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Converte documento
+	result, err := h.documentUseCase.ConvertToPDFA(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		switch err.Error() {
+		case "documento não encontrado":
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		case "acesso negado":
+			return response.ErrorForbidden(c, err, "acesso negado")
+		case "PDF protegido por senha", "senha do PDF inválida":
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		if strings.HasPrefix(err.Error(), "nível PDF/A inválido") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao converter documento para PDF/A")
+	}
+
+	return response.SuccessOK(c, result)
+}
+
+// ValidatePDFA valida um documento contra um nível PDF/A
+// @Summary Valida a conformidade PDF/A
+// @Description Verifica a versão atual contra as regras do nível informado (ou do declarado no XMP; 2b quando ausente), listando cada violação com a cláusula da ISO 19005. O nível registrado no documento é atualizado conforme o resultado
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param conformance query string false "Nível PDF/A" Enums(1b, 2b, 3b)
+// @Success 200 {object} dto.PDFAValidationResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/pdfa/validate [get]
+func (h *DocumentHandler) ValidatePDFA(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	conformance := strings.ToLower(strings.TrimSpace(c.QueryParam("conformance")))
+	result, err := h.documentUseCase.ValidatePDFA(c.Request().Context(), documentID, userUUID, conformance)
+	if err != nil {
+		switch err.Error() {
+		case "documento não encontrado":
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		case "acesso negado":
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		if strings.HasPrefix(err.Error(), "nível PDF/A inválido") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao validar PDF/A")
+	}
+
+	return response.SuccessOK(c, result)
+}
+
+// ApplyHeaderFooter aplica cabeçalhos e rodapés a um documento
+// @Summary Aplica cabeçalhos e rodapés
+// @Description Desenha modelos nas posições esquerda, central e direita do cabeçalho e do rodapé com os marcadores {page}, {total}, {date}, {name} e {bates}, gerando uma nova versão
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.HeaderFooterRequest true "Modelos, páginas, fonte, margens e numeração Bates"
+// @Success 200 {object} dto.HeaderFooterResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/header-footer [post]
+func (h *DocumentHandler) ApplyHeaderFooter(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.HeaderFooterRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	sources := []dto.HeaderFooterSource{{DocumentID: documentID.String(), Name: req.Name}}
+	return h.applyHeaderFooter(c, userUUID, sources, req.HeaderFooterOptions)
+}
+
+// ApplyHeaderFooterBatch aplica cabeçalhos e rodapés a um conjunto de documentos
+// @Summary Aplica cabeçalhos e rodapés a um conjunto de documentos
+// @Description Aplica os mesmos modelos a cada documento, na ordem informada; a numeração Bates continua de um documento para o seguinte
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param request body dto.BatchHeaderFooterRequest true "Documentos, modelos, páginas, fonte, margens e numeração Bates"
+// @Success 200 {object} dto.HeaderFooterResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/header-footer [post]
+func (h *DocumentHandler) ApplyHeaderFooterBatch(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	var req dto.BatchHeaderFooterRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	return h.applyHeaderFooter(c, userUUID, req.Documents, req.HeaderFooterOptions)
+}
+
+// applyHeaderFooter executa a aplicação de cabeçalhos e rodapés e converte os erros em respostas HTTP
+func (h *DocumentHandler) applyHeaderFooter(c echo.Context, userUUID uuid.UUID, sources []dto.HeaderFooterSource, options dto.HeaderFooterOptions) error {
+	result, err := h.documentUseCase.ApplyHeaderFooter(c.Request().Context(), userUUID, sources, options)
+	if err != nil {
+		switch err.Error() {
+		case "documento não encontrado":
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		case "acesso negado":
+			return response.ErrorForbidden(c, err, "acesso negado")
+		case "informe ao menos um texto de cabeçalho ou rodapé",
+			"numeração Bates informada sem o marcador {bates} no modelo":
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		if strings.HasPrefix(err.Error(), "marcador desconhecido") ||
+			strings.HasPrefix(err.Error(), "documento repetido") ||
+			strings.HasPrefix(err.Error(), "página inválida") ||
+			strings.HasPrefix(err.Error(), "intervalo de páginas") ||
+			strings.HasPrefix(err.Error(), "número de página inválido") ||
+			strings.HasPrefix(err.Error(), "texto inválido") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao aplicar cabeçalhos e rodapés")
+	}
+
+	result.Message = "Cabeçalhos e rodapés aplicados com sucesso"
+	return response.SuccessOK(c, result)
+}
+
+// ListAnnotations lista as anotações de um documento
+// @Summary Lista as anotações de um documento
+// @Description Retorna as anotações (destaque, sublinhado, tachado, nota, texto livre e tinta) em PDF points (origem no topo esquerdo), de todas as páginas ou de uma página
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param page query int false "Número da página (vazio = todas)"
+// @Success 200 {object} dto.AnnotationListResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/annotations [get]
+func (h *DocumentHandler) ListAnnotations(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	page := 0
+	if pageStr := c.QueryParam("page"); pageStr != "" {
+		if page, err = strconv.Atoi(pageStr); err != nil || page < 1 {
+			return response.ErrorBadRequest(c, err, "número de página inválido")
+		}
+	}
+
+	// Lista anotações
+	annotations, err := h.documentUseCase.ListAnnotations(c.Request().Context(), documentID, userUUID, page)
+	if err != nil {
+		return annotationErrorResponse(c, err, "erro ao listar anotações")
+	}
+
+	return response.SuccessOK(c, annotations)
+}
+
+// CreateAnnotation cria uma anotação em um documento
+// @Summary Cria uma anotação
+// @Description Cria uma anotação na camada de anotações do documento; o PDF só é alterado em /annotations/apply
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.AnnotationRequest true "Dados da anotação"
+// @Success 201 {object} model.Annotation
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/annotations [post]
+func (h *DocumentHandler) CreateAnnotation(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.AnnotationRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Cria anotação
+	annotation, err := h.documentUseCase.CreateAnnotation(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		return annotationErrorResponse(c, err, "erro ao criar anotação")
+	}
+
+	return response.SuccessCreated(c, annotation, "Anotação criada com sucesso")
+}
+
+// UpdateAnnotation substitui uma anotação de um documento
+// @Summary Atualiza uma anotação
+// @Description Substitui os dados da anotação, mantendo o ID e a data de criação
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param annotationId path string true "ID da anotação"
+// @Param request body dto.AnnotationRequest true "Dados da anotação"
+// @Success 200 {object} model.Annotation
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/annotations/{annotationId} [put]
+func (h *DocumentHandler) UpdateAnnotation(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	annotationID, err := uuid.Parse(c.Param("annotationId"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de anotação inválido")
+	}
+
+	var req dto.AnnotationRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Atualiza anotação
+	annotation, err := h.documentUseCase.UpdateAnnotation(c.Request().Context(), documentID, annotationID, userUUID, req)
+	if err != nil {
+		return annotationErrorResponse(c, err, "erro ao atualizar anotação")
+	}
+
+	return response.SuccessOK(c, annotation)
+}
+
+// DeleteAnnotation remove uma anotação de um documento
+// @Summary Remove uma anotação
+// @Description Remove a anotação da camada de anotações do documento; o PDF só é alterado em /annotations/apply
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param annotationId path string true "ID da anotação"
+// @Success 200 {object} response.SuccessResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/annotations/{annotationId} [delete]
+func (h *DocumentHandler) DeleteAnnotation(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	annotationID, err := uuid.Parse(c.Param("annotationId"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de anotação inválido")
+	}
+
+	// Remove anotação
+	if err := h.documentUseCase.DeleteAnnotation(c.Request().Context(), documentID, annotationID, userUUID); err != nil {
+		return annotationErrorResponse(c, err, "erro ao remover anotação")
+	}
+
+	return response.SuccessOK(c, map[string]string{"message": "Anotação removida com sucesso"})
+}
+
+// ApplyAnnotations grava as anotações de um documento no PDF
+// @Summary Grava as anotações no PDF
+// @Description Grava as anotações do documento como anotações nativas do PDF (/Annot com aparições), visíveis e editáveis em outros leitores, gerando uma nova versão. As anotações desses tipos já existentes no PDF são substituídas
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Success 200 {object} dto.ProcessDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/annotations/apply [post]
+func (h *DocumentHandler) ApplyAnnotations(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	// Grava anotações
+	document, err := h.documentUseCase.ApplyAnnotations(c.Request().Context(), documentID, userUUID)
+	if err != nil {
+		return annotationErrorResponse(c, err, "erro ao gravar anotações")
+	}
+
+	return response.SuccessOK(c, dto.ProcessDocumentResponse{
+		Document: *document,
+		Message:  "Anotações gravadas com sucesso",
+	})
+}
+
+// ExportAnnotations exporta as anotações de um documento em XFDF
+// @Summary Exporta as anotações em XFDF
+// @Description Exporta as anotações do documento como arquivo XFDF, para revisão em leitores de PDF
+// @Tags documents
+// @Security Bearer
+// @Produce application/vnd.adobe.xfdf
+// @Param id path string true "ID do documento"
+// @Success 200 {file} binary
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/annotations/export [get]
+func (h *DocumentHandler) ExportAnnotations(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	// Exporta anotações
+	data, filename, err := h.documentUseCase.ExportAnnotations(c.Request().Context(), documentID, userUUID)
+	if err != nil {
+		return annotationErrorResponse(c, err, "erro ao exportar anotações")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, "application/vnd.adobe.xfdf", data)
+}
+
+// ImportAnnotations importa anotações de um arquivo XFDF
+// @Summary Importa anotações de XFDF
+// @Description Importa as anotações de um arquivo XFDF; anotações exportadas por este serviço (mesmo nome) são atualizadas e as demais são criadas
+// @Tags documents
+// @Security Bearer
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param file formData file true "Arquivo XFDF"
+// @Success 200 {object} dto.ImportAnnotationsResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 413 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/annotations/import [post]
+func (h *DocumentHandler) ImportAnnotations(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	// Obtém o arquivo do form
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "arquivo não fornecido")
+	}
+
+	// Valida tamanho do arquivo
+	if file.Size > h.maxUploadSize {
+		return response.Error(c, http.StatusRequestEntityTooLarge, nil, "arquivo muito grande")
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "erro ao abrir arquivo")
+	}
+	defer src.Close()
+
+	fileData, err := io.ReadAll(io.LimitReader(src, h.maxUploadSize+1))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "erro ao ler arquivo")
+	}
+	if int64(len(fileData)) > h.maxUploadSize {
+		return response.Error(c, http.StatusRequestEntityTooLarge, nil, "arquivo muito grande")
+	}
+
+	// Importa anotações
+	result, err := h.documentUseCase.ImportAnnotations(c.Request().Context(), documentID, userUUID, fileData)
+	if err != nil {
+		return annotationErrorResponse(c, err, "erro ao importar anotações")
+	}
+
+	result.Message = "Anotações importadas com sucesso"
+	return response.SuccessOK(c, result)
+}
+
+// annotationErrorResponse converte os erros das operações de anotações em respostas HTTP
+func annotationErrorResponse(c echo.Context, err error, message string) error {
+	switch err.Error() {
+	case "documento não encontrado", "anotação não encontrada":
+		return response.ErrorNotFound(c, err, err.Error())
+	case "acesso negado":
+		return response.ErrorForbidden(c, err, "acesso negado")
+	case "arquivo XFDF de anotações inválido":
+		return response.ErrorBadRequest(c, err, err.Error())
+	}
+	if strings.HasPrefix(err.Error(), "informe ") ||
+		strings.HasPrefix(err.Error(), "página inválida") ||
+		strings.HasPrefix(err.Error(), "texto livre contém") {
+		return response.ErrorBadRequest(c, err, err.Error())
+	}
+	return response.ErrorInternalServer(c, err, message)
+}
+
+// GetMetadata lê os metadados de um documento
+// @Summary Lê os metadados
+// @Description Retorna título, autor, assunto, palavras-chave, criador, produtor, datas e propriedades personalizadas do dicionário Info e do pacote XMP da versão atual, além do nome original do arquivo
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Success 200 {object} dto.MetadataResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/metadata [get]
+func (h *DocumentHandler) GetMetadata(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	// Lê metadados
+	metadata, err := h.documentUseCase.GetMetadata(c.Request().Context(), documentID, userUUID)
+	if err != nil {
+		return metadataErrorResponse(c, err, "erro ao ler metadados")
+	}
+
+	return response.SuccessOK(c, metadata)
+}
+
+// UpdateMetadata altera os metadados de um documento
+// @Summary Altera os metadados
+// @Description Altera título, autor, assunto, palavras-chave, criador e propriedades personalizadas no dicionário Info e no pacote XMP (mantidos sincronizados) em uma nova versão, e o nome original do documento. Os metadados ficam pesquisáveis na listagem de documentos
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.UpdateMetadataRequest true "Metadados a alterar"
+// @Success 200 {object} dto.ProcessDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/metadata [patch]
+func (h *DocumentHandler) UpdateMetadata(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.UpdateMetadataRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Altera metadados
+	document, err := h.documentUseCase.UpdateMetadata(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		return metadataErrorResponse(c, err, "erro ao alterar metadados")
+	}
+
+	return response.SuccessOK(c, dto.ProcessDocumentResponse{
+		Document: *document,
+		Message:  "Metadados alterados com sucesso",
+	})
+}
+
+// metadataErrorResponse converte os erros das operações de metadados em respostas HTTP
+func metadataErrorResponse(c echo.Context, err error, message string) error {
+	switch err.Error() {
+	case "documento não encontrado":
+		return response.ErrorNotFound(c, err, err.Error())
+	case "acesso negado":
+		return response.ErrorForbidden(c, err, "acesso negado")
+	}
+	if strings.HasPrefix(err.Error(), "informe ") ||
+		strings.HasPrefix(err.Error(), "nome de propriedade inválido") ||
+		strings.HasPrefix(err.Error(), "documento criptografado") {
+		return response.ErrorBadRequest(c, err, err.Error())
+	}
+	return response.ErrorInternalServer(c, err, message)
+}
+
+// ListBookmarks lista os marcadores de um documento
+// @Summary Lista os marcadores
+// @Description Retorna a árvore de marcadores (outline) da versão atual, com título, página, visualização e zoom de destino, estado aberto/fechado e o caminho de cada item (ex.: "2.1"), usado nas edições
+// @Tags documents
+// @Security Bearer
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Success 200 {object} dto.BookmarksResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/bookmarks [get]
+func (h *DocumentHandler) ListBookmarks(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	// Lista marcadores
+	bookmarks, err := h.documentUseCase.ListBookmarks(c.Request().Context(), documentID, userUUID)
+	if err != nil {
+		return bookmarkErrorResponse(c, err, "erro ao listar marcadores")
+	}
+
+	return response.SuccessOK(c, bookmarks)
+}
+
+// ReplaceBookmarks substitui os marcadores de um documento
+// @Summary Substitui os marcadores
+// @Description Substitui toda a árvore de marcadores do PDF, gerando uma nova versão. Lista vazia remove os marcadores
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.ReplaceBookmarksRequest true "Nova árvore de marcadores"
+// @Success 200 {object} dto.ProcessDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/bookmarks [put]
+func (h *DocumentHandler) ReplaceBookmarks(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.ReplaceBookmarksRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Substitui marcadores
+	document, err := h.documentUseCase.ReplaceBookmarks(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		return bookmarkErrorResponse(c, err, "erro ao substituir marcadores")
+	}
+
+	return response.SuccessOK(c, dto.ProcessDocumentResponse{
+		Document: *document,
+		Message:  "Marcadores substituídos com sucesso",
+	})
+}
+
+// EditBookmarks edita os marcadores de um documento
+// @Summary Edita os marcadores
+// @Description Aplica, em ordem, operações de adicionar, renomear, mover, remover, abrir/fechar e alterar o destino de marcadores, gerando uma única nova versão. Uma operação inválida cancela todas
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.EditBookmarksRequest true "Operações de edição"
+// @Success 200 {object} dto.ProcessDocumentResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/bookmarks [patch]
+func (h *DocumentHandler) EditBookmarks(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.EditBookmarksRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Edita marcadores
+	document, err := h.documentUseCase.EditBookmarks(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		return bookmarkErrorResponse(c, err, "erro ao editar marcadores")
+	}
+
+	return response.SuccessOK(c, dto.ProcessDocumentResponse{
+		Document: *document,
+		Message:  "Marcadores editados com sucesso",
+	})
+}
+
+// GenerateBookmarks gera marcadores a partir dos títulos de um documento
+// @Summary Gera marcadores a partir dos títulos
+// @Description Detecta títulos pelo tamanho da fonte (linhas curtas maiores que o corpo do texto, um nível por tamanho) e substitui os marcadores por eles, gerando uma nova versão. Com preview apenas retorna os marcadores propostos
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do documento"
+// @Param request body dto.GenerateBookmarksRequest false "Opções da detecção"
+// @Success 200 {object} dto.GenerateBookmarksResponse
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/bookmarks/generate [post]
+func (h *DocumentHandler) GenerateBookmarks(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.GenerateBookmarksRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Gera marcadores
+	result, err := h.documentUseCase.GenerateBookmarks(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		return bookmarkErrorResponse(c, err, "erro ao gerar marcadores")
+	}
+
+	result.Message = "Marcadores gerados com sucesso"
+	if req.Preview {
+		result.Message = "Prévia dos marcadores gerada com sucesso"
+	}
+
+	return response.SuccessOK(c, result)
+}
+
+// bookmarkErrorResponse converte os erros das operações de marcadores em respostas HTTP
+func bookmarkErrorResponse(c echo.Context, err error, message string) error {
+	switch err.Error() {
+	case "documento não encontrado":
+		return response.ErrorNotFound(c, err, err.Error())
+	case "acesso negado":
+		return response.ErrorForbidden(c, err, "acesso negado")
+	case "nenhum título encontrado no texto do documento":
+		return response.ErrorBadRequest(c, err, err.Error())
+	}
+	if strings.HasPrefix(err.Error(), "informe ") ||
+		strings.HasPrefix(err.Error(), "página inválida") ||
+		strings.HasPrefix(err.Error(), "operação ") {
+		return response.ErrorBadRequest(c, err, err.Error())
+	}
+	return response.ErrorInternalServer(c, err, message)
+}
+
+// ExportImages exporta páginas de um documento como imagens
+// @Summary Exporta páginas como imagens
+// @Description Renderiza as páginas selecionadas em PNG, JPEG (com qualidade) ou TIFF na resolução (dpi) ou largura (width) escolhida, opcionalmente em tons de cinza e com fundo transparente (PNG e TIFF). Uma página retorna a imagem; várias páginas retornam um ZIP com uma imagem por página, ou um único TIFF multipágina com multiPage
+// @Tags documents
+// @Security Bearer
+// @Accept json
+// @Produce image/png
+// @Produce image/jpeg
+// @Produce image/tiff
+// @Produce application/zip
+// @Param id path string true "ID do documento"
+// @Param request body dto.ExportImagesRequest true "Páginas, formato e resolução"
+// @Success 200 {file} binary
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/export/images [post]
+func (h *DocumentHandler) ExportImages(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	var req dto.ExportImagesRequest
+	if err := c.Bind(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "dados inválidos")
+	}
+
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Exporta imagens
+	data, filename, contentType, err := h.documentUseCase.ExportImages(c.Request().Context(), documentID, userUUID, req)
+	if err != nil {
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		if strings.HasPrefix(err.Error(), "exportação inválida") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao exportar imagens")
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Blob(http.StatusOK, contentType, data)
+}
+
+// GeneratePreview gera preview de uma página do documento
+// @Summary Gera preview de uma página
+// @Description Retorna uma imagem (preview) de uma página específica do PDF em PNG, JPEG ou WebP (sem perdas), com resolução (dpi, até 1200) ou largura (width, até 4096 pixels); clip renderiza apenas uma região (x,y,largura,altura em PDF points, origem no topo esquerdo da página exibida) e thumbnail gera uma miniatura de até size pixels. Imagens acima de 10000 pixels por lado ou 36 milhões de pixels são recusadas
+// @Tags documents
+// @Security Bearer
+// @Produce image/png
+// @Produce image/jpeg
+// @Produce image/webp
+// @Param id path string true "ID do documento"
+// @Param page path int true "Número da página"
+// @Param dpi query number false "Resolução (18 a 1200; padrão 150)"
+// @Param width query int false "Largura em pixels (16 a 4096), no lugar de dpi"
+// @Param format query string false "Formato da imagem" Enums(png, jpeg, webp) default(png)
+// @Param quality query int false "Qualidade JPEG (1 a 100)" default(85)
+// @Param clip query string false "Região x,y,largura,altura em PDF points (ex.: 72,100,200,150)"
+// @Param thumbnail query bool false "Gera uma miniatura (formato padrão jpeg)"
+// @Param size query int false "Lado do quadrado da miniatura em pixels (32 a 512)" default(200)
+// @Success 200 {file} binary
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/preview/{page} [get]
+func (h *DocumentHandler) GeneratePreview(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	pageNum, err := strconv.Atoi(c.Param("page"))
+	if err != nil || pageNum < 1 {
+		return response.ErrorBadRequest(c, err, "número de página inválido")
+	}
+
+	req := dto.PreviewRequest{
+		Format: strings.ToLower(c.QueryParam("format")),
+		Clip:   c.QueryParam("clip"),
+	}
+	if thumbnailStr := c.QueryParam("thumbnail"); thumbnailStr != "" {
+		if req.Thumbnail, err = strconv.ParseBool(thumbnailStr); err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro thumbnail inválido")
+		}
+	}
+	if req.Format == "" {
+		req.Format = string(model.ImageFormatPNG)
+		if req.Thumbnail {
+			req.Format = string(model.ImageFormatJPEG)
+		}
+	}
+	if dpiStr := c.QueryParam("dpi"); dpiStr != "" {
+		if req.DPI, err = strconv.ParseFloat(dpiStr, 64); err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro dpi inválido")
+		}
+	}
+	if widthStr := c.QueryParam("width"); widthStr != "" {
+		if req.Width, err = strconv.Atoi(widthStr); err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro width inválido")
+		}
+	}
+	if qualityStr := c.QueryParam("quality"); qualityStr != "" {
+		if req.Quality, err = strconv.Atoi(qualityStr); err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro quality inválido")
+		}
+	}
+	if sizeStr := c.QueryParam("size"); sizeStr != "" {
+		if req.Size, err = strconv.Atoi(sizeStr); err != nil {
+			return response.ErrorBadRequest(c, err, "parâmetro size inválido")
+		}
+	}
+	if err := c.Validate(&req); err != nil {
+		return response.ErrorBadRequest(c, err, "validação falhou")
+	}
+
+	// Gera preview
+	preview, contentType, err := h.previewUseCase.GeneratePreview(c.Request().Context(), documentID, userUUID, pageNum, req)
+	if err != nil {
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		if strings.HasPrefix(err.Error(), "preview inválida") || strings.HasPrefix(err.Error(), "página inválida") {
+			return response.ErrorBadRequest(c, err, err.Error())
+		}
+		return response.ErrorInternalServer(c, err, "erro ao gerar preview")
+	}
+
+	return c.Blob(http.StatusOK, contentType, preview)
+}
+
+// DownloadDocument envia o PDF da versão atual do documento
+// @Summary Baixa o PDF de um documento
+// @Description Envia o arquivo da versão atual com suporte a requisições de faixas de bytes (Range, If-Range) e ETag pelo checksum, permitindo que visualizadores exibam documentos linearizados antes do download completo
+// @Tags documents
+// @Security Bearer
+// @Produce application/pdf
+// @Param id path string true "ID do documento"
+// @Param Range header string false "Faixa de bytes (ex.: bytes=0-65535)"
+// @Success 200 {file} binary
+// @Success 206 {file} binary
+// @Failure 400 {object} response.ErrorResponse
+// @Failure 401 {object} response.ErrorResponse
+// @Failure 403 {object} response.ErrorResponse
+// @Failure 404 {object} response.ErrorResponse
+// @Failure 416 {object} response.ErrorResponse
+// @Router /api/v1/documents/{id}/download [get]
+func (h *DocumentHandler) DownloadDocument(c echo.Context) error {
+	// Usa DefaultUserID quando não há autenticação
+	userUUID := DefaultUserID
+
+	documentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.ErrorBadRequest(c, err, "ID de documento inválido")
+	}
+
+	file, document, err := h.documentUseCase.OpenDocumentFile(c.Request().Context(), documentID, userUUID)
+	if err != nil {
+		if err.Error() == "documento não encontrado" {
+			return response.ErrorNotFound(c, err, "documento não encontrado")
+		}
+		if err.Error() == "acesso negado" {
+			return response.ErrorForbidden(c, err, "acesso negado")
+		}
+		return response.ErrorInternalServer(c, err, "erro ao baixar documento")
+	}
+	defer file.Close()
+
+	filename := document.OriginalFilename
+	if filename == "" {
+		filename = documentID.String() + ".pdf"
 	}
 
-	c.Response().Header().Set("Content-Type", "image/png")
-	return c.Blob(http.StatusOK, "image/png", preview)
+	// http.ServeContent atende Range/If-Range e responde 206 ou 416; o ETag muda a cada versão
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "application/pdf")
+	// FormatMediaType escapa aspas e codifica nomes fora do ASCII como filename* (RFC 2231)
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	header.Set("ETag", fmt.Sprintf("%q", document.Checksum))
+	header.Set("Cache-Control", "private, no-cache")
+	http.ServeContent(c.Response(), c.Request(), filename, document.UpdatedAt, file)
+	return nil
 }
 
 // DeleteDocument remove um documento
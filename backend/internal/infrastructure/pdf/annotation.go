@@ -0,0 +1,738 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// annotationSubtypes mapeia os tipos do modelo para os subtipos de anotação do PDF
+var annotationSubtypes = map[appModel.AnnotationType]string{
+	appModel.AnnotationHighlight: "Highlight",
+	appModel.AnnotationUnderline: "Underline",
+	appModel.AnnotationStrikeOut: "StrikeOut",
+	appModel.AnnotationNote:      "Text",
+	appModel.AnnotationFreeText:  "FreeText",
+	appModel.AnnotationInk:       "Ink",
+}
+
+// annotationTypes mapeia os subtipos do PDF de volta para os tipos do modelo
+var annotationTypes = func() map[string]appModel.AnnotationType {
+	bySubtype := make(map[string]appModel.AnnotationType, len(annotationSubtypes))
+	for annotationType, subtype := range annotationSubtypes {
+		bySubtype[subtype] = annotationType
+	}
+	return bySubtype
+}()
+
+// Aparência padrão das anotações gravadas
+const (
+	annotationFlagNoZoom      = 1 << 3
+	annotationFlagNoRotate    = 1 << 4
+	annotationFontResName     = "Helv"
+	annotationFontBaseFont    = "Helvetica"
+	annotationTextPadding     = 2.0
+	annotationLineHeight      = 1.15
+	defaultAnnotationFontSize = 12.0
+	defaultAnnotationInkWidth = 1.0
+)
+
+// Operadores de cor e fonte da aparência padrão (DA) das anotações de texto livre
+var (
+	daFontSizePattern = regexp.MustCompile(`([\d.]+)\s+Tf`)
+	daRGBPattern      = regexp.MustCompile(`([\d.]+)\s+([\d.]+)\s+([\d.]+)\s+rg`)
+	daGrayPattern     = regexp.MustCompile(`(?:^|\s)([\d.]+)\s+g(?:\s|$)`)
+)
+
+// pageSpace converte coordenadas com origem no topo esquerdo para o espaço do PDF de uma página
+type pageSpace struct {
+	llx, ury float64
+}
+
+// newPageSpace cria a conversão a partir da MediaBox da página
+func newPageSpace(mediaBox *types.Rectangle) pageSpace {
+	return pageSpace{llx: mediaBox.LL.X, ury: mediaBox.UR.Y}
+}
+
+// toPDF converte um ponto com origem no topo esquerdo para o espaço do PDF
+func (s pageSpace) toPDF(x, y float64) (float64, float64) {
+	return s.llx + x, s.ury - y
+}
+
+// fromPDF converte um ponto do espaço do PDF para a origem no topo esquerdo
+func (s pageSpace) fromPDF(x, y float64) (float64, float64) {
+	return x - s.llx, s.ury - y
+}
+
+// rectToPDF converte o retângulo da anotação para o espaço do PDF
+func (s pageSpace) rectToPDF(annotation appModel.Annotation) bbox {
+	llx, ury := s.toPDF(annotation.X, annotation.Y)
+	return bbox{llx: llx, lly: ury - annotation.Height, urx: llx + annotation.Width, ury: ury}
+}
+
+// setRectFromPDF define o retângulo da anotação a partir de um retângulo do PDF
+func (s pageSpace) setRectFromPDF(annotation *appModel.Annotation, rect bbox) {
+	annotation.X, annotation.Y = s.fromPDF(rect.llx, rect.ury)
+	annotation.Width = rect.urx - rect.llx
+	annotation.Height = rect.ury - rect.lly
+}
+
+// quadPointsToPDF converte os quadriláteros para QuadPoints (superior esquerdo, superior direito,
+// inferior esquerdo e inferior direito, a ordem usada pelos leitores de PDF)
+func (s pageSpace) quadPointsToPDF(quads []appModel.Quad) []float64 {
+	points := make([]float64, 0, len(quads)*8)
+	for _, q := range quads {
+		for _, corner := range []appModel.Point{q[0], q[1], q[3], q[2]} {
+			x, y := s.toPDF(corner.X, corner.Y)
+			points = append(points, x, y)
+		}
+	}
+	return points
+}
+
+// quadsFromPDF converte QuadPoints para quadriláteros com origem no topo esquerdo
+func (s pageSpace) quadsFromPDF(points []float64) []appModel.Quad {
+	var quads []appModel.Quad
+	for i := 0; i+8 <= len(points); i += 8 {
+		var corners [4]appModel.Point
+		for j := range corners {
+			x, y := s.fromPDF(points[i+2*j], points[i+2*j+1])
+			corners[j] = appModel.Point{X: x, Y: y}
+		}
+		quads = append(quads, appModel.Quad{corners[0], corners[1], corners[3], corners[2]})
+	}
+	return quads
+}
+
+// inkToPDF converte os traços para listas de coordenadas do PDF
+func (s pageSpace) inkToPDF(strokes appModel.InkStrokes) [][]float64 {
+	paths := make([][]float64, 0, len(strokes))
+	for _, stroke := range strokes {
+		path := make([]float64, 0, len(stroke)*2)
+		for _, point := range stroke {
+			x, y := s.toPDF(point.X, point.Y)
+			path = append(path, x, y)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// inkFromPDF converte listas de coordenadas do PDF para traços com origem no topo esquerdo
+func (s pageSpace) inkFromPDF(paths [][]float64) appModel.InkStrokes {
+	strokes := make(appModel.InkStrokes, 0, len(paths))
+	for _, path := range paths {
+		stroke := make([]appModel.Point, 0, len(path)/2)
+		for i := 0; i+2 <= len(path); i += 2 {
+			x, y := s.fromPDF(path[i], path[i+1])
+			stroke = append(stroke, appModel.Point{X: x, Y: y})
+		}
+		strokes = append(strokes, stroke)
+	}
+	return strokes
+}
+
+// ExtractAnnotations lê as anotações suportadas (destaque, sublinhado, tachado, nota, texto livre e tinta)
+// de todas as páginas; os demais tipos (links, widgets, carimbos etc.) são ignorados
+func (p *PDFCPUProcessor) ExtractAnnotations(ctx context.Context, filePath string) ([]appModel.Annotation, error) {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	var annotations []appModel.Annotation
+	for pageNum := 1; pageNum <= pdfCtx.PageCount; pageNum++ {
+		pageDict, _, inherited, err := pdfCtx.PageDict(pageNum, false)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler página %d: %w", pageNum, err)
+		}
+		if inherited == nil || inherited.MediaBox == nil {
+			continue
+		}
+		space := newPageSpace(inherited.MediaBox)
+
+		annots, err := pdfCtx.DereferenceArray(pageDict["Annots"])
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler anotações da página %d: %w", pageNum, err)
+		}
+
+		for _, obj := range annots {
+			annotDict, err := pdfCtx.DereferenceDict(obj)
+			if err != nil || annotDict == nil {
+				continue
+			}
+			annotation, ok := readAnnotation(pdfCtx, annotDict, space)
+			if !ok {
+				continue
+			}
+			annotation.Page = pageNum
+			annotations = append(annotations, annotation)
+		}
+	}
+
+	logger.Logger.Debug("Anotações extraídas",
+		zap.String("file", filePath),
+		zap.Int("annotations_count", len(annotations)),
+	)
+
+	return annotations, nil
+}
+
+// readAnnotation converte um dicionário de anotação de tipo suportado para o modelo
+// O ID vem da entrada NM quando ela é um UUID (anotações gravadas por WriteAnnotations)
+func readAnnotation(pdfCtx *pdfcpuModel.Context, annotDict types.Dict, space pageSpace) (appModel.Annotation, bool) {
+	subtype := annotDict.Subtype()
+	if subtype == nil {
+		return appModel.Annotation{}, false
+	}
+	annotationType, ok := annotationTypes[*subtype]
+	if !ok {
+		return appModel.Annotation{}, false
+	}
+
+	rect, ok := dictRect(pdfCtx, annotDict, "Rect")
+	if !ok {
+		return appModel.Annotation{}, false
+	}
+
+	annotation := appModel.Annotation{
+		Type:     annotationType,
+		Author:   dictText(pdfCtx, annotDict, "T"),
+		Contents: dictText(pdfCtx, annotDict, "Contents"),
+		Opacity:  1,
+	}
+	space.setRectFromPDF(&annotation, rect)
+
+	if id, err := uuid.Parse(dictText(pdfCtx, annotDict, "NM")); err == nil {
+		annotation.ID = id
+	}
+	if created, ok := types.DateTime(dictText(pdfCtx, annotDict, "CreationDate"), true); ok {
+		annotation.CreatedAt = created
+	}
+	if modified, ok := types.DateTime(dictText(pdfCtx, annotDict, "M"), true); ok {
+		annotation.UpdatedAt = modified
+	}
+	if annotDict["CA"] != nil {
+		if opacity, err := pdfCtx.DereferenceNumber(annotDict["CA"]); err == nil {
+			annotation.Opacity = opacity
+		}
+	}
+
+	if color, ok := dictColor(pdfCtx, annotDict["C"]); ok {
+		annotation.Color = color.hex()
+	}
+
+	switch annotationType {
+	case appModel.AnnotationHighlight, appModel.AnnotationUnderline, appModel.AnnotationStrikeOut:
+		annotation.Quads = space.quadsFromPDF(dictNumbers(pdfCtx, annotDict["QuadPoints"]))
+		if len(annotation.Quads) == 0 {
+			// Sem QuadPoints, o retângulo inteiro é o trecho marcado
+			annotation.Quads = appModel.AnnotationQuads{rectQuad(annotation)}
+		}
+
+	case appModel.AnnotationInk:
+		inkList, _ := pdfCtx.DereferenceArray(annotDict["InkList"])
+		paths := make([][]float64, 0, len(inkList))
+		for _, path := range inkList {
+			paths = append(paths, dictNumbers(pdfCtx, path))
+		}
+		annotation.InkList = space.inkFromPDF(paths)
+		annotation.LineWidth = borderWidth(pdfCtx, annotDict)
+
+	case appModel.AnnotationFreeText:
+		// A cor do texto e o tamanho da fonte ficam na aparência padrão (DA); C é a cor de fundo
+		annotation.Color = ""
+		fontSize, color := parseDefaultAppearance(dictText(pdfCtx, annotDict, "DA"))
+		annotation.FontSize = fontSize
+		if color != nil {
+			annotation.Color = color.hex()
+		}
+	}
+
+	return annotation, true
+}
+
+// WriteAnnotations grava as anotações como objetos /Annot padrão, com aparições para leitores que não as geram
+// As anotações suportadas existentes no PDF (e seus popups) são substituídas: a lista informada passa a ser
+// a camada de anotações do documento; links, widgets e demais tipos são mantidos
+func (p *PDFCPUProcessor) WriteAnnotations(ctx context.Context, filePath string, annotations []appModel.Annotation) error {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	for _, annotation := range annotations {
+		if annotation.Page < 1 || annotation.Page > pdfCtx.PageCount {
+			return fmt.Errorf("página inválida: %d (PDF tem %d páginas)", annotation.Page, pdfCtx.PageCount)
+		}
+	}
+
+	writer := &annotationWriter{ctx: pdfCtx}
+	removed := 0
+	for pageNum := 1; pageNum <= pdfCtx.PageCount; pageNum++ {
+		pageDict, pageRef, inherited, err := pdfCtx.PageDict(pageNum, false)
+		if err != nil {
+			return fmt.Errorf("erro ao ler página %d: %w", pageNum, err)
+		}
+
+		annots, err := pdfCtx.DereferenceArray(pageDict["Annots"])
+		if err != nil {
+			return fmt.Errorf("erro ao ler anotações da página %d: %w", pageNum, err)
+		}
+		kept := keepUnmanagedAnnotations(pdfCtx, annots)
+		removed += len(annots) - len(kept)
+
+		for _, annotation := range annotations {
+			if annotation.Page != pageNum {
+				continue
+			}
+			if inherited == nil || inherited.MediaBox == nil {
+				return fmt.Errorf("página %d não possui MediaBox", pageNum)
+			}
+
+			ref, err := writer.add(annotation, newPageSpace(inherited.MediaBox), *pageRef)
+			if err != nil {
+				return err
+			}
+			kept = append(kept, ref)
+		}
+
+		if len(kept) == 0 {
+			pageDict.Delete("Annots")
+		} else {
+			pageDict.Update("Annots", kept)
+		}
+	}
+
+	if err := rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		return api.WriteContext(pdfCtx, out)
+	}); err != nil {
+		return fmt.Errorf("erro ao salvar PDF: %w", err)
+	}
+
+	logger.Logger.Debug("Anotações gravadas",
+		zap.String("file", filePath),
+		zap.Int("annotations_count", len(annotations)),
+		zap.Int("removed_count", removed),
+	)
+
+	return nil
+}
+
+// keepUnmanagedAnnotations remove da lista as anotações de tipos suportados e os popups associados a elas
+func keepUnmanagedAnnotations(pdfCtx *pdfcpuModel.Context, annots types.Array) types.Array {
+	managed := make(map[int]bool)
+	for _, obj := range annots {
+		annotDict, err := pdfCtx.DereferenceDict(obj)
+		if err != nil || annotDict == nil {
+			continue
+		}
+		subtype := annotDict.Subtype()
+		if subtype == nil {
+			continue
+		}
+		if _, ok := annotationTypes[*subtype]; !ok {
+			continue
+		}
+		if ref, ok := obj.(types.IndirectRef); ok {
+			managed[ref.ObjectNumber.Value()] = true
+		}
+		if popup, ok := annotDict["Popup"].(types.IndirectRef); ok {
+			managed[popup.ObjectNumber.Value()] = true
+		}
+	}
+
+	kept := types.Array{}
+	for _, obj := range annots {
+		if ref, ok := obj.(types.IndirectRef); ok && managed[ref.ObjectNumber.Value()] {
+			continue
+		}
+
+		annotDict, err := pdfCtx.DereferenceDict(obj)
+		if err == nil && annotDict != nil {
+			if subtype := annotDict.Subtype(); subtype != nil {
+				if _, ok := annotationTypes[*subtype]; ok {
+					continue
+				}
+			}
+			if parent, ok := annotDict["Parent"].(types.IndirectRef); ok && managed[parent.ObjectNumber.Value()] {
+				continue
+			}
+		}
+		kept = append(kept, obj)
+	}
+	return kept
+}
+
+// annotationWriter cria os dicionários e as aparições das anotações
+type annotationWriter struct {
+	ctx     *pdfcpuModel.Context
+	fontRef *types.IndirectRef // Helvetica compartilhada pelas aparições de texto livre
+}
+
+// add cria o dicionário da anotação na página e retorna sua referência
+func (w *annotationWriter) add(annotation appModel.Annotation, space pageSpace, pageRef types.IndirectRef) (types.IndirectRef, error) {
+	subtype, ok := annotationSubtypes[annotation.Type]
+	if !ok {
+		return types.IndirectRef{}, fmt.Errorf("tipo de anotação inválido: %s", annotation.Type)
+	}
+
+	color := rgbColor{R: 1, G: 1}
+	if annotation.Color != "" {
+		var err error
+		if color, err = parseHexColor(annotation.Color); err != nil {
+			return types.IndirectRef{}, err
+		}
+	}
+
+	opacity := annotation.Opacity
+	if opacity <= 0 || opacity > 1 {
+		opacity = 1
+	}
+
+	created, modified := annotation.CreatedAt, annotation.UpdatedAt
+	if created.IsZero() {
+		created = time.Now()
+	}
+	if modified.IsZero() {
+		modified = created
+	}
+
+	rect := space.rectToPDF(annotation)
+	annotDict := types.Dict{
+		"Type":         types.Name("Annot"),
+		"Subtype":      types.Name(subtype),
+		"Rect":         types.NewNumberArray(rect.llx, rect.lly, rect.urx, rect.ury),
+		"P":            pageRef,
+		"F":            types.Integer(annotationFlagPrint),
+		"CreationDate": types.StringLiteral(types.DateString(created)),
+		"M":            types.StringLiteral(types.DateString(modified)),
+		"CA":           types.Float(opacity),
+	}
+	if annotation.ID != uuid.Nil {
+		annotDict["NM"] = types.StringLiteral(annotation.ID.String())
+	}
+	if annotation.Author != "" {
+		annotDict["T"] = pdfTextString(annotation.Author)
+	}
+	if annotation.Contents != "" {
+		annotDict["Contents"] = pdfTextString(annotation.Contents)
+	}
+
+	var content string
+	resources := types.Dict{}
+	graphicsState := types.Dict{"Type": types.Name("ExtGState"), "CA": types.Float(opacity), "ca": types.Float(opacity)}
+	rgb := fmt.Sprintf("%s %s %s", formatNumber(color.R), formatNumber(color.G), formatNumber(color.B))
+
+	switch annotation.Type {
+	case appModel.AnnotationHighlight, appModel.AnnotationUnderline, appModel.AnnotationStrikeOut:
+		if len(annotation.Quads) == 0 {
+			return types.IndirectRef{}, fmt.Errorf("anotação %s sem trechos marcados", annotation.Type)
+		}
+		quadPoints := space.quadPointsToPDF(annotation.Quads)
+		annotDict["QuadPoints"] = types.NewNumberArray(quadPoints...)
+		annotDict["C"] = types.NewNumberArray(color.R, color.G, color.B)
+		if annotation.Type == appModel.AnnotationHighlight {
+			graphicsState["BM"] = types.Name("Multiply")
+		}
+		content = textMarkupAppearance(annotation.Type, quadPoints, rgb)
+
+	case appModel.AnnotationNote:
+		annotDict["Name"] = types.Name("Comment")
+		annotDict["Open"] = types.Boolean(false)
+		annotDict["C"] = types.NewNumberArray(color.R, color.G, color.B)
+		annotDict["F"] = types.Integer(annotationFlagPrint | annotationFlagNoZoom | annotationFlagNoRotate)
+		content = noteAppearance(rect, rgb)
+
+	case appModel.AnnotationFreeText:
+		fontSize := annotation.FontSize
+		if fontSize <= 0 {
+			fontSize = defaultAnnotationFontSize
+		}
+		color = rgbColor{}
+		if annotation.Color != "" {
+			color, _ = parseHexColor(annotation.Color)
+		}
+		rgb = fmt.Sprintf("%s %s %s", formatNumber(color.R), formatNumber(color.G), formatNumber(color.B))
+		annotDict["DA"] = types.StringLiteral(fmt.Sprintf("/%s %s Tf %s rg", annotationFontResName, formatNumber(fontSize), rgb))
+		annotDict["BS"] = types.Dict{"W": types.Integer(0)}
+
+		fontRef, err := w.font()
+		if err != nil {
+			return types.IndirectRef{}, err
+		}
+		resources["Font"] = types.Dict{annotationFontResName: *fontRef}
+
+		if content, err = freeTextAppearance(annotation.Contents, rect, fontSize, rgb); err != nil {
+			return types.IndirectRef{}, err
+		}
+
+	case appModel.AnnotationInk:
+		if len(annotation.InkList) == 0 {
+			return types.IndirectRef{}, fmt.Errorf("anotação ink sem traços")
+		}
+		lineWidth := annotation.LineWidth
+		if lineWidth <= 0 {
+			lineWidth = defaultAnnotationInkWidth
+		}
+		paths := space.inkToPDF(annotation.InkList)
+		inkList := make(types.Array, 0, len(paths))
+		for _, path := range paths {
+			inkList = append(inkList, types.NewNumberArray(path...))
+		}
+		annotDict["InkList"] = inkList
+		annotDict["BS"] = types.Dict{"W": types.Float(lineWidth)}
+		annotDict["C"] = types.NewNumberArray(color.R, color.G, color.B)
+		content = inkAppearance(paths, lineWidth, rgb)
+	}
+
+	resources["ExtGState"] = types.Dict{"GS0": graphicsState}
+	appearance, err := w.appearance(content, rect, resources)
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+	annotDict["AP"] = types.Dict{"N": appearance}
+
+	ref, err := w.ctx.IndRefForNewObject(annotDict)
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+	return *ref, nil
+}
+
+// appearance cria o form XObject de aparição; a BBox coincide com o retângulo da anotação no espaço
+// da página, de modo que o conteúdo usa as coordenadas da página diretamente
+func (w *annotationWriter) appearance(content string, rect bbox, resources types.Dict) (types.IndirectRef, error) {
+	streamDict, err := w.ctx.NewStreamDictForBuf([]byte(content))
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+
+	streamDict.Insert("Type", types.Name("XObject"))
+	streamDict.Insert("Subtype", types.Name("Form"))
+	streamDict.Insert("BBox", types.NewNumberArray(rect.llx, rect.lly, rect.urx, rect.ury))
+	streamDict.Insert("Resources", resources)
+
+	if err := streamDict.Encode(); err != nil {
+		return types.IndirectRef{}, err
+	}
+
+	ref, err := w.ctx.IndRefForNewObject(*streamDict)
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+	return *ref, nil
+}
+
+// font retorna a Helvetica (WinAnsiEncoding) das aparições de texto livre, criando-a na primeira chamada
+func (w *annotationWriter) font() (*types.IndirectRef, error) {
+	if w.fontRef != nil {
+		return w.fontRef, nil
+	}
+
+	ref, err := w.ctx.IndRefForNewObject(types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name(annotationFontBaseFont),
+		"Encoding": types.Name("WinAnsiEncoding"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	w.fontRef = ref
+	return ref, nil
+}
+
+// textMarkupAppearance desenha destaques (quadriláteros preenchidos), sublinhados e tachados (linhas)
+func textMarkupAppearance(annotationType appModel.AnnotationType, quadPoints []float64, rgb string) string {
+	var content strings.Builder
+	content.WriteString("/GS0 gs\n")
+
+	for i := 0; i+8 <= len(quadPoints); i += 8 {
+		// QuadPoints: superior esquerdo, superior direito, inferior esquerdo, inferior direito
+		ulx, uly := quadPoints[i], quadPoints[i+1]
+		urx, ury := quadPoints[i+2], quadPoints[i+3]
+		llx, lly := quadPoints[i+4], quadPoints[i+5]
+		lrx, lry := quadPoints[i+6], quadPoints[i+7]
+
+		if annotationType == appModel.AnnotationHighlight {
+			fmt.Fprintf(&content, "%s rg\n%s %s m %s %s l %s %s l %s %s l h f\n", rgb,
+				formatNumber(ulx), formatNumber(uly), formatNumber(urx), formatNumber(ury),
+				formatNumber(lrx), formatNumber(lry), formatNumber(llx), formatNumber(lly))
+			continue
+		}
+
+		// A linha acompanha a base do trecho (sublinhado) ou o meio da altura das minúsculas (tachado)
+		height := math.Hypot(ulx-llx, uly-lly)
+		position := 0.08
+		if annotationType == appModel.AnnotationStrikeOut {
+			position = 0.4
+		}
+		fmt.Fprintf(&content, "%s RG %s w\n%s %s m %s %s l S\n", rgb, formatNumber(math.Max(0.5, height/14)),
+			formatNumber(llx+(ulx-llx)*position), formatNumber(lly+(uly-lly)*position),
+			formatNumber(lrx+(urx-lrx)*position), formatNumber(lry+(ury-lry)*position))
+	}
+
+	return content.String()
+}
+
+// noteAppearance desenha o ícone da nota adesiva: balão preenchido com a cor e linhas de texto
+func noteAppearance(rect bbox, rgb string) string {
+	width, height := rect.urx-rect.llx, rect.ury-rect.lly
+	inset := math.Min(width, height) * 0.1
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "/GS0 gs\n%s rg 0 G 0.75 w\n%s %s %s %s re B\n", rgb,
+		formatNumber(rect.llx+inset), formatNumber(rect.lly+inset), formatNumber(width-2*inset), formatNumber(height-2*inset))
+	for i := 1; i <= 3; i++ {
+		y := rect.ury - inset - (height-2*inset)*float64(i)/4
+		fmt.Fprintf(&content, "%s %s m %s %s l S\n",
+			formatNumber(rect.llx+2*inset), formatNumber(y), formatNumber(rect.urx-2*inset), formatNumber(y))
+	}
+	return content.String()
+}
+
+// freeTextAppearance desenha o texto livre quebrado em linhas dentro do retângulo, sem borda
+func freeTextAppearance(text string, rect bbox, fontSize float64, rgb string) (string, error) {
+	encoded, err := charmap.Windows1252.NewEncoder().String(text)
+	if err != nil {
+		return "", fmt.Errorf("texto da anotação contém caracteres não suportados pelas fontes padrão do PDF")
+	}
+
+	width := rect.urx - rect.llx
+	lines := wrapWinAnsi(encoded, annotationFontBaseFont, fontSize, width-2*annotationTextPadding)
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "/GS0 gs\nq\n%s %s %s %s re W n\nBT\n/%s %s Tf\n%s rg\n%s TL\n%s %s Td\n",
+		formatNumber(rect.llx), formatNumber(rect.lly), formatNumber(width), formatNumber(rect.ury-rect.lly),
+		annotationFontResName, formatNumber(fontSize), rgb, formatNumber(fontSize*annotationLineHeight),
+		formatNumber(rect.llx+annotationTextPadding), formatNumber(rect.ury-annotationTextPadding-fontSize))
+	for i, line := range lines {
+		if i > 0 {
+			content.WriteString("T*\n")
+		}
+		fmt.Fprintf(&content, "(%s) Tj\n", escapePDFString(line))
+	}
+	content.WriteString("ET\nQ\n")
+	return content.String(), nil
+}
+
+// inkAppearance desenha os traços com extremidades e junções arredondadas
+func inkAppearance(paths [][]float64, lineWidth float64, rgb string) string {
+	var content strings.Builder
+	fmt.Fprintf(&content, "/GS0 gs\n%s RG %s w 1 J 1 j\n", rgb, formatNumber(lineWidth))
+	for _, path := range paths {
+		for i := 0; i+2 <= len(path); i += 2 {
+			operator := "l"
+			if i == 0 {
+				operator = "m"
+			}
+			fmt.Fprintf(&content, "%s %s %s\n", formatNumber(path[i]), formatNumber(path[i+1]), operator)
+		}
+		if len(path) == 2 {
+			// Um único ponto vira um traço de comprimento zero, desenhado como ponto pela extremidade arredondada
+			fmt.Fprintf(&content, "%s %s l\n", formatNumber(path[0]), formatNumber(path[1]))
+		}
+		content.WriteString("S\n")
+	}
+	return content.String()
+}
+
+// rectQuad retorna o quadrilátero equivalente ao retângulo da anotação
+func rectQuad(annotation appModel.Annotation) appModel.Quad {
+	left, top := annotation.X, annotation.Y
+	right, bottom := annotation.X+annotation.Width, annotation.Y+annotation.Height
+	return appModel.Quad{{X: left, Y: top}, {X: right, Y: top}, {X: right, Y: bottom}, {X: left, Y: bottom}}
+}
+
+// dictText lê uma entrada de texto de um dicionário; ausente ou inválida resulta em vazio
+func dictText(pdfCtx *pdfcpuModel.Context, dict types.Dict, key string) string {
+	if dict[key] == nil {
+		return ""
+	}
+	text, err := pdfCtx.DereferenceText(dict[key])
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+// dictNumbers lê um array de números; elementos inválidos interrompem a leitura
+func dictNumbers(pdfCtx *pdfcpuModel.Context, obj types.Object) []float64 {
+	values, err := pdfCtx.DereferenceArray(obj)
+	if err != nil {
+		return nil
+	}
+
+	numbers := make([]float64, 0, len(values))
+	for _, value := range values {
+		number, err := pdfCtx.DereferenceNumber(value)
+		if err != nil {
+			return numbers
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers
+}
+
+// dictColor lê uma cor de anotação (cinza, RGB ou CMYK); array vazio significa transparente
+func dictColor(pdfCtx *pdfcpuModel.Context, obj types.Object) (rgbColor, bool) {
+	values := dictNumbers(pdfCtx, obj)
+	switch len(values) {
+	case 1:
+		return rgbColor{R: values[0], G: values[0], B: values[0]}, true
+	case 3:
+		return rgbColor{R: values[0], G: values[1], B: values[2]}, true
+	case 4:
+		k := values[3]
+		return rgbColor{R: (1 - values[0]) * (1 - k), G: (1 - values[1]) * (1 - k), B: (1 - values[2]) * (1 - k)}, true
+	}
+	return rgbColor{}, false
+}
+
+// borderWidth lê a espessura da borda (BS/W ou Border); o padrão do PDF é 1
+func borderWidth(pdfCtx *pdfcpuModel.Context, annotDict types.Dict) float64 {
+	if bs, err := pdfCtx.DereferenceDict(annotDict["BS"]); err == nil && bs != nil && bs["W"] != nil {
+		if width, err := pdfCtx.DereferenceNumber(bs["W"]); err == nil {
+			return width
+		}
+	}
+	if border := dictNumbers(pdfCtx, annotDict["Border"]); len(border) >= 3 {
+		return border[2]
+	}
+	return defaultAnnotationInkWidth
+}
+
+// parseDefaultAppearance lê o tamanho da fonte e a cor do texto de uma aparência padrão (DA)
+func parseDefaultAppearance(da string) (float64, *rgbColor) {
+	var fontSize float64
+	if match := daFontSizePattern.FindStringSubmatch(da); match != nil {
+		fontSize, _ = strconv.ParseFloat(match[1], 64)
+	}
+
+	if match := daRGBPattern.FindStringSubmatch(da); match != nil {
+		r, _ := strconv.ParseFloat(match[1], 64)
+		g, _ := strconv.ParseFloat(match[2], 64)
+		b, _ := strconv.ParseFloat(match[3], 64)
+		return fontSize, &rgbColor{R: r, G: g, B: b}
+	}
+	if match := daGrayPattern.FindStringSubmatch(da); match != nil {
+		gray, _ := strconv.ParseFloat(match[1], 64)
+		return fontSize, &rgbColor{R: gray, G: gray, B: gray}
+	}
+	return fontSize, nil
+}
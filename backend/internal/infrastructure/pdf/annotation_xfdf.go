@@ -0,0 +1,326 @@
+package pdf
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// xfdfAnnotationElements mapeia os tipos do modelo para os elementos XFDF (ISO 19444-1)
+var xfdfAnnotationElements = map[appModel.AnnotationType]string{
+	appModel.AnnotationHighlight: "highlight",
+	appModel.AnnotationUnderline: "underline",
+	appModel.AnnotationStrikeOut: "strikeout",
+	appModel.AnnotationNote:      "text",
+	appModel.AnnotationFreeText:  "freetext",
+	appModel.AnnotationInk:       "ink",
+}
+
+// xfdfAnnotsDocument é a estrutura de um documento XFDF com anotações
+type xfdfAnnotsDocument struct {
+	XMLName xml.Name    `xml:"xfdf"`
+	XMLNS   string      `xml:"xmlns,attr,omitempty"`
+	Space   string      `xml:"xml:space,attr,omitempty"`
+	Annots  xfdfAnnots  `xml:"annots"`
+	Fields  *xfdfFields `xml:"fields,omitempty"`
+}
+
+// xfdfAnnots contém as anotações de qualquer tipo (o nome do elemento é o tipo)
+type xfdfAnnots struct {
+	Items []xfdfAnnotation `xml:",any"`
+}
+
+// xfdfFields é ignorado na leitura de anotações, mas aceito em arquivos que também trazem campos
+type xfdfFields struct {
+	Inner []byte `xml:",innerxml"`
+}
+
+// xfdfAnnotation é uma anotação XFDF; coordenadas no espaço do PDF e página a partir de 0
+type xfdfAnnotation struct {
+	XMLName           xml.Name
+	Page              int      `xml:"page,attr"`
+	Rect              string   `xml:"rect,attr"`
+	Name              string   `xml:"name,attr,omitempty"`
+	Title             string   `xml:"title,attr,omitempty"`
+	Color             string   `xml:"color,attr,omitempty"`
+	Opacity           string   `xml:"opacity,attr,omitempty"`
+	CreationDate      string   `xml:"creationdate,attr,omitempty"`
+	Date              string   `xml:"date,attr,omitempty"`
+	Coords            string   `xml:"coords,attr,omitempty"`
+	Width             string   `xml:"width,attr,omitempty"`
+	Icon              string   `xml:"icon,attr,omitempty"`
+	Contents          string   `xml:"contents,omitempty"`
+	DefaultAppearance string   `xml:"defaultappearance,omitempty"`
+	InkList           *xfdfInk `xml:"inklist,omitempty"`
+}
+
+// xfdfInk contém os traços de uma anotação de tinta, com pontos "x,y" separados por ponto e vírgula
+type xfdfInk struct {
+	Gestures []string `xml:"gesture"`
+}
+
+// ExportAnnotationsXFDF gera um XFDF com as anotações informadas, convertendo as coordenadas para o espaço
+// de cada página do PDF
+func (p *PDFCPUProcessor) ExportAnnotationsXFDF(ctx context.Context, filePath string, annotations []appModel.Annotation) ([]byte, error) {
+	spaces, err := readPageSpaces(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := xfdfAnnotsDocument{XMLNS: xfdfNamespace, Space: "preserve"}
+	for _, annotation := range annotations {
+		if annotation.Page < 1 || annotation.Page > len(spaces) {
+			return nil, fmt.Errorf("página inválida: %d (PDF tem %d páginas)", annotation.Page, len(spaces))
+		}
+		element, ok := xfdfAnnotationElements[annotation.Type]
+		if !ok {
+			return nil, fmt.Errorf("tipo de anotação inválido: %s", annotation.Type)
+		}
+		space := spaces[annotation.Page-1]
+
+		rect := space.rectToPDF(annotation)
+		item := xfdfAnnotation{
+			XMLName:  xml.Name{Local: element},
+			Page:     annotation.Page - 1,
+			Rect:     joinNumbers(rect.llx, rect.lly, rect.urx, rect.ury),
+			Title:    annotation.Author,
+			Color:    annotation.Color,
+			Contents: annotation.Contents,
+		}
+		if annotation.ID != uuid.Nil {
+			item.Name = annotation.ID.String()
+		}
+		if annotation.Opacity > 0 && annotation.Opacity < 1 {
+			item.Opacity = formatNumber(annotation.Opacity)
+		}
+		if !annotation.CreatedAt.IsZero() {
+			item.CreationDate = types.DateString(annotation.CreatedAt)
+		}
+		if !annotation.UpdatedAt.IsZero() {
+			item.Date = types.DateString(annotation.UpdatedAt)
+		}
+
+		switch annotation.Type {
+		case appModel.AnnotationHighlight, appModel.AnnotationUnderline, appModel.AnnotationStrikeOut:
+			item.Coords = joinNumbers(space.quadPointsToPDF(annotation.Quads)...)
+
+		case appModel.AnnotationNote:
+			item.Icon = "Comment"
+
+		case appModel.AnnotationFreeText:
+			// Em texto livre a cor da anotação é a do texto, gravada na aparência padrão
+			item.Color = ""
+			fontSize := annotation.FontSize
+			if fontSize <= 0 {
+				fontSize = defaultAnnotationFontSize
+			}
+			color := rgbColor{}
+			if annotation.Color != "" {
+				if color, err = parseHexColor(annotation.Color); err != nil {
+					return nil, err
+				}
+			}
+			item.DefaultAppearance = fmt.Sprintf("/%s %s Tf %s %s %s rg", annotationFontResName, formatNumber(fontSize),
+				formatNumber(color.R), formatNumber(color.G), formatNumber(color.B))
+
+		case appModel.AnnotationInk:
+			if annotation.LineWidth > 0 {
+				item.Width = formatNumber(annotation.LineWidth)
+			}
+			item.InkList = &xfdfInk{}
+			for _, path := range space.inkToPDF(annotation.InkList) {
+				points := make([]string, 0, len(path)/2)
+				for i := 0; i+2 <= len(path); i += 2 {
+					points = append(points, joinNumbers(path[i], path[i+1]))
+				}
+				item.InkList.Gestures = append(item.InkList.Gestures, strings.Join(points, ";"))
+			}
+		}
+
+		doc.Annots.Items = append(doc.Annots.Items, item)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar XFDF: %w", err)
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// ParseAnnotationsXFDF lê as anotações de um XFDF, convertendo as coordenadas para a origem no topo esquerdo
+// das páginas do PDF; elementos de tipos não suportados são ignorados
+func (p *PDFCPUProcessor) ParseAnnotationsXFDF(ctx context.Context, filePath string, data []byte) ([]appModel.Annotation, error) {
+	var doc xfdfAnnotsDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("erro ao ler XFDF: %w", err)
+	}
+
+	spaces, err := readPageSpaces(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	byElement := make(map[string]appModel.AnnotationType, len(xfdfAnnotationElements))
+	for annotationType, element := range xfdfAnnotationElements {
+		byElement[element] = annotationType
+	}
+
+	var annotations []appModel.Annotation
+	skipped := 0
+	for _, item := range doc.Annots.Items {
+		annotationType, ok := byElement[strings.ToLower(item.XMLName.Local)]
+		if !ok {
+			skipped++
+			continue
+		}
+
+		annotation, err := item.toAnnotation(annotationType, spaces)
+		if err != nil {
+			return nil, err
+		}
+		annotations = append(annotations, annotation)
+	}
+
+	logger.Logger.Debug("Anotações lidas do XFDF",
+		zap.Int("annotations_count", len(annotations)),
+		zap.Int("skipped_count", skipped),
+	)
+
+	return annotations, nil
+}
+
+// toAnnotation converte o elemento XFDF para o modelo
+func (item xfdfAnnotation) toAnnotation(annotationType appModel.AnnotationType, spaces []pageSpace) (appModel.Annotation, error) {
+	if item.Page < 0 || item.Page >= len(spaces) {
+		return appModel.Annotation{}, fmt.Errorf("página inválida: %d (PDF tem %d páginas)", item.Page+1, len(spaces))
+	}
+	space := spaces[item.Page]
+
+	rect := parseNumbers(item.Rect)
+	if len(rect) != 4 {
+		return appModel.Annotation{}, fmt.Errorf("retângulo inválido na anotação XFDF: %q", item.Rect)
+	}
+
+	annotation := appModel.Annotation{
+		Page:     item.Page + 1,
+		Type:     annotationType,
+		Author:   item.Title,
+		Contents: item.Contents,
+		Opacity:  1,
+	}
+	space.setRectFromPDF(&annotation, bbox{
+		llx: min(rect[0], rect[2]), lly: min(rect[1], rect[3]),
+		urx: max(rect[0], rect[2]), ury: max(rect[1], rect[3]),
+	})
+
+	if id, err := uuid.Parse(item.Name); err == nil {
+		annotation.ID = id
+	}
+	if item.Color != "" {
+		color, err := parseHexColor(item.Color)
+		if err != nil {
+			return appModel.Annotation{}, err
+		}
+		annotation.Color = color.hex()
+	}
+	if opacity, err := strconv.ParseFloat(item.Opacity, 64); err == nil && opacity > 0 && opacity <= 1 {
+		annotation.Opacity = opacity
+	}
+	if created, ok := types.DateTime(item.CreationDate, true); ok {
+		annotation.CreatedAt = created
+	}
+	if modified, ok := types.DateTime(item.Date, true); ok {
+		annotation.UpdatedAt = modified
+	}
+
+	switch annotationType {
+	case appModel.AnnotationHighlight, appModel.AnnotationUnderline, appModel.AnnotationStrikeOut:
+		annotation.Quads = space.quadsFromPDF(parseNumbers(item.Coords))
+		if len(annotation.Quads) == 0 {
+			annotation.Quads = appModel.AnnotationQuads{rectQuad(annotation)}
+		}
+
+	case appModel.AnnotationFreeText:
+		fontSize, color := parseDefaultAppearance(item.DefaultAppearance)
+		annotation.FontSize = fontSize
+		annotation.Color = ""
+		if color != nil {
+			annotation.Color = color.hex()
+		}
+
+	case appModel.AnnotationInk:
+		var paths [][]float64
+		if item.InkList != nil {
+			for _, gesture := range item.InkList.Gestures {
+				paths = append(paths, parseNumbers(strings.ReplaceAll(gesture, ";", ",")))
+			}
+		}
+		annotation.InkList = space.inkFromPDF(paths)
+		annotation.LineWidth = defaultAnnotationInkWidth
+		if width, err := strconv.ParseFloat(item.Width, 64); err == nil && width > 0 {
+			annotation.LineWidth = width
+		}
+	}
+
+	return annotation, nil
+}
+
+// readPageSpaces lê a conversão de coordenadas de cada página do PDF
+func readPageSpaces(filePath string) ([]pageSpace, error) {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+	return pageSpaces(pdfCtx)
+}
+
+// pageSpaces retorna a conversão de coordenadas de cada página, na ordem
+func pageSpaces(pdfCtx *pdfcpuModel.Context) ([]pageSpace, error) {
+	spaces := make([]pageSpace, 0, pdfCtx.PageCount)
+	for pageNum := 1; pageNum <= pdfCtx.PageCount; pageNum++ {
+		_, _, inherited, err := pdfCtx.PageDict(pageNum, false)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler página %d: %w", pageNum, err)
+		}
+		if inherited == nil || inherited.MediaBox == nil {
+			return nil, fmt.Errorf("página %d não possui MediaBox", pageNum)
+		}
+		spaces = append(spaces, newPageSpace(inherited.MediaBox))
+	}
+	return spaces, nil
+}
+
+// joinNumbers formata números separados por vírgula, como nos atributos XFDF
+func joinNumbers(values ...float64) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = formatNumber(value)
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseNumbers lê números separados por vírgula ou espaço; valores inválidos são ignorados
+func parseNumbers(value string) []float64 {
+	fields := strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+
+	numbers := make([]float64, 0, len(fields))
+	for _, field := range fields {
+		if number, err := strconv.ParseFloat(field, 64); err == nil {
+			numbers = append(numbers, number)
+		}
+	}
+	return numbers
+}
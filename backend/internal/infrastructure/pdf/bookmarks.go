@@ -0,0 +1,538 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// Flags de estilo de um item do outline (entrada F)
+const (
+	outlineFlagItalic = 1 << 0
+	outlineFlagBold   = 1 << 1
+)
+
+// maxOutlineItems limita a leitura do outline, protegendo contra listas circulares
+const maxOutlineItems = 100000
+
+// outlineViews mapeia os ajustes de destino do PDF para o modelo; os demais são lidos como página inteira
+var outlineViews = map[string]appModel.BookmarkView{
+	"Fit":   appModel.BookmarkViewFit,
+	"FitB":  appModel.BookmarkViewFit,
+	"FitH":  appModel.BookmarkViewFitWidth,
+	"FitBH": appModel.BookmarkViewFitWidth,
+	"FitV":  appModel.BookmarkViewFitHeight,
+	"FitBV": appModel.BookmarkViewFitHeight,
+	"XYZ":   appModel.BookmarkViewXYZ,
+}
+
+// ExtractBookmarks lê a hierarquia de marcadores (outline) do PDF, com destino, zoom, estado e estilo
+// Retorna uma lista vazia quando o PDF não possui marcadores
+func (p *PDFCPUProcessor) ExtractBookmarks(ctx context.Context, filePath string) ([]appModel.Bookmark, error) {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	bookmarks, err := readOutline(pdfCtx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler marcadores: %w", err)
+	}
+
+	return bookmarks, nil
+}
+
+// WriteBookmarks substitui o outline do PDF pelos marcadores informados (lista vazia remove o outline)
+func (p *PDFCPUProcessor) WriteBookmarks(ctx context.Context, filePath string, bookmarks []appModel.Bookmark) error {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	if err := writeOutline(pdfCtx, bookmarks); err != nil {
+		return err
+	}
+
+	if err := rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		return api.WriteContext(pdfCtx, out)
+	}); err != nil {
+		return fmt.Errorf("erro ao salvar PDF: %w", err)
+	}
+
+	logger.Logger.Debug("Marcadores gravados",
+		zap.String("file", filePath),
+		zap.Int("bookmarks_count", countBookmarks(bookmarks)),
+	)
+
+	return nil
+}
+
+// readOutline converte o outline do catálogo para o modelo
+func readOutline(pdfCtx *pdfcpuModel.Context) ([]appModel.Bookmark, error) {
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+
+	outlines, err := pdfCtx.DereferenceDict(rootDict["Outlines"])
+	if err != nil || outlines == nil {
+		return []appModel.Bookmark{}, err
+	}
+
+	if err := pdfCtx.LocateNameTree("Dests", false); err != nil {
+		return nil, err
+	}
+
+	reader := &outlineReader{ctx: pdfCtx, pages: make(map[int]int), visited: make(map[int]bool)}
+	for pageNum := 1; pageNum <= pdfCtx.PageCount; pageNum++ {
+		_, pageRef, inherited, err := pdfCtx.PageDict(pageNum, false)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler página %d: %w", pageNum, err)
+		}
+		if pageRef != nil {
+			reader.pages[pageRef.ObjectNumber.Value()] = pageNum
+		}
+		space := pageSpace{}
+		if inherited != nil && inherited.MediaBox != nil {
+			space = newPageSpace(inherited.MediaBox)
+		}
+		reader.spaces = append(reader.spaces, space)
+	}
+
+	return reader.items(outlines["First"]), nil
+}
+
+// outlineReader percorre os itens do outline resolvendo páginas e destinos
+type outlineReader struct {
+	ctx     *pdfcpuModel.Context
+	pages   map[int]int // Número do objeto da página -> número da página
+	spaces  []pageSpace
+	visited map[int]bool
+}
+
+// items lê uma lista de itens irmãos a partir do primeiro
+func (r *outlineReader) items(first types.Object) []appModel.Bookmark {
+	bookmarks := []appModel.Bookmark{}
+	for obj := first; obj != nil; {
+		ref, ok := obj.(types.IndirectRef)
+		if !ok || r.visited[ref.ObjectNumber.Value()] || len(r.visited) >= maxOutlineItems {
+			break
+		}
+		r.visited[ref.ObjectNumber.Value()] = true
+
+		item, err := r.ctx.DereferenceDict(ref)
+		if err != nil || item == nil {
+			break
+		}
+
+		bookmark := appModel.Bookmark{Title: dictText(r.ctx, item, "Title")}
+		r.readDestination(item, &bookmark)
+
+		if count := item.IntEntry("Count"); count != nil {
+			bookmark.Open = *count > 0
+		}
+		if flags := item.IntEntry("F"); flags != nil {
+			bookmark.Bold = *flags&outlineFlagBold != 0
+			bookmark.Italic = *flags&outlineFlagItalic != 0
+		}
+		// Preto é a cor padrão dos itens e não é informado
+		if color, ok := dictColor(r.ctx, item["C"]); ok && color != (rgbColor{}) {
+			bookmark.Color = color.hex()
+		}
+
+		bookmark.Children = r.items(item["First"])
+		if len(bookmark.Children) == 0 {
+			bookmark.Children = nil
+			bookmark.Open = false
+		}
+
+		bookmarks = append(bookmarks, bookmark)
+		obj = item["Next"]
+	}
+	return bookmarks
+}
+
+// readDestination preenche página e ajuste do destino (entrada Dest ou ação GoTo)
+func (r *outlineReader) readDestination(item types.Dict, bookmark *appModel.Bookmark) {
+	dest := item["Dest"]
+	if dest == nil {
+		action, err := r.ctx.DereferenceDict(item["A"])
+		if err != nil || action == nil || action.NameEntry("S") == nil || *action.NameEntry("S") != "GoTo" {
+			return
+		}
+		dest = action["D"]
+	}
+
+	array := r.destinationArray(dest)
+	if len(array) == 0 {
+		return
+	}
+
+	switch page := array[0].(type) {
+	case types.IndirectRef:
+		bookmark.Page = r.pages[page.ObjectNumber.Value()]
+	case types.Integer:
+		// Destinos com número de página (a partir de 0) são usados em ações remotas, aceitos por tolerância
+		bookmark.Page = page.Value() + 1
+	}
+	if bookmark.Page < 1 || bookmark.Page > len(r.spaces) {
+		bookmark.Page = 0
+		return
+	}
+
+	bookmark.View = appModel.BookmarkViewFit
+	if len(array) < 2 {
+		return
+	}
+	fit, ok := array[1].(types.Name)
+	if !ok {
+		return
+	}
+	if view, found := outlineViews[fit.Value()]; found {
+		bookmark.View = view
+	}
+
+	space := r.spaces[bookmark.Page-1]
+	number := func(i int) *float64 {
+		if i >= len(array) || array[i] == nil {
+			return nil
+		}
+		value, err := r.ctx.DereferenceNumber(array[i])
+		if err != nil {
+			return nil
+		}
+		return &value
+	}
+
+	switch bookmark.View {
+	case appModel.BookmarkViewXYZ:
+		if left := number(2); left != nil {
+			value := roundPoints(*left - space.llx)
+			bookmark.Left = &value
+		}
+		if top := number(3); top != nil {
+			value := roundPoints(space.ury - *top)
+			bookmark.Top = &value
+		}
+		if zoom := number(4); zoom != nil && *zoom > 0 {
+			bookmark.Zoom = *zoom
+		}
+	case appModel.BookmarkViewFitWidth:
+		if top := number(2); top != nil {
+			value := roundPoints(space.ury - *top)
+			bookmark.Top = &value
+		}
+	case appModel.BookmarkViewFitHeight:
+		if left := number(2); left != nil {
+			value := roundPoints(*left - space.llx)
+			bookmark.Left = &value
+		}
+	}
+}
+
+// destinationArray resolve um destino explícito ou nomeado para o array [página ajuste ...]
+func (r *outlineReader) destinationArray(dest types.Object) types.Array {
+	obj, err := r.ctx.Dereference(dest)
+	if err != nil || obj == nil {
+		return nil
+	}
+
+	var name string
+	switch value := obj.(type) {
+	case types.Array:
+		return value
+	case types.Dict:
+		return r.destinationArray(value["D"])
+	case types.Name:
+		name = value.Value()
+	case types.StringLiteral:
+		if name, err = types.StringLiteralToString(value); err != nil {
+			return nil
+		}
+	case types.HexLiteral:
+		if name, err = types.HexLiteralToString(value); err != nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	if tree := r.ctx.Names["Dests"]; tree != nil {
+		if target, found := tree.Value(name); found {
+			return r.namedDestination(target)
+		}
+	}
+
+	// Destinos nomeados no dicionário Dests do catálogo (PDF 1.1)
+	rootDict, err := r.ctx.Catalog()
+	if err != nil {
+		return nil
+	}
+	dests, err := r.ctx.DereferenceDict(rootDict["Dests"])
+	if err != nil || dests == nil {
+		return nil
+	}
+	if target, found := dests.Find(name); found {
+		return r.namedDestination(target)
+	}
+	return nil
+}
+
+// namedDestination resolve o valor de um destino nomeado, que não pode ser outro nome
+func (r *outlineReader) namedDestination(target types.Object) types.Array {
+	obj, err := r.ctx.Dereference(target)
+	if err != nil {
+		return nil
+	}
+	switch value := obj.(type) {
+	case types.Array:
+		return value
+	case types.Dict:
+		array, _ := r.ctx.DereferenceArray(value["D"])
+		return array
+	}
+	return nil
+}
+
+// writeOutline substitui o outline do catálogo pelos marcadores informados
+func writeOutline(pdfCtx *pdfcpuModel.Context, bookmarks []appModel.Bookmark) error {
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return fmt.Errorf("erro ao ler catálogo do PDF: %w", err)
+	}
+
+	// Os itens anteriores deixam de ser referenciados e não são gravados
+	rootDict.Delete("Outlines")
+	pdfCtx.Outlines = nil
+	if len(bookmarks) == 0 {
+		return nil
+	}
+
+	writer := &outlineWriter{ctx: pdfCtx}
+	outlines := types.Dict{"Type": types.Name("Outlines")}
+	outlinesRef, err := pdfCtx.IndRefForNewObject(outlines)
+	if err != nil {
+		return err
+	}
+
+	first, last, visible, err := writer.items(bookmarks, *outlinesRef)
+	if err != nil {
+		return err
+	}
+
+	outlines["First"] = first
+	outlines["Last"] = last
+	outlines["Count"] = types.Integer(visible)
+	rootDict["Outlines"] = *outlinesRef
+	pdfCtx.Outlines = outlines
+
+	return nil
+}
+
+// outlineWriter cria os dicionários dos itens do outline
+type outlineWriter struct {
+	ctx *pdfcpuModel.Context
+}
+
+// items grava uma lista de itens irmãos e retorna o primeiro, o último e a quantidade de itens visíveis
+// (os itens da lista e os descendentes de itens abertos)
+func (w *outlineWriter) items(bookmarks []appModel.Bookmark, parent types.IndirectRef) (types.IndirectRef, types.IndirectRef, int, error) {
+	var first, prev types.IndirectRef
+	var prevDict types.Dict
+	visible := 0
+
+	for i, bookmark := range bookmarks {
+		item := types.Dict{
+			"Title":  pdfTextString(bookmark.Title),
+			"Parent": parent,
+		}
+		ref, err := w.ctx.IndRefForNewObject(item)
+		if err != nil {
+			return first, prev, 0, err
+		}
+
+		if bookmark.Page > 0 {
+			dest, err := w.destination(bookmark)
+			if err != nil {
+				return first, prev, 0, err
+			}
+			item["Dest"] = dest
+		}
+
+		flags := 0
+		if bookmark.Bold {
+			flags |= outlineFlagBold
+		}
+		if bookmark.Italic {
+			flags |= outlineFlagItalic
+		}
+		if flags != 0 {
+			item["F"] = types.Integer(flags)
+		}
+		if bookmark.Color != "" {
+			color, err := parseHexColor(bookmark.Color)
+			if err != nil {
+				return first, prev, 0, err
+			}
+			item["C"] = types.Array{types.Float(color.R), types.Float(color.G), types.Float(color.B)}
+		}
+
+		visible++
+		if len(bookmark.Children) > 0 {
+			childFirst, childLast, childVisible, err := w.items(bookmark.Children, *ref)
+			if err != nil {
+				return first, prev, 0, err
+			}
+			item["First"] = childFirst
+			item["Last"] = childLast
+
+			// Count positivo indica item aberto; negativo, fechado
+			if bookmark.Open {
+				item["Count"] = types.Integer(childVisible)
+				visible += childVisible
+			} else {
+				item["Count"] = types.Integer(-childVisible)
+			}
+		}
+
+		if i == 0 {
+			first = *ref
+		} else {
+			item["Prev"] = prev
+			prevDict["Next"] = *ref
+		}
+		prev, prevDict = *ref, item
+	}
+
+	return first, prev, visible, nil
+}
+
+// destination monta o destino explícito do marcador, convertendo as posições para o espaço da página
+func (w *outlineWriter) destination(bookmark appModel.Bookmark) (types.Array, error) {
+	if bookmark.Page > w.ctx.PageCount {
+		return nil, fmt.Errorf("página inválida: %d (PDF tem %d páginas)", bookmark.Page, w.ctx.PageCount)
+	}
+
+	_, pageRef, inherited, err := w.ctx.PageDict(bookmark.Page, false)
+	if err != nil || pageRef == nil {
+		return nil, fmt.Errorf("erro ao ler página %d: %w", bookmark.Page, err)
+	}
+	space := pageSpace{}
+	if inherited != nil && inherited.MediaBox != nil {
+		space = newPageSpace(inherited.MediaBox)
+	}
+
+	left := func() types.Object {
+		if bookmark.Left == nil {
+			return nil
+		}
+		return types.Float(space.llx + *bookmark.Left)
+	}
+	top := func() types.Object {
+		if bookmark.Top == nil {
+			return nil
+		}
+		return types.Float(space.ury - *bookmark.Top)
+	}
+
+	switch bookmark.View {
+	case appModel.BookmarkViewXYZ:
+		var zoom types.Object
+		if bookmark.Zoom > 0 {
+			zoom = types.Float(bookmark.Zoom)
+		}
+		return types.Array{*pageRef, types.Name("XYZ"), left(), top(), zoom}, nil
+	case appModel.BookmarkViewFitWidth:
+		return types.Array{*pageRef, types.Name("FitH"), top()}, nil
+	case appModel.BookmarkViewFitHeight:
+		return types.Array{*pageRef, types.Name("FitV"), left()}, nil
+	}
+	return types.Array{*pageRef, types.Name("Fit")}, nil
+}
+
+// preserveBookmarks executa uma operação que reorganiza as páginas e regrava o outline anterior com os
+// destinos ajustados. order lista, para cada página resultante, a página original (0 = página nova);
+// marcadores de páginas removidas são excluídos e seus filhos passam a ocupar o lugar deles
+func preserveBookmarks(filePath string, order []int, operation func() error) error {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	bookmarks, err := readOutline(pdfCtx)
+	if err != nil {
+		logger.Logger.Warn("Erro ao ler marcadores; o outline não será ajustado",
+			zap.String("file", filePath),
+			zap.Error(err),
+		)
+		return operation()
+	}
+
+	if err := operation(); err != nil {
+		return err
+	}
+	if len(bookmarks) == 0 {
+		return nil
+	}
+
+	// Cada página original passa a apontar para a sua primeira ocorrência no resultado
+	newPages := make(map[int]int, len(order))
+	for i, original := range order {
+		if _, found := newPages[original]; !found && original > 0 {
+			newPages[original] = i + 1
+		}
+	}
+
+	remapped := remapBookmarks(bookmarks, newPages)
+
+	if pdfCtx, err = api.ReadContextFile(filePath); err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+	if err := writeOutline(pdfCtx, remapped); err != nil {
+		return fmt.Errorf("erro ao ajustar marcadores: %w", err)
+	}
+
+	return rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		return api.WriteContext(pdfCtx, out)
+	})
+}
+
+// remapBookmarks atualiza as páginas de destino; marcadores de páginas removidas dão lugar aos seus filhos
+func remapBookmarks(bookmarks []appModel.Bookmark, newPages map[int]int) []appModel.Bookmark {
+	var result []appModel.Bookmark
+	for _, bookmark := range bookmarks {
+		children := remapBookmarks(bookmark.Children, newPages)
+		if bookmark.Page == 0 {
+			bookmark.Children = children
+			result = append(result, bookmark)
+			continue
+		}
+
+		page, found := newPages[bookmark.Page]
+		if !found {
+			result = append(result, children...)
+			continue
+		}
+
+		bookmark.Page = page
+		bookmark.Children = children
+		result = append(result, bookmark)
+	}
+	return result
+}
+
+// countBookmarks conta os marcadores de todos os níveis
+func countBookmarks(bookmarks []appModel.Bookmark) int {
+	count := len(bookmarks)
+	for _, bookmark := range bookmarks {
+		count += countBookmarks(bookmark.Children)
+	}
+	return count
+}
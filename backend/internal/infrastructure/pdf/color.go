@@ -0,0 +1,46 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// rgbColor representa uma cor RGB com componentes entre 0 e 1
+type rgbColor struct {
+	R, G, B float64
+}
+
+// parseHexColor converte uma cor no formato #RGB ou #RRGGBB para rgbColor
+func parseHexColor(hex string) (rgbColor, error) {
+	value := strings.TrimPrefix(strings.TrimSpace(hex), "#")
+
+	// Expande o formato curto (#RGB -> #RRGGBB)
+	if len(value) == 3 {
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	}
+
+	if len(value) != 6 {
+		return rgbColor{}, fmt.Errorf("cor inválida: %s (esperado #RRGGBB)", hex)
+	}
+
+	parsed, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return rgbColor{}, fmt.Errorf("cor inválida: %s (esperado #RRGGBB)", hex)
+	}
+
+	return rgbColor{
+		R: float64((parsed>>16)&0xFF) / 255.0,
+		G: float64((parsed>>8)&0xFF) / 255.0,
+		B: float64(parsed&0xFF) / 255.0,
+	}, nil
+}
+
+// hex formata a cor no formato #RRGGBB
+func (c rgbColor) hex() string {
+	component := func(value float64) int {
+		return int(math.Round(math.Max(0, math.Min(1, value)) * 255))
+	}
+	return fmt.Sprintf("#%02X%02X%02X", component(c.R), component(c.G), component(c.B))
+}
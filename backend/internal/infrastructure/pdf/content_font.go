@@ -0,0 +1,136 @@
+package pdf
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+)
+
+const (
+	// Métricas usadas quando a fonte do PDF não informa os valores (frações de em)
+	defaultGlyphWidth = 0.5
+	defaultAscent     = 0.8
+	defaultDescent    = -0.2
+)
+
+// contentFont reúne as métricas de uma fonte já existente no PDF, usadas para
+// posicionar cada glifo dos operadores de texto do content stream
+type contentFont struct {
+	font       *model.PdfFont // nil quando a fonte não pôde ser carregada
+	twoByte    bool           // Códigos de 2 bytes (CMap Identity-H/V)
+	multiByte  bool           // CMap desconhecido: a string é tratada como um único bloco
+	widthScale float64        // Converte larguras do espaço do glifo para em
+	ascent     float64        // Em frações de em
+	descent    float64        // Em frações de em (negativo)
+}
+
+// contentGlyph representa um glifo (ou bloco indivisível de glifos) de uma string de texto
+type contentGlyph struct {
+	bytes []byte
+	width float64 // Em frações de em
+	space bool    // Código de 1 byte 32, afetado pelo espaçamento entre palavras (Tw)
+}
+
+// contentFontFromObject carrega as métricas de uma fonte do dicionário de recursos
+func contentFontFromObject(obj core.PdfObject) *contentFont {
+	cf := &contentFont{widthScale: 0.001, ascent: defaultAscent, descent: defaultDescent}
+
+	font, err := model.NewPdfFontFromPdfObject(obj)
+	if err != nil {
+		return cf
+	}
+	cf.font = font
+
+	if dict, ok := core.GetDict(obj); ok {
+		// Fontes Type3 definem a própria escala do espaço do glifo
+		if matrixValues, ok := core.GetArray(dict.Get("FontMatrix")); ok && matrixValues.Len() == 6 {
+			if values, err := matrixValues.ToFloat64Array(); err == nil && values[0] != 0 {
+				cf.widthScale = values[0]
+			}
+		}
+
+		// Fontes compostas: apenas CMaps Identity têm tamanho de código conhecido (2 bytes)
+		if font.IsCID() {
+			encoding, _ := core.GetNameVal(dict.Get("Encoding"))
+			if encoding == "Identity-H" || encoding == "Identity-V" {
+				cf.twoByte = true
+			} else {
+				cf.multiByte = true
+			}
+		}
+	}
+
+	if descriptor := font.FontDescriptor(); descriptor != nil {
+		if ascent, err := core.GetNumberAsFloat(descriptor.Ascent); err == nil && ascent > 0 {
+			cf.ascent = ascent / 1000
+		}
+		if descent, err := core.GetNumberAsFloat(descriptor.Descent); err == nil && descent <= 0 {
+			cf.descent = descent / 1000
+		}
+	}
+
+	return cf
+}
+
+// glyphs divide os bytes de uma string de texto em glifos com suas larguras
+func (cf *contentFont) glyphs(data []byte) []contentGlyph {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if cf.multiByte || (cf.twoByte && len(data)%2 != 0) {
+		return []contentGlyph{{bytes: data, width: cf.width(data)}}
+	}
+
+	size := 1
+	if cf.twoByte {
+		size = 2
+	}
+
+	glyphs := make([]contentGlyph, 0, len(data)/size)
+	for i := 0; i < len(data); i += size {
+		code := data[i : i+size]
+		glyphs = append(glyphs, contentGlyph{
+			bytes: code,
+			width: cf.width(code),
+			space: size == 1 && code[0] == ' ',
+		})
+	}
+
+	return glyphs
+}
+
+// text decodifica os códigos para Unicode (vazio quando a fonte não tem mapeamento)
+func (cf *contentFont) text(data []byte) string {
+	if cf.font == nil {
+		// Sem fonte, assume uma codificação de 1 byte compatível com Latin-1
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes)
+	}
+
+	text, _, _ := cf.font.CharcodeBytesToUnicode(data)
+	return strings.ReplaceAll(text, string(utf8.RuneError), "")
+}
+
+// width retorna a largura total dos códigos em frações de em
+func (cf *contentFont) width(data []byte) float64 {
+	if cf.font == nil {
+		return defaultGlyphWidth * float64(len(data))
+	}
+
+	width := 0.0
+	for _, code := range cf.font.BytesToCharcodes(data) {
+		metrics, ok := cf.font.GetCharMetrics(code)
+		if !ok {
+			width += defaultGlyphWidth
+			continue
+		}
+		width += metrics.Wx * cf.widthScale
+	}
+	return width
+}
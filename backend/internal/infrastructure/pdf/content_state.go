@@ -0,0 +1,206 @@
+package pdf
+
+import (
+	"github.com/unidoc/unipdf/v3/contentstream"
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+)
+
+// Interpretação de content streams compartilhada pela redação e pela extração de texto
+
+// contentState é o subconjunto do estado gráfico necessário para posicionar o conteúdo
+type contentState struct {
+	ctm       matrix
+	lineWidth float64
+	clip      bbox // Aproximação retangular da área de recorte, em espaço do dispositivo
+
+	font        *contentFont
+	fontSize    float64
+	charSpacing float64
+	wordSpacing float64
+	hScale      float64
+	leading     float64
+	rise        float64
+}
+
+// newContentState cria o estado gráfico inicial de uma página
+func newContentState() contentState {
+	return contentState{ctm: identityMatrix, lineWidth: 1, clip: infiniteBBox, hScale: 1}
+}
+
+// textCursor guarda a matriz de texto e a matriz de início de linha de um objeto de texto (BT/ET)
+type textCursor struct {
+	tm  matrix
+	tlm matrix
+}
+
+// newLine avança para a próxima linha deslocando a matriz de início de linha
+func (c *textCursor) newLine(tx, ty float64) {
+	c.tlm = translateMatrix(tx, ty).multiply(c.tlm)
+	c.tm = c.tlm
+}
+
+// applyTextOperator atualiza o estado de texto com operadores de estado e posicionamento
+// (BT, Tf, Tc, Tw, Tz, TL, Ts, Td, TD, Tm, T*). Retorna false para os demais operadores
+func (s *contentState) applyTextOperator(op *contentstream.ContentStreamOperation, cursor *textCursor, fonts fontCache, resources *model.PdfPageResources) bool {
+	switch op.Operand {
+	case "BT":
+		cursor.tm, cursor.tlm = identityMatrix, identityMatrix
+
+	case "Tf":
+		if name, ok := nameParam(op.Params, 0); ok {
+			s.font = fonts.get(resources, name)
+		}
+		if size, ok := numberParam(op.Params, 1); ok {
+			s.fontSize = size
+		}
+
+	case "Tc":
+		if value, ok := numberParam(op.Params, 0); ok {
+			s.charSpacing = value
+		}
+
+	case "Tw":
+		if value, ok := numberParam(op.Params, 0); ok {
+			s.wordSpacing = value
+		}
+
+	case "Tz":
+		if value, ok := numberParam(op.Params, 0); ok {
+			s.hScale = value / 100
+		}
+
+	case "TL":
+		if value, ok := numberParam(op.Params, 0); ok {
+			s.leading = value
+		}
+
+	case "Ts":
+		if value, ok := numberParam(op.Params, 0); ok {
+			s.rise = value
+		}
+
+	case "Td", "TD":
+		tx, okX := numberParam(op.Params, 0)
+		ty, okY := numberParam(op.Params, 1)
+		if okX && okY {
+			if op.Operand == "TD" {
+				s.leading = -ty
+			}
+			cursor.newLine(tx, ty)
+		}
+
+	case "Tm":
+		if m, ok := matrixFromParams(op.Params); ok {
+			cursor.tlm, cursor.tm = m, m
+		}
+
+	case "T*":
+		cursor.newLine(0, -s.leading)
+
+	default:
+		return false
+	}
+
+	return true
+}
+
+// currentFont retorna a fonte atual ou métricas padrão quando nenhuma foi selecionada
+func (s *contentState) currentFont() *contentFont {
+	if s.font == nil {
+		return contentFontFromObject(nil)
+	}
+	return s.font
+}
+
+// glyphAdvance retorna o deslocamento horizontal do glifo no espaço de texto
+func (s *contentState) glyphAdvance(glyph contentGlyph) float64 {
+	advance := glyph.width*s.fontSize + s.charSpacing
+	if glyph.space {
+		advance += s.wordSpacing
+	}
+	return advance * s.hScale
+}
+
+// glyphBox retorna a caixa do glifo (largura × ascendente/descendente) em espaço do dispositivo
+func (s *contentState) glyphBox(glyph contentGlyph, font *contentFont, tm matrix) bbox {
+	return s.glyphQuad(glyph, font, tm).bbox()
+}
+
+// glyphQuad retorna os cantos da caixa do glifo em espaço do dispositivo, preservando a rotação
+func (s *contentState) glyphQuad(glyph contentGlyph, font *contentFont, tm matrix) quad {
+	return bbox{
+		llx: 0,
+		lly: s.rise + font.descent*s.fontSize,
+		urx: glyph.width * s.fontSize * s.hScale,
+		ury: s.rise + font.ascent*s.fontSize,
+	}.quad(tm.multiply(s.ctm))
+}
+
+// fontCache evita recarregar a mesma fonte a cada operador Tf
+type fontCache map[core.PdfObject]*contentFont
+
+// get carrega a fonte pelo nome no dicionário de recursos
+func (c fontCache) get(resources *model.PdfPageResources, name core.PdfObjectName) *contentFont {
+	obj, ok := resources.GetFontByName(name)
+	if !ok {
+		return contentFontFromObject(nil)
+	}
+
+	if cached, ok := c[obj]; ok {
+		return cached
+	}
+
+	font := contentFontFromObject(obj)
+	c[obj] = font
+	return font
+}
+
+// formPlacement retorna a CTM do conteúdo de um form XObject e sua BBox em espaço do dispositivo
+func formPlacement(xform *model.XObjectForm, ctm matrix) (matrix, bbox) {
+	formMatrix := identityMatrix
+	if values, ok := core.GetArray(xform.Matrix); ok {
+		if m, ok := matrixFromParams(values.Elements()); ok {
+			formMatrix = m
+		}
+	}
+	formCTM := formMatrix.multiply(ctm)
+
+	formBox := infiniteBBox
+	if values, ok := core.GetArray(xform.BBox); ok {
+		if rect, err := model.NewPdfRectangle(*values); err == nil {
+			formBox = bbox{rect.Llx, rect.Lly, rect.Urx, rect.Ury}.transform(formCTM)
+		}
+	}
+
+	return formCTM, formBox
+}
+
+// numberParam lê um operando numérico
+func numberParam(params []core.PdfObject, index int) (float64, bool) {
+	if index >= len(params) {
+		return 0, false
+	}
+	value, err := core.GetNumberAsFloat(params[index])
+	return value, err == nil
+}
+
+// nameParam lê um operando do tipo nome
+func nameParam(params []core.PdfObject, index int) (core.PdfObjectName, bool) {
+	if index >= len(params) {
+		return "", false
+	}
+	name, ok := core.GetName(params[index])
+	if !ok {
+		return "", false
+	}
+	return *name, true
+}
+
+// objectParam retorna um operando ou nil quando ausente
+func objectParam(params []core.PdfObject, index int) core.PdfObject {
+	if index >= len(params) {
+		return nil
+	}
+	return params[index]
+}
@@ -0,0 +1,188 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/unidoc/unipdf/v3/contentstream"
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+	"go.uber.org/zap"
+)
+
+// bezierKappa é a distância dos pontos de controle usada para aproximar um quarto de elipse
+const bezierKappa = 0.5522847498
+
+// AddDrawing adiciona uma forma vetorial a uma página específica do PDF
+func (p *PDFCPUProcessor) AddDrawing(ctx context.Context, filePath string, pageNum int, drawing appModel.Drawing) error {
+	if err := validateDrawing(drawing); err != nil {
+		return err
+	}
+
+	err := appendPageContent(filePath, pageNum, func(page *model.PdfPage, pageHeight float64) (*contentstream.ContentCreator, error) {
+		return buildDrawingContent(page, pageHeight, drawing)
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Logger.Debug("Desenho adicionado ao PDF",
+		zap.String("file", filePath),
+		zap.Int("page", pageNum),
+		zap.String("shape", string(drawing.Shape)),
+		zap.Int("points", len(drawing.Points)),
+	)
+
+	return nil
+}
+
+// validateDrawing valida os campos obrigatórios de cada forma
+func validateDrawing(drawing appModel.Drawing) error {
+	switch drawing.Shape {
+	case appModel.DrawingShapeRectangle, appModel.DrawingShapeEllipse:
+		if drawing.Width <= 0 || drawing.Height <= 0 {
+			return fmt.Errorf("forma '%s' requer largura e altura maiores que zero", drawing.Shape)
+		}
+	case appModel.DrawingShapeLine:
+		if len(drawing.Points) != 2 {
+			return fmt.Errorf("forma 'line' requer exatamente 2 pontos")
+		}
+	case appModel.DrawingShapePolyline:
+		if len(drawing.Points) < 2 {
+			return fmt.Errorf("forma 'polyline' requer pelo menos 2 pontos")
+		}
+	case appModel.DrawingShapePolygon:
+		if len(drawing.Points) < 3 {
+			return fmt.Errorf("forma 'polygon' requer pelo menos 3 pontos")
+		}
+	default:
+		return fmt.Errorf("forma de desenho desconhecida: %s", drawing.Shape)
+	}
+
+	if drawing.Opacity < 0 || drawing.Opacity > 1 {
+		return fmt.Errorf("opacidade deve estar entre 0 e 1")
+	}
+
+	// Um padrão só com zeros é inválido (ISO 32000-1, 8.4.3.6): exige ao menos um valor positivo
+	dashLength := 0.0
+	for _, value := range drawing.DashPattern {
+		if value < 0 {
+			return fmt.Errorf("padrão de tracejado não pode conter valores negativos")
+		}
+		dashLength += value
+	}
+	if len(drawing.DashPattern) > 0 && dashLength == 0 {
+		return fmt.Errorf("padrão de tracejado deve conter ao menos um valor maior que zero")
+	}
+
+	return nil
+}
+
+// buildDrawingContent gera os operadores vetoriais de uma forma
+func buildDrawingContent(page *model.PdfPage, pageHeight float64, drawing appModel.Drawing) (*contentstream.ContentCreator, error) {
+	hasStroke := drawing.StrokeColor != ""
+	hasFill := drawing.FillColor != "" && drawing.Shape != appModel.DrawingShapeLine
+
+	// Sem cor definida, desenha apenas o contorno em preto
+	if !hasStroke && !hasFill {
+		drawing.StrokeColor = "#000000"
+		hasStroke = true
+	}
+
+	contentCreator := contentstream.NewContentCreator()
+	contentCreator.Add_q() // Save graphics state
+
+	// Aplica opacidade através de um ExtGState
+	if drawing.Opacity < 1 {
+		gsName, err := registerOpacity(page.Resources, drawing.Opacity)
+		if err != nil {
+			return nil, err
+		}
+		contentCreator.Add_gs(gsName)
+	}
+
+	if hasStroke {
+		stroke, err := parseHexColor(drawing.StrokeColor)
+		if err != nil {
+			return nil, err
+		}
+		contentCreator.Add_RG(stroke.R, stroke.G, stroke.B)
+
+		lineWidth := drawing.LineWidth
+		if lineWidth <= 0 {
+			lineWidth = 1.0 // Espessura padrão
+		}
+		contentCreator.Add_w(lineWidth)
+
+		if len(drawing.DashPattern) > 0 {
+			dashArray := make([]float64, len(drawing.DashPattern))
+			copy(dashArray, drawing.DashPattern)
+			contentCreator.AddOperand(contentstream.ContentStreamOperation{
+				Operand: "d",
+				Params:  []core.PdfObject{core.MakeArrayFromFloats(dashArray), core.MakeInteger(0)},
+			})
+		}
+
+		// Traços livres ficam mais naturais com pontas e junções arredondadas
+		if drawing.Shape == appModel.DrawingShapePolyline {
+			// Add_J/Add_j emitem nomes, mas os operadores exigem inteiros
+			contentCreator.AddOperand(contentstream.ContentStreamOperation{Operand: "J", Params: []core.PdfObject{core.MakeInteger(1)}})
+			contentCreator.AddOperand(contentstream.ContentStreamOperation{Operand: "j", Params: []core.PdfObject{core.MakeInteger(1)}})
+		}
+	}
+
+	if hasFill {
+		fill, err := parseHexColor(drawing.FillColor)
+		if err != nil {
+			return nil, err
+		}
+		contentCreator.Add_rg(fill.R, fill.G, fill.B)
+	}
+
+	// Constrói o caminho convertendo y do topo para a base da página
+	switch drawing.Shape {
+	case appModel.DrawingShapeRectangle:
+		contentCreator.Add_re(drawing.X, pageHeight-drawing.Y-drawing.Height, drawing.Width, drawing.Height)
+
+	case appModel.DrawingShapeEllipse:
+		rx := drawing.Width / 2
+		ry := drawing.Height / 2
+		cx := drawing.X + rx
+		cy := pageHeight - drawing.Y - ry
+		ox := rx * bezierKappa
+		oy := ry * bezierKappa
+
+		contentCreator.Add_m(cx+rx, cy)
+		contentCreator.Add_c(cx+rx, cy+oy, cx+ox, cy+ry, cx, cy+ry)
+		contentCreator.Add_c(cx-ox, cy+ry, cx-rx, cy+oy, cx-rx, cy)
+		contentCreator.Add_c(cx-rx, cy-oy, cx-ox, cy-ry, cx, cy-ry)
+		contentCreator.Add_c(cx+ox, cy-ry, cx+rx, cy-oy, cx+rx, cy)
+		contentCreator.Add_h()
+
+	default:
+		first := drawing.Points[0]
+		contentCreator.Add_m(first.X, pageHeight-first.Y)
+		for _, point := range drawing.Points[1:] {
+			contentCreator.Add_l(point.X, pageHeight-point.Y)
+		}
+		if drawing.Shape == appModel.DrawingShapePolygon {
+			contentCreator.Add_h()
+		}
+	}
+
+	// Pinta o caminho conforme contorno/preenchimento
+	switch {
+	case hasStroke && hasFill:
+		contentCreator.Add_B()
+	case hasFill:
+		contentCreator.Add_f()
+	default:
+		contentCreator.Add_S()
+	}
+
+	contentCreator.Add_Q() // Restore graphics state
+
+	return contentCreator, nil
+}
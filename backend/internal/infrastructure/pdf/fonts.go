@@ -0,0 +1,354 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/pkg/logger"
+	pdfcpuFont "github.com/pdfcpu/pdfcpu/pkg/font"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/unidoc/unipdf/v3/model"
+	"go.uber.org/zap"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// DefaultFontFamily é a família Unicode embutida no servidor
+	DefaultFontFamily = "go"
+
+	FontWeightNormal = "normal"
+	FontWeightBold   = "bold"
+)
+
+// fontFace representa uma variação (família + peso) de uma fonte TrueType/OpenType
+type fontFace struct {
+	family   string
+	weight   string
+	data     []byte
+	sfnt     *sfnt.Font
+	userFont string // Nome da fonte instalada no pdfcpu (vazio quando indisponível)
+}
+
+// hasGlyph verifica se a fonte possui um glifo para o caractere
+func (f *fontFace) hasGlyph(r rune) bool {
+	index, err := f.sfnt.GlyphIndex(nil, r)
+	return err == nil && index != 0
+}
+
+// metricsPPEM é a escala usada para obter métricas com precisão suficiente (1000 unidades por em)
+var metricsPPEM = fixed.I(1000)
+
+// advance retorna a largura do caractere em frações de em (1 em = tamanho da fonte)
+func (f *fontFace) advance(r rune) float64 {
+	index, err := f.sfnt.GlyphIndex(nil, r)
+	if err != nil {
+		return 0
+	}
+
+	adv, err := f.sfnt.GlyphAdvance(nil, index, metricsPPEM, font.HintingNone)
+	if err != nil {
+		return 0
+	}
+
+	return float64(adv) / 64 / 1000
+}
+
+// ascent retorna a altura acima da linha de base em frações de em
+func (f *fontFace) ascent() float64 {
+	metrics, err := f.sfnt.Metrics(nil, metricsPPEM, font.HintingNone)
+	if err != nil {
+		return 0.8 // Valor típico quando a fonte não informa métricas
+	}
+
+	return float64(metrics.Ascent) / 64 / 1000
+}
+
+// descent retorna a profundidade abaixo da linha de base em frações de em
+func (f *fontFace) descent() float64 {
+	metrics, err := f.sfnt.Metrics(nil, metricsPPEM, font.HintingNone)
+	if err != nil {
+		return 0.2 // Valor típico quando a fonte não informa métricas
+	}
+
+	return float64(metrics.Descent) / 64 / 1000
+}
+
+// newPdfFont cria uma fonte composta (Type0/Identity-H) com ToUnicode, pronta para subsetting
+// Cada escrita deve usar uma instância nova, pois a fonte registra os glifos utilizados
+func (f *fontFace) newPdfFont() (*model.PdfFont, error) {
+	pdfFont, err := model.NewCompositePdfFontFromTTF(bytes.NewReader(f.data))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar fonte %s (%s): %w", f.family, f.weight, err)
+	}
+	return pdfFont, nil
+}
+
+// fontRegistry mantém as fontes disponíveis para inserção de texto
+type fontRegistry struct {
+	faces    map[string]*fontFace // Chave: família|peso
+	families []string             // Ordem de fallback para caracteres sem glifo
+}
+
+// newFontRegistry registra as fontes embutidas e as fontes encontradas no diretório informado
+func newFontRegistry(fontsPath string) (*fontRegistry, error) {
+	registry := &fontRegistry{faces: make(map[string]*fontFace)}
+
+	// Fontes embutidas (Go fonts, cobertura WGL4: latim, grego e cirílico)
+	if err := registry.register(DefaultFontFamily, FontWeightNormal, goregular.TTF); err != nil {
+		return nil, err
+	}
+	if err := registry.register(DefaultFontFamily, FontWeightBold, gobold.TTF); err != nil {
+		return nil, err
+	}
+
+	if fontsPath == "" {
+		return registry, nil
+	}
+
+	entries, err := os.ReadDir(fontsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return registry, nil
+		}
+		return nil, fmt.Errorf("erro ao ler diretório de fontes: %w", err)
+	}
+
+	// Ordena para que o fallback seja determinístico
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".ttf" && ext != ".otf") {
+			continue
+		}
+
+		fontPath := filepath.Join(fontsPath, entry.Name())
+		if err := registry.registerFile(fontPath); err != nil {
+			// Uma fonte inválida não deve impedir o uso das demais
+			logger.Logger.Warn("Fonte ignorada", zap.String("file", fontPath), zap.Error(err))
+		}
+	}
+
+	return registry, nil
+}
+
+// registerFile registra uma fonte a partir do arquivo, usando a família e subfamília da tabela name
+func (r *fontRegistry) registerFile(fontPath string) error {
+	data, err := os.ReadFile(fontPath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler fonte: %w", err)
+	}
+
+	parsed, err := sfnt.Parse(data)
+	if err != nil {
+		return fmt.Errorf("fonte inválida: %w", err)
+	}
+
+	family, err := parsed.Name(nil, sfnt.NameIDTypographicFamily)
+	if err != nil || family == "" {
+		family, err = parsed.Name(nil, sfnt.NameIDFamily)
+		if err != nil {
+			return fmt.Errorf("fonte sem nome de família: %w", err)
+		}
+	}
+
+	subfamily, _ := parsed.Name(nil, sfnt.NameIDTypographicSubfamily)
+	if subfamily == "" {
+		subfamily, _ = parsed.Name(nil, sfnt.NameIDSubfamily)
+	}
+	subfamily = strings.ToLower(subfamily)
+
+	// Variações itálicas não são suportadas
+	if strings.Contains(subfamily, "italic") || strings.Contains(subfamily, "oblique") {
+		return fmt.Errorf("variação itálica não suportada: %s", subfamily)
+	}
+
+	weight := FontWeightNormal
+	if strings.Contains(subfamily, "bold") {
+		weight = FontWeightBold
+	}
+
+	return r.register(family, weight, data)
+}
+
+// register adiciona uma variação de fonte ao registro
+func (r *fontRegistry) register(family, weight string, data []byte) error {
+	parsed, err := sfnt.Parse(data)
+	if err != nil {
+		return fmt.Errorf("fonte inválida: %w", err)
+	}
+
+	family = normalizeFontFamily(family)
+	key := family + "|" + weight
+	if _, exists := r.faces[key]; exists {
+		return nil
+	}
+
+	if !r.hasFamily(family) {
+		r.families = append(r.families, family)
+	}
+
+	r.faces[key] = &fontFace{
+		family: family,
+		weight: weight,
+		data:   data,
+		sfnt:   parsed,
+	}
+
+	return nil
+}
+
+// installUserFonts instala as variações registradas como fontes do usuário do pdfcpu, que as embute como
+// fontes compostas (subset e ToUnicode) em carimbos de texto e campos de formulário fora do WinAnsi
+// Variações que o pdfcpu não consegue ler (ex.: OpenType CFF) ficam de fora sem impedir as demais
+func (r *fontRegistry) installUserFonts() error {
+	// A configuração padrão define o diretório de fontes do pdfcpu e carrega as já instaladas
+	pdfcpuModel.NewDefaultConfiguration()
+	if pdfcpuFont.UserFontDir == "" {
+		return fmt.Errorf("diretório de fontes do pdfcpu não configurado")
+	}
+
+	installed := make(map[*fontFace]string)
+	for _, face := range r.faces {
+		name, err := face.sfnt.Name(nil, sfnt.NameIDPostScript)
+		if err != nil || name == "" {
+			logger.Logger.Warn("Fonte sem nome PostScript", zap.String("family", face.family), zap.String("weight", face.weight))
+			continue
+		}
+		if !pdfcpuFont.IsUserFont(name) {
+			if err := pdfcpuFont.InstallFontFromBytes(pdfcpuFont.UserFontDir, name, face.data); err != nil {
+				logger.Logger.Warn("Fonte não instalada no pdfcpu", zap.String("font", name), zap.Error(err))
+				continue
+			}
+		}
+		installed[face] = name
+	}
+
+	if err := pdfcpuFont.LoadUserFonts(); err != nil {
+		return fmt.Errorf("erro ao carregar fontes do pdfcpu: %w", err)
+	}
+
+	for face, name := range installed {
+		if pdfcpuFont.IsUserFont(name) {
+			face.userFont = name
+		}
+	}
+	return nil
+}
+
+// hasFamily verifica se a família está registrada
+func (r *fontRegistry) hasFamily(family string) bool {
+	for _, registered := range r.families {
+		if registered == family {
+			return true
+		}
+	}
+	return false
+}
+
+// face retorna a variação de uma família, usando o peso normal quando o pedido não existe
+func (r *fontRegistry) face(family, weight string) (*fontFace, error) {
+	family = normalizeFontFamily(family)
+	if family == "" {
+		family = DefaultFontFamily
+	}
+	if weight == "" {
+		weight = FontWeightNormal
+	}
+
+	if face, ok := r.faces[family+"|"+weight]; ok {
+		return face, nil
+	}
+	if face, ok := r.faces[family+"|"+FontWeightNormal]; ok {
+		return face, nil
+	}
+	if face, ok := r.faces[family+"|"+FontWeightBold]; ok {
+		return face, nil
+	}
+
+	return nil, fmt.Errorf("família de fonte não encontrada: %s", family)
+}
+
+// textRun representa um trecho de texto desenhado com uma única fonte
+type textRun struct {
+	face *fontFace
+	text string
+}
+
+// splitRuns divide o texto em trechos, usando fontes de fallback para caracteres
+// que não existem na fonte escolhida (ex.: CJK quando há uma fonte CJK instalada)
+// Caracteres sem glifo em nenhuma fonte (exceto espaços e controles) são recusados com a lista deles
+func (r *fontRegistry) splitRuns(text, family, weight string) ([]textRun, error) {
+	primary, err := r.face(family, weight)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []textRun
+	var missing []string
+	for _, char := range text {
+		face := r.faceForRune(primary, char)
+		if face == primary && !primary.hasGlyph(char) && !unicode.IsSpace(char) && !unicode.IsControl(char) {
+			if description := fmt.Sprintf("%q (%U)", char, char); !slices.Contains(missing, description) {
+				missing = append(missing, description)
+			}
+		}
+
+		if len(runs) > 0 && runs[len(runs)-1].face == face {
+			runs[len(runs)-1].text += string(char)
+			continue
+		}
+		runs = append(runs, textRun{face: face, text: string(char)})
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s; instale em FONTS_PATH uma fonte que os contenha", domain.ErrUnsupportedCharacters, strings.Join(missing, ", "))
+	}
+
+	return runs, nil
+}
+
+// faceForRune escolhe a primeira fonte com glifo para o caractere, preferindo a fonte principal
+func (r *fontRegistry) faceForRune(primary *fontFace, char rune) *fontFace {
+	if primary.hasGlyph(char) {
+		return primary
+	}
+
+	for _, family := range r.families {
+		if family == primary.family {
+			continue
+		}
+		face, err := r.face(family, primary.weight)
+		if err == nil && face.hasGlyph(char) {
+			return face
+		}
+	}
+
+	// Nenhuma fonte cobre o caractere: mantém a principal (splitRuns recusa o texto; measure usa o .notdef)
+	return primary
+}
+
+// measure retorna a largura do texto em frações de em, considerando as fontes de fallback
+func (r *fontRegistry) measure(text string, primary *fontFace) float64 {
+	width := 0.0
+	for _, char := range text {
+		width += r.faceForRune(primary, char).advance(char)
+	}
+	return width
+}
+
+// normalizeFontFamily normaliza o nome da família para comparação
+func normalizeFontFamily(family string) string {
+	return strings.ToLower(strings.TrimSpace(family))
+}
@@ -0,0 +1,71 @@
+package pdf
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func TestSplitRunsRejectsUncoveredCharacters(t *testing.T) {
+	registry, err := newFontRegistry("")
+	if err != nil {
+		t.Fatalf("erro ao criar registro de fontes: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		text    string
+		missing []string // Vazio quando o texto é aceito
+	}{
+		{"latim, grego e cirílico", "Łukasz Żółć, Ελλάδα, Москва", nil},
+		{"espaços e controles", "a b\tc", nil},
+		{"CJK", "Contrato 契約", []string{"U+5951", "U+7D04"}},
+		{"emoji repetido", "Aprovado 👍👍", []string{"U+1F44D"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs, err := registry.splitRuns(tt.text, DefaultFontFamily, FontWeightNormal)
+			if len(tt.missing) == 0 {
+				if err != nil {
+					t.Fatalf("splitRuns(%q): %v", tt.text, err)
+				}
+				if len(runs) == 0 {
+					t.Fatalf("splitRuns(%q) sem trechos", tt.text)
+				}
+				return
+			}
+
+			if !errors.Is(err, domain.ErrUnsupportedCharacters) {
+				t.Fatalf("esperado ErrUnsupportedCharacters, obtido %v", err)
+			}
+			for _, code := range tt.missing {
+				if strings.Count(err.Error(), code) != 1 {
+					t.Errorf("%s deveria aparecer uma vez em %q", code, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestAddWatermarkRejectsUncoveredCharacters(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	processor, err := NewPDFCPUProcessor("")
+	if err != nil {
+		t.Fatalf("erro ao criar processador: %v", err)
+	}
+	p := processor.(*PDFCPUProcessor)
+
+	filePath := newBlankPDF(t, 1)
+	err = p.AddWatermark(context.Background(), filePath, nil, appModel.Watermark{Text: "機密", Opacity: 0.5})
+	if !errors.Is(err, domain.ErrUnsupportedCharacters) {
+		t.Fatalf("esperado ErrUnsupportedCharacters, obtido %v", err)
+	}
+}
@@ -0,0 +1,1186 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/form"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// Flags de campo (entrada Ff) usados na classificação dos campos
+const (
+	fieldFlagReadOnly    = 1 << 0
+	fieldFlagRequired    = 1 << 1
+	fieldFlagMultiline   = 1 << 12
+	fieldFlagRadio       = 1 << 15
+	fieldFlagPushButton  = 1 << 16
+	fieldFlagCombo       = 1 << 17
+	fieldFlagEdit        = 1 << 18
+	fieldFlagMultiSelect = 1 << 21
+)
+
+// maxFieldDepth limita a recursão na árvore de campos do formulário
+const maxFieldDepth = 32
+
+// Flags de anotação (entrada F) que ocultam o widget
+const (
+	annotationFlagHidden = 1 << 1
+	annotationFlagNoView = 1 << 5
+)
+
+// dateFormatPattern localiza o formato das ações JavaScript de data do Acrobat
+var dateFormatPattern = regexp.MustCompile(`AFDate_Format(?:Ex)?\(\s*"([^"]*)"`)
+
+// ListFormFields lista os campos do formulário (AcroForm) com valores, opções e widgets
+// Retorna uma lista vazia quando o PDF não possui formulário
+func (p *PDFCPUProcessor) ListFormFields(ctx context.Context, filePath string) ([]appModel.FormField, error) {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	reader, err := newFormReader(pdfCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]appModel.FormField, 0)
+	if reader.acroForm == nil {
+		return fields, nil
+	}
+
+	rootFields, err := pdfCtx.DereferenceArray(reader.acroForm["Fields"])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler campos do formulário: %w", err)
+	}
+
+	if err := reader.walk(rootFields, "", reader.rootAttrs(), &fields, 0); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// FillForm preenche campos do formulário pelo nome completo e regenera as aparições
+// Valores aceitos: string para texto, data, radio e combo; bool (ou "true"/"false") para checkbox;
+// string ou lista de strings para list
+func (p *PDFCPUProcessor) FillForm(ctx context.Context, filePath string, values map[string]interface{}) error {
+	if len(values) == 0 {
+		return fmt.Errorf("nenhum campo informado para preenchimento")
+	}
+
+	config := pdfcpuModel.NewDefaultConfiguration()
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao abrir PDF: %w", err)
+	}
+	group, err := api.ExportForm(file, filepath.Base(filePath), config)
+	file.Close()
+	if err != nil {
+		return fmt.Errorf("erro ao ler formulário: %w", err)
+	}
+	if group == nil || len(group.Forms) == 0 {
+		return fmt.Errorf("PDF não possui campos de formulário preenchíveis")
+	}
+
+	// Monta um formulário apenas com os campos alterados; os demais permanecem intactos
+	fill, combos, matched, err := formFillData(group.Forms[0], values)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for name := range values {
+		if !matched[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("campos de formulário não encontrados: %s", strings.Join(missing, ", "))
+	}
+
+	if len(combos) < len(matched) {
+		data, err := json.Marshal(form.FormGroup{Header: group.Header, Forms: []form.Form{fill}})
+		if err != nil {
+			return fmt.Errorf("erro ao serializar valores do formulário: %w", err)
+		}
+
+		err = rewriteFile(filePath, func(in *os.File, out *os.File) error {
+			return api.FillForm(in, bytes.NewReader(data), out, config)
+		})
+		// Valores iguais aos atuais não alteram o PDF, que permanece como está
+		if err != nil && !errors.Is(err, api.ErrNoFormFieldsAffected) {
+			return fmt.Errorf("erro ao preencher formulário: %w", err)
+		}
+	}
+
+	// O pdfcpu não regenera a aparição de listas suspensas nem aceita valores livres em combos editáveis
+	if len(combos) > 0 {
+		if err := fillComboBoxes(filePath, combos); err != nil {
+			return fmt.Errorf("erro ao preencher lista suspensa: %w", err)
+		}
+	}
+
+	logger.Logger.Debug("Formulário preenchido",
+		zap.String("file", filePath),
+		zap.Int("fields_count", len(values)),
+	)
+
+	return nil
+}
+
+// fillComboBoxes define o valor das listas suspensas e regenera suas aparições
+// Com fonte padrão do PDF a aparição é desenhada aqui; com outras fontes fica a cargo do leitor (NeedAppearances)
+func fillComboBoxes(filePath string, values map[string]string) error {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	reader, err := newFormReader(pdfCtx)
+	if err != nil {
+		return err
+	}
+	if reader.acroForm == nil {
+		return fmt.Errorf("PDF não possui formulário")
+	}
+
+	rootFields, err := pdfCtx.DereferenceArray(reader.acroForm["Fields"])
+	if err != nil {
+		return fmt.Errorf("erro ao ler campos do formulário: %w", err)
+	}
+	var fields []appModel.FormField
+	if err := reader.walk(rootFields, "", reader.rootAttrs(), &fields, 0); err != nil {
+		return err
+	}
+
+	for name, value := range values {
+		field, ok := reader.terminals[name]
+		if !ok {
+			return fmt.Errorf("campo de formulário não encontrado: %s", name)
+		}
+
+		// Índice da opção selecionada (I), ausente para valores livres
+		index := -1
+		for i, option := range reader.choiceOptions(field.attrs.options) {
+			if option.Value == value {
+				index = i
+				break
+			}
+		}
+
+		if value == "" {
+			field.dict.Delete("V")
+		} else {
+			field.dict.Update("V", pdfTextString(value))
+		}
+		if index >= 0 {
+			field.dict.Update("I", types.Array{types.Integer(index)})
+		} else {
+			field.dict.Delete("I")
+		}
+
+		for _, widgetObj := range field.widgets {
+			widget, err := pdfCtx.DereferenceDict(widgetObj)
+			if err != nil || widget == nil {
+				continue
+			}
+			if !reader.refreshTextAppearance(widget, field.attrs.da, value) {
+				widget.Delete("AP")
+				reader.acroForm.Update("NeedAppearances", types.Boolean(true))
+			}
+		}
+	}
+
+	return rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		return api.WriteContext(pdfCtx, out)
+	})
+}
+
+// daFontPattern lê o operador de fonte (/Nome tamanho Tf) da aparência padrão
+var daFontPattern = regexp.MustCompile(`/(\S+)\s+([0-9.]+)\s+Tf`)
+
+// refreshTextAppearance redesenha a aparição de um widget de texto de linha única com o valor informado
+// Retorna false quando a fonte do campo não é uma fonte padrão do PDF
+func (r *formReader) refreshTextAppearance(widget types.Dict, da, value string) bool {
+	match := daFontPattern.FindStringSubmatch(da)
+	if match == nil {
+		return false
+	}
+	fontSize, _ := strconv.ParseFloat(match[2], 64)
+
+	resources, err := r.ctx.DereferenceDict(r.acroForm["DR"])
+	if err != nil || resources == nil {
+		return false
+	}
+	fonts, err := r.ctx.DereferenceDict(resources["Font"])
+	if err != nil || fonts == nil {
+		return false
+	}
+	fontObj, found := fonts.Find(match[1])
+	if !found {
+		return false
+	}
+	fontDict, err := r.ctx.DereferenceDict(fontObj)
+	if err != nil || fontDict == nil {
+		return false
+	}
+
+	baseFont := fontDict.NameEntry("BaseFont")
+	if baseFont == nil {
+		return false
+	}
+	var selected *fieldFont
+	for _, candidate := range fieldFonts {
+		if candidate.baseFont == *baseFont {
+			selected = &fieldFont{baseFont: candidate.baseFont, resName: match[1]}
+			break
+		}
+	}
+	if selected == nil {
+		return false
+	}
+
+	rect, ok := dictRect(r.ctx, widget, "Rect")
+	if !ok || rect.isEmpty() {
+		return false
+	}
+	width, height := rect.urx-rect.llx, rect.ury-rect.lly
+
+	content, err := textAppearance(value, false, fontSize, *selected, width, height)
+	if err != nil {
+		return false
+	}
+	appearance, err := newAppearanceStream(r.ctx, content, width, height, types.Dict{selected.resName: fontObj})
+	if err != nil {
+		return false
+	}
+
+	widget.Update("AP", types.Dict{"N": appearance})
+	return true
+}
+
+// FlattenForm converte as aparições dos campos em conteúdo estático das páginas e remove o formulário
+func (p *PDFCPUProcessor) FlattenForm(ctx context.Context, filePath string) error {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return fmt.Errorf("erro ao ler catálogo do PDF: %w", err)
+	}
+
+	if _, found := rootDict.Find("AcroForm"); !found {
+		return fmt.Errorf("PDF não possui formulário")
+	}
+
+	flattened := 0
+	for pageNum := 1; pageNum <= pdfCtx.PageCount; pageNum++ {
+		count, err := flattenPageWidgets(pdfCtx, pageNum)
+		if err != nil {
+			return fmt.Errorf("erro ao achatar formulário na página %d: %w", pageNum, err)
+		}
+		flattened += count
+	}
+
+	rootDict.Delete("AcroForm")
+	pdfCtx.Form = nil
+
+	if err := rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		return api.WriteContext(pdfCtx, out)
+	}); err != nil {
+		return fmt.Errorf("erro ao salvar PDF: %w", err)
+	}
+
+	logger.Logger.Debug("Formulário achatado",
+		zap.String("file", filePath),
+		zap.Int("widgets_count", flattened),
+	)
+
+	return nil
+}
+
+// flattenPageWidgets desenha as aparições dos widgets de uma página no seu conteúdo e os remove das anotações
+func flattenPageWidgets(pdfCtx *pdfcpuModel.Context, pageNum int) (int, error) {
+	pageDict, _, inherited, err := pdfCtx.PageDict(pageNum, false)
+	if err != nil {
+		return 0, err
+	}
+
+	annots, err := pdfCtx.DereferenceArray(pageDict["Annots"])
+	if err != nil || len(annots) == 0 {
+		return 0, err
+	}
+
+	var (
+		kept    types.Array
+		content strings.Builder
+		forms   = make(map[string]types.IndirectRef)
+	)
+
+	for _, annot := range annots {
+		annotDict, err := pdfCtx.DereferenceDict(annot)
+		if err != nil || annotDict == nil || !isWidget(annotDict) {
+			kept = append(kept, annot)
+			continue
+		}
+
+		appearance, ok := widgetAppearance(pdfCtx, annotDict)
+		if !ok {
+			continue
+		}
+
+		flags := 0
+		if f := annotDict.IntEntry("F"); f != nil {
+			flags = *f
+		}
+		if flags&(annotationFlagHidden|annotationFlagNoView) != 0 {
+			continue
+		}
+
+		rect, ok := dictRect(pdfCtx, annotDict, "Rect")
+		if !ok || rect.isEmpty() {
+			continue
+		}
+
+		placement, ok := appearancePlacement(pdfCtx, appearance.dict, rect)
+		if !ok {
+			continue
+		}
+
+		// Aparições são form XObjects; garante as entradas exigidas para uso com Do
+		appearance.dict.Insert("Type", types.Name("XObject"))
+		appearance.dict.Insert("Subtype", types.Name("Form"))
+
+		name := fmt.Sprintf("FlatForm%d", len(forms)+1)
+		forms[name] = appearance.ref
+		fmt.Fprintf(&content, "q %s cm /%s Do Q\n", formatMatrix(placement), name)
+	}
+
+	if len(forms) == 0 && len(kept) == len(annots) {
+		return 0, nil
+	}
+
+	if len(forms) > 0 {
+		if err := addPageXObjects(pdfCtx, pageDict, inherited, forms); err != nil {
+			return 0, err
+		}
+		if err := appendIsolatedContent(pdfCtx, pageDict, []byte(content.String())); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(kept) > 0 {
+		pageDict.Update("Annots", kept)
+	} else {
+		pageDict.Delete("Annots")
+	}
+
+	return len(forms), nil
+}
+
+// widgetStream é o stream de aparição de um widget e sua referência indireta
+type widgetStream struct {
+	dict types.Dict
+	ref  types.IndirectRef
+}
+
+// widgetAppearance retorna a aparição normal (AP /N) do widget no estado atual (AS)
+func widgetAppearance(pdfCtx *pdfcpuModel.Context, annotDict types.Dict) (widgetStream, bool) {
+	apDict, err := pdfCtx.DereferenceDict(annotDict["AP"])
+	if err != nil || apDict == nil {
+		return widgetStream{}, false
+	}
+
+	normal, found := apDict.Find("N")
+	if !found {
+		return widgetStream{}, false
+	}
+
+	// Aparições com estados (checkbox e radio) são selecionadas pela entrada AS
+	if states, err := pdfCtx.DereferenceDict(normal); err == nil && states != nil {
+		state := annotDict.NameEntry("AS")
+		if state == nil {
+			return widgetStream{}, false
+		}
+		normal, found = states.Find(*state)
+		if !found {
+			return widgetStream{}, false
+		}
+	}
+
+	ref, ok := normal.(types.IndirectRef)
+	if !ok {
+		return widgetStream{}, false
+	}
+
+	streamDict, _, err := pdfCtx.DereferenceStreamDict(ref)
+	if err != nil || streamDict == nil {
+		return widgetStream{}, false
+	}
+
+	return widgetStream{dict: streamDict.Dict, ref: ref}, true
+}
+
+// appearancePlacement calcula a matriz que posiciona a aparição no retângulo do widget
+// (BBox transformada pela Matrix da aparição é ajustada ao Rect, como definido na especificação)
+func appearancePlacement(pdfCtx *pdfcpuModel.Context, appearance types.Dict, rect bbox) (matrix, bool) {
+	formBox, ok := dictRect(pdfCtx, appearance, "BBox")
+	if !ok {
+		return identityMatrix, false
+	}
+
+	formMatrix := identityMatrix
+	if values, err := pdfCtx.DereferenceArray(appearance["Matrix"]); err == nil && len(values) == 6 {
+		for i, value := range values {
+			number, err := pdfCtx.DereferenceNumber(value)
+			if err != nil {
+				return identityMatrix, false
+			}
+			formMatrix[i] = number
+		}
+	}
+
+	transformed := formBox.transform(formMatrix)
+	width, height := transformed.urx-transformed.llx, transformed.ury-transformed.lly
+	if width <= 0 || height <= 0 {
+		return identityMatrix, false
+	}
+
+	sx := (rect.urx - rect.llx) / width
+	sy := (rect.ury - rect.lly) / height
+	return matrix{sx, 0, 0, sy, rect.llx - transformed.llx*sx, rect.lly - transformed.lly*sy}, true
+}
+
+// addPageXObjects registra form XObjects nos recursos da página
+// Recursos herdados são copiados para a própria página antes da alteração
+func addPageXObjects(pdfCtx *pdfcpuModel.Context, pageDict types.Dict, inherited *pdfcpuModel.InheritedPageAttrs, xobjects map[string]types.IndirectRef) error {
+	resources, err := pdfCtx.DereferenceDict(pageDict["Resources"])
+	if err != nil {
+		return err
+	}
+	if resources == nil {
+		resources = types.Dict{}
+		if inherited != nil && inherited.Resources != nil {
+			resources = inherited.Resources.Clone().(types.Dict)
+		}
+		pageDict.Update("Resources", resources)
+	}
+
+	xobjectDict, err := pdfCtx.DereferenceDict(resources["XObject"])
+	if err != nil {
+		return err
+	}
+	if xobjectDict == nil {
+		xobjectDict = types.Dict{}
+		resources.Update("XObject", xobjectDict)
+	}
+
+	for name, ref := range xobjects {
+		xobjectDict.Update(name, ref)
+	}
+
+	return nil
+}
+
+// appendIsolatedContent acrescenta conteúdo à página isolando o conteúdo existente com q/Q
+func appendIsolatedContent(pdfCtx *pdfcpuModel.Context, pageDict types.Dict, content []byte) error {
+	newStream := func(data []byte) (*types.IndirectRef, error) {
+		streamDict, err := pdfCtx.NewStreamDictForBuf(data)
+		if err != nil {
+			return nil, err
+		}
+		if err := streamDict.Encode(); err != nil {
+			return nil, err
+		}
+		return pdfCtx.IndRefForNewObject(*streamDict)
+	}
+
+	var existing types.Array
+	if obj, found := pageDict.Find("Contents"); found {
+		if array, err := pdfCtx.DereferenceArray(obj); err == nil && array != nil {
+			existing = array
+		} else {
+			existing = types.Array{obj}
+		}
+	}
+
+	before, err := newStream([]byte("q\n"))
+	if err != nil {
+		return err
+	}
+	after, err := newStream(append([]byte("\nQ\n"), content...))
+	if err != nil {
+		return err
+	}
+
+	contents := types.Array{*before}
+	contents = append(contents, existing...)
+	contents = append(contents, *after)
+	pageDict.Update("Contents", contents)
+
+	return nil
+}
+
+// inheritedFieldAttrs são os atributos herdados pelos campos filhos
+type inheritedFieldAttrs struct {
+	fieldType string
+	flags     int
+	value     types.Object
+	def       types.Object
+	maxLength int
+	options   types.Object
+	da        string // Aparência padrão (fonte, tamanho e cor do texto)
+}
+
+// formReader percorre a árvore de campos do AcroForm
+type formReader struct {
+	ctx        *pdfcpuModel.Context
+	acroForm   types.Dict
+	widgetPage map[int]int // Número do objeto do widget → página
+	pageNumber map[int]int // Número do objeto da página → página
+	mediaBoxes map[int]bbox
+	terminals  map[string]terminalField // Campos terminais pelo nome completo
+}
+
+// terminalField é um campo terminal com seu dicionário, widgets e atributos herdados
+type terminalField struct {
+	dict    types.Dict
+	widgets types.Array
+	attrs   inheritedFieldAttrs
+}
+
+// newFormReader prepara o leitor, mapeando widgets e páginas
+func newFormReader(pdfCtx *pdfcpuModel.Context) (*formReader, error) {
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler catálogo do PDF: %w", err)
+	}
+
+	acroForm, err := pdfCtx.DereferenceDict(rootDict["AcroForm"])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler formulário: %w", err)
+	}
+
+	reader := &formReader{
+		ctx:        pdfCtx,
+		acroForm:   acroForm,
+		widgetPage: make(map[int]int),
+		pageNumber: make(map[int]int),
+		mediaBoxes: make(map[int]bbox),
+		terminals:  make(map[string]terminalField),
+	}
+
+	for pageNum := 1; pageNum <= pdfCtx.PageCount; pageNum++ {
+		pageDict, pageRef, inherited, err := pdfCtx.PageDict(pageNum, false)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler página %d: %w", pageNum, err)
+		}
+
+		if pageRef != nil {
+			reader.pageNumber[pageRef.ObjectNumber.Value()] = pageNum
+		}
+		if inherited != nil && inherited.MediaBox != nil {
+			box := inherited.MediaBox
+			reader.mediaBoxes[pageNum] = bbox{box.LL.X, box.LL.Y, box.UR.X, box.UR.Y}
+		}
+
+		annots, _ := pdfCtx.DereferenceArray(pageDict["Annots"])
+		for _, annot := range annots {
+			if ref, ok := annot.(types.IndirectRef); ok {
+				reader.widgetPage[ref.ObjectNumber.Value()] = pageNum
+			}
+		}
+	}
+
+	return reader, nil
+}
+
+// walk percorre os campos acumulando os campos terminais (que possuem valor)
+func (r *formReader) walk(fields types.Array, parentName string, inherited inheritedFieldAttrs, result *[]appModel.FormField, depth int) error {
+	if depth > maxFieldDepth {
+		return fmt.Errorf("árvore de campos do formulário muito profunda")
+	}
+
+	for _, fieldObj := range fields {
+		fieldDict, err := r.ctx.DereferenceDict(fieldObj)
+		if err != nil || fieldDict == nil {
+			continue
+		}
+
+		name := parentName
+		if partial, err := r.text(fieldDict["T"]); err == nil && partial != "" {
+			if name != "" {
+				name += "."
+			}
+			name += partial
+		}
+
+		attrs := r.inherit(fieldDict, inherited)
+
+		// Filhos com nome são campos; filhos sem nome são widgets deste campo
+		kids, _ := r.ctx.DereferenceArray(fieldDict["Kids"])
+		var childFields, widgets types.Array
+		for _, kid := range kids {
+			kidDict, err := r.ctx.DereferenceDict(kid)
+			if err != nil || kidDict == nil {
+				continue
+			}
+			if _, hasName := kidDict.Find("T"); hasName {
+				childFields = append(childFields, kid)
+			} else {
+				widgets = append(widgets, kid)
+			}
+		}
+
+		if len(childFields) > 0 {
+			if err := r.walk(childFields, name, attrs, result, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Campo terminal sem filhos: o próprio dicionário é o widget
+		if len(kids) == 0 {
+			widgets = types.Array{fieldObj}
+		}
+
+		r.terminals[name] = terminalField{dict: fieldDict, widgets: widgets, attrs: attrs}
+		*result = append(*result, r.field(name, fieldDict, attrs, widgets))
+	}
+
+	return nil
+}
+
+// rootAttrs retorna os atributos definidos no próprio AcroForm (aparência padrão)
+func (r *formReader) rootAttrs() inheritedFieldAttrs {
+	var attrs inheritedFieldAttrs
+	if da, err := r.text(r.acroForm["DA"]); err == nil {
+		attrs.da = da
+	}
+	return attrs
+}
+
+// inherit combina os atributos herdáveis do campo com os do campo pai
+func (r *formReader) inherit(fieldDict types.Dict, parent inheritedFieldAttrs) inheritedFieldAttrs {
+	attrs := parent
+	if fieldType := fieldDict.NameEntry("FT"); fieldType != nil {
+		attrs.fieldType = *fieldType
+	}
+	if flags, err := r.ctx.DereferenceInteger(fieldDict["Ff"]); err == nil && flags != nil {
+		attrs.flags = flags.Value()
+	}
+	if value, found := fieldDict.Find("V"); found {
+		attrs.value = value
+	}
+	if def, found := fieldDict.Find("DV"); found {
+		attrs.def = def
+	}
+	if maxLength, err := r.ctx.DereferenceInteger(fieldDict["MaxLen"]); err == nil && maxLength != nil {
+		attrs.maxLength = maxLength.Value()
+	}
+	if options, found := fieldDict.Find("Opt"); found {
+		attrs.options = options
+	}
+	if da, err := r.text(fieldDict["DA"]); err == nil {
+		attrs.da = da
+	}
+	return attrs
+}
+
+// field monta o campo terminal com tipo, valores, opções e widgets
+func (r *formReader) field(name string, fieldDict types.Dict, attrs inheritedFieldAttrs, widgetObjs types.Array) appModel.FormField {
+	field := appModel.FormField{
+		Name:     name,
+		ReadOnly: attrs.flags&fieldFlagReadOnly != 0,
+		Required: attrs.flags&fieldFlagRequired != 0,
+		Widgets:  make([]appModel.FormWidget, 0, len(widgetObjs)),
+	}
+	if label, err := r.text(fieldDict["TU"]); err == nil {
+		field.Label = label
+	}
+
+	for _, widgetObj := range widgetObjs {
+		if widget, ok := r.widget(widgetObj); ok {
+			field.Widgets = append(field.Widgets, widget)
+		}
+	}
+
+	switch attrs.fieldType {
+	case "Tx":
+		field.Type = appModel.FormFieldText
+		field.Multiline = attrs.flags&fieldFlagMultiline != 0
+		field.MaxLength = attrs.maxLength
+		field.Value = r.textValue(attrs.value)
+		if attrs.def != nil {
+			field.DefaultValue = r.textValue(attrs.def)
+		}
+		if dateFormat := r.dateFormat(fieldDict); dateFormat != "" {
+			field.Type = appModel.FormFieldDate
+			field.DateFormat = dateFormat
+		}
+
+	case "Btn":
+		switch {
+		case attrs.flags&fieldFlagPushButton != 0:
+			field.Type = appModel.FormFieldButton
+		case attrs.flags&fieldFlagRadio != 0:
+			field.Type = appModel.FormFieldRadio
+			field.Value = r.stateValue(attrs.value)
+			for _, widget := range field.Widgets {
+				option := appModel.FormOption{Value: widget.OnState}
+				if widget.OnState != "" && !slices.Contains(field.Options, option) {
+					field.Options = append(field.Options, option)
+				}
+			}
+		default:
+			field.Type = appModel.FormFieldCheckbox
+			field.Value = r.stateValue(attrs.value) != ""
+			if attrs.def != nil {
+				field.DefaultValue = r.stateValue(attrs.def) != ""
+			}
+		}
+
+	case "Ch":
+		field.Type = appModel.FormFieldList
+		if attrs.flags&fieldFlagCombo != 0 {
+			field.Type = appModel.FormFieldCombo
+			field.Editable = attrs.flags&fieldFlagEdit != 0
+		}
+		field.MultiSelect = field.Type == appModel.FormFieldList && attrs.flags&fieldFlagMultiSelect != 0
+		field.Options = r.choiceOptions(attrs.options)
+
+		values := r.choiceValues(attrs.value)
+		if field.MultiSelect {
+			field.Value = values
+		} else {
+			field.Value = ""
+			if len(values) > 0 {
+				field.Value = values[0]
+			}
+		}
+
+	case "Sig":
+		field.Type = appModel.FormFieldSignature
+		field.Value = attrs.value != nil
+	}
+
+	return field
+}
+
+// widget lê a página, o retângulo e o estado marcado de um widget
+func (r *formReader) widget(widgetObj types.Object) (appModel.FormWidget, bool) {
+	widgetDict, err := r.ctx.DereferenceDict(widgetObj)
+	if err != nil || widgetDict == nil {
+		return appModel.FormWidget{}, false
+	}
+
+	pageNum := 0
+	if ref, ok := widgetObj.(types.IndirectRef); ok {
+		pageNum = r.widgetPage[ref.ObjectNumber.Value()]
+	}
+	if pageNum == 0 {
+		if pageRef := widgetDict.IndirectRefEntry("P"); pageRef != nil {
+			pageNum = r.pageNumber[pageRef.ObjectNumber.Value()]
+		}
+	}
+
+	widget := appModel.FormWidget{Page: pageNum}
+
+	if rect, ok := dictRect(r.ctx, widgetDict, "Rect"); ok {
+		mediaBox, ok := r.mediaBoxes[pageNum]
+		if !ok {
+			mediaBox = bbox{0, 0, 0, rect.ury}
+		}
+		widget.TextBox = appModel.TextBox{
+			X:      roundPoints(rect.llx - mediaBox.llx),
+			Y:      roundPoints(mediaBox.ury - rect.ury),
+			Width:  roundPoints(rect.urx - rect.llx),
+			Height: roundPoints(rect.ury - rect.lly),
+		}
+	}
+
+	// O estado marcado é a chave da aparição normal diferente de Off
+	if apDict, err := r.ctx.DereferenceDict(widgetDict["AP"]); err == nil && apDict != nil {
+		if states, err := r.ctx.DereferenceDict(apDict["N"]); err == nil && states != nil {
+			for state := range states {
+				if state != "Off" {
+					widget.OnState = state
+					break
+				}
+			}
+		}
+	}
+
+	return widget, true
+}
+
+// dateFormat retorna o formato de data definido pela ação de formatação do campo
+func (r *formReader) dateFormat(fieldDict types.Dict) string {
+	actions, err := r.ctx.DereferenceDict(fieldDict["AA"])
+	if err != nil || actions == nil {
+		return ""
+	}
+	format, err := r.ctx.DereferenceDict(actions["F"])
+	if err != nil || format == nil {
+		return ""
+	}
+
+	var script string
+	if streamDict, _, err := r.ctx.DereferenceStreamDict(format["JS"]); err == nil && streamDict != nil {
+		if err := streamDict.Decode(); err == nil {
+			script = string(streamDict.Content)
+		}
+	} else if text, err := r.text(format["JS"]); err == nil {
+		script = text
+	}
+
+	if match := dateFormatPattern.FindStringSubmatch(script); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// text lê um objeto de texto (string literal ou hexadecimal)
+func (r *formReader) text(obj types.Object) (string, error) {
+	if obj == nil {
+		return "", fmt.Errorf("texto ausente")
+	}
+	return r.ctx.DereferenceText(obj)
+}
+
+// textValue lê o valor de um campo de texto (rich text em streams é ignorado)
+func (r *formReader) textValue(obj types.Object) string {
+	value, err := r.text(obj)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+// stateValue lê o estado de um botão (nome); "Off" e ausente viram vazio
+func (r *formReader) stateValue(obj types.Object) string {
+	if obj == nil {
+		return ""
+	}
+	resolved, err := r.ctx.Dereference(obj)
+	if err != nil {
+		return ""
+	}
+
+	var value string
+	switch v := resolved.(type) {
+	case types.Name:
+		value = v.Value()
+	default:
+		value, _ = pdfcpuModel.Text(resolved)
+	}
+
+	if value == "Off" {
+		return ""
+	}
+	return value
+}
+
+// choiceValues lê o valor de um campo de escolha (string ou lista de strings)
+func (r *formReader) choiceValues(obj types.Object) []string {
+	values := make([]string, 0)
+	if obj == nil {
+		return values
+	}
+
+	if array, err := r.ctx.DereferenceArray(obj); err == nil && array != nil {
+		for _, item := range array {
+			if value, err := r.text(item); err == nil {
+				values = append(values, value)
+			}
+		}
+		return values
+	}
+
+	if value, err := r.text(obj); err == nil {
+		values = append(values, value)
+	}
+	return values
+}
+
+// choiceOptions lê as opções de um campo de escolha: strings ou pares [valor exportado, texto exibido]
+func (r *formReader) choiceOptions(obj types.Object) []appModel.FormOption {
+	if obj == nil {
+		return nil
+	}
+	array, err := r.ctx.DereferenceArray(obj)
+	if err != nil {
+		return nil
+	}
+
+	options := make([]appModel.FormOption, 0, len(array))
+	for _, item := range array {
+		if pair, err := r.ctx.DereferenceArray(item); err == nil && len(pair) == 2 {
+			value, _ := r.text(pair[0])
+			label, _ := r.text(pair[1])
+			option := appModel.FormOption{Value: value}
+			if label != value {
+				option.Label = label
+			}
+			options = append(options, option)
+			continue
+		}
+		if value, err := r.text(item); err == nil {
+			options = append(options, appModel.FormOption{Value: value})
+		}
+	}
+	return options
+}
+
+// formFillData monta o formulário do pdfcpu com os valores informados, por tipo de campo
+// Listas suspensas são retornadas à parte (preenchidas por fillComboBoxes), junto com os nomes
+// que correspondem a algum campo
+func formFillData(current form.Form, values map[string]interface{}) (form.Form, map[string]string, map[string]bool, error) {
+	var fill form.Form
+	combos := make(map[string]string)
+	matched := make(map[string]bool)
+
+	for _, field := range current.TextFields {
+		if value, ok := values[field.Name]; ok {
+			text, err := formString(field.Name, value)
+			if err != nil {
+				return fill, nil, nil, err
+			}
+			if field.MaxLen > 0 && len([]rune(text)) > field.MaxLen {
+				return fill, nil, nil, fmt.Errorf("campo %s aceita no máximo %d caracteres", field.Name, field.MaxLen)
+			}
+			field.Value = text
+			fill.TextFields = append(fill.TextFields, field)
+			matched[field.Name] = true
+		}
+	}
+
+	for _, field := range current.DateFields {
+		if value, ok := values[field.Name]; ok {
+			text, err := formString(field.Name, value)
+			if err != nil {
+				return fill, nil, nil, err
+			}
+			field.Value = text
+			fill.DateFields = append(fill.DateFields, field)
+			matched[field.Name] = true
+		}
+	}
+
+	for _, field := range current.CheckBoxes {
+		if value, ok := values[field.Name]; ok {
+			checked, err := formBool(field.Name, value)
+			if err != nil {
+				return fill, nil, nil, err
+			}
+			field.Value = checked
+			fill.CheckBoxes = append(fill.CheckBoxes, field)
+			matched[field.Name] = true
+		}
+	}
+
+	for _, field := range current.RadioButtonGroups {
+		if value, ok := values[field.Name]; ok {
+			option, err := formString(field.Name, value)
+			if err != nil {
+				return fill, nil, nil, err
+			}
+			if option != "" && option != "Off" && !slices.Contains(field.Options, option) {
+				return fill, nil, nil, fmt.Errorf("opção inválida para o campo %s: %s", field.Name, option)
+			}
+			if option == "Off" {
+				option = ""
+			}
+			field.Value = option
+			fill.RadioButtonGroups = append(fill.RadioButtonGroups, field)
+			matched[field.Name] = true
+		}
+	}
+
+	for _, field := range current.ComboBoxes {
+		if value, ok := values[field.Name]; ok {
+			option, err := formString(field.Name, value)
+			if err != nil {
+				return fill, nil, nil, err
+			}
+			if option != "" && !field.Editable && !slices.Contains(field.Options, option) {
+				return fill, nil, nil, fmt.Errorf("opção inválida para o campo %s: %s", field.Name, option)
+			}
+			combos[field.Name] = option
+			matched[field.Name] = true
+		}
+	}
+
+	for _, field := range current.ListBoxes {
+		if value, ok := values[field.Name]; ok {
+			options, err := formStrings(field.Name, value)
+			if err != nil {
+				return fill, nil, nil, err
+			}
+			if len(options) > 1 && !field.Multi {
+				return fill, nil, nil, fmt.Errorf("campo %s aceita apenas uma opção", field.Name)
+			}
+			for _, option := range options {
+				if !slices.Contains(field.Options, option) {
+					return fill, nil, nil, fmt.Errorf("opção inválida para o campo %s: %s", field.Name, option)
+				}
+			}
+			field.Values = options
+			fill.ListBoxes = append(fill.ListBoxes, field)
+			matched[field.Name] = true
+		}
+	}
+
+	return fill, combos, matched, nil
+}
+
+// formString converte um valor informado em texto
+func formString(name string, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	}
+	return "", fmt.Errorf("valor inválido para o campo %s", name)
+}
+
+// formStrings converte um valor informado em lista de textos (valor único vira lista de um item)
+func formStrings(name string, value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			text, err := formString(name, item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, text)
+		}
+		return result, nil
+	}
+
+	text, err := formString(name, value)
+	if err != nil {
+		return nil, err
+	}
+	if text == "" {
+		return []string{}, nil
+	}
+	return []string{text}, nil
+}
+
+// formBool converte um valor informado no estado de um checkbox
+// Além de booleanos, aceita textos: vazio, "false", "0", "no", "não" e "Off" desmarcam; os demais
+// (incluindo o nome do estado marcado, como em FDF/XFDF) marcam
+func formBool(name string, value interface{}) (bool, error) {
+	switch v := value.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return v, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		switch strings.ToLower(strings.TrimSpace(v)) {
+		case "", "false", "0", "no", "não", "nao", "off":
+			return false, nil
+		}
+		return true, nil
+	}
+	return false, fmt.Errorf("valor inválido para o campo %s", name)
+}
+
+// isWidget verifica se a anotação é um widget de formulário
+func isWidget(annotDict types.Dict) bool {
+	subtype := annotDict.Subtype()
+	return subtype != nil && *subtype == "Widget"
+}
+
+// dictRect lê um retângulo normalizado (entradas Rect e BBox)
+func dictRect(pdfCtx *pdfcpuModel.Context, dict types.Dict, key string) (bbox, bool) {
+	values, err := pdfCtx.DereferenceArray(dict[key])
+	if err != nil || len(values) != 4 {
+		return bbox{}, false
+	}
+
+	var numbers [4]float64
+	for i, value := range values {
+		number, err := pdfCtx.DereferenceNumber(value)
+		if err != nil {
+			return bbox{}, false
+		}
+		numbers[i] = number
+	}
+
+	return bbox{
+		math.Min(numbers[0], numbers[2]), math.Min(numbers[1], numbers[3]),
+		math.Max(numbers[0], numbers[2]), math.Max(numbers[1], numbers[3]),
+	}, true
+}
+
+// formatMatrix formata uma matriz como operandos de content stream
+func formatMatrix(m matrix) string {
+	parts := make([]string, len(m))
+	for i, value := range m {
+		parts[i] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return strings.Join(parts, " ")
+}
+
+// rewriteFile reescreve o PDF por meio de um arquivo temporário no mesmo diretório
+func rewriteFile(filePath string, write func(in *os.File, out *os.File) error) error {
+	in, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.CreateTemp(filepath.Dir(filePath), "pdf_rewrite_*.pdf")
+	if err != nil {
+		return err
+	}
+	tempPath := out.Name()
+	defer os.Remove(tempPath)
+
+	if err := write(in, out); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	in.Close()
+	return os.Rename(tempPath, filePath)
+}
@@ -0,0 +1,787 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Flag de radio que impede desmarcar todas as opções do grupo
+const fieldFlagNoToggleToOff = 1 << 14
+
+// annotationFlagPrint marca o widget para impressão
+const annotationFlagPrint = 1 << 2
+
+// Aparência padrão dos campos criados
+const (
+	fieldBorderGray     = 0.6
+	fieldPadding        = 2.0
+	fieldMaxAutoSize    = 12.0
+	fieldMinAutoSize    = 4.0
+	fieldLineHeight     = 1.15
+	checkMarkGlyph      = "4" // Marca de seleção em ZapfDingbats
+	radioDotGlyph       = "l" // Círculo preenchido em ZapfDingbats
+	zapfDingbatsName    = "ZapfDingbats"
+	zapfDingbatsResName = "ZaDb"
+)
+
+// fieldFont é uma fonte padrão do PDF (não embutida) disponível para campos de formulário
+// Os nomes de recurso seguem a convenção usada pelos leitores de PDF no dicionário DR
+type fieldFont struct {
+	baseFont string
+	resName  string
+}
+
+// fieldFonts mapeia família|peso para as fontes padrão do PDF
+var fieldFonts = map[string]fieldFont{
+	"helvetica|normal": {"Helvetica", "Helv"},
+	"helvetica|bold":   {"Helvetica-Bold", "HeBo"},
+	"times|normal":     {"Times-Roman", "TiRo"},
+	"times|bold":       {"Times-Bold", "TiBo"},
+	"courier|normal":   {"Courier", "Cour"},
+	"courier|bold":     {"Courier-Bold", "CoBo"},
+}
+
+// resolveFieldFont escolhe a fonte padrão do campo (Helvetica quando a família não é informada)
+func resolveFieldFont(family, weight string) (fieldFont, error) {
+	family = normalizeFontFamily(family)
+	if family == "" {
+		family = "helvetica"
+	}
+	if weight == "" {
+		weight = FontWeightNormal
+	}
+
+	selected, ok := fieldFonts[family+"|"+weight]
+	if !ok {
+		return fieldFont{}, fmt.Errorf("fonte de campo não suportada: %s (use helvetica, times ou courier)", family)
+	}
+	return selected, nil
+}
+
+// AddFormField cria um campo de formulário (AcroForm) com widget e aparição na página
+// Botões de radio com o mesmo nome são adicionados ao mesmo grupo
+func (p *PDFCPUProcessor) AddFormField(ctx context.Context, filePath string, definition appModel.FormFieldDefinition) error {
+	if err := validateFieldDefinition(definition); err != nil {
+		return err
+	}
+
+	fieldFont, err := resolveFieldFont(definition.FontFamily, definition.FontWeight)
+	if err != nil {
+		return err
+	}
+
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	if definition.Page < 1 || definition.Page > pdfCtx.PageCount {
+		return fmt.Errorf("página inválida: %d (PDF tem %d páginas)", definition.Page, pdfCtx.PageCount)
+	}
+
+	pageDict, pageRef, inherited, err := pdfCtx.PageDict(definition.Page, false)
+	if err != nil {
+		return fmt.Errorf("erro ao ler página %d: %w", definition.Page, err)
+	}
+	if inherited == nil || inherited.MediaBox == nil {
+		return fmt.Errorf("página %d não possui MediaBox", definition.Page)
+	}
+
+	// Converte o retângulo (origem no topo) para o espaço do PDF, considerando a origem da MediaBox
+	mediaBox := inherited.MediaBox
+	rect := bbox{
+		llx: mediaBox.LL.X + definition.X,
+		lly: mediaBox.UR.Y - definition.Y - definition.Height,
+		urx: mediaBox.LL.X + definition.X + definition.Width,
+		ury: mediaBox.UR.Y - definition.Y,
+	}
+
+	builder, err := newFieldBuilder(pdfCtx, p.fonts)
+	if err != nil {
+		return err
+	}
+
+	existing, err := builder.findField(definition.Name)
+	if err != nil {
+		return err
+	}
+
+	var widgetRef *types.IndirectRef
+	if definition.Type == appModel.FormFieldRadio {
+		widgetRef, err = builder.addRadioButton(definition, existing, rect, *pageRef)
+	} else {
+		if existing != nil {
+			return fmt.Errorf("já existe um campo com o nome %s", definition.Name)
+		}
+		widgetRef, err = builder.addField(definition, fieldFont, rect, *pageRef)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Widget na lista de anotações da página
+	annots, err := pdfCtx.DereferenceArray(pageDict["Annots"])
+	if err != nil {
+		return fmt.Errorf("erro ao ler anotações da página: %w", err)
+	}
+	pageDict.Update("Annots", append(annots, *widgetRef))
+
+	if err := rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		return api.WriteContext(pdfCtx, out)
+	}); err != nil {
+		return fmt.Errorf("erro ao salvar PDF: %w", err)
+	}
+
+	logger.Logger.Debug("Campo de formulário criado",
+		zap.String("file", filePath),
+		zap.String("name", definition.Name),
+		zap.String("type", string(definition.Type)),
+		zap.Int("page", definition.Page),
+	)
+
+	return nil
+}
+
+// validateFieldDefinition valida a definição do campo antes de abrir o PDF
+func validateFieldDefinition(definition appModel.FormFieldDefinition) error {
+	name := strings.TrimSpace(definition.Name)
+	if name == "" {
+		return fmt.Errorf("nome do campo é obrigatório")
+	}
+	if strings.Contains(name, ".") {
+		return fmt.Errorf("nome do campo não pode conter ponto: %s", name)
+	}
+	if definition.Width <= 0 || definition.Height <= 0 {
+		return fmt.Errorf("campo requer largura e altura maiores que zero")
+	}
+	if definition.MaxLength < 0 {
+		return fmt.Errorf("tamanho máximo do campo não pode ser negativo")
+	}
+
+	switch definition.Type {
+	case appModel.FormFieldText:
+		if definition.MaxLength > 0 && len([]rune(definition.DefaultValue)) > definition.MaxLength {
+			return fmt.Errorf("valor padrão excede o tamanho máximo do campo (%d)", definition.MaxLength)
+		}
+
+	case appModel.FormFieldCheckbox:
+		if _, err := formBool(name, definition.DefaultValue); err != nil {
+			return err
+		}
+
+	case appModel.FormFieldRadio:
+		if definition.ExportValue == "" {
+			return fmt.Errorf("botão de radio requer o valor exportado (exportValue)")
+		}
+
+	case appModel.FormFieldCombo:
+		if len(definition.Options) == 0 {
+			return fmt.Errorf("lista suspensa requer ao menos uma opção")
+		}
+		if definition.DefaultValue != "" && !definition.Editable && !slices.Contains(definition.Options, definition.DefaultValue) {
+			return fmt.Errorf("valor padrão não está entre as opções: %s", definition.DefaultValue)
+		}
+
+	case appModel.FormFieldSignature:
+
+	default:
+		return fmt.Errorf("tipo de campo não suportado para criação: %s", definition.Type)
+	}
+
+	return nil
+}
+
+// fieldBuilder cria campos no AcroForm do documento, criando o formulário quando ausente
+type fieldBuilder struct {
+	ctx      *pdfcpuModel.Context
+	acroForm types.Dict
+	fonts    types.Dict    // Fontes do dicionário DR
+	registry *fontRegistry // Fontes Unicode para valores fora do WinAnsi
+}
+
+// newFieldBuilder obtém (ou cria) o AcroForm com os recursos padrão
+func newFieldBuilder(pdfCtx *pdfcpuModel.Context, registry *fontRegistry) (*fieldBuilder, error) {
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler catálogo do PDF: %w", err)
+	}
+
+	acroForm, err := pdfCtx.DereferenceDict(rootDict["AcroForm"])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler formulário: %w", err)
+	}
+	if acroForm == nil {
+		acroForm = types.Dict{"Fields": types.Array{}}
+		ref, err := pdfCtx.IndRefForNewObject(acroForm)
+		if err != nil {
+			return nil, err
+		}
+		rootDict.Update("AcroForm", *ref)
+	}
+
+	// Formulários XFA dinâmicos ignoram o AcroForm; o XFA é removido para que os novos campos apareçam
+	acroForm.Delete("XFA")
+	acroForm.Delete("NeedAppearances")
+
+	resources, err := pdfCtx.DereferenceDict(acroForm["DR"])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler recursos do formulário: %w", err)
+	}
+	if resources == nil {
+		resources = types.Dict{}
+		acroForm.Update("DR", resources)
+	}
+
+	fonts, err := pdfCtx.DereferenceDict(resources["Font"])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler fontes do formulário: %w", err)
+	}
+	if fonts == nil {
+		fonts = types.Dict{}
+		resources.Update("Font", fonts)
+	}
+
+	builder := &fieldBuilder{ctx: pdfCtx, acroForm: acroForm, fonts: fonts, registry: registry}
+
+	if _, found := acroForm.Find("DA"); !found {
+		if _, err := builder.fontRef(fieldFonts["helvetica|normal"]); err != nil {
+			return nil, err
+		}
+		acroForm.Update("DA", types.StringLiteral("/Helv 0 Tf 0 g"))
+	}
+
+	return builder, nil
+}
+
+// fontRef retorna a fonte registrada no DR, registrando-a quando ausente
+func (b *fieldBuilder) fontRef(selected fieldFont) (types.Object, error) {
+	if ref, found := b.fonts.Find(selected.resName); found {
+		return ref, nil
+	}
+
+	fontDict := types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name(selected.baseFont),
+	}
+	if selected.baseFont != zapfDingbatsName {
+		fontDict["Encoding"] = types.Name("WinAnsiEncoding")
+	}
+
+	ref, err := b.ctx.IndRefForNewObject(fontDict)
+	if err != nil {
+		return nil, err
+	}
+	b.fonts.Update(selected.resName, *ref)
+	return *ref, nil
+}
+
+// findField procura um campo de primeiro nível pelo nome
+func (b *fieldBuilder) findField(name string) (*types.IndirectRef, error) {
+	fields, err := b.ctx.DereferenceArray(b.acroForm["Fields"])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler campos do formulário: %w", err)
+	}
+
+	for _, fieldObj := range fields {
+		fieldDict, err := b.ctx.DereferenceDict(fieldObj)
+		if err != nil || fieldDict == nil {
+			continue
+		}
+		partial, err := b.ctx.DereferenceText(fieldDict["T"])
+		if err != nil || partial != name {
+			continue
+		}
+		if ref, ok := fieldObj.(types.IndirectRef); ok {
+			return &ref, nil
+		}
+		return nil, fmt.Errorf("já existe um campo com o nome %s", name)
+	}
+
+	return nil, nil
+}
+
+// registerField acrescenta o campo à lista de campos do formulário
+func (b *fieldBuilder) registerField(ref types.IndirectRef) error {
+	fields, err := b.ctx.DereferenceArray(b.acroForm["Fields"])
+	if err != nil {
+		return fmt.Errorf("erro ao ler campos do formulário: %w", err)
+	}
+	b.acroForm.Update("Fields", append(fields, ref))
+	return nil
+}
+
+// widgetDict cria o dicionário de anotação comum aos widgets
+func widgetDict(rect bbox, pageRef types.IndirectRef, characteristics types.Dict) types.Dict {
+	characteristics["BC"] = types.NewNumberArray(fieldBorderGray, fieldBorderGray, fieldBorderGray)
+	return types.Dict{
+		"Type":    types.Name("Annot"),
+		"Subtype": types.Name("Widget"),
+		"Rect":    types.NewNumberArray(rect.llx, rect.lly, rect.urx, rect.ury),
+		"P":       pageRef,
+		"F":       types.Integer(annotationFlagPrint),
+		"MK":      characteristics,
+		"BS":      types.Dict{"W": types.Integer(1), "S": types.Name("S")},
+	}
+}
+
+// commonFlags retorna as flags de somente leitura e obrigatório
+func commonFlags(definition appModel.FormFieldDefinition) int {
+	flags := 0
+	if definition.ReadOnly {
+		flags |= fieldFlagReadOnly
+	}
+	if definition.Required {
+		flags |= fieldFlagRequired
+	}
+	return flags
+}
+
+// addField cria um campo com um único widget (campo e widget no mesmo dicionário)
+func (b *fieldBuilder) addField(definition appModel.FormFieldDefinition, selected fieldFont, rect bbox, pageRef types.IndirectRef) (*types.IndirectRef, error) {
+	width, height := rect.urx-rect.llx, rect.ury-rect.lly
+	flags := commonFlags(definition)
+
+	var (
+		field      types.Dict
+		appearance types.Object
+		err        error
+	)
+
+	switch definition.Type {
+	case appModel.FormFieldText, appModel.FormFieldCombo:
+		fontObj, err := b.fontRef(selected)
+		if err != nil {
+			return nil, err
+		}
+
+		field = widgetDict(rect, pageRef, types.Dict{})
+		field["DA"] = types.StringLiteral(fmt.Sprintf("/%s %s Tf 0 g", selected.resName, formatNumber(definition.FontSize)))
+
+		if definition.Type == appModel.FormFieldText {
+			field["FT"] = types.Name("Tx")
+			if definition.Multiline {
+				flags |= fieldFlagMultiline
+			}
+			if definition.MaxLength > 0 {
+				field["MaxLen"] = types.Integer(definition.MaxLength)
+			}
+		} else {
+			field["FT"] = types.Name("Ch")
+			flags |= fieldFlagCombo
+			if definition.Editable {
+				flags |= fieldFlagEdit
+			}
+			options := make(types.Array, 0, len(definition.Options))
+			for _, option := range definition.Options {
+				options = append(options, pdfTextString(option))
+			}
+			field["Opt"] = options
+		}
+
+		if definition.DefaultValue != "" {
+			field["V"] = pdfTextString(definition.DefaultValue)
+			field["DV"] = pdfTextString(definition.DefaultValue)
+		}
+
+		var content []byte
+		fonts := types.Dict{selected.resName: fontObj}
+		if _, encodeErr := encodeWinAnsi(definition.DefaultValue); encodeErr != nil {
+			// Valores fora do WinAnsi são desenhados com a fonte Unicode do servidor; o DA mantém a fonte padrão,
+			// usada pelos leitores ao editar e pelo preenchimento (que troca para uma fonte Unicode quando necessário)
+			content, fonts, err = b.unicodeTextAppearance(definition.DefaultValue, definition.Multiline, definition.FontSize, definition.FontWeight, width, height)
+		} else {
+			content, err = textAppearance(definition.DefaultValue, definition.Multiline, definition.FontSize, selected, width, height)
+		}
+		if err != nil {
+			return nil, err
+		}
+		appearance, err = newAppearanceStream(b.ctx, content, width, height, fonts)
+		if err != nil {
+			return nil, err
+		}
+
+	case appModel.FormFieldCheckbox:
+		fontObj, err := b.fontRef(fieldFont{baseFont: zapfDingbatsName, resName: zapfDingbatsResName})
+		if err != nil {
+			return nil, err
+		}
+
+		onState := definition.ExportValue
+		if onState == "" {
+			onState = "Yes"
+		}
+		state := "Off"
+		if checked, _ := formBool(definition.Name, definition.DefaultValue); checked {
+			state = onState
+		}
+
+		field = widgetDict(rect, pageRef, types.Dict{"CA": types.StringLiteral(checkMarkGlyph)})
+		field["FT"] = types.Name("Btn")
+		field["DA"] = types.StringLiteral("/" + zapfDingbatsResName + " 0 Tf 0 g")
+		field["V"] = types.Name(state)
+		field["DV"] = types.Name(state)
+		field["AS"] = types.Name(state)
+
+		zapf := fieldFont{baseFont: zapfDingbatsName, resName: zapfDingbatsResName}
+		on, err := newAppearanceStream(b.ctx, glyphAppearance(checkMarkGlyph, width, height), width, height, types.Dict{zapf.resName: fontObj})
+		if err != nil {
+			return nil, err
+		}
+		off, err := newAppearanceStream(b.ctx, borderAppearance(width, height), width, height, nil)
+		if err != nil {
+			return nil, err
+		}
+		appearance = types.Dict{onState: on, "Off": off}
+
+	case appModel.FormFieldSignature:
+		field = widgetDict(rect, pageRef, types.Dict{})
+		field["FT"] = types.Name("Sig")
+		appearance, err = newAppearanceStream(b.ctx, borderAppearance(width, height), width, height, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	field["T"] = pdfTextString(definition.Name)
+	if flags != 0 {
+		field["Ff"] = types.Integer(flags)
+	}
+	field["AP"] = types.Dict{"N": appearance}
+
+	ref, err := b.ctx.IndRefForNewObject(field)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.registerField(*ref); err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// addRadioButton adiciona um botão ao grupo de radio, criando o grupo quando ausente
+// Um valor padrão informado seleciona a opção correspondente em todo o grupo
+func (b *fieldBuilder) addRadioButton(definition appModel.FormFieldDefinition, groupRef *types.IndirectRef, rect bbox, pageRef types.IndirectRef) (*types.IndirectRef, error) {
+	var group types.Dict
+	if groupRef != nil {
+		existing, err := b.ctx.DereferenceDict(*groupRef)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler grupo de radio: %w", err)
+		}
+		fieldType := existing.NameEntry("FT")
+		flags := existing.IntEntry("Ff")
+		if fieldType == nil || *fieldType != "Btn" || flags == nil || *flags&fieldFlagRadio == 0 {
+			return nil, fmt.Errorf("já existe um campo com o nome %s", definition.Name)
+		}
+		group = existing
+	} else {
+		group = types.Dict{
+			"FT":   types.Name("Btn"),
+			"T":    pdfTextString(definition.Name),
+			"Ff":   types.Integer(commonFlags(definition) | fieldFlagRadio | fieldFlagNoToggleToOff),
+			"V":    types.Name("Off"),
+			"Kids": types.Array{},
+		}
+		ref, err := b.ctx.IndRefForNewObject(group)
+		if err != nil {
+			return nil, err
+		}
+		groupRef = ref
+		if err := b.registerField(*ref); err != nil {
+			return nil, err
+		}
+	}
+
+	width, height := rect.urx-rect.llx, rect.ury-rect.lly
+	zapf := fieldFont{baseFont: zapfDingbatsName, resName: zapfDingbatsResName}
+	if _, err := b.fontRef(zapf); err != nil {
+		return nil, err
+	}
+
+	on, err := newAppearanceStream(b.ctx, radioAppearance(width, height, true), width, height, nil)
+	if err != nil {
+		return nil, err
+	}
+	off, err := newAppearanceStream(b.ctx, radioAppearance(width, height, false), width, height, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := "Off"
+	if value := group.NameEntry("V"); value != nil {
+		selected = *value
+	}
+	if definition.DefaultValue != "" {
+		selected = definition.DefaultValue
+		group.Update("V", types.Name(selected))
+		group.Update("DV", types.Name(selected))
+	}
+
+	widget := widgetDict(rect, pageRef, types.Dict{"CA": types.StringLiteral(radioDotGlyph)})
+	widget["Parent"] = *groupRef
+	widget["DA"] = types.StringLiteral("/" + zapfDingbatsResName + " 0 Tf 0 g")
+	widget["AP"] = types.Dict{"N": types.Dict{definition.ExportValue: on, "Off": off}}
+
+	widgetRef, err := b.ctx.IndRefForNewObject(widget)
+	if err != nil {
+		return nil, err
+	}
+
+	kids, err := b.ctx.DereferenceArray(group["Kids"])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler botões do grupo de radio: %w", err)
+	}
+	kids = append(kids, *widgetRef)
+	group.Update("Kids", kids)
+
+	// Sincroniza o estado de todos os botões com a opção selecionada
+	for _, kid := range kids {
+		kidDict, err := b.ctx.DereferenceDict(kid)
+		if err != nil || kidDict == nil {
+			continue
+		}
+		state := "Off"
+		if apDict, err := b.ctx.DereferenceDict(kidDict["AP"]); err == nil && apDict != nil {
+			if states, err := b.ctx.DereferenceDict(apDict["N"]); err == nil && states != nil {
+				if _, found := states.Find(selected); found {
+					state = selected
+				}
+			}
+		}
+		kidDict.Update("AS", types.Name(state))
+	}
+
+	return widgetRef, nil
+}
+
+// newAppearanceStream cria o form XObject de aparição de um widget com a BBox do tamanho do retângulo
+// fonts são as fontes usadas pelo conteúdo, por nome de recurso (nil quando não há texto)
+func newAppearanceStream(pdfCtx *pdfcpuModel.Context, content []byte, width, height float64, fonts types.Dict) (types.IndirectRef, error) {
+	streamDict, err := pdfCtx.NewStreamDictForBuf(content)
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+
+	streamDict.Insert("Type", types.Name("XObject"))
+	streamDict.Insert("Subtype", types.Name("Form"))
+	streamDict.Insert("BBox", types.NewNumberArray(0, 0, width, height))
+	if len(fonts) > 0 {
+		streamDict.Insert("Resources", types.Dict{"Font": fonts})
+	}
+
+	if err := streamDict.Encode(); err != nil {
+		return types.IndirectRef{}, err
+	}
+
+	ref, err := pdfCtx.IndRefForNewObject(*streamDict)
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+	return *ref, nil
+}
+
+// borderAppearance desenha a borda padrão do widget
+func borderAppearance(width, height float64) []byte {
+	return []byte(fmt.Sprintf("%s G 1 w 0.5 0.5 %s %s re S\n",
+		formatNumber(fieldBorderGray), formatNumber(width-1), formatNumber(height-1)))
+}
+
+// textAppearance gera a aparição de campos de texto e listas suspensas com o valor informado
+// Tamanho de fonte 0 ajusta o texto à altura do campo
+func textAppearance(value string, multiline bool, fontSize float64, selected fieldFont, width, height float64) ([]byte, error) {
+	encoded, err := encodeWinAnsi(value)
+	if err != nil {
+		return nil, err
+	}
+
+	fontSize = fieldFontSize(fontSize, height)
+
+	var lines [][]stampRun
+	if encoded != "" {
+		wrapped := []string{encoded}
+		if multiline {
+			wrapped = wrapWinAnsi(encoded, selected.baseFont, fontSize, width-2*fieldPadding)
+		}
+		for _, line := range wrapped {
+			lines = append(lines, []stampRun{{resName: selected.resName, text: escapePDFString(line)}})
+		}
+	}
+
+	return fieldTextContent(lines, multiline, fontSize, width, height), nil
+}
+
+// unicodeTextAppearance gera a aparição de um valor fora do WinAnsi com a fonte Unicode do servidor
+// (embutida com subset) e retorna as fontes usadas, por nome de recurso
+func (b *fieldBuilder) unicodeTextAppearance(value string, multiline bool, fontSize float64, weight string, width, height float64) ([]byte, types.Dict, error) {
+	fontSize = fieldFontSize(fontSize, height)
+
+	primary, err := b.registry.face(DefaultFontFamily, weight)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lines := []string{value}
+	if multiline {
+		measure := func(s string) float64 { return b.registry.measure(s, primary) * fontSize }
+		lines = wrapLines(value, measure, width-2*fieldPadding)
+	}
+
+	layout, err := b.registry.unicodeStampLayout(b.ctx, lines, textStampStyle{fontWeight: weight, fontSize: fontSize})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fieldTextContent(layout.lines, multiline, fontSize, width, height), layout.fonts, nil
+}
+
+// fieldFontSize retorna o tamanho de fonte do campo; 0 ajusta o texto à altura do campo
+func fieldFontSize(fontSize, height float64) float64 {
+	if fontSize > 0 {
+		return fontSize
+	}
+	return math.Max(fieldMinAutoSize, math.Min(fieldMaxAutoSize, (height-2*fieldPadding)/fieldLineHeight))
+}
+
+// fieldTextContent desenha as linhas (já codificadas) dentro da borda do campo
+// Campos de uma linha centralizam a linha de base; os de várias linhas começam no topo
+func fieldTextContent(lines [][]stampRun, multiline bool, fontSize, width, height float64) []byte {
+	var content strings.Builder
+	content.Write(borderAppearance(width, height))
+	fmt.Fprintf(&content, "/Tx BMC\nq\n1 1 %s %s re W n\nBT\n0 g\n", formatNumber(width-2), formatNumber(height-2))
+
+	if len(lines) > 0 {
+		if multiline {
+			fmt.Fprintf(&content, "%s TL\n%s %s Td\n", formatNumber(fontSize*fieldLineHeight),
+				formatNumber(fieldPadding), formatNumber(height-fieldPadding-fontSize))
+		} else {
+			// Linha de base centralizada verticalmente (altura aproximada das maiúsculas: 0,7 em)
+			fmt.Fprintf(&content, "%s %s Td\n", formatNumber(fieldPadding), formatNumber((height-fontSize*0.7)/2))
+		}
+	}
+	for i, line := range lines {
+		if i > 0 {
+			content.WriteString("T*\n")
+		}
+		for _, run := range line {
+			fmt.Fprintf(&content, "/%s %s Tf\n(%s) Tj\n", run.resName, formatNumber(fontSize), run.text)
+		}
+	}
+
+	content.WriteString("ET\nQ\nEMC\n")
+	return []byte(content.String())
+}
+
+// glyphAppearance gera a aparição marcada de um checkbox: borda e o glifo ZapfDingbats centralizado
+func glyphAppearance(glyph string, width, height float64) []byte {
+	fontSize := math.Min(width, height) * 0.8
+	glyphWidth := font.TextWidth(glyph, zapfDingbatsName, 1000) / 1000 * fontSize
+
+	var content strings.Builder
+	content.Write(borderAppearance(width, height))
+	fmt.Fprintf(&content, "q\nBT\n/%s %s Tf\n0 g\n%s %s Td\n(%s) Tj\nET\nQ\n",
+		zapfDingbatsResName, formatNumber(fontSize),
+		formatNumber((width-glyphWidth)/2), formatNumber((height-fontSize*0.7)/2), glyph)
+	return []byte(content.String())
+}
+
+// radioAppearance gera a aparição de um botão de radio: círculo de borda e, quando marcado, o ponto central
+func radioAppearance(width, height float64, checked bool) []byte {
+	cx, cy := width/2, height/2
+	radius := math.Min(width, height)/2 - 0.5
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "%s G 1 w\n", formatNumber(fieldBorderGray))
+	writeCircle(&content, cx, cy, radius)
+	content.WriteString("S\n")
+	if checked {
+		content.WriteString("0 g\n")
+		writeCircle(&content, cx, cy, radius*0.5)
+		content.WriteString("f\n")
+	}
+	return []byte(content.String())
+}
+
+// writeCircle escreve um círculo com quatro curvas de Bézier
+func writeCircle(content *strings.Builder, cx, cy, r float64) {
+	k := r * bezierKappa
+	fmt.Fprintf(content, "%s %s m\n", formatNumber(cx+r), formatNumber(cy))
+	fmt.Fprintf(content, "%s %s %s %s %s %s c\n", formatNumber(cx+r), formatNumber(cy+k), formatNumber(cx+k), formatNumber(cy+r), formatNumber(cx), formatNumber(cy+r))
+	fmt.Fprintf(content, "%s %s %s %s %s %s c\n", formatNumber(cx-k), formatNumber(cy+r), formatNumber(cx-r), formatNumber(cy+k), formatNumber(cx-r), formatNumber(cy))
+	fmt.Fprintf(content, "%s %s %s %s %s %s c\n", formatNumber(cx-r), formatNumber(cy-k), formatNumber(cx-k), formatNumber(cy-r), formatNumber(cx), formatNumber(cy-r))
+	fmt.Fprintf(content, "%s %s %s %s %s %s c\n", formatNumber(cx+k), formatNumber(cy-r), formatNumber(cx+r), formatNumber(cy-k), formatNumber(cx+r), formatNumber(cy))
+}
+
+// wrapWinAnsi quebra o texto (já codificado em WinAnsi) em linhas que cabem na largura com a fonte padrão
+func wrapWinAnsi(text, baseFont string, fontSize, maxWidth float64) []string {
+	return wrapLines(text, func(s string) float64 {
+		return font.TextWidth(s, baseFont, 1000) / 1000 * fontSize
+	}, maxWidth)
+}
+
+// wrapLines quebra o texto em linhas que cabem na largura, sem dividir palavras; quebras de linha são mantidas
+func wrapLines(text string, measure func(string) float64, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && measure(candidate) > maxWidth {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// encodeWinAnsi codifica o texto em WinAnsiEncoding, usada pelas fontes padrão dos campos
+func encodeWinAnsi(text string) (string, error) {
+	encoded, err := charmap.Windows1252.NewEncoder().String(text)
+	if err != nil {
+		return "", fmt.Errorf("valor do campo contém caracteres não suportados pelas fontes padrão do PDF")
+	}
+	return encoded, nil
+}
+
+// escapePDFString escapa uma string literal de content stream
+func escapePDFString(text string) string {
+	escaped, _ := types.Escape(text)
+	return *escaped
+}
+
+// pdfTextString codifica um texto do documento (nomes e valores de campos)
+// ASCII é mantido como literal; os demais textos usam UTF-16BE
+func pdfTextString(text string) types.StringLiteral {
+	for _, r := range text {
+		if r > 0x7e {
+			escaped, _ := types.EscapedUTF16String(text)
+			return types.StringLiteral(*escaped)
+		}
+	}
+	return types.StringLiteral(escapePDFString(text))
+}
+
+// formatNumber formata um número para content streams e dicionários
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(math.Round(value*1000)/1000, 'f', -1, 64)
+}
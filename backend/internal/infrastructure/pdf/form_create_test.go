@@ -0,0 +1,45 @@
+package pdf
+
+import (
+	"context"
+	"testing"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func TestAddFormFieldAcceptsUnicodeDefaultValue(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	processor, err := NewPDFCPUProcessor("")
+	if err != nil {
+		t.Fatalf("erro ao criar processador: %v", err)
+	}
+	p := processor.(*PDFCPUProcessor)
+
+	for _, multiline := range []bool{false, true} {
+		filePath := newBlankPDF(t, 1)
+		definition := appModel.FormFieldDefinition{
+			Name: "nome", Type: appModel.FormFieldText, Page: 1,
+			X: 72, Y: 72, Width: 200, Height: 40,
+			DefaultValue: "Łukasz Żółć", Multiline: multiline,
+		}
+
+		if err := p.AddFormField(context.Background(), filePath, definition); err != nil {
+			t.Fatalf("AddFormField(multiline=%v): %v", multiline, err)
+		}
+
+		fields, err := p.ListFormFields(context.Background(), filePath)
+		if err != nil {
+			t.Fatalf("ListFormFields: %v", err)
+		}
+		if len(fields) != 1 || fields[0].Value != definition.DefaultValue {
+			t.Errorf("valor %q não encontrado em %+v", definition.DefaultValue, fields)
+		}
+
+		if err := p.FillForm(context.Background(), filePath, map[string]interface{}{"nome": "Zoë"}); err != nil {
+			t.Errorf("FillForm: %v", err)
+		}
+	}
+}
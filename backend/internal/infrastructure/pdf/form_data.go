@@ -0,0 +1,350 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/fdf"
+)
+
+// Namespace dos documentos XFDF
+const xfdfNamespace = "http://ns.adobe.com/xfdf/"
+
+// formDataNode é um nó da árvore de nomes de campos (um nível por parte do nome completo)
+type formDataNode struct {
+	name     string
+	value    *formDataValue
+	children []*formDataNode
+}
+
+// formDataValue é o valor exportado de um campo: texto, nome (estados de botões) ou lista de textos
+type formDataValue struct {
+	text   string
+	isName bool
+	list   []string
+	isList bool
+}
+
+// child retorna o filho com o nome informado, criando-o quando ausente
+func (n *formDataNode) child(name string) *formDataNode {
+	for _, child := range n.children {
+		if child.name == name {
+			return child
+		}
+	}
+	child := &formDataNode{name: name}
+	n.children = append(n.children, child)
+	return child
+}
+
+// ExportFormData exporta os valores dos campos do formulário em FDF ou XFDF
+// Botões de ação e assinaturas não possuem valor intercambiável e são ignorados
+func (p *PDFCPUProcessor) ExportFormData(ctx context.Context, filePath string, format appModel.FormDataFormat) ([]byte, error) {
+	fields, err := p.ListFormFields(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &formDataNode{}
+	for _, field := range fields {
+		value, ok := exportedValue(field)
+		if !ok {
+			continue
+		}
+
+		node := root
+		for _, part := range strings.Split(field.Name, ".") {
+			node = node.child(part)
+		}
+		node.value = value
+	}
+
+	switch format {
+	case appModel.FormDataFDF:
+		return encodeFDF(root), nil
+	case appModel.FormDataXFDF:
+		return encodeXFDF(root)
+	}
+	return nil, fmt.Errorf("formato de dados de formulário inválido: %s", format)
+}
+
+// exportedValue converte o valor do campo para intercâmbio
+// Checkbox exporta o nome do estado marcado (ou Off), como fazem os leitores de PDF
+func exportedValue(field appModel.FormField) (*formDataValue, bool) {
+	switch field.Type {
+	case appModel.FormFieldButton, appModel.FormFieldSignature:
+		return nil, false
+
+	case appModel.FormFieldCheckbox:
+		state := "Off"
+		if checked, _ := field.Value.(bool); checked {
+			state = "Yes"
+			if len(field.Widgets) > 0 && field.Widgets[0].OnState != "" {
+				state = field.Widgets[0].OnState
+			}
+		}
+		return &formDataValue{text: state, isName: true}, true
+
+	case appModel.FormFieldRadio:
+		state, _ := field.Value.(string)
+		if state == "" {
+			state = "Off"
+		}
+		return &formDataValue{text: state, isName: true}, true
+	}
+
+	switch value := field.Value.(type) {
+	case []string:
+		return &formDataValue{list: value, isList: true}, true
+	case string:
+		return &formDataValue{text: value}, true
+	}
+	return &formDataValue{}, true
+}
+
+// encodeFDF gera um arquivo FDF com a árvore de campos (entradas T, V e Kids)
+func encodeFDF(root *formDataNode) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%FDF-1.2\n%\xe2\xe3\xcf\xd3\n")
+	buf.WriteString("1 0 obj\n<< /FDF << /Fields [")
+	for _, node := range root.children {
+		writeFDFField(&buf, node)
+	}
+	buf.WriteString("] >> >>\nendobj\n")
+	buf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+// writeFDFField escreve o dicionário de um campo e de seus filhos
+func writeFDFField(buf *bytes.Buffer, node *formDataNode) {
+	buf.WriteString("<< /T ")
+	buf.WriteString(fdfString(node.name))
+
+	if value := node.value; value != nil {
+		buf.WriteString(" /V ")
+		switch {
+		case value.isName:
+			buf.WriteString(core.MakeName(value.text).WriteString())
+		case value.isList:
+			buf.WriteString("[")
+			for i, item := range value.list {
+				if i > 0 {
+					buf.WriteString(" ")
+				}
+				buf.WriteString(fdfString(item))
+			}
+			buf.WriteString("]")
+		default:
+			buf.WriteString(fdfString(value.text))
+		}
+	}
+
+	if len(node.children) > 0 {
+		buf.WriteString(" /Kids [")
+		for _, child := range node.children {
+			writeFDFField(buf, child)
+		}
+		buf.WriteString("]")
+	}
+
+	buf.WriteString(" >>")
+}
+
+// fdfString codifica um texto como string PDF: literal para ASCII, UTF-16BE hexadecimal para os demais
+func fdfString(text string) string {
+	ascii := true
+	for _, r := range text {
+		if r > 0x7e || (r < 0x20 && r != '\n' && r != '\r' && r != '\t') {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return core.MakeString(text).WriteString()
+	}
+
+	encoded := []byte{0xfe, 0xff}
+	for _, unit := range utf16.Encode([]rune(text)) {
+		encoded = append(encoded, byte(unit>>8), byte(unit))
+	}
+	return "<" + strings.ToUpper(hex.EncodeToString(encoded)) + ">"
+}
+
+// xfdfDocument é a estrutura de um documento XFDF
+type xfdfDocument struct {
+	XMLName xml.Name    `xml:"xfdf"`
+	XMLNS   string      `xml:"xmlns,attr,omitempty"`
+	Space   string      `xml:"xml:space,attr,omitempty"`
+	Fields  []xfdfField `xml:"fields>field"`
+}
+
+// xfdfField é um campo XFDF; campos com filhos representam os níveis do nome completo
+type xfdfField struct {
+	Name   string      `xml:"name,attr"`
+	Values []string    `xml:"value"`
+	Fields []xfdfField `xml:"field"`
+}
+
+// encodeXFDF gera um documento XFDF com a árvore de campos
+func encodeXFDF(root *formDataNode) ([]byte, error) {
+	var toXFDF func(node *formDataNode) xfdfField
+	toXFDF = func(node *formDataNode) xfdfField {
+		field := xfdfField{Name: node.name}
+		if value := node.value; value != nil {
+			if value.isList {
+				field.Values = value.list
+			} else {
+				field.Values = []string{value.text}
+			}
+		}
+		for _, child := range node.children {
+			field.Fields = append(field.Fields, toXFDF(child))
+		}
+		return field
+	}
+
+	doc := xfdfDocument{XMLNS: xfdfNamespace, Space: "preserve"}
+	for _, node := range root.children {
+		doc.Fields = append(doc.Fields, toXFDF(node))
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar XFDF: %w", err)
+	}
+
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// ParseFormData lê valores de campos de um arquivo FDF ou XFDF (o formato é detectado pelo conteúdo)
+// Retorna os valores pelo nome completo do campo, no formato aceito por FillForm
+func (p *PDFCPUProcessor) ParseFormData(ctx context.Context, data []byte) (map[string]interface{}, appModel.FormDataFormat, error) {
+	trimmed := bytes.TrimLeft(data, " \t\r\n\xef\xbb\xbf")
+
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("%FDF")):
+		values, err := parseFDF(data)
+		return values, appModel.FormDataFDF, err
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		values, err := parseXFDF(data)
+		return values, appModel.FormDataXFDF, err
+	}
+
+	return nil, "", fmt.Errorf("arquivo de dados de formulário inválido: esperado FDF ou XFDF")
+}
+
+// parseFDF lê os valores de um FDF, percorrendo a hierarquia de campos (Kids)
+func parseFDF(data []byte) (map[string]interface{}, error) {
+	doc, err := fdf.Load(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler FDF: %w", err)
+	}
+
+	fields, err := doc.FieldDictionaries()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler campos do FDF: %w", err)
+	}
+
+	values := make(map[string]interface{})
+	for name, field := range fields {
+		collectFDFValues(field, name, values, 0)
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("FDF não possui valores de campos")
+	}
+
+	return values, nil
+}
+
+// collectFDFValues acumula os valores do campo e de seus filhos
+func collectFDFValues(field *core.PdfObjectDictionary, name string, values map[string]interface{}, depth int) {
+	if depth > maxFieldDepth {
+		return
+	}
+
+	if value := core.TraceToDirectObject(field.Get("V")); value != nil {
+		if parsed, ok := fdfValue(value); ok {
+			values[name] = parsed
+		}
+	}
+
+	kids, ok := core.GetArray(field.Get("Kids"))
+	if !ok {
+		return
+	}
+	for _, kid := range kids.Elements() {
+		kidDict, ok := core.GetDict(kid)
+		if !ok {
+			continue
+		}
+		partial, ok := core.GetString(kidDict.Get("T"))
+		if !ok {
+			continue
+		}
+		collectFDFValues(kidDict, name+"."+partial.Decoded(), values, depth+1)
+	}
+}
+
+// fdfValue converte o valor de um campo FDF: texto e nome viram string, arrays viram lista de strings
+func fdfValue(value core.PdfObject) (interface{}, bool) {
+	switch v := value.(type) {
+	case *core.PdfObjectString:
+		return v.Decoded(), true
+	case *core.PdfObjectName:
+		return string(*v), true
+	case *core.PdfObjectArray:
+		list := make([]string, 0, v.Len())
+		for _, item := range v.Elements() {
+			if text, ok := core.GetString(item); ok {
+				list = append(list, text.Decoded())
+			}
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// parseXFDF lê os valores de um XFDF, concatenando os nomes dos campos aninhados
+func parseXFDF(data []byte) (map[string]interface{}, error) {
+	var doc xfdfDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("erro ao ler XFDF: %w", err)
+	}
+
+	values := make(map[string]interface{})
+
+	var collect func(fields []xfdfField, prefix string)
+	collect = func(fields []xfdfField, prefix string) {
+		for _, field := range fields {
+			name := field.Name
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+
+			switch len(field.Values) {
+			case 0:
+			case 1:
+				values[name] = field.Values[0]
+			default:
+				values[name] = field.Values
+			}
+
+			collect(field.Fields, name)
+		}
+	}
+	collect(doc.Fields, "")
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("XFDF não possui valores de campos")
+	}
+
+	return values, nil
+}
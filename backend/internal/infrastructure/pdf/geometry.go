@@ -0,0 +1,148 @@
+package pdf
+
+import (
+	"math"
+
+	"github.com/unidoc/unipdf/v3/core"
+)
+
+// matrix representa uma matriz de transformação do PDF [a b c d e f]
+type matrix [6]float64
+
+// identityMatrix é a matriz identidade
+var identityMatrix = matrix{1, 0, 0, 1, 0, 0}
+
+// translateMatrix cria uma matriz de translação
+func translateMatrix(tx, ty float64) matrix {
+	return matrix{1, 0, 0, 1, tx, ty}
+}
+
+// matrixFromParams lê os seis operandos numéricos de uma matriz (operadores cm e Tm, entrada Matrix)
+func matrixFromParams(params []core.PdfObject) (matrix, bool) {
+	if len(params) != 6 {
+		return identityMatrix, false
+	}
+
+	values, err := core.GetNumbersAsFloat(params)
+	if err != nil {
+		return identityMatrix, false
+	}
+
+	return matrix{values[0], values[1], values[2], values[3], values[4], values[5]}, true
+}
+
+// multiply retorna m × n, ou seja, aplica m e depois n
+func (m matrix) multiply(n matrix) matrix {
+	return matrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+// transform aplica a matriz a um ponto
+func (m matrix) transform(x, y float64) (float64, float64) {
+	return x*m[0] + y*m[2] + m[4], x*m[1] + y*m[3] + m[5]
+}
+
+// inverse retorna a matriz inversa; falha quando a matriz é degenerada
+func (m matrix) inverse() (matrix, bool) {
+	det := m[0]*m[3] - m[1]*m[2]
+	if math.Abs(det) < 1e-12 {
+		return identityMatrix, false
+	}
+
+	return matrix{
+		m[3] / det,
+		-m[1] / det,
+		-m[2] / det,
+		m[0] / det,
+		(m[2]*m[5] - m[3]*m[4]) / det,
+		(m[1]*m[4] - m[0]*m[5]) / det,
+	}, true
+}
+
+// scale retorna o fator de escala médio da matriz (usado para espessura de linha)
+func (m matrix) scale() float64 {
+	return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+// bbox representa um retângulo alinhado aos eixos
+type bbox struct {
+	llx, lly, urx, ury float64
+}
+
+// emptyBBox é um retângulo vazio, neutro para extend
+var emptyBBox = bbox{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+
+// infiniteBBox cobre todo o plano (ex.: área de recorte inicial)
+var infiniteBBox = bbox{math.Inf(-1), math.Inf(-1), math.Inf(1), math.Inf(1)}
+
+// isEmpty verifica se o retângulo não contém nenhum ponto
+func (b bbox) isEmpty() bool {
+	return b.llx > b.urx || b.lly > b.ury
+}
+
+// extend amplia o retângulo para conter o ponto
+func (b bbox) extend(x, y float64) bbox {
+	return bbox{math.Min(b.llx, x), math.Min(b.lly, y), math.Max(b.urx, x), math.Max(b.ury, y)}
+}
+
+// expand amplia o retângulo em todas as direções
+func (b bbox) expand(margin float64) bbox {
+	if b.isEmpty() {
+		return b
+	}
+	return bbox{b.llx - margin, b.lly - margin, b.urx + margin, b.ury + margin}
+}
+
+// intersects verifica se os retângulos se sobrepõem com área positiva
+func (b bbox) intersects(o bbox) bool {
+	return b.llx < o.urx && o.llx < b.urx && b.lly < o.ury && o.lly < b.ury
+}
+
+// contains verifica se o retângulo contém inteiramente o outro
+func (b bbox) contains(o bbox) bool {
+	return b.llx <= o.llx && b.lly <= o.lly && b.urx >= o.urx && b.ury >= o.ury
+}
+
+// intersection retorna a interseção dos retângulos (vazia quando não se sobrepõem)
+func (b bbox) intersection(o bbox) bbox {
+	return bbox{math.Max(b.llx, o.llx), math.Max(b.lly, o.lly), math.Min(b.urx, o.urx), math.Min(b.ury, o.ury)}
+}
+
+// transform retorna o menor retângulo que contém os quatro cantos transformados
+func (b bbox) transform(m matrix) bbox {
+	if b.isEmpty() {
+		return b
+	}
+	return b.quad(m).bbox()
+}
+
+// quad retorna os quatro cantos transformados do retângulo
+func (b bbox) quad(m matrix) quad {
+	var q quad
+	for i, corner := range [4][2]float64{{b.llx, b.lly}, {b.urx, b.lly}, {b.urx, b.ury}, {b.llx, b.ury}} {
+		q[i][0], q[i][1] = m.transform(corner[0], corner[1])
+	}
+	return q
+}
+
+// quad é um quadrilátero (retângulo possivelmente rotacionado), com os cantos na ordem
+// inferior esquerdo, inferior direito, superior direito e superior esquerdo do conteúdo
+type quad [4][2]float64
+
+// bbox retorna o menor retângulo alinhado aos eixos que contém o quadrilátero
+func (q quad) bbox() bbox {
+	result := emptyBBox
+	for _, corner := range q {
+		result = result.extend(corner[0], corner[1])
+	}
+	return result
+}
+
+// unitSquare é o espaço de uma imagem (imagens são desenhadas no quadrado unitário)
+var unitSquare = bbox{0, 0, 1, 1}
@@ -0,0 +1,116 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// headerFooterSlot associa o texto de uma posição à âncora do pdfcpu, ao sentido das margens e ao alinhamento das linhas
+type headerFooterSlot struct {
+	text   string
+	anchor types.Anchor
+	dx, dy float64 // Sinais aplicados às margens horizontal e vertical
+	align  appModel.TextAlign
+}
+
+// AddHeaderFooter desenha cabeçalhos e rodapés já resolvidos em cada página informada
+// Cada posição é desenhada como um carimbo PDF sobre o conteúdo (ver textStampPDF), em tamanho real
+func (p *PDFCPUProcessor) AddHeaderFooter(ctx context.Context, filePath string, pages []appModel.PageHeaderFooter, style appModel.HeaderFooterStyle) error {
+	selected, err := resolveFieldFont(style.FontFamily, style.FontWeight)
+	if err != nil {
+		return err
+	}
+
+	fontSize := math.Round(style.FontSize)
+	if fontSize < 1 {
+		return fmt.Errorf("tamanho de fonte inválido: %.1f", style.FontSize)
+	}
+
+	numPages, err := api.PageCountFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao obter número de páginas: %w", err)
+	}
+
+	// Textos repetidos (ex.: nome do documento) reaproveitam o mesmo carimbo
+	rendered := make(map[headerFooterSlot][]byte)
+	stamps := make(map[int][]*pdfcpuModel.Watermark, len(pages))
+	for _, page := range pages {
+		if page.Page < 1 || page.Page > numPages {
+			return fmt.Errorf("página inválida: %d (PDF tem %d páginas)", page.Page, numPages)
+		}
+
+		slots := []headerFooterSlot{
+			{page.Header.Left, types.TopLeft, 1, -1, appModel.TextAlignLeft},
+			{page.Header.Center, types.TopCenter, 0, -1, appModel.TextAlignCenter},
+			{page.Header.Right, types.TopRight, -1, -1, appModel.TextAlignRight},
+			{page.Footer.Left, types.BottomLeft, 1, 1, appModel.TextAlignLeft},
+			{page.Footer.Center, types.BottomCenter, 0, 1, appModel.TextAlignCenter},
+			{page.Footer.Right, types.BottomRight, -1, 1, appModel.TextAlignRight},
+		}
+
+		for _, slot := range slots {
+			if slot.text == "" {
+				continue
+			}
+
+			stamp, ok := rendered[slot]
+			if !ok {
+				stamp, err = p.textStampPDF(slot.text, textStampStyle{
+					fontFamily: style.FontFamily,
+					fontWeight: style.FontWeight,
+					fontSize:   fontSize,
+					color:      style.Color,
+					align:      slot.align,
+				})
+				if err != nil {
+					return fmt.Errorf("erro ao preparar texto da página %d: %w", page.Page, err)
+				}
+				rendered[slot] = stamp
+			}
+
+			wm, err := api.PDFWatermarkForReadSeeker(bytes.NewReader(stamp), 1, "", true, false, types.POINTS)
+			if err != nil {
+				return fmt.Errorf("erro ao preparar texto da página %d: %w", page.Page, err)
+			}
+
+			wm.Pos = slot.anchor
+			wm.Dx = slot.dx * style.MarginX
+			wm.Dy = slot.dy * style.MarginY
+			wm.Scale = 1
+			wm.ScaleAbs = true
+			wm.UserRotOrDiagonal = true
+			wm.Diagonal = pdfcpuModel.NoDiagonal
+			wm.Rotation = 0
+			wm.Opacity = 1
+
+			stamps[page.Page] = append(stamps[page.Page], wm)
+		}
+	}
+
+	if len(stamps) == 0 {
+		return nil
+	}
+
+	config := pdfcpuModel.NewDefaultConfiguration()
+	if err := api.AddWatermarksSliceMapFile(filePath, "", stamps, config); err != nil {
+		return fmt.Errorf("erro ao aplicar cabeçalho e rodapé: %w", err)
+	}
+
+	logger.Logger.Debug("Cabeçalho e rodapé aplicados",
+		zap.String("file", filePath),
+		zap.Int("pages_count", len(stamps)),
+		zap.String("font", selected.baseFont),
+		zap.Float64("font_size", fontSize),
+	)
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package pdf
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// newBlankPDF cria um PDF com páginas A4 vazias no diretório temporário do teste
+func newBlankPDF(t *testing.T, pages int) string {
+	t.Helper()
+
+	pdfCtx, err := pdfcpu.CreateContextWithXRefTable(nil, types.PaperSize["A4"])
+	if err != nil {
+		t.Fatalf("erro ao criar PDF: %v", err)
+	}
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		t.Fatalf("erro ao ler catálogo: %v", err)
+	}
+	pagesRef := rootDict.IndirectRefEntry("Pages")
+	pagesDict, err := pdfCtx.DereferenceDict(*pagesRef)
+	if err != nil {
+		t.Fatalf("erro ao ler árvore de páginas: %v", err)
+	}
+
+	kids := types.Array{}
+	for i := 0; i < pages; i++ {
+		pageRef, err := pdfCtx.EmptyPage(pagesRef, nil, 0)
+		if err != nil {
+			t.Fatalf("erro ao criar página: %v", err)
+		}
+		kids = append(kids, *pageRef)
+	}
+	pagesDict.Update("Kids", kids)
+	pagesDict.Update("Count", types.Integer(pages))
+	pdfCtx.PageCount = pages
+
+	filePath := filepath.Join(t.TempDir(), "blank.pdf")
+	if err := api.WriteContextFile(pdfCtx, filePath); err != nil {
+		t.Fatalf("erro ao salvar PDF: %v", err)
+	}
+	return filePath
+}
+
+func TestAddHeaderFooterKeepsLiteralText(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	processor, err := NewPDFCPUProcessor("")
+	if err != nil {
+		t.Fatalf("erro ao criar processador: %v", err)
+	}
+	p := processor.(*PDFCPUProcessor)
+
+	filePath := newBlankPDF(t, 2)
+	pages := []appModel.PageHeaderFooter{
+		{Page: 1, Header: appModel.HeaderFooterSlots{Left: "Desconto 50%p"}, Footer: appModel.HeaderFooterSlots{Right: "ACME-%P 100%"}},
+		{Page: 2, Header: appModel.HeaderFooterSlots{Center: "Łódź %p"}},
+	}
+	style := appModel.HeaderFooterStyle{FontSize: 10, MarginX: 36, MarginY: 24}
+
+	if err := p.AddHeaderFooter(context.Background(), filePath, pages, style); err != nil {
+		t.Fatalf("AddHeaderFooter: %v", err)
+	}
+
+	texts, err := p.ExtractText(context.Background(), filePath, nil)
+	if err != nil {
+		t.Fatalf("ExtractText: %v", err)
+	}
+	if len(texts) != 2 {
+		t.Fatalf("esperadas 2 páginas, obtidas %d", len(texts))
+	}
+
+	expected := map[int][]string{
+		1: {"Desconto 50%p", "ACME-%P 100%"},
+		2: {"Łódź %p"},
+	}
+	for _, page := range texts {
+		for _, want := range expected[page.Page] {
+			if !strings.Contains(page.Text, want) {
+				t.Errorf("página %d: texto %q não encontrado em %q", page.Page, want, page.Text)
+			}
+		}
+	}
+}
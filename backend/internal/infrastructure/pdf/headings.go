@@ -0,0 +1,226 @@
+package pdf
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHeadingLevels    = 3
+	defaultHeadingSizeRatio = 1.15
+	defaultHeadingMaxWords  = 20
+	// Títulos mais longos são truncados (o restante costuma ser texto corrido mal agrupado)
+	maxHeadingRunes = 200
+	// Linhas repetidas em pelo menos este número de páginas (e na maioria delas) são cabeçalhos corridos
+	runningHeaderMinPages = 3
+)
+
+// headingLine é uma linha de texto candidata a título
+type headingLine struct {
+	page  int
+	text  string
+	size  float64 // Maior fonte da linha, arredondada para meio point
+	top   float64 // Topo da linha com origem no topo da página
+	lower float64 // Base da linha com origem no topo da página
+}
+
+// DetectHeadings detecta títulos pelo tamanho da fonte: linhas curtas com fonte maior que a do corpo
+// do texto (a fonte com mais caracteres no documento); cada tamanho distinto, do maior para o menor,
+// forma um nível. Linhas repetidas na maioria das páginas (cabeçalhos corridos) são ignoradas
+func (p *PDFCPUProcessor) DetectHeadings(ctx context.Context, filePath string, options appModel.HeadingOptions) ([]appModel.Heading, error) {
+	if options.MaxLevel <= 0 {
+		options.MaxLevel = defaultHeadingLevels
+	}
+	if options.MinSizeRatio <= 0 {
+		options.MinSizeRatio = defaultHeadingSizeRatio
+	}
+	if options.MaxTitleWords <= 0 {
+		options.MaxTitleWords = defaultHeadingMaxWords
+	}
+
+	reader, file, numPages, err := openReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	sizeChars := make(map[float64]int)
+	var lines []headingLine
+
+	for pageNum := 1; pageNum <= numPages; pageNum++ {
+		pageLines, mediaBox, err := readPageLines(reader, pageNum)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, line := range pageLines {
+			wordTexts := make([]string, 0, len(line.words))
+			lineBox := emptyBBox
+			size := 0.0
+
+			for _, word := range line.words {
+				for _, glyph := range word.glyphs {
+					glyphSize := roundFontSize(glyph.size)
+					sizeChars[glyphSize] += len([]rune(glyph.text))
+					size = math.Max(size, glyphSize)
+				}
+				box := word.bbox()
+				lineBox = lineBox.extend(box.llx, box.lly).extend(box.urx, box.ury)
+				wordTexts = append(wordTexts, word.text())
+			}
+
+			if lineBox.isEmpty() || len(wordTexts) > options.MaxTitleWords {
+				continue
+			}
+
+			lines = append(lines, headingLine{
+				page:  pageNum,
+				text:  strings.Join(wordTexts, " "),
+				size:  size,
+				top:   mediaBox.Ury - lineBox.ury,
+				lower: mediaBox.Ury - lineBox.lly,
+			})
+		}
+	}
+
+	bodySize := bodyFontSize(sizeChars)
+	if bodySize == 0 {
+		return []appModel.Heading{}, nil
+	}
+
+	var candidates []headingLine
+	for _, line := range lines {
+		if line.size >= bodySize*options.MinSizeRatio && hasLetter(line.text) {
+			candidates = append(candidates, line)
+		}
+	}
+
+	candidates = dropRunningHeaders(mergeHeadingLines(candidates), numPages)
+
+	// Cada tamanho distinto (do maior para o menor) é um nível; tamanhos além de MaxLevel são descartados
+	levels := make(map[float64]int)
+	var sizes []float64
+	for _, candidate := range candidates {
+		if _, ok := levels[candidate.size]; !ok {
+			levels[candidate.size] = 0
+			sizes = append(sizes, candidate.size)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(sizes)))
+	for i, size := range sizes {
+		levels[size] = i + 1
+	}
+
+	headings := make([]appModel.Heading, 0, len(candidates))
+	for _, candidate := range candidates {
+		level := levels[candidate.size]
+		if level > options.MaxLevel {
+			continue
+		}
+
+		title := []rune(candidate.text)
+		if len(title) > maxHeadingRunes {
+			title = append(title[:maxHeadingRunes-1], '…')
+		}
+
+		headings = append(headings, appModel.Heading{
+			Page:     candidate.page,
+			Level:    level,
+			Title:    string(title),
+			Top:      roundPoints(math.Max(candidate.top, 0)),
+			FontSize: candidate.size,
+		})
+	}
+
+	logger.Logger.Debug("Títulos detectados",
+		zap.String("file", filePath),
+		zap.Float64("body_font_size", bodySize),
+		zap.Int("headings_count", len(headings)),
+	)
+
+	return headings, nil
+}
+
+// roundFontSize arredonda o tamanho da fonte para meio point, absorvendo diferenças de escala
+func roundFontSize(size float64) float64 {
+	return math.Round(size*2) / 2
+}
+
+// bodyFontSize retorna o tamanho de fonte com mais caracteres (o menor em caso de empate)
+func bodyFontSize(sizeChars map[float64]int) float64 {
+	body, most := 0.0, 0
+	for size, chars := range sizeChars {
+		if chars > most || (chars == most && size < body) {
+			body, most = size, chars
+		}
+	}
+	return body
+}
+
+// hasLetter verifica se o texto contém alguma letra (descarta números de página e separadores)
+func hasLetter(text string) bool {
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeHeadingLines junta linhas consecutivas da mesma página e fonte que continuam o mesmo título
+func mergeHeadingLines(lines []headingLine) []headingLine {
+	var merged []headingLine
+	for _, line := range lines {
+		if n := len(merged); n > 0 {
+			prev := &merged[n-1]
+			if prev.page == line.page && prev.size == line.size &&
+				line.top >= prev.top && line.top-prev.lower <= line.size {
+				prev.text += " " + line.text
+				prev.lower = math.Max(prev.lower, line.lower)
+				continue
+			}
+		}
+		merged = append(merged, line)
+	}
+	return merged
+}
+
+// dropRunningHeaders remove linhas que se repetem na maioria das páginas, ignorando dígitos
+// (ex.: título do capítulo no topo de cada página, "Página 3 de 10")
+func dropRunningHeaders(lines []headingLine, numPages int) []headingLine {
+	pagesByText := make(map[string]map[int]bool)
+	for _, line := range lines {
+		key := runningHeaderKey(line.text)
+		if pagesByText[key] == nil {
+			pagesByText[key] = make(map[int]bool)
+		}
+		pagesByText[key][line.page] = true
+	}
+
+	result := make([]headingLine, 0, len(lines))
+	for _, line := range lines {
+		pages := len(pagesByText[runningHeaderKey(line.text)])
+		if pages >= runningHeaderMinPages && pages*2 > numPages {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
+// runningHeaderKey normaliza o texto para comparar linhas repetidas
+func runningHeaderKey(text string) string {
+	return strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, text)), " ")
+}
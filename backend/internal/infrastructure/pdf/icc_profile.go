@@ -0,0 +1,131 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"sync"
+)
+
+// Nome e registro da condição de saída sRGB usados no OutputIntent PDF/A
+const (
+	srgbOutputCondition = "sRGB IEC61966-2.1"
+	srgbRegistryName    = "http://www.color.org"
+)
+
+var (
+	srgbProfileOnce sync.Once
+	srgbProfileData []byte
+)
+
+// srgbICCProfile retorna um perfil ICC v2 de monitor para o espaço sRGB (IEC 61966-2-1): primárias e ponto
+// branco adaptados a D50 (Bradford) e curva de transferência tabelada. A versão 2 é aceita por todas as
+// partes da ISO 19005 (o PDF/A-1 não admite perfis ICC v4)
+func srgbICCProfile() []byte {
+	srgbProfileOnce.Do(func() {
+		srgbProfileData = buildSRGBProfile()
+	})
+	return srgbProfileData
+}
+
+// buildSRGBProfile monta o perfil: cabeçalho de 128 bytes, tabela de tags e dados das tags alinhados a 4 bytes
+func buildSRGBProfile() []byte {
+	xyz := func(x, y, z float64) []byte {
+		var buf bytes.Buffer
+		buf.WriteString("XYZ ")
+		buf.Write(make([]byte, 4))
+		for _, value := range []float64{x, y, z} {
+			binary.Write(&buf, binary.BigEndian, int32(math.Round(value*65536)))
+		}
+		return buf.Bytes()
+	}
+
+	description := func(text string) []byte {
+		var buf bytes.Buffer
+		buf.WriteString("desc")
+		buf.Write(make([]byte, 4))
+		binary.Write(&buf, binary.BigEndian, uint32(len(text)+1))
+		buf.WriteString(text)
+		buf.WriteByte(0)
+		buf.Write(make([]byte, 4+4+2+1+67)) // Sem descrições Unicode e ScriptCode
+		return buf.Bytes()
+	}
+
+	copyright := func(text string) []byte {
+		var buf bytes.Buffer
+		buf.WriteString("text")
+		buf.Write(make([]byte, 4))
+		buf.WriteString(text)
+		buf.WriteByte(0)
+		return buf.Bytes()
+	}
+
+	// Curva sRGB: linear até 0,04045 e potência 2,4 acima
+	var curve bytes.Buffer
+	curve.WriteString("curv")
+	curve.Write(make([]byte, 4))
+	const samples = 1024
+	binary.Write(&curve, binary.BigEndian, uint32(samples))
+	for i := 0; i < samples; i++ {
+		x := float64(i) / (samples - 1)
+		y := x / 12.92
+		if x > 0.04045 {
+			y = math.Pow((x+0.055)/1.055, 2.4)
+		}
+		binary.Write(&curve, binary.BigEndian, uint16(math.Round(y*65535)))
+	}
+
+	tags := []struct {
+		signature string
+		data      []byte
+	}{
+		{"desc", description(srgbOutputCondition)},
+		{"cprt", copyright("No copyright, use freely")},
+		{"wtpt", xyz(0.9642, 1.0, 0.8249)},
+		{"rXYZ", xyz(0.4361, 0.2225, 0.0139)},
+		{"gXYZ", xyz(0.3851, 0.7169, 0.0971)},
+		{"bXYZ", xyz(0.1431, 0.0606, 0.7141)},
+		{"rTRC", curve.Bytes()},
+		{"gTRC", nil}, // Compartilham os dados de rTRC
+		{"bTRC", nil},
+	}
+
+	var table, data bytes.Buffer
+	dataStart := 128 + 4 + 12*len(tags)
+	binary.Write(&table, binary.BigEndian, uint32(len(tags)))
+	var lastOffset, lastSize int
+	for _, tag := range tags {
+		if tag.data != nil {
+			lastOffset = dataStart + data.Len()
+			lastSize = len(tag.data)
+			data.Write(tag.data)
+			for data.Len()%4 != 0 {
+				data.WriteByte(0)
+			}
+		}
+		table.WriteString(tag.signature)
+		binary.Write(&table, binary.BigEndian, uint32(lastOffset))
+		binary.Write(&table, binary.BigEndian, uint32(lastSize))
+	}
+
+	size := dataStart + data.Len()
+	header := make([]byte, 128)
+	binary.BigEndian.PutUint32(header[0:], uint32(size))
+	binary.BigEndian.PutUint32(header[8:], 0x02100000) // Versão 2.1
+	copy(header[12:], "mntr")
+	copy(header[16:], "RGB ")
+	copy(header[20:], "XYZ ")
+	for i, value := range []uint16{2000, 1, 1, 0, 0, 0} {
+		binary.BigEndian.PutUint16(header[24+i*2:], value)
+	}
+	copy(header[36:], "acsp")
+	binary.BigEndian.PutUint32(header[68:], uint32(int32(math.Round(0.9642*65536))))
+	binary.BigEndian.PutUint32(header[72:], uint32(int32(math.Round(1.0*65536))))
+	binary.BigEndian.PutUint32(header[76:], uint32(int32(math.Round(0.8249*65536))))
+
+	profile := make([]byte, 0, size)
+	profile = append(profile, header...)
+	profile = append(profile, table.Bytes()...)
+	profile = append(profile, data.Bytes()...)
+	return profile
+}
@@ -0,0 +1,195 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"os"
+
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+)
+
+// loadImageXObject carrega uma imagem do disco e cria o XObject correspondente
+// JPEGs são embutidos sem recompressão (DCTDecode); demais formatos mantêm o colorspace nativo
+// e, se houver transparência, o canal alfa é gravado como soft mask (SMask)
+func loadImageXObject(imagePath string) (*model.XObjectImage, error) {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler arquivo de imagem: %w", err)
+	}
+
+	imgConfig, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decodificar imagem: %w", err)
+	}
+
+	if format == "jpeg" {
+		return jpegXObject(data, imgConfig)
+	}
+
+	goImg, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decodificar imagem: %w", err)
+	}
+
+	return rasterXObject(goImg)
+}
+
+// jpegXObject embute o JPEG original como stream DCTDecode
+func jpegXObject(data []byte, imgConfig image.Config) (*model.XObjectImage, error) {
+	var colorspace model.PdfColorspace
+	components := 3
+
+	switch imgConfig.ColorModel {
+	case color.GrayModel:
+		colorspace = model.NewPdfColorspaceDeviceGray()
+		components = 1
+	case color.CMYKModel:
+		colorspace = model.NewPdfColorspaceDeviceCMYK()
+		components = 4
+	default:
+		colorspace = model.NewPdfColorspaceDeviceRGB()
+	}
+
+	encoder := core.NewDCTEncoder()
+	encoder.Width = imgConfig.Width
+	encoder.Height = imgConfig.Height
+	encoder.ColorComponents = components
+	encoder.BitsPerComponent = 8
+
+	width := int64(imgConfig.Width)
+	height := int64(imgConfig.Height)
+	bitsPerComponent := int64(8)
+
+	ximg := model.NewXObjectImage()
+	ximg.Width = &width
+	ximg.Height = &height
+	ximg.BitsPerComponent = &bitsPerComponent
+	ximg.ColorSpace = colorspace
+	ximg.Filter = encoder
+	ximg.Stream = data
+
+	// JPEGs CMYK gerados pelo Photoshop (marcador Adobe) armazenam os valores invertidos
+	if components == 4 && hasAdobeMarker(data) {
+		ximg.Decode = core.MakeArrayFromFloats([]float64{1, 0, 1, 0, 1, 0, 1, 0})
+	}
+
+	return ximg, nil
+}
+
+// rasterXObject cria um XObject Flate a partir de uma imagem decodificada
+func rasterXObject(goImg image.Image) (*model.XObjectImage, error) {
+	bounds := goImg.Bounds()
+	width := bounds.Dx()
+	height := bounds.Dy()
+	rect := image.Rect(0, 0, width, height)
+
+	var (
+		colorspace model.PdfColorspace
+		components int
+		imgData    []byte
+		alpha      []byte
+	)
+
+	switch goImg.(type) {
+	case *image.Gray, *image.Gray16:
+		gray := image.NewGray(rect)
+		draw.Draw(gray, rect, goImg, bounds.Min, draw.Src)
+		colorspace = model.NewPdfColorspaceDeviceGray()
+		components = 1
+		imgData = gray.Pix
+
+	case *image.CMYK:
+		cmyk := image.NewCMYK(rect)
+		draw.Draw(cmyk, rect, goImg, bounds.Min, draw.Src)
+		colorspace = model.NewPdfColorspaceDeviceCMYK()
+		components = 4
+		imgData = cmyk.Pix
+
+	default:
+		// NRGBA mantém as cores sem pré-multiplicação pelo alfa
+		nrgba := image.NewNRGBA(rect)
+		draw.Draw(nrgba, rect, goImg, bounds.Min, draw.Src)
+		colorspace = model.NewPdfColorspaceDeviceRGB()
+		components = 3
+		imgData, alpha = splitAlpha(nrgba.Pix)
+	}
+
+	ximg, err := model.NewXObjectImageFromImage(&model.Image{
+		Width:            int64(width),
+		Height:           int64(height),
+		BitsPerComponent: 8,
+		ColorComponents:  components,
+		Data:             imgData,
+	}, colorspace, core.NewFlateEncoder())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar XObject de imagem: %w", err)
+	}
+
+	// Canal alfa como soft mask em escala de cinza
+	if alpha != nil {
+		smask, err := model.NewXObjectImageFromImage(&model.Image{
+			Width:            int64(width),
+			Height:           int64(height),
+			BitsPerComponent: 8,
+			ColorComponents:  1,
+			Data:             alpha,
+		}, model.NewPdfColorspaceDeviceGray(), core.NewFlateEncoder())
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar máscara de transparência: %w", err)
+		}
+		ximg.SMask = smask.ToPdfObject()
+	}
+
+	return ximg, nil
+}
+
+// splitAlpha separa pixels NRGBA em dados RGB e canal alfa
+// Retorna alfa nil quando a imagem é totalmente opaca
+func splitAlpha(pix []byte) ([]byte, []byte) {
+	pixelCount := len(pix) / 4
+	rgb := make([]byte, 0, pixelCount*3)
+	alpha := make([]byte, 0, pixelCount)
+	opaque := true
+
+	for i := 0; i < len(pix); i += 4 {
+		rgb = append(rgb, pix[i], pix[i+1], pix[i+2])
+		alpha = append(alpha, pix[i+3])
+		if pix[i+3] != 0xFF {
+			opaque = false
+		}
+	}
+
+	if opaque {
+		return rgb, nil
+	}
+	return rgb, alpha
+}
+
+// hasAdobeMarker verifica se o JPEG possui o segmento APP14 "Adobe"
+func hasAdobeMarker(data []byte) bool {
+	// Percorre os segmentos a partir do SOI até o início dos dados (SOS)
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return false
+		}
+
+		marker := data[i+1]
+		if marker == 0xDA { // SOS
+			return false
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if marker == 0xEE && i+4+5 <= len(data) && string(data[i+4:i+9]) == "Adobe" {
+			return true
+		}
+
+		i += 2 + length
+	}
+
+	return false
+}
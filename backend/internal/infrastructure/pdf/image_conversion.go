@@ -0,0 +1,519 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/hhrutter/tiff"
+	"github.com/unidoc/unipdf/v3/common"
+	"github.com/unidoc/unipdf/v3/contentstream"
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+	"go.uber.org/zap"
+)
+
+const (
+	// Resolução assumida para imagens sem resolução gravada no arquivo
+	defaultImageDPI = 96.0
+
+	// Limite de páginas lidas de um TIFF (evita laços em arquivos malformados)
+	maxTIFFPages = 1000
+
+	// Limite de pixels de cada imagem, verificado antes de decodificar (cerca de 200 MB em RGBA)
+	maxImagePixels = 50_000_000
+)
+
+// imageFrame é uma imagem a ser colocada em uma página: XObject, tamanho em pixels, resolução e orientação
+type imageFrame struct {
+	ximg        *model.XObjectImage
+	width       int
+	height      int
+	dpiX        float64
+	dpiY        float64
+	orientation int // Orientação EXIF/TIFF (1 a 8); 1 = sem transformação
+}
+
+// ImagesToPDF cria um PDF com uma página por imagem (e por página de TIFFs multipágina), na ordem informada
+// JPEGs podem ser embutidos sem recompressão (DCTDecode); a orientação EXIF é aplicada pela matriz de
+// posicionamento, sem decodificar a imagem
+func (p *PDFCPUProcessor) ImagesToPDF(ctx context.Context, outputPath string, images []appModel.ImageFile, options appModel.ImageConversionOptions) error {
+	if len(images) == 0 {
+		return errors.New("nenhuma imagem informada")
+	}
+
+	// Desabilita logs do unipdf para evitar poluição
+	common.SetLogger(common.NewConsoleLogger(common.LogLevelError))
+
+	writer := model.NewPdfWriter()
+	pageCount := 0
+	for i, img := range images {
+		frames, err := imageFrames(img.Data, options)
+		if err != nil {
+			return fmt.Errorf("imagem %d (%s): %w", i+1, img.Name, err)
+		}
+
+		for _, frame := range frames {
+			page, err := imagePage(frame, options)
+			if err != nil {
+				return fmt.Errorf("imagem %d (%s): %w", i+1, img.Name, err)
+			}
+			if err := writer.AddPage(page); err != nil {
+				return fmt.Errorf("erro ao adicionar página: %w", err)
+			}
+			pageCount++
+		}
+	}
+
+	if err := writer.WriteToFile(outputPath); err != nil {
+		return fmt.Errorf("erro ao salvar PDF: %w", err)
+	}
+
+	logger.Logger.Debug("PDF criado a partir de imagens",
+		zap.String("file", outputPath),
+		zap.Int("images", len(images)),
+		zap.Int("pages", pageCount),
+		zap.String("page_size", string(options.PageSize)),
+	)
+
+	return nil
+}
+
+// imageFrames decodifica o arquivo de imagem em uma ou mais páginas
+func imageFrames(data []byte, options appModel.ImageConversionOptions) ([]imageFrame, error) {
+	imgConfig, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: formato não suportado: %v", domain.ErrInvalidImage, err)
+	}
+	if err := checkImageSize(imgConfig.Width, imgConfig.Height); err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case "jpeg":
+		frame := imageFrame{width: imgConfig.Width, height: imgConfig.Height, orientation: 1}
+		frame.dpiX, frame.dpiY = jpegResolution(data)
+		if options.AutoRotate {
+			frame.orientation = jpegOrientation(data)
+		}
+		if options.JPEGPassthrough {
+			frame.ximg, err = jpegXObject(data, imgConfig)
+		} else {
+			frame.ximg, err = decodedXObject(data)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return []imageFrame{frame}, nil
+
+	case "png":
+		frame := imageFrame{width: imgConfig.Width, height: imgConfig.Height, orientation: 1}
+		frame.dpiX, frame.dpiY = pngResolution(data)
+		if frame.ximg, err = decodedXObject(data); err != nil {
+			return nil, err
+		}
+		return []imageFrame{frame}, nil
+
+	case "tiff":
+		return tiffFrames(data, options)
+	}
+
+	return nil, fmt.Errorf("%w: formato não suportado: %s", domain.ErrInvalidImage, format)
+}
+
+// checkImageSize recusa imagens cujas dimensões excedem o limite de pixels, sem decodificá-las
+func checkImageSize(width, height int) error {
+	if width < 1 || height < 1 || int64(width)*int64(height) > maxImagePixels {
+		return fmt.Errorf("%w: %dx%d pixels (máximo de %d no total)", domain.ErrInvalidImage, width, height, maxImagePixels)
+	}
+	return nil
+}
+
+// decodedXObject decodifica a imagem e cria um XObject Flate (com soft mask para transparência)
+func decodedXObject(data []byte) (*model.XObjectImage, error) {
+	goImg, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: erro ao decodificar: %v", domain.ErrInvalidImage, err)
+	}
+	return rasterXObject(goImg)
+}
+
+// tiffFrames lê todas as páginas (IFDs) de um TIFF, como os recebidos por fax
+func tiffFrames(data []byte, options appModel.ImageConversionOptions) ([]imageFrame, error) {
+	ifds, err := tiffIFDs(data)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := make([]imageFrame, 0, len(ifds))
+	for i, ifd := range ifds {
+		if err := checkImageSize(ifd.width, ifd.height); err != nil {
+			return nil, fmt.Errorf("página %d do TIFF: %w", i+1, err)
+		}
+		goImg, err := tiff.DecodeAt(bytes.NewReader(data), ifd.offset)
+		if err != nil {
+			return nil, fmt.Errorf("%w: erro ao decodificar página %d do TIFF: %v", domain.ErrInvalidImage, i+1, err)
+		}
+		ximg, err := rasterXObject(goImg)
+		if err != nil {
+			return nil, err
+		}
+
+		frame := imageFrame{
+			ximg:        ximg,
+			width:       goImg.Bounds().Dx(),
+			height:      goImg.Bounds().Dy(),
+			dpiX:        ifd.dpiX,
+			dpiY:        ifd.dpiY,
+			orientation: 1,
+		}
+		if options.AutoRotate {
+			frame.orientation = ifd.orientation
+		}
+		frames = append(frames, frame)
+	}
+	return frames, nil
+}
+
+// imagePage cria a página com a imagem posicionada conforme o tamanho de página, as margens e a orientação
+func imagePage(frame imageFrame, options appModel.ImageConversionOptions) (*model.PdfPage, error) {
+	// Tamanho natural em points; orientações 5 a 8 giram a imagem em 90 graus
+	imageWidth := float64(frame.width) / frame.dpiX * 72
+	imageHeight := float64(frame.height) / frame.dpiY * 72
+	if frame.orientation >= 5 && frame.orientation <= 8 {
+		imageWidth, imageHeight = imageHeight, imageWidth
+	}
+
+	margin := options.Margin
+	pageWidth, pageHeight, fixed := options.PageSize.Dimensions()
+	drawWidth, drawHeight := imageWidth, imageHeight
+	if !fixed {
+		pageWidth, pageHeight = imageWidth+2*margin, imageHeight+2*margin
+	} else {
+		landscape := options.Orientation == appModel.ImageOrientationLandscape ||
+			(options.Orientation != appModel.ImageOrientationPortrait && imageWidth > imageHeight)
+		if landscape {
+			pageWidth, pageHeight = pageHeight, pageWidth
+		}
+
+		areaWidth, areaHeight := pageWidth-2*margin, pageHeight-2*margin
+		if areaWidth <= 0 || areaHeight <= 0 {
+			return nil, fmt.Errorf("%w: margens maiores que a página", domain.ErrInvalidImage)
+		}
+
+		// Ajusta a imagem à área útil mantendo a proporção
+		scale := math.Min(areaWidth/imageWidth, areaHeight/imageHeight)
+		drawWidth, drawHeight = imageWidth*scale, imageHeight*scale
+	}
+	x := (pageWidth - drawWidth) / 2
+	y := (pageHeight - drawHeight) / 2
+
+	page := model.NewPdfPage()
+	page.MediaBox = &model.PdfRectangle{Llx: 0, Lly: 0, Urx: pageWidth, Ury: pageHeight}
+	if page.Resources == nil {
+		page.Resources = model.NewPdfPageResources()
+	}
+
+	imageName := page.Resources.GenerateXObjectName()
+	if err := page.Resources.SetXObjectImageByName(imageName, frame.ximg); err != nil {
+		return nil, fmt.Errorf("erro ao registrar imagem: %w", err)
+	}
+
+	a, b, c, d, e, f := orientationMatrix(frame.orientation)
+	contentCreator := contentstream.NewContentCreator()
+	contentCreator.Add_q()
+	contentCreator.Add_cm(drawWidth*a, drawHeight*b, drawWidth*c, drawHeight*d, x+drawWidth*e, y+drawHeight*f)
+	contentCreator.Add_Do(imageName)
+	contentCreator.Add_Q()
+
+	if err := page.SetContentStreams([]string{contentCreator.String()}, core.NewFlateEncoder()); err != nil {
+		return nil, fmt.Errorf("erro ao criar content stream: %w", err)
+	}
+	return page, nil
+}
+
+// orientationMatrix retorna a matriz que leva o quadrado unitário da imagem gravada ao quadrado unitário
+// exibido, conforme a orientação EXIF (1 normal, 2 espelhada, 3 180°, 4 invertida, 5 transposta,
+// 6 90° horário, 7 transversa, 8 90° anti-horário)
+func orientationMatrix(orientation int) (a, b, c, d, e, f float64) {
+	switch orientation {
+	case 2:
+		return -1, 0, 0, 1, 1, 0
+	case 3:
+		return -1, 0, 0, -1, 1, 1
+	case 4:
+		return 1, 0, 0, -1, 0, 1
+	case 5:
+		return 0, -1, -1, 0, 1, 1
+	case 6:
+		return 0, -1, 1, 0, 0, 1
+	case 7:
+		return 0, 1, 1, 0, 0, 0
+	case 8:
+		return 0, 1, -1, 0, 1, 0
+	}
+	return 1, 0, 0, 1, 0, 0
+}
+
+// jpegSegments percorre os segmentos de cabeçalho do JPEG até o início dos dados (SOS)
+// visit retorna false para interromper a leitura
+func jpegSegments(data []byte, visit func(marker byte, payload []byte) bool) {
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return
+		}
+
+		marker := data[i+1]
+		if marker == 0xDA { // SOS
+			return
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return
+		}
+		if !visit(marker, data[i+4:i+2+length]) {
+			return
+		}
+
+		i += 2 + length
+	}
+}
+
+// jpegResolution lê a densidade do segmento JFIF (APP0); sem ela, usa a resolução padrão
+func jpegResolution(data []byte) (float64, float64) {
+	dpiX, dpiY := defaultImageDPI, defaultImageDPI
+	jpegSegments(data, func(marker byte, payload []byte) bool {
+		if marker != 0xE0 || len(payload) < 12 || string(payload[:5]) != "JFIF\x00" {
+			return true
+		}
+
+		x := float64(binary.BigEndian.Uint16(payload[8:10]))
+		y := float64(binary.BigEndian.Uint16(payload[10:12]))
+		switch payload[7] {
+		case 1: // Pontos por polegada
+			dpiX, dpiY = validDPI(x), validDPI(y)
+		case 2: // Pontos por centímetro
+			dpiX, dpiY = validDPI(x*2.54), validDPI(y*2.54)
+		}
+		return false
+	})
+	return dpiX, dpiY
+}
+
+// jpegOrientation lê a orientação do bloco EXIF (APP1); 1 quando ausente
+func jpegOrientation(data []byte) int {
+	orientation := 1
+	jpegSegments(data, func(marker byte, payload []byte) bool {
+		if marker != 0xE1 || len(payload) < 14 || string(payload[:6]) != "Exif\x00\x00" {
+			return true
+		}
+
+		exif := payload[6:]
+		byteOrder, ok := tiffByteOrder(exif)
+		if !ok {
+			return false
+		}
+		entries, _, err := readIFD(exif, byteOrder, int64(byteOrder.Uint32(exif[4:8])))
+		if err == nil {
+			if value, found := entries[tiffTagOrientation]; found {
+				orientation = validOrientation(int(value.short(byteOrder)))
+			}
+		}
+		return false
+	})
+	return orientation
+}
+
+// pngResolution lê a densidade do bloco pHYs; sem ela, usa a resolução padrão
+func pngResolution(data []byte) (float64, float64) {
+	for i := 8; i+12 <= len(data); {
+		length := int(binary.BigEndian.Uint32(data[i : i+4]))
+		chunk := string(data[i+4 : i+8])
+		if length < 0 || i+12+length > len(data) || chunk == "IDAT" {
+			break
+		}
+
+		if chunk == "pHYs" && length >= 9 {
+			payload := data[i+8 : i+8+length]
+			if payload[8] == 1 { // Pixels por metro
+				x := float64(binary.BigEndian.Uint32(payload[0:4])) * 0.0254
+				y := float64(binary.BigEndian.Uint32(payload[4:8])) * 0.0254
+				return validDPI(x), validDPI(y)
+			}
+			break
+		}
+
+		i += 12 + length
+	}
+	return defaultImageDPI, defaultImageDPI
+}
+
+// Tags TIFF usadas na conversão (TIFF 6.0, seção 8); largura e altura estão em image_export.go
+const (
+	tiffTagOrientation    = 274
+	tiffTagXResolution    = 282
+	tiffTagYResolution    = 283
+	tiffTagResolutionUnit = 296
+)
+
+// tiffEntry é uma entrada de IFD: tipo, quantidade e o campo de valor (ou deslocamento) de 4 bytes
+type tiffEntry struct {
+	typ   uint16
+	count uint32
+	value []byte
+}
+
+// short retorna o valor de uma entrada SHORT ou LONG
+func (e tiffEntry) short(byteOrder binary.ByteOrder) uint32 {
+	if e.typ == 4 {
+		return byteOrder.Uint32(e.value)
+	}
+	return uint32(byteOrder.Uint16(e.value))
+}
+
+// tiffIFD é uma página do TIFF com suas dimensões, resolução e orientação
+type tiffIFD struct {
+	offset      int64
+	width       int
+	height      int
+	dpiX        float64
+	dpiY        float64
+	orientation int
+}
+
+// tiffByteOrder identifica a ordem dos bytes pelo cabeçalho ("II" ou "MM")
+func tiffByteOrder(data []byte) (binary.ByteOrder, bool) {
+	if len(data) < 8 {
+		return nil, false
+	}
+	switch string(data[:4]) {
+	case "II*\x00":
+		return binary.LittleEndian, true
+	case "MM\x00*":
+		return binary.BigEndian, true
+	}
+	return nil, false
+}
+
+// readIFD lê as entradas de um IFD e o deslocamento do próximo
+func readIFD(data []byte, byteOrder binary.ByteOrder, offset int64) (map[uint16]tiffEntry, int64, error) {
+	if offset < 8 || offset+2 > int64(len(data)) {
+		return nil, 0, errors.New("TIFF com IFD inválido")
+	}
+
+	count := int64(byteOrder.Uint16(data[offset : offset+2]))
+	end := offset + 2 + count*12
+	if end+4 > int64(len(data)) {
+		return nil, 0, errors.New("TIFF com IFD truncado")
+	}
+
+	entries := make(map[uint16]tiffEntry, count)
+	for i := int64(0); i < count; i++ {
+		entry := data[offset+2+i*12 : offset+2+(i+1)*12]
+		entries[byteOrder.Uint16(entry[0:2])] = tiffEntry{
+			typ:   byteOrder.Uint16(entry[2:4]),
+			count: byteOrder.Uint32(entry[4:8]),
+			value: entry[8:12],
+		}
+	}
+	return entries, int64(byteOrder.Uint32(data[end : end+4])), nil
+}
+
+// tiffIFDs percorre a cadeia de IFDs do TIFF, lendo resolução e orientação de cada página
+func tiffIFDs(data []byte) ([]tiffIFD, error) {
+	byteOrder, ok := tiffByteOrder(data)
+	if !ok {
+		return nil, fmt.Errorf("%w: cabeçalho TIFF inválido", domain.ErrInvalidImage)
+	}
+
+	var ifds []tiffIFD
+	visited := make(map[int64]bool)
+	for offset := int64(byteOrder.Uint32(data[4:8])); offset != 0; {
+		if visited[offset] || len(ifds) >= maxTIFFPages {
+			break
+		}
+		visited[offset] = true
+
+		entries, next, err := readIFD(data, byteOrder, offset)
+		if err != nil {
+			if len(ifds) > 0 {
+				break // Páginas já lidas são mantidas
+			}
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImage, err)
+		}
+
+		ifd := tiffIFD{offset: offset, dpiX: defaultImageDPI, dpiY: defaultImageDPI, orientation: 1}
+		if entry, found := entries[tiffTagImageWidth]; found {
+			ifd.width = int(entry.short(byteOrder))
+		}
+		if entry, found := entries[tiffTagImageLength]; found {
+			ifd.height = int(entry.short(byteOrder))
+		}
+		x, xOK := tiffRational(data, byteOrder, entries[tiffTagXResolution])
+		y, yOK := tiffRational(data, byteOrder, entries[tiffTagYResolution])
+		if xOK && yOK {
+			unit := uint32(2) // Polegadas
+			if entry, found := entries[tiffTagResolutionUnit]; found {
+				unit = entry.short(byteOrder)
+			}
+			switch unit {
+			case 2:
+				ifd.dpiX, ifd.dpiY = validDPI(x), validDPI(y)
+			case 3: // Centímetros
+				ifd.dpiX, ifd.dpiY = validDPI(x*2.54), validDPI(y*2.54)
+			}
+		}
+		if entry, found := entries[tiffTagOrientation]; found {
+			ifd.orientation = validOrientation(int(entry.short(byteOrder)))
+		}
+
+		ifds = append(ifds, ifd)
+		offset = next
+	}
+
+	if len(ifds) == 0 {
+		return nil, fmt.Errorf("%w: TIFF sem páginas", domain.ErrInvalidImage)
+	}
+	return ifds, nil
+}
+
+// tiffRational lê o valor de uma entrada RATIONAL (numerador e denominador apontados pelo campo de valor)
+func tiffRational(data []byte, byteOrder binary.ByteOrder, entry tiffEntry) (float64, bool) {
+	if entry.typ != 5 || entry.count < 1 {
+		return 0, false
+	}
+	offset := int64(byteOrder.Uint32(entry.value))
+	if offset+8 > int64(len(data)) {
+		return 0, false
+	}
+	numerator := byteOrder.Uint32(data[offset : offset+4])
+	denominator := byteOrder.Uint32(data[offset+4 : offset+8])
+	if denominator == 0 {
+		return 0, false
+	}
+	return float64(numerator) / float64(denominator), true
+}
+
+// validDPI descarta resoluções implausíveis, comuns em arquivos que gravam apenas a proporção
+func validDPI(dpi float64) float64 {
+	if dpi < 10 || dpi > 10000 {
+		return defaultImageDPI
+	}
+	return dpi
+}
+
+// validOrientation descarta valores de orientação fora do intervalo EXIF
+func validOrientation(orientation int) int {
+	if orientation < 1 || orientation > 8 {
+		return 1
+	}
+	return orientation
+}
@@ -0,0 +1,39 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"path/filepath"
+	"testing"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func TestImagesToPDFRejectsOversizedImage(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("erro ao gerar PNG: %v", err)
+	}
+	// Declara 20000x20000 pixels no IHDR (largura e altura nos bytes 16 a 23) e recalcula o CRC do bloco
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:20], 20000)
+	binary.BigEndian.PutUint32(data[20:24], 20000)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+
+	p := &PDFCPUProcessor{}
+	outputPath := filepath.Join(t.TempDir(), "images.pdf")
+	err := p.ImagesToPDF(context.Background(), outputPath, []appModel.ImageFile{{Name: "grande.png", Data: data}}, appModel.ImageConversionOptions{})
+	if !errors.Is(err, domain.ErrInvalidImage) {
+		t.Fatalf("esperado ErrInvalidImage, obtido %v", err)
+	}
+}
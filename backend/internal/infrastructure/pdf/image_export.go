@@ -0,0 +1,490 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"math"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/unidoc/unipdf/v3/common"
+	"github.com/unidoc/unipdf/v3/model"
+	"github.com/unidoc/unipdf/v3/render"
+	"go.uber.org/zap"
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	// Limites de renderização: A4 a 600 DPI tem cerca de 35 milhões de pixels (140 MB em RGBA)
+	maxRenderWidth  = 10000
+	maxRenderPixels = 36_000_000
+
+	// Resolução usada quando nem DPI nem largura são informados
+	defaultRenderDPI = 150.0
+)
+
+// ExportPageImages renderiza as páginas informadas e as codifica no formato escolhido, uma imagem por página
+// (ou um único TIFF com todas as páginas quando options.MultiPage)
+func (p *PDFCPUProcessor) ExportPageImages(ctx context.Context, filePath string, pages []int, options appModel.ImageExportOptions) ([]appModel.PageImage, error) {
+	if options.Transparent && options.Format == appModel.ImageFormatJPEG {
+		return nil, errors.New("fundo transparente não é suportado em JPEG")
+	}
+
+	// Desabilita logs do unipdf para evitar poluição
+	common.SetLogger(common.NewConsoleLogger(common.LogLevelError))
+
+	reader, file, err := model.NewPdfReaderFromFile(filePath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar PDF: %w", err)
+	}
+	defer file.Close()
+
+	numPages, err := reader.GetNumPages()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter número de páginas: %w", err)
+	}
+
+	var tiffPages *tiffWriter
+	if options.Format == appModel.ImageFormatTIFF && options.MultiPage {
+		tiffPages = newTIFFWriter(len(pages))
+	}
+
+	var images []appModel.PageImage
+	for _, pageNum := range pages {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if pageNum < 1 || pageNum > numPages {
+			return nil, fmt.Errorf("página inválida: %d (PDF tem %d páginas)", pageNum, numPages)
+		}
+
+		page, err := reader.GetPage(pageNum)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao obter página %d: %w", pageNum, err)
+		}
+
+		img, dpi, err := renderPageImage(page, options.DPI, options.Width)
+		if err != nil {
+			return nil, fmt.Errorf("página %d: %w", pageNum, err)
+		}
+		img = adjustRenderedImage(img, options.Grayscale, options.Transparent)
+
+		if tiffPages != nil {
+			if err := tiffPages.addPage(img, dpi, options.Transparent); err != nil {
+				return nil, fmt.Errorf("página %d: %w", pageNum, err)
+			}
+			continue
+		}
+
+		data, err := encodePageImage(img, dpi, options.Format, options.JPEGQuality, options.Transparent)
+		if err != nil {
+			return nil, fmt.Errorf("página %d: %w", pageNum, err)
+		}
+		images = append(images, appModel.PageImage{Pages: []int{pageNum}, Data: data})
+	}
+
+	if tiffPages != nil {
+		images = append(images, appModel.PageImage{Pages: pages, Data: tiffPages.bytes()})
+	}
+
+	logger.Logger.Debug("Páginas exportadas como imagens",
+		zap.String("file", filePath),
+		zap.Int("pages", len(pages)),
+		zap.Int("images", len(images)),
+		zap.String("format", string(options.Format)),
+	)
+
+	return images, nil
+}
+
+// pageDisplaySize retorna o tamanho exibido da página em PDF points: a CropBox (ou MediaBox) com a rotação aplicada
+func pageDisplaySize(page *model.PdfPage) (float64, float64, error) {
+	box, err := page.GetMediaBox()
+	if err != nil {
+		return 0, 0, fmt.Errorf("erro ao obter dimensões da página: %w", err)
+	}
+	if page.CropBox != nil {
+		box = page.CropBox
+	}
+	width, height := math.Abs(box.Width()), math.Abs(box.Height())
+
+	if page.Rotate != nil && (*page.Rotate/90)%2 != 0 {
+		width, height = height, width
+	}
+	return width, height, nil
+}
+
+// renderPageImage renderiza a página com a largura informada ou, sem largura, na resolução informada
+// (defaultRenderDPI quando zero). Retorna a imagem e a resolução efetiva
+func renderPageImage(page *model.PdfPage, dpi float64, width int) (image.Image, float64, error) {
+	pageWidth, pageHeight, err := pageDisplaySize(page)
+	if err != nil {
+		return nil, 0, err
+	}
+	if pageWidth <= 0 || pageHeight <= 0 {
+		return nil, 0, errors.New("página sem dimensões válidas")
+	}
+
+	if width <= 0 {
+		if dpi <= 0 {
+			dpi = defaultRenderDPI
+		}
+		width = int(math.Round(pageWidth * dpi / 72))
+	}
+	dpi = float64(width) * 72 / pageWidth
+
+	height := int(math.Round(pageHeight * dpi / 72))
+	if width < 1 || height < 1 {
+		return nil, 0, errors.New("dimensões da imagem muito pequenas")
+	}
+	if width > maxRenderWidth || height > maxRenderWidth || width*height > maxRenderPixels {
+		return nil, 0, fmt.Errorf("%w: %dx%d pixels (máximo de %d por lado e %d no total)",
+			domain.ErrRenderLimit, width, height, maxRenderWidth, maxRenderPixels)
+	}
+
+	device := render.NewImageDevice()
+	device.OutputWidth = width
+
+	img, err := device.Render(page)
+	if err != nil {
+		return nil, 0, fmt.Errorf("erro ao renderizar página: %w", err)
+	}
+
+	return img, dpi, nil
+}
+
+// renderThumbnail renderiza a página reduzida para caber em um quadrado de size pixels. A página é renderizada
+// com o dobro do tamanho e reduzida com filtro Catmull-Rom, o que deixa o texto mais legível que a renderização
+// direta em tamanho pequeno
+func renderThumbnail(page *model.PdfPage, size int) (image.Image, error) {
+	pageWidth, pageHeight, err := pageDisplaySize(page)
+	if err != nil {
+		return nil, err
+	}
+	if pageWidth <= 0 || pageHeight <= 0 {
+		return nil, errors.New("página sem dimensões válidas")
+	}
+
+	scale := float64(size) / math.Max(pageWidth, pageHeight)
+	width := max(int(math.Round(pageWidth*scale)), 1)
+	height := max(int(math.Round(pageHeight*scale)), 1)
+
+	img, _, err := renderPageImage(page, 0, 2*width)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(thumbnail, thumbnail.Bounds(), img, img.Bounds(), xdraw.Src, nil)
+	return thumbnail, nil
+}
+
+// clipPage restringe a página à região informada (no sistema da página exibida, com origem no topo esquerdo),
+// convertendo-a para o espaço do PDF conforme a rotação. A renderização passa a cobrir apenas a região
+func clipPage(page *model.PdfPage, clip appModel.PreviewClip) error {
+	box, err := page.GetMediaBox()
+	if err != nil {
+		return fmt.Errorf("erro ao obter dimensões da página: %w", err)
+	}
+	if page.CropBox != nil {
+		box = page.CropBox
+	}
+	box.Normalize()
+
+	rotation := int64(0)
+	if page.Rotate != nil {
+		rotation = ((*page.Rotate % 360) + 360) % 360
+	}
+
+	// A página exibida é o retângulo visível girado no sentido horário
+	var llx, lly, width, height float64
+	switch rotation {
+	case 90:
+		llx, lly, width, height = box.Llx+clip.Y, box.Lly+clip.X, clip.Height, clip.Width
+	case 180:
+		llx, lly, width, height = box.Urx-clip.X-clip.Width, box.Lly+clip.Y, clip.Width, clip.Height
+	case 270:
+		llx, lly, width, height = box.Urx-clip.Y-clip.Height, box.Ury-clip.X-clip.Width, clip.Height, clip.Width
+	default:
+		llx, lly, width, height = box.Llx+clip.X, box.Ury-clip.Y-clip.Height, clip.Width, clip.Height
+	}
+
+	// Interseção com a área visível
+	region := model.PdfRectangle{
+		Llx: math.Max(llx, box.Llx),
+		Lly: math.Max(lly, box.Lly),
+		Urx: math.Min(llx+width, box.Urx),
+		Ury: math.Min(lly+height, box.Ury),
+	}
+	if region.Urx-region.Llx < 1 || region.Ury-region.Lly < 1 {
+		return domain.ErrClipOutsidePage
+	}
+
+	page.MediaBox = &region
+	page.CropBox = nil
+	return nil
+}
+
+// adjustRenderedImage converte a imagem renderizada (sempre sobre fundo branco) para tons de cinza e/ou remove o
+// fundo branco. A transparência é obtida "subtraindo" o branco de cada pixel (como a ferramenta Color to Alpha),
+// o que preserva as bordas suavizadas do texto; áreas brancas do conteúdo também ficam transparentes
+func adjustRenderedImage(img image.Image, grayscale, transparent bool) image.Image {
+	if !grayscale && !transparent {
+		return img
+	}
+
+	rgba, ok := img.(*image.RGBA)
+	if !ok || rgba.Stride != 4*rgba.Rect.Dx() {
+		rgba = image.NewRGBA(img.Bounds())
+		draw.Draw(rgba, rgba.Bounds(), img, img.Bounds().Min, draw.Src)
+	}
+
+	if !transparent {
+		gray := image.NewGray(rgba.Bounds())
+		for i, j := 0, 0; i < len(rgba.Pix); i, j = i+4, j+1 {
+			gray.Pix[j] = luminance(rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2])
+		}
+		return gray
+	}
+
+	// Opacidade = quanto o pixel se afasta do branco; cor pré-multiplicada = cor - (255 - opacidade)
+	out := image.NewRGBA(rgba.Bounds())
+	for i := 0; i < len(rgba.Pix); i += 4 {
+		r, g, b := int(rgba.Pix[i]), int(rgba.Pix[i+1]), int(rgba.Pix[i+2])
+		if grayscale {
+			r = int(luminance(rgba.Pix[i], rgba.Pix[i+1], rgba.Pix[i+2]))
+			g, b = r, r
+		}
+		alpha := 255 - min(r, g, b)
+
+		out.Pix[i] = uint8(r + alpha - 255)
+		out.Pix[i+1] = uint8(g + alpha - 255)
+		out.Pix[i+2] = uint8(b + alpha - 255)
+		out.Pix[i+3] = uint8(alpha)
+	}
+	return out
+}
+
+// luminance converte uma cor RGB em tom de cinza (ITU-R BT.601, como color.GrayModel)
+func luminance(r, g, b uint8) uint8 {
+	return uint8((19595*uint32(r) + 38470*uint32(g) + 7471*uint32(b) + 1<<15) >> 16)
+}
+
+// encodePageImage codifica a imagem de uma página no formato informado; alpha indica que a transparência
+// deve ser gravada (em PNG ela é sempre preservada)
+func encodePageImage(img image.Image, dpi float64, format appModel.ImageFormat, quality int, alpha bool) ([]byte, error) {
+	var buf bytes.Buffer
+	switch format {
+	case appModel.ImageFormatPNG:
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, fmt.Errorf("erro ao codificar imagem PNG: %w", err)
+		}
+	case appModel.ImageFormatJPEG:
+		if quality < 1 || quality > 100 {
+			quality = jpeg.DefaultQuality
+		}
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, fmt.Errorf("erro ao codificar imagem JPEG: %w", err)
+		}
+	case appModel.ImageFormatWebP:
+		if err := encodeWebP(&buf, img); err != nil {
+			return nil, fmt.Errorf("erro ao codificar imagem WebP: %w", err)
+		}
+	case appModel.ImageFormatTIFF:
+		writer := newTIFFWriter(1)
+		if err := writer.addPage(img, dpi, alpha); err != nil {
+			return nil, err
+		}
+		return writer.bytes(), nil
+	default:
+		return nil, fmt.Errorf("formato de imagem inválido: %s", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// Demais tags TIFF gravadas pelo tiffWriter (as de resolução estão em image_conversion.go)
+const (
+	tiffTagNewSubfileType  = 254
+	tiffTagImageWidth      = 256
+	tiffTagImageLength     = 257
+	tiffTagBitsPerSample   = 258
+	tiffTagCompression     = 259
+	tiffTagPhotometric     = 262
+	tiffTagStripOffsets    = 273
+	tiffTagSamplesPerPixel = 277
+	tiffTagRowsPerStrip    = 278
+	tiffTagStripByteCounts = 279
+	tiffTagPlanarConfig    = 284
+	tiffTagPageNumber      = 297
+	tiffTagExtraSamples    = 338
+
+	tiffTypeShort    = 3
+	tiffTypeLong     = 4
+	tiffTypeRational = 5
+
+	tiffCompressionDeflate = 8
+)
+
+// tiffWriter grava um TIFF little-endian com uma página (IFD) por imagem, cada uma em uma única faixa Deflate.
+// Cada página é comprimida assim que adicionada, sem manter as imagens renderizadas em memória
+type tiffWriter struct {
+	buf        bytes.Buffer
+	pageCount  int
+	pageIndex  int
+	nextIFDPos int // Posição do ponteiro a ser preenchido com o offset do próximo IFD
+}
+
+// tiffField é uma entrada de IFD; valores que não cabem em 4 bytes são gravados após o IFD
+type tiffField struct {
+	tag    uint16
+	typ    uint16
+	values []uint32 // Em RATIONAL, pares numerador/denominador
+}
+
+// newTIFFWriter inicia um TIFF com a quantidade total de páginas (gravada na tag PageNumber)
+func newTIFFWriter(pageCount int) *tiffWriter {
+	w := &tiffWriter{pageCount: pageCount, nextIFDPos: 4}
+	w.buf.Write([]byte{'I', 'I', 42, 0, 0, 0, 0, 0})
+	return w
+}
+
+// addPage grava a imagem como uma nova página: em tons de cinza (8 bits), RGB ou, com alpha, RGBA com alfa
+// não associado
+func (w *tiffWriter) addPage(img image.Image, dpi float64, alpha bool) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	gray, isGray := img.(*image.Gray)
+	samples := 3
+	photometric := uint32(2) // RGB
+	switch {
+	case isGray:
+		samples, photometric = 1, 1 // BlackIsZero
+	case alpha:
+		samples = 4
+	}
+
+	// Comprime as linhas da imagem (zlib, conforme a compressão Deflate do TIFF)
+	var strip bytes.Buffer
+	zw := zlib.NewWriter(&strip)
+	row := make([]byte, width*samples)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		if isGray {
+			i := gray.PixOffset(bounds.Min.X, y)
+			copy(row, gray.Pix[i:i+width])
+		} else {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				r, g, b, a := img.At(x, y).RGBA()
+				o := (x - bounds.Min.X) * samples
+				if samples == 4 && a > 0 && a < 0xffff {
+					// Alfa não associado: desfaz a pré-multiplicação
+					r, g, b = r*0xffff/a, g*0xffff/a, b*0xffff/a
+				}
+				row[o], row[o+1], row[o+2] = uint8(r>>8), uint8(g>>8), uint8(b>>8)
+				if samples == 4 {
+					row[o+3] = uint8(a >> 8)
+				}
+			}
+		}
+		if _, err := zw.Write(row); err != nil {
+			return fmt.Errorf("erro ao codificar imagem TIFF: %w", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("erro ao codificar imagem TIFF: %w", err)
+	}
+
+	// Faixa de dados, seguida do IFD (alinhado em 2 bytes)
+	stripOffset := w.buf.Len()
+	w.buf.Write(strip.Bytes())
+	if w.buf.Len()%2 != 0 {
+		w.buf.WriteByte(0)
+	}
+
+	bitsPerSample := make([]uint32, samples)
+	for i := range bitsPerSample {
+		bitsPerSample[i] = 8
+	}
+	resolution := uint32(math.Round(dpi * 100))
+	fields := []tiffField{
+		{tiffTagNewSubfileType, tiffTypeLong, []uint32{2}}, // Página de um documento multipágina
+		{tiffTagImageWidth, tiffTypeLong, []uint32{uint32(width)}},
+		{tiffTagImageLength, tiffTypeLong, []uint32{uint32(height)}},
+		{tiffTagBitsPerSample, tiffTypeShort, bitsPerSample},
+		{tiffTagCompression, tiffTypeShort, []uint32{tiffCompressionDeflate}},
+		{tiffTagPhotometric, tiffTypeShort, []uint32{photometric}},
+		{tiffTagStripOffsets, tiffTypeLong, []uint32{uint32(stripOffset)}},
+		{tiffTagSamplesPerPixel, tiffTypeShort, []uint32{uint32(samples)}},
+		{tiffTagRowsPerStrip, tiffTypeLong, []uint32{uint32(height)}},
+		{tiffTagStripByteCounts, tiffTypeLong, []uint32{uint32(strip.Len())}},
+		{tiffTagXResolution, tiffTypeRational, []uint32{resolution, 100}},
+		{tiffTagYResolution, tiffTypeRational, []uint32{resolution, 100}},
+		{tiffTagPlanarConfig, tiffTypeShort, []uint32{1}},
+		{tiffTagResolutionUnit, tiffTypeShort, []uint32{2}}, // Polegadas
+		{tiffTagPageNumber, tiffTypeShort, []uint32{uint32(w.pageIndex), uint32(w.pageCount)}},
+	}
+	if samples == 4 {
+		fields = append(fields, tiffField{tiffTagExtraSamples, tiffTypeShort, []uint32{2}})
+	}
+
+	w.writeIFD(fields)
+	w.pageIndex++
+	return nil
+}
+
+// writeIFD grava o IFD na posição atual, encadeando-o ao anterior (ou ao cabeçalho)
+func (w *tiffWriter) writeIFD(fields []tiffField) {
+	ifdOffset := w.buf.Len()
+	binary.LittleEndian.PutUint32(w.buf.Bytes()[w.nextIFDPos:], uint32(ifdOffset))
+
+	// Valores externos ficam logo após o IFD: 2 bytes de contagem, 12 por entrada e 4 do próximo IFD
+	extraOffset := ifdOffset + 2 + 12*len(fields) + 4
+	var entries, extra bytes.Buffer
+	for _, field := range fields {
+		var value bytes.Buffer
+		for _, v := range field.values {
+			if field.typ == tiffTypeShort {
+				binary.Write(&value, binary.LittleEndian, uint16(v))
+			} else {
+				binary.Write(&value, binary.LittleEndian, v)
+			}
+		}
+
+		count := len(field.values)
+		if field.typ == tiffTypeRational {
+			count /= 2
+		}
+		binary.Write(&entries, binary.LittleEndian, field.tag)
+		binary.Write(&entries, binary.LittleEndian, field.typ)
+		binary.Write(&entries, binary.LittleEndian, uint32(count))
+
+		if value.Len() <= 4 {
+			padded := make([]byte, 4)
+			copy(padded, value.Bytes())
+			entries.Write(padded)
+			continue
+		}
+		binary.Write(&entries, binary.LittleEndian, uint32(extraOffset+extra.Len()))
+		extra.Write(value.Bytes())
+	}
+
+	binary.Write(&w.buf, binary.LittleEndian, uint16(len(fields)))
+	w.buf.Write(entries.Bytes())
+	w.nextIFDPos = w.buf.Len()
+	w.buf.Write([]byte{0, 0, 0, 0})
+	w.buf.Write(extra.Bytes())
+}
+
+// bytes retorna o conteúdo do TIFF
+func (w *tiffWriter) bytes() []byte {
+	return w.buf.Bytes()
+}
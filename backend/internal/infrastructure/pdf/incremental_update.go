@@ -0,0 +1,247 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// startXRefPattern localiza a última palavra-chave startxref e o deslocamento da tabela de referências
+var startXRefPattern = regexp.MustCompile(`startxref\s+(\d+)\s+%%EOF`)
+
+// incrementalUpdate acumula objetos novos e alterados de uma atualização incremental (ISO 32000-1, 7.5.6)
+// Os bytes originais são preservados; a atualização é acrescentada ao final do arquivo com uma nova
+// seção de referências cruzadas apontando para a anterior (Prev)
+type incrementalUpdate struct {
+	ctx      *pdfcpuModel.Context
+	firstNew int          // Primeiro número de objeto criado pela atualização
+	changed  map[int]bool // Objetos existentes alterados
+	raw      map[int][]byte
+}
+
+// newIncrementalUpdate prepara a atualização sobre o contexto lido do arquivo original
+func newIncrementalUpdate(pdfCtx *pdfcpuModel.Context) *incrementalUpdate {
+	return &incrementalUpdate{
+		ctx:      pdfCtx,
+		firstNew: *pdfCtx.Size,
+		changed:  make(map[int]bool),
+		raw:      make(map[int][]byte),
+	}
+}
+
+// add insere um novo objeto sempre com número inédito (o pdfcpu reutilizaria a lista de objetos livres)
+func (u *incrementalUpdate) add(obj types.Object) (types.IndirectRef, error) {
+	objNr, err := u.ctx.InsertObject(obj)
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+	return *types.NewIndirectRef(objNr, 0), nil
+}
+
+// addRaw reserva um número de objeto cujo corpo já está serializado (ex.: dicionário de assinatura)
+func (u *incrementalUpdate) addRaw(body []byte) (types.IndirectRef, error) {
+	ref, err := u.add(types.Dict{})
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+	u.raw[ref.ObjectNumber.Value()] = body
+	return ref, nil
+}
+
+// touch marca um objeto existente como alterado para que seja regravado na atualização
+func (u *incrementalUpdate) touch(ref types.IndirectRef) {
+	if objNr := ref.ObjectNumber.Value(); objNr < u.firstNew {
+		u.changed[objNr] = true
+	}
+}
+
+// write gera o arquivo atualizado: bytes originais, objetos da atualização, referências cruzadas e trailer
+// Retorna também o deslocamento de cada objeto gravado
+func (u *incrementalUpdate) write(original []byte) ([]byte, map[int]int, error) {
+	prev, err := lastXRefOffset(original)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	objNrs := make([]int, 0, len(u.changed)+*u.ctx.Size-u.firstNew)
+	for objNr := range u.changed {
+		objNrs = append(objNrs, objNr)
+	}
+	for objNr := u.firstNew; objNr < *u.ctx.Size; objNr++ {
+		objNrs = append(objNrs, objNr)
+	}
+	sort.Ints(objNrs)
+
+	var buf bytes.Buffer
+	buf.Write(original)
+	if len(original) > 0 && original[len(original)-1] != '\n' && original[len(original)-1] != '\r' {
+		buf.WriteByte('\n')
+	}
+
+	offsets := make(map[int]int, len(objNrs))
+	generations := make(map[int]int, len(objNrs))
+	for _, objNr := range objNrs {
+		entry, found := u.ctx.Find(objNr)
+		if !found {
+			return nil, nil, fmt.Errorf("objeto %d não encontrado", objNr)
+		}
+
+		generation := 0
+		if entry.Generation != nil {
+			generation = *entry.Generation
+		}
+
+		body, ok := u.raw[objNr]
+		if !ok {
+			if body, err = serializeObject(entry.Object); err != nil {
+				return nil, nil, fmt.Errorf("erro ao serializar objeto %d: %w", objNr, err)
+			}
+		}
+
+		offsets[objNr] = buf.Len()
+		generations[objNr] = generation
+		fmt.Fprintf(&buf, "%d %d obj\n", objNr, generation)
+		buf.Write(body)
+		buf.WriteString("\nendobj\n")
+	}
+
+	if u.ctx.Read.UsingXRefStreams && !u.ctx.Read.Hybrid {
+		err = u.writeXRefStream(&buf, objNrs, offsets, generations, prev)
+	} else {
+		u.writeXRefTable(&buf, objNrs, offsets, generations, prev)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buf.Bytes(), offsets, nil
+}
+
+// trailerDict monta as entradas comuns ao trailer e ao dicionário do fluxo de referências
+func (u *incrementalUpdate) trailerDict(size, prev int) types.Dict {
+	trailer := types.Dict{
+		"Size": types.Integer(size),
+		"Prev": types.Integer(prev),
+		"Root": *u.ctx.Root,
+	}
+	if u.ctx.Info != nil {
+		trailer["Info"] = *u.ctx.Info
+	}
+	if len(u.ctx.ID) > 0 {
+		trailer["ID"] = u.ctx.ID
+	}
+	return trailer
+}
+
+// writeXRefTable grava uma tabela de referências clássica com subseções de objetos contíguos
+func (u *incrementalUpdate) writeXRefTable(buf *bytes.Buffer, objNrs []int, offsets, generations map[int]int, prev int) {
+	xrefOffset := buf.Len()
+	buf.WriteString("xref\n")
+	for _, section := range xrefSections(objNrs) {
+		fmt.Fprintf(buf, "%d %d\n", section[0], len(section))
+		for _, objNr := range section {
+			fmt.Fprintf(buf, "%010d %05d n\r\n", offsets[objNr], generations[objNr])
+		}
+	}
+
+	buf.WriteString("trailer\n")
+	buf.WriteString(u.trailerDict(*u.ctx.Size, prev).PDFString())
+	fmt.Fprintf(buf, "\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+}
+
+// writeXRefStream grava as referências em um fluxo (PDF 1.5), mantendo o formato do arquivo original
+func (u *incrementalUpdate) writeXRefStream(buf *bytes.Buffer, objNrs []int, offsets, generations map[int]int, prev int) error {
+	xrefNr := *u.ctx.Size
+	xrefOffset := buf.Len()
+	objNrs = append(objNrs, xrefNr)
+	offsets[xrefNr] = xrefOffset
+	generations[xrefNr] = 0
+
+	offsetWidth := 4
+	if xrefOffset > 0xffffffff {
+		offsetWidth = 8
+	}
+
+	var data bytes.Buffer
+	index := types.Array{}
+	entry := make([]byte, 8)
+	for _, section := range xrefSections(objNrs) {
+		index = append(index, types.Integer(section[0]), types.Integer(len(section)))
+		for _, objNr := range section {
+			data.WriteByte(1)
+			binary.BigEndian.PutUint64(entry, uint64(offsets[objNr]))
+			data.Write(entry[8-offsetWidth:])
+			binary.BigEndian.PutUint16(entry, uint16(generations[objNr]))
+			data.Write(entry[:2])
+		}
+	}
+
+	dict := u.trailerDict(xrefNr+1, prev)
+	dict["Type"] = types.Name("XRef")
+	dict["Index"] = index
+	dict["W"] = types.NewIntegerArray(1, offsetWidth, 2)
+	dict["Length"] = types.Integer(data.Len())
+
+	fmt.Fprintf(buf, "%d 0 obj\n%s\nstream\n", xrefNr, dict.PDFString())
+	buf.Write(data.Bytes())
+	fmt.Fprintf(buf, "\nendstream\nendobj\nstartxref\n%d\n%%%%EOF\n", xrefOffset)
+	return nil
+}
+
+// xrefSections agrupa números de objeto ordenados em sequências contíguas
+func xrefSections(objNrs []int) [][]int {
+	var sections [][]int
+	for i, objNr := range objNrs {
+		if i == 0 || objNr != objNrs[i-1]+1 {
+			sections = append(sections, nil)
+		}
+		sections[len(sections)-1] = append(sections[len(sections)-1], objNr)
+	}
+	return sections
+}
+
+// serializeObject serializa um objeto para gravação na atualização
+func serializeObject(obj types.Object) ([]byte, error) {
+	switch o := obj.(type) {
+	case types.StreamDict:
+		if o.Raw == nil {
+			return nil, errors.New("fluxo sem conteúdo codificado")
+		}
+		o.Dict["Length"] = types.Integer(len(o.Raw))
+		var buf bytes.Buffer
+		buf.WriteString(o.Dict.PDFString())
+		buf.WriteString("\nstream\n")
+		buf.Write(o.Raw)
+		buf.WriteString("\nendstream")
+		return buf.Bytes(), nil
+	case nil:
+		return []byte("null"), nil
+	}
+	return []byte(obj.PDFString()), nil
+}
+
+// lastXRefOffset retorna o deslocamento da última seção de referências cruzadas do arquivo
+func lastXRefOffset(data []byte) (int, error) {
+	tail := data
+	if len(tail) > 4096 {
+		tail = tail[len(tail)-4096:]
+	}
+
+	matches := startXRefPattern.FindAllSubmatch(tail, -1)
+	if len(matches) == 0 {
+		return 0, errors.New("PDF sem startxref; não é possível acrescentar uma atualização incremental")
+	}
+
+	offset, err := strconv.Atoi(string(matches[len(matches)-1][1]))
+	if err != nil || offset <= 0 || offset >= len(data) {
+		return 0, errors.New("deslocamento de startxref inválido")
+	}
+	return offset, nil
+}
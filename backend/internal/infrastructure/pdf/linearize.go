@@ -0,0 +1,715 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"os"
+	"strings"
+
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// Tamanho reservado ao dicionário de linearização; os valores só são conhecidos após o cálculo dos
+// deslocamentos e o espaço restante é preenchido com espaços
+const linearizationDictSize = 200
+
+// Profundidade máxima da árvore de páginas percorrida na linearização
+const maxPageTreeDepth = 64
+
+// Atributos de página herdáveis da árvore de páginas (ISO 32000-1, tabela 30)
+var inheritablePageAttrs = []string{"Resources", "MediaBox", "CropBox", "Rotate"}
+
+// LinearizePDF reescreve o documento linearizado (visualização rápida na web, ISO 32000-1, anexo F):
+// catálogo e objetos da primeira página no início do arquivo, com uma seção de referências própria e o
+// fluxo de dicas que permite ao visualizador requisitar as demais páginas por faixas de bytes
+// Os objetos de object streams são gravados sem compressão de referências, exigência da linearização
+func (p *PDFCPUProcessor) LinearizePDF(ctx context.Context, filePath string) error {
+	encrypted, err := isEncrypted(filePath)
+	if err != nil {
+		return err
+	}
+	if encrypted {
+		return errors.New("documento protegido por senha; remova a proteção antes de linearizar")
+	}
+
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	linearized, err := linearize(pdfCtx)
+	if err != nil {
+		return fmt.Errorf("erro ao linearizar PDF: %w", err)
+	}
+
+	if err := rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		_, err := out.Write(linearized)
+		return err
+	}); err != nil {
+		return fmt.Errorf("erro ao salvar PDF: %w", err)
+	}
+
+	logger.Logger.Debug("PDF linearizado",
+		zap.String("file", filePath),
+		zap.Int("pages", pdfCtx.PageCount),
+		zap.Int("size", len(linearized)),
+	)
+
+	return nil
+}
+
+// linearizer reorganiza os objetos alcançáveis do documento nas partes de um arquivo linearizado
+type linearizer struct {
+	xref    *pdfcpuModel.XRefTable
+	catalog int
+	pages   []int        // Objetos de página, na ordem do documento
+	stops   map[int]bool // Catálogo, nós da árvore de páginas e páginas: limites da busca por página
+	numbers map[int]int  // Número original -> número no arquivo linearizado
+}
+
+// linearizedObject é um objeto já serializado com seu número no arquivo linearizado
+type linearizedObject struct {
+	number int
+	data   []byte // "n 0 obj ... endobj\n"
+	offset int
+}
+
+// linearize gera o arquivo linearizado:
+// cabeçalho, dicionário de linearização, referências e trailer da primeira página, catálogo, fluxo de dicas,
+// objetos da primeira página, demais páginas, objetos compartilhados, demais objetos e referências principais
+func linearize(pdfCtx *pdfcpuModel.Context) ([]byte, error) {
+	if pdfCtx.Root == nil {
+		return nil, errors.New("PDF sem catálogo")
+	}
+
+	l := &linearizer{
+		xref:    pdfCtx.XRefTable,
+		catalog: pdfCtx.Root.ObjectNumber.Value(),
+		stops:   make(map[int]bool),
+		numbers: make(map[int]int),
+	}
+	l.stops[l.catalog] = true
+
+	catalog, err := l.xref.DereferenceDict(*pdfCtx.Root)
+	if err != nil || catalog == nil {
+		return nil, errors.New("catálogo inválido")
+	}
+	pagesRef, ok := catalog["Pages"].(types.IndirectRef)
+	if !ok {
+		return nil, errors.New("árvore de páginas inválida")
+	}
+	if err := l.collectPages(pagesRef, types.Dict{}, 0); err != nil {
+		return nil, err
+	}
+	if len(l.pages) == 0 {
+		return nil, errors.New("PDF sem páginas")
+	}
+
+	// Objetos de cada página e quantas páginas usam cada objeto
+	pageObjects := make([][]int, len(l.pages))
+	users := make(map[int]int)
+	for i, pageNr := range l.pages {
+		pageObjects[i] = l.reachable(pageNr)
+		for _, objNr := range pageObjects[i] {
+			users[objNr]++
+		}
+	}
+
+	// Parte 6: primeira página com todos os seus objetos, inclusive os compartilhados
+	firstPage := pageObjects[0]
+	placed := map[int]bool{l.catalog: true}
+	for _, objNr := range firstPage {
+		placed[objNr] = true
+	}
+
+	// Parte 7: cada página seguida de seus objetos exclusivos
+	private := make([][]int, len(l.pages))
+	for i := 1; i < len(l.pages); i++ {
+		for _, objNr := range pageObjects[i] {
+			if !placed[objNr] && users[objNr] == 1 {
+				private[i] = append(private[i], objNr)
+				placed[objNr] = true
+			}
+		}
+	}
+
+	// Parte 8: objetos usados por mais de uma página que não estão na primeira
+	var shared []int
+	for i := 1; i < len(l.pages); i++ {
+		for _, objNr := range pageObjects[i] {
+			if !placed[objNr] {
+				shared = append(shared, objNr)
+				placed[objNr] = true
+			}
+		}
+	}
+
+	// Parte 9: demais objetos alcançáveis pelo catálogo e pelo dicionário Info
+	var roots []int
+	roots = append(roots, l.catalog)
+	if pdfCtx.Info != nil {
+		roots = append(roots, pdfCtx.Info.ObjectNumber.Value())
+	}
+	var others []int
+	for _, objNr := range l.walk(roots, nil) {
+		if !placed[objNr] {
+			others = append(others, objNr)
+			placed[objNr] = true
+		}
+	}
+
+	// Seção principal (objetos 1 a m-1) e seção da primeira página (m a n-1), numeradas na ordem do arquivo
+	var main []int
+	for _, objNrs := range private[1:] {
+		main = append(main, objNrs...)
+	}
+	main = append(main, shared...)
+	main = append(main, others...)
+	for i, objNr := range main {
+		l.numbers[objNr] = i + 1
+	}
+	firstNr := len(main) + 1
+	linNr, catalogNr, hintNr := firstNr, firstNr+1, firstNr+2
+	l.numbers[l.catalog] = catalogNr
+	for i, objNr := range firstPage {
+		l.numbers[objNr] = hintNr + 1 + i
+	}
+	size := hintNr + 1 + len(firstPage)
+
+	serialize := func(objNrs []int) ([]*linearizedObject, error) {
+		objects := make([]*linearizedObject, 0, len(objNrs))
+		for _, objNr := range objNrs {
+			obj, err := l.serialize(objNr)
+			if err != nil {
+				return nil, err
+			}
+			objects = append(objects, obj)
+		}
+		return objects, nil
+	}
+
+	catalogObj, err := l.serialize(l.catalog)
+	if err != nil {
+		return nil, err
+	}
+	firstPageObjs, err := serialize(firstPage)
+	if err != nil {
+		return nil, err
+	}
+	privateObjs := make([][]*linearizedObject, len(l.pages))
+	for i := 1; i < len(l.pages); i++ {
+		if privateObjs[i], err = serialize(private[i]); err != nil {
+			return nil, err
+		}
+	}
+	sharedObjs, err := serialize(shared)
+	if err != nil {
+		return nil, err
+	}
+	otherObjs, err := serialize(others)
+	if err != nil {
+		return nil, err
+	}
+
+	// Trailer da primeira página; o espaço de Prev é reservado e completado com espaços
+	trailer := types.Dict{
+		"Size": types.Integer(size),
+		"Root": *types.NewIndirectRef(catalogNr, 0),
+	}
+	if pdfCtx.Info != nil {
+		if infoNr, ok := l.numbers[pdfCtx.Info.ObjectNumber.Value()]; ok {
+			trailer["Info"] = *types.NewIndirectRef(infoNr, 0)
+		}
+	}
+	trailer["ID"] = documentID(pdfCtx.ID)
+	trailer["Prev"] = types.Integer(0)
+	trailerSize := len(trailer.PDFString()) + 20
+
+	header := fmt.Sprintf("%%PDF-%s\n%%\xe2\xe3\xcf\xd3\n", pdfCtx.XRefTable.Version())
+	linObjSize := len(fmt.Sprintf("%d 0 obj\n", linNr)) + linearizationDictSize + len("\nendobj\n")
+	firstXRefSize := len(fmt.Sprintf("xref\n%d %d\n", linNr, size-linNr)) + 20*(size-linNr)
+	firstTrailerSize := len("trailer\n") + trailerSize + len("\nstartxref\n0\n%%EOF\n")
+
+	// Deslocamentos dos objetos; hintSize é o tamanho do objeto do fluxo de dicas
+	layout := func(hintSize int) (hintOffset, firstPageEnd, mainXRef int) {
+		pos := len(header) + linObjSize + firstXRefSize + firstTrailerSize
+		place := func(obj *linearizedObject) {
+			obj.offset = pos
+			pos += len(obj.data)
+		}
+		place(catalogObj)
+		hintOffset = pos
+		pos += hintSize
+		for _, obj := range firstPageObjs {
+			place(obj)
+		}
+		firstPageEnd = pos
+		for _, objs := range privateObjs[1:] {
+			for _, obj := range objs {
+				place(obj)
+			}
+		}
+		for _, obj := range sharedObjs {
+			place(obj)
+		}
+		for _, obj := range otherObjs {
+			place(obj)
+		}
+		return hintOffset, firstPageEnd, pos
+	}
+
+	// Os deslocamentos das tabelas de dicas ignoram o próprio fluxo de dicas (ISO 32000-1, F.4)
+	layout(0)
+	hintData := l.hintTables(pageObjects, firstPageObjs, privateObjs, sharedObjs, users)
+	hintObj, err := hintStreamObject(hintNr, hintData)
+	if err != nil {
+		return nil, err
+	}
+	hintOffset, firstPageEnd, mainXRef := layout(len(hintObj))
+
+	mainXRefHeader := fmt.Sprintf("xref\n0 %d\n", len(main)+1)
+	mainEntries := make([]int, 0, len(main))
+	for _, objs := range privateObjs[1:] {
+		for _, obj := range objs {
+			mainEntries = append(mainEntries, obj.offset)
+		}
+	}
+	for _, obj := range sharedObjs {
+		mainEntries = append(mainEntries, obj.offset)
+	}
+	for _, obj := range otherObjs {
+		mainEntries = append(mainEntries, obj.offset)
+	}
+
+	var mainTail bytes.Buffer
+	mainTail.WriteString(mainXRefHeader)
+	mainTail.WriteString("0000000000 65535 f\r\n")
+	for _, offset := range mainEntries {
+		fmt.Fprintf(&mainTail, "%010d 00000 n\r\n", offset)
+	}
+	firstXRef := len(header) + linObjSize
+	fmt.Fprintf(&mainTail, "trailer\n%s\nstartxref\n%d\n%%%%EOF\n", types.Dict{"Size": types.Integer(size)}.PDFString(), firstXRef)
+	fileSize := mainXRef + mainTail.Len()
+
+	linDict := fmt.Sprintf("<</Linearized 1/L %d/H [%d %d]/O %d/E %d/N %d/T %d>>",
+		fileSize, hintOffset, len(hintObj), l.numbers[l.pages[0]], firstPageEnd, len(l.pages),
+		mainXRef+len(mainXRefHeader)-1)
+
+	var buf bytes.Buffer
+	buf.Grow(fileSize)
+	buf.WriteString(header)
+	fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", linNr, padRight(linDict, linearizationDictSize))
+
+	// Referências da primeira página: dicionário de linearização, catálogo, fluxo de dicas e primeira página
+	fmt.Fprintf(&buf, "xref\n%d %d\n", linNr, size-linNr)
+	fmt.Fprintf(&buf, "%010d 00000 n\r\n", len(header))
+	fmt.Fprintf(&buf, "%010d 00000 n\r\n", catalogObj.offset)
+	fmt.Fprintf(&buf, "%010d 00000 n\r\n", hintOffset)
+	for _, obj := range firstPageObjs {
+		fmt.Fprintf(&buf, "%010d 00000 n\r\n", obj.offset)
+	}
+	trailer["Prev"] = types.Integer(mainXRef)
+	fmt.Fprintf(&buf, "trailer\n%s\nstartxref\n0\n%%%%EOF\n", padRight(trailer.PDFString(), trailerSize))
+
+	buf.Write(catalogObj.data)
+	buf.Write(hintObj)
+	for _, obj := range firstPageObjs {
+		buf.Write(obj.data)
+	}
+	for _, objs := range privateObjs[1:] {
+		for _, obj := range objs {
+			buf.Write(obj.data)
+		}
+	}
+	for _, obj := range sharedObjs {
+		buf.Write(obj.data)
+	}
+	for _, obj := range otherObjs {
+		buf.Write(obj.data)
+	}
+	buf.Write(mainTail.Bytes())
+
+	if buf.Len() != fileSize {
+		return nil, fmt.Errorf("tamanho do arquivo linearizado divergente: %d != %d", buf.Len(), fileSize)
+	}
+	return buf.Bytes(), nil
+}
+
+// collectPages percorre a árvore de páginas registrando as páginas em ordem e copiando os atributos
+// herdáveis para cada página, pois o arquivo linearizado não deve depender da herança (ISO 32000-1, F.3.4)
+func (l *linearizer) collectPages(ref types.IndirectRef, inherited types.Dict, depth int) error {
+	objNr := ref.ObjectNumber.Value()
+	if depth > maxPageTreeDepth || l.stops[objNr] {
+		return errors.New("árvore de páginas inválida")
+	}
+	l.stops[objNr] = true
+
+	node, err := l.xref.DereferenceDict(ref)
+	if err != nil || node == nil {
+		return fmt.Errorf("nó da árvore de páginas %d inválido", objNr)
+	}
+
+	kids, isTree := node["Kids"]
+	if typ, ok := node["Type"].(types.Name); ok && typ == "Page" {
+		isTree = false
+	}
+	if !isTree {
+		for _, key := range inheritablePageAttrs {
+			if _, found := node[key]; !found {
+				if value, found := inherited[key]; found {
+					node[key] = value
+				}
+			}
+		}
+		l.pages = append(l.pages, objNr)
+		return nil
+	}
+
+	attrs := types.Dict{}
+	for _, key := range inheritablePageAttrs {
+		if value, found := node[key]; found {
+			attrs[key] = value
+			delete(node, key)
+		} else if value, found := inherited[key]; found {
+			attrs[key] = value
+		}
+	}
+
+	kidsArray, err := l.xref.DereferenceArray(kids)
+	if err != nil {
+		return fmt.Errorf("nó da árvore de páginas %d inválido", objNr)
+	}
+	for _, kid := range kidsArray {
+		kidRef, ok := kid.(types.IndirectRef)
+		if !ok {
+			return fmt.Errorf("nó da árvore de páginas %d inválido", objNr)
+		}
+		if err := l.collectPages(kidRef, attrs, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reachable retorna a página e os objetos alcançáveis por ela, sem entrar em outras páginas, na árvore de
+// páginas ou no catálogo (ex.: anotações que apontam para outras páginas)
+func (l *linearizer) reachable(pageNr int) []int {
+	return l.walk([]int{pageNr}, l.stops)
+}
+
+// walk percorre em largura os objetos alcançáveis a partir das raízes; objetos em stops só são visitados
+// quando são raízes. O tamanho dos fluxos é regravado diretamente, então Length não é seguido
+func (l *linearizer) walk(roots []int, stops map[int]bool) []int {
+	visited := make(map[int]bool)
+	var order []int
+	queue := append([]int(nil), roots...)
+	for _, objNr := range roots {
+		visited[objNr] = true
+	}
+
+	for len(queue) > 0 {
+		objNr := queue[0]
+		queue = queue[1:]
+
+		entry, found := l.xref.Find(objNr)
+		if !found || entry.Free || entry.Object == nil {
+			continue
+		}
+		order = append(order, objNr)
+
+		visit := func(ref types.IndirectRef) {
+			next := ref.ObjectNumber.Value()
+			if visited[next] || stops[next] {
+				return
+			}
+			visited[next] = true
+			queue = append(queue, next)
+		}
+
+		if sd, ok := entry.Object.(types.StreamDict); ok {
+			collectRefs(sd.Dict, "Length", visit)
+		} else {
+			collectRefs(entry.Object, "", visit)
+		}
+	}
+	return order
+}
+
+// collectRefs chama visit para cada referência indireta do objeto, ignorando a chave skip no primeiro nível
+func collectRefs(obj types.Object, skip string, visit func(types.IndirectRef)) {
+	switch o := obj.(type) {
+	case types.IndirectRef:
+		visit(o)
+	case types.Dict:
+		for key, value := range o {
+			if key != skip {
+				collectRefs(value, "", visit)
+			}
+		}
+	case types.Array:
+		for _, value := range o {
+			collectRefs(value, "", visit)
+		}
+	}
+}
+
+// renumber copia o objeto trocando as referências pelos números do arquivo linearizado
+// Referências a objetos descartados (livres ou inexistentes) são removidas dos dicionários
+func (l *linearizer) renumber(obj types.Object) types.Object {
+	switch o := obj.(type) {
+	case types.IndirectRef:
+		if objNr, ok := l.numbers[o.ObjectNumber.Value()]; ok {
+			return *types.NewIndirectRef(objNr, 0)
+		}
+		return nil
+	case types.Dict:
+		dict := make(types.Dict, len(o))
+		for key, value := range o {
+			if renumbered := l.renumber(value); renumbered != nil {
+				dict[key] = renumbered
+			}
+		}
+		return dict
+	case types.Array:
+		array := make(types.Array, len(o))
+		for i, value := range o {
+			array[i] = l.renumber(value)
+		}
+		return array
+	case types.StreamDict:
+		o.Dict = l.renumber(o.Dict).(types.Dict)
+		return o
+	}
+	return obj
+}
+
+// serialize grava o objeto com seu novo número
+func (l *linearizer) serialize(objNr int) (*linearizedObject, error) {
+	entry, found := l.xref.Find(objNr)
+	if !found {
+		return nil, fmt.Errorf("objeto %d não encontrado", objNr)
+	}
+	body, err := serializeObject(l.renumber(entry.Object))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar objeto %d: %w", objNr, err)
+	}
+
+	number := l.numbers[objNr]
+	data := make([]byte, 0, len(body)+32)
+	data = fmt.Appendf(data, "%d 0 obj\n", number)
+	data = append(data, body...)
+	data = append(data, "\nendobj\n"...)
+	return &linearizedObject{number: number, data: data}, nil
+}
+
+// hintTables monta a tabela de deslocamentos de páginas e a de objetos compartilhados (ISO 32000-1, F.4)
+// Como no Acrobat, o deslocamento do conteúdo é registrado como zero e seu tamanho como o da página
+func (l *linearizer) hintTables(pageObjects [][]int, firstPage []*linearizedObject, private [][]*linearizedObject, shared []*linearizedObject, users map[int]int) hintStream {
+	// Identificadores dos grupos compartilhados: objetos da primeira página, depois os da parte 8
+	groups := append(append([]*linearizedObject(nil), firstPage...), shared...)
+	groupIDs := make(map[int]int, len(groups))
+	for i, obj := range groups {
+		groupIDs[obj.number] = i
+	}
+
+	type pageEntry struct {
+		objects int
+		length  int
+		shared  []int
+	}
+	entries := make([]pageEntry, len(l.pages))
+	for i := range l.pages {
+		section := firstPage
+		if i > 0 {
+			section = private[i]
+		}
+		last := section[len(section)-1]
+		entries[i].objects = len(section)
+		entries[i].length = last.offset + len(last.data) - section[0].offset
+
+		for _, objNr := range pageObjects[i] {
+			if users[objNr] > 1 {
+				entries[i].shared = append(entries[i].shared, groupIDs[l.numbers[objNr]])
+			}
+		}
+	}
+
+	minObjects, maxObjects := entries[0].objects, entries[0].objects
+	minLength, maxLength := entries[0].length, entries[0].length
+	maxShared := 0
+	for _, entry := range entries {
+		minObjects, maxObjects = min(minObjects, entry.objects), max(maxObjects, entry.objects)
+		minLength, maxLength = min(minLength, entry.length), max(maxLength, entry.length)
+		maxShared = max(maxShared, len(entry.shared))
+	}
+	objectsBits := bitLength(maxObjects - minObjects)
+	lengthBits := bitLength(maxLength - minLength)
+	sharedBits := bitLength(maxShared)
+	groupBits := bitLength(len(groups) - 1)
+
+	var w bitWriter
+
+	// Cabeçalho da tabela de deslocamentos de páginas (tabela F.3)
+	w.write(minObjects, 32)
+	w.write(firstPage[0].offset, 32)
+	w.write(objectsBits, 16)
+	w.write(minLength, 32)
+	w.write(lengthBits, 16)
+	w.write(0, 32) // Menor deslocamento do conteúdo
+	w.write(0, 16)
+	w.write(minLength, 32) // Menor tamanho do conteúdo
+	w.write(lengthBits, 16)
+	w.write(sharedBits, 16)
+	w.write(groupBits, 16)
+	w.write(0, 16) // Bits do numerador da posição fracionária
+	w.write(1, 16) // Denominador
+
+	// Entradas por página (tabela F.4), cada item de todas as páginas alinhado ao byte
+	for _, entry := range entries {
+		w.write(entry.objects-minObjects, objectsBits)
+	}
+	w.flush()
+	for _, entry := range entries {
+		w.write(entry.length-minLength, lengthBits)
+	}
+	w.flush()
+	for _, entry := range entries {
+		w.write(len(entry.shared), sharedBits)
+	}
+	w.flush()
+	for _, entry := range entries {
+		for _, id := range entry.shared {
+			w.write(id, groupBits)
+		}
+	}
+	w.flush()
+	for _, entry := range entries {
+		w.write(entry.length-minLength, lengthBits)
+	}
+	w.flush()
+
+	sharedOffset := w.buf.Len()
+
+	// Cabeçalho da tabela de objetos compartilhados (tabela F.5)
+	minGroup, maxGroup := len(groups[0].data), len(groups[0].data)
+	for _, obj := range groups {
+		minGroup, maxGroup = min(minGroup, len(obj.data)), max(maxGroup, len(obj.data))
+	}
+	groupLengthBits := bitLength(maxGroup - minGroup)
+	if len(shared) > 0 {
+		w.write(shared[0].number, 32)
+		w.write(shared[0].offset, 32)
+	} else {
+		w.write(0, 32)
+		w.write(0, 32)
+	}
+	w.write(len(firstPage), 32)
+	w.write(len(groups), 32)
+	w.write(0, 16) // Cada grupo contém um único objeto
+	w.write(minGroup, 32)
+	w.write(groupLengthBits, 16)
+
+	// Entradas por grupo (tabela F.6): tamanho e indicador de assinatura MD5 (ausente)
+	for _, obj := range groups {
+		w.write(len(obj.data)-minGroup, groupLengthBits)
+	}
+	w.flush()
+	for range groups {
+		w.write(0, 1)
+	}
+	w.flush()
+
+	return hintStream{data: w.buf.Bytes(), sharedOffset: sharedOffset}
+}
+
+// hintStream contém as tabelas de dicas e a posição da tabela de objetos compartilhados
+type hintStream struct {
+	data         []byte
+	sharedOffset int
+}
+
+// hintStreamObject serializa o fluxo de dicas primário comprimido
+func hintStreamObject(objNr int, hint hintStream) ([]byte, error) {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(hint.data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	body, err := serializeObject(types.StreamDict{
+		Dict: types.Dict{
+			"Filter": types.Name("FlateDecode"),
+			"S":      types.Integer(hint.sharedOffset),
+		},
+		Raw: compressed.Bytes(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fmt.Appendf(nil, "%d 0 obj\n%s\nendobj\n", objNr, body), nil
+}
+
+// bitWriter grava inteiros sem sinal com a quantidade de bits informada, do bit mais significativo
+type bitWriter struct {
+	buf   bytes.Buffer
+	cur   byte
+	nbits int
+}
+
+func (w *bitWriter) write(value, nbits int) {
+	for i := nbits - 1; i >= 0; i-- {
+		w.cur = w.cur<<1 | byte(value>>i&1)
+		w.nbits++
+		if w.nbits == 8 {
+			w.buf.WriteByte(w.cur)
+			w.cur, w.nbits = 0, 0
+		}
+	}
+}
+
+// flush completa o byte atual com zeros
+func (w *bitWriter) flush() {
+	if w.nbits > 0 {
+		w.write(0, 8-w.nbits)
+	}
+}
+
+// bitLength retorna a quantidade de bits necessária para representar o valor (0 para zero)
+func bitLength(value int) int {
+	if value <= 0 {
+		return 0
+	}
+	return bits.Len(uint(value))
+}
+
+// documentID mantém o identificador do documento ou gera um novo quando ausente
+func documentID(id types.Array) types.Array {
+	if len(id) == 2 {
+		return id
+	}
+	random := make([]byte, 16)
+	_, _ = rand.Read(random)
+	value := types.HexLiteral(hex.EncodeToString(random))
+	return types.Array{value, value}
+}
+
+// padRight completa o texto com espaços até o tamanho informado
+func padRight(s string, size int) string {
+	if len(s) >= size {
+		return s
+	}
+	return s + strings.Repeat(" ", size-len(s))
+}
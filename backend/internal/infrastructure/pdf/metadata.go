@@ -0,0 +1,639 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// Namespaces XMP usados nos metadados do documento
+const (
+	xmpNamespaceRDF  = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmpNamespaceDC   = "http://purl.org/dc/elements/1.1/"
+	xmpNamespaceXMP  = "http://ns.adobe.com/xap/1.0/"
+	xmpNamespacePDF  = "http://ns.adobe.com/pdf/1.3/"
+	xmpNamespacePDFX = "http://ns.adobe.com/pdfx/1.3/" // Propriedades personalizadas do dicionário Info
+	xmpNamespaceXML  = "http://www.w3.org/XML/1998/namespace"
+
+	xmpNamespacePDFAID        = "http://www.aiim.org/pdfa/ns/id/" // Identificação PDF/A (parte e nível)
+	xmpNamespacePDFAExtension = "http://www.aiim.org/pdfa/ns/extension/"
+	xmpNamespacePDFASchema    = "http://www.aiim.org/pdfa/ns/schema#"
+	xmpNamespacePDFAProperty  = "http://www.aiim.org/pdfa/ns/property#"
+)
+
+// Espaço reservado ao final do pacote XMP para edições no próprio lugar (recomendação da especificação XMP)
+const xmpPaddingSize = 2048
+
+// infoStandardKeys são as entradas do dicionário Info definidas pela especificação (ISO 32000-1, 14.3.3)
+var infoStandardKeys = map[string]bool{
+	"Title": true, "Author": true, "Subject": true, "Keywords": true, "Creator": true,
+	"Producer": true, "CreationDate": true, "ModDate": true, "Trapped": true,
+}
+
+// ReadMetadata lê os metadados do dicionário Info e do pacote XMP do catálogo
+func (p *PDFCPUProcessor) ReadMetadata(ctx context.Context, filePath string) (*appModel.PDFMetadata, error) {
+	return readMetadata(filePath)
+}
+
+// readMetadata lê os metadados do dicionário Info e do pacote XMP de um arquivo
+func readMetadata(filePath string) (*appModel.PDFMetadata, error) {
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	metadata := &appModel.PDFMetadata{}
+	if info, err := infoDict(pdfCtx); err == nil && info != nil {
+		metadata.Info = readInfoDict(pdfCtx, info)
+	}
+
+	packet, err := xmpPacket(pdfCtx)
+	if err != nil {
+		logger.Logger.Warn("Erro ao ler pacote XMP", zap.String("file", filePath), zap.Error(err))
+	}
+	if packet != nil {
+		xmp, err := parseXMP(packet)
+		if err != nil {
+			logger.Logger.Warn("Pacote XMP inválido", zap.String("file", filePath), zap.Error(err))
+		} else {
+			metadata.XMP = xmp
+		}
+	}
+
+	return metadata, nil
+}
+
+// WriteMetadata grava os metadados descritivos e as propriedades personalizadas em uma atualização
+// incremental, substituindo o dicionário Info e gerando um pacote XMP com os mesmos valores
+// Producer e CreationDate do arquivo são mantidos (a CreationDate informada prevalece); ModDate recebe a data atual
+func (p *PDFCPUProcessor) WriteMetadata(ctx context.Context, filePath string, metadata appModel.DocumentMetadata) error {
+	if err := writeMetadata(filePath, metadata); err != nil {
+		return err
+	}
+
+	logger.Logger.Debug("Metadados gravados",
+		zap.String("file", filePath),
+		zap.Int("custom_count", len(metadata.Custom)),
+	)
+
+	return nil
+}
+
+// writeMetadata grava os metadados em uma atualização incremental do arquivo
+func writeMetadata(filePath string, metadata appModel.DocumentMetadata) error {
+	original, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	// Sem validação: o contexto é usado apenas para localizar e alterar objetos, sem reescrever o arquivo
+	pdfCtx, err := api.ReadContext(bytes.NewReader(original), pdfcpuModel.NewDefaultConfiguration())
+	if err != nil {
+		return fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+	if pdfCtx.Encrypt != nil {
+		return errors.New("documento criptografado: remova a proteção antes de alterar os metadados")
+	}
+
+	update := newIncrementalUpdate(pdfCtx)
+	now := time.Now()
+
+	existing, err := infoDict(pdfCtx)
+	if err != nil {
+		return fmt.Errorf("erro ao ler dicionário Info: %w", err)
+	}
+
+	info := types.Dict{}
+	for _, key := range []string{"Producer", "CreationDate", "Trapped"} {
+		if value, found := existing[key]; found {
+			info[key] = value
+		}
+	}
+	for key, value := range map[string]string{
+		"Title":    metadata.Title,
+		"Author":   metadata.Author,
+		"Subject":  metadata.Subject,
+		"Keywords": metadata.Keywords,
+		"Creator":  metadata.Creator,
+	} {
+		if value != "" {
+			info[key] = pdfTextString(value)
+		}
+	}
+	for name, value := range metadata.Custom {
+		info[name] = pdfTextString(value)
+	}
+	if metadata.CreationDate != nil {
+		info["CreationDate"] = types.StringLiteral(types.DateString(*metadata.CreationDate))
+	}
+	info["ModDate"] = types.StringLiteral(types.DateString(now))
+
+	if pdfCtx.Info != nil {
+		if entry, found := pdfCtx.FindTableEntryForIndRef(pdfCtx.Info); found {
+			entry.Object = info
+			update.touch(*pdfCtx.Info)
+		}
+	} else {
+		infoRef, err := update.add(info)
+		if err != nil {
+			return err
+		}
+		pdfCtx.Info = &infoRef
+	}
+
+	// O XMP reflete o dicionário Info gravado, inclusive Producer e CreationDate mantidos
+	written := readInfoDict(pdfCtx, info)
+	written.ModDate = &now
+	written.PDFA = metadata.PDFA
+	if err := setXMPPacket(update, buildXMP(written, now)); err != nil {
+		return err
+	}
+
+	output, _, err := update.write(original)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar atualização incremental: %w", err)
+	}
+
+	if err := rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		_, err := out.Write(output)
+		return err
+	}); err != nil {
+		return fmt.Errorf("erro ao salvar PDF: %w", err)
+	}
+
+	return nil
+}
+
+// preserveMetadata executa uma operação que recria o documento e restaura os metadados descritivos
+// (dicionário Info e pacote XMP) quando ela os descarta, como a reorganização de páginas
+func preserveMetadata(filePath string, operation func() error) error {
+	before, err := readMetadata(filePath)
+	if err != nil {
+		return operation()
+	}
+
+	if err := operation(); err != nil {
+		return err
+	}
+
+	metadata := before.Merged()
+	if !hasDescriptiveMetadata(metadata) {
+		return nil
+	}
+	if after, err := readMetadata(filePath); err == nil &&
+		sameDescriptiveMetadata(after.Merged(), metadata) && (before.XMP == nil || after.XMP != nil) {
+		return nil
+	}
+
+	if err := writeMetadata(filePath, metadata); err != nil {
+		return fmt.Errorf("erro ao restaurar metadados: %w", err)
+	}
+	return nil
+}
+
+// hasDescriptiveMetadata verifica se há metadados editáveis (os demais são gerados pelas ferramentas)
+func hasDescriptiveMetadata(metadata appModel.DocumentMetadata) bool {
+	return metadata.Title != "" || metadata.Author != "" || metadata.Subject != "" ||
+		metadata.Keywords != "" || metadata.Creator != "" || len(metadata.Custom) > 0
+}
+
+// sameDescriptiveMetadata compara os metadados editáveis
+func sameDescriptiveMetadata(a, b appModel.DocumentMetadata) bool {
+	if a.Title != b.Title || a.Author != b.Author || a.Subject != b.Subject ||
+		a.Keywords != b.Keywords || a.Creator != b.Creator || len(a.Custom) != len(b.Custom) {
+		return false
+	}
+	for name, value := range a.Custom {
+		if b.Custom[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// infoDict retorna o dicionário Info do trailer (nil quando ausente)
+func infoDict(pdfCtx *pdfcpuModel.Context) (types.Dict, error) {
+	if pdfCtx.Info == nil {
+		return nil, nil
+	}
+	return pdfCtx.DereferenceDict(*pdfCtx.Info)
+}
+
+// readInfoDict converte as entradas do dicionário Info para o modelo
+func readInfoDict(pdfCtx *pdfcpuModel.Context, info types.Dict) appModel.DocumentMetadata {
+	// Espaços nas extremidades são descartados, como na leitura do XMP
+	text := func(key string) string {
+		return strings.TrimSpace(dictText(pdfCtx, info, key))
+	}
+
+	metadata := appModel.DocumentMetadata{
+		Title:        text("Title"),
+		Author:       text("Author"),
+		Subject:      text("Subject"),
+		Keywords:     text("Keywords"),
+		Creator:      text("Creator"),
+		Producer:     text("Producer"),
+		CreationDate: infoDate(pdfCtx, info, "CreationDate"),
+		ModDate:      infoDate(pdfCtx, info, "ModDate"),
+	}
+
+	for key := range info {
+		if infoStandardKeys[key] {
+			continue
+		}
+		if value := text(key); value != "" {
+			if metadata.Custom == nil {
+				metadata.Custom = make(appModel.MetadataProperties)
+			}
+			metadata.Custom[key] = value
+		}
+	}
+
+	return metadata
+}
+
+// infoDate lê uma data do dicionário Info (D:AAAAMMDDHHmmSSOHH'mm), tolerando formatos incompletos
+func infoDate(pdfCtx *pdfcpuModel.Context, info types.Dict, key string) *time.Time {
+	text := dictText(pdfCtx, info, key)
+	if text == "" {
+		return nil
+	}
+	date, ok := types.DateTime(text, true)
+	if !ok {
+		return nil
+	}
+	return &date
+}
+
+// xmpPacket retorna o conteúdo decodificado do fluxo /Metadata do catálogo (nil quando ausente)
+func xmpPacket(pdfCtx *pdfcpuModel.Context) ([]byte, error) {
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return nil, err
+	}
+	if rootDict["Metadata"] == nil {
+		return nil, nil
+	}
+
+	stream, _, err := pdfCtx.DereferenceStreamDict(rootDict["Metadata"])
+	if err != nil || stream == nil {
+		return nil, err
+	}
+	if err := stream.Decode(); err != nil {
+		return nil, err
+	}
+	return stream.Content, nil
+}
+
+// setXMPPacket grava o pacote XMP no fluxo /Metadata do catálogo, sem compressão (legível por indexadores)
+func setXMPPacket(update *incrementalUpdate, packet []byte) error {
+	pdfCtx := update.ctx
+
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return fmt.Errorf("erro ao ler catálogo do PDF: %w", err)
+	}
+
+	stream := types.StreamDict{
+		Dict:    types.Dict{"Type": types.Name("Metadata"), "Subtype": types.Name("XML")},
+		Content: packet,
+		Raw:     packet,
+	}
+
+	if ref, ok := rootDict["Metadata"].(types.IndirectRef); ok {
+		if entry, found := pdfCtx.FindTableEntryForIndRef(&ref); found {
+			entry.Object = stream
+			update.touch(ref)
+			return nil
+		}
+	}
+
+	streamRef, err := update.add(stream)
+	if err != nil {
+		return err
+	}
+	rootDict.Update("Metadata", streamRef)
+	update.touch(*pdfCtx.Root)
+	return nil
+}
+
+// buildXMP gera o pacote XMP com os metadados do documento (Dublin Core, XMP básico, PDF e propriedades
+// personalizadas em pdfx, como fazem os leitores de PDF). Com um nível PDF/A declarado, inclui a
+// identificação pdfaid e o esquema de extensão que descreve as propriedades pdfx (ISO 19005-1, 6.7.8)
+func buildXMP(metadata appModel.DocumentMetadata, now time.Time) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<?xpacket begin=\"\ufeff\" id=\"W5M0MpCehiHzreSzNTczkc9d\"?>\n")
+	buf.WriteString("<x:xmpmeta xmlns:x=\"adobe:ns:meta/\">\n")
+	buf.WriteString(" <rdf:RDF xmlns:rdf=\"" + xmpNamespaceRDF + "\">\n")
+	buf.WriteString("  <rdf:Description rdf:about=\"\"\n")
+	buf.WriteString("    xmlns:dc=\"" + xmpNamespaceDC + "\"\n")
+	buf.WriteString("    xmlns:xmp=\"" + xmpNamespaceXMP + "\"\n")
+	buf.WriteString("    xmlns:pdf=\"" + xmpNamespacePDF + "\"\n")
+	if metadata.PDFA != "" {
+		buf.WriteString("    xmlns:pdfaid=\"" + xmpNamespacePDFAID + "\"\n")
+	}
+	buf.WriteString("    xmlns:pdfx=\"" + xmpNamespacePDFX + "\">\n")
+
+	property := func(name, value string) {
+		if value == "" {
+			return
+		}
+		buf.WriteString("   <" + name + ">")
+		xml.EscapeText(&buf, []byte(value))
+		buf.WriteString("</" + name + ">\n")
+	}
+	container := func(name, kind, value string) {
+		if value == "" {
+			return
+		}
+		lang := ""
+		if kind == "Alt" {
+			lang = " xml:lang=\"x-default\""
+		}
+		buf.WriteString("   <" + name + "><rdf:" + kind + "><rdf:li" + lang + ">")
+		xml.EscapeText(&buf, []byte(value))
+		buf.WriteString("</rdf:li></rdf:" + kind + "></" + name + ">\n")
+	}
+	date := func(name string, value *time.Time) {
+		if value != nil {
+			property(name, value.Format(time.RFC3339))
+		}
+	}
+
+	property("dc:format", "application/pdf")
+	container("dc:title", "Alt", metadata.Title)
+	container("dc:creator", "Seq", metadata.Author)
+	container("dc:description", "Alt", metadata.Subject)
+	property("pdf:Keywords", metadata.Keywords)
+	property("pdf:Producer", metadata.Producer)
+	property("xmp:CreatorTool", metadata.Creator)
+	date("xmp:CreateDate", metadata.CreationDate)
+	date("xmp:ModifyDate", metadata.ModDate)
+	date("xmp:MetadataDate", &now)
+
+	names := make([]string, 0, len(metadata.Custom))
+	for name := range metadata.Custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		property("pdfx:"+xmpEncodePropertyName(name), metadata.Custom[name])
+	}
+
+	if metadata.PDFA != "" {
+		part, level := splitPDFAConformance(metadata.PDFA)
+		property("pdfaid:part", part)
+		property("pdfaid:conformance", strings.ToUpper(level))
+	}
+	buf.WriteString("  </rdf:Description>\n")
+
+	if metadata.PDFA != "" && len(names) > 0 {
+		buf.WriteString("  <rdf:Description rdf:about=\"\"\n")
+		buf.WriteString("    xmlns:pdfaExtension=\"" + xmpNamespacePDFAExtension + "\"\n")
+		buf.WriteString("    xmlns:pdfaSchema=\"" + xmpNamespacePDFASchema + "\"\n")
+		buf.WriteString("    xmlns:pdfaProperty=\"" + xmpNamespacePDFAProperty + "\">\n")
+		buf.WriteString("   <pdfaExtension:schemas><rdf:Bag><rdf:li rdf:parseType=\"Resource\">\n")
+		buf.WriteString("    <pdfaSchema:schema>Custom document information properties</pdfaSchema:schema>\n")
+		buf.WriteString("    <pdfaSchema:namespaceURI>" + xmpNamespacePDFX + "</pdfaSchema:namespaceURI>\n")
+		buf.WriteString("    <pdfaSchema:prefix>pdfx</pdfaSchema:prefix>\n")
+		buf.WriteString("    <pdfaSchema:property><rdf:Seq>\n")
+		for _, name := range names {
+			buf.WriteString("     <rdf:li rdf:parseType=\"Resource\">")
+			buf.WriteString("<pdfaProperty:name>" + xmpEncodePropertyName(name) + "</pdfaProperty:name>")
+			buf.WriteString("<pdfaProperty:valueType>Text</pdfaProperty:valueType>")
+			buf.WriteString("<pdfaProperty:category>external</pdfaProperty:category>")
+			buf.WriteString("<pdfaProperty:description>Document information entry</pdfaProperty:description>")
+			buf.WriteString("</rdf:li>\n")
+		}
+		buf.WriteString("    </rdf:Seq></pdfaSchema:property>\n")
+		buf.WriteString("   </rdf:li></rdf:Bag></pdfaExtension:schemas>\n")
+		buf.WriteString("  </rdf:Description>\n")
+	}
+	buf.WriteString(" </rdf:RDF>\n")
+	buf.WriteString("</x:xmpmeta>\n")
+	for i := 0; i < xmpPaddingSize/64; i++ {
+		buf.WriteString(strings.Repeat(" ", 63) + "\n")
+	}
+	buf.WriteString("<?xpacket end=\"w\"?>")
+
+	return buf.Bytes()
+}
+
+// xmpProperty acumula o valor de uma propriedade XMP durante a leitura
+type xmpProperty struct {
+	name   xml.Name
+	depth  int
+	text   strings.Builder
+	items  []string
+	inItem bool
+	lang   string // Idioma do item atual de rdf:Alt
+	chosen int    // Índice do item x-default em rdf:Alt (-1 quando ausente)
+}
+
+// parseXMP lê as propriedades conhecidas do pacote XMP, em elementos ou atributos de rdf:Description
+func parseXMP(packet []byte) (*appModel.DocumentMetadata, error) {
+	metadata := &appModel.DocumentMetadata{}
+	decoder := xml.NewDecoder(bytes.NewReader(packet))
+	decoder.Strict = false
+
+	depth := 0
+	descriptionDepth := -1
+	var current *xmpProperty
+	var item strings.Builder
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			depth++
+			switch {
+			case current != nil:
+				if t.Name.Space == xmpNamespaceRDF && t.Name.Local == "li" {
+					current.inItem = true
+					current.lang = ""
+					for _, attr := range t.Attr {
+						if attr.Name.Space == xmpNamespaceXML && attr.Name.Local == "lang" {
+							current.lang = attr.Value
+						}
+					}
+					item.Reset()
+				}
+			case t.Name.Space == xmpNamespaceRDF && t.Name.Local == "Description":
+				descriptionDepth = depth
+				for _, attr := range t.Attr {
+					setXMPProperty(metadata, attr.Name, []string{attr.Value}, 0)
+				}
+			case descriptionDepth >= 0 && depth == descriptionDepth+1:
+				current = &xmpProperty{name: t.Name, depth: depth, chosen: -1}
+			}
+
+		case xml.CharData:
+			if current != nil {
+				if current.inItem {
+					item.Write(t)
+				} else {
+					current.text.Write(t)
+				}
+			}
+
+		case xml.EndElement:
+			if current != nil {
+				switch {
+				case depth == current.depth:
+					values := current.items
+					if len(values) == 0 {
+						values = []string{current.text.String()}
+					}
+					setXMPProperty(metadata, current.name, values, current.chosen)
+					current = nil
+				case current.inItem && t.Name.Space == xmpNamespaceRDF && t.Name.Local == "li":
+					if current.lang == "x-default" && current.chosen < 0 {
+						current.chosen = len(current.items)
+					}
+					current.items = append(current.items, strings.TrimSpace(item.String()))
+					current.inItem = false
+				}
+			}
+			if depth == descriptionDepth {
+				descriptionDepth = -1
+			}
+			depth--
+		}
+	}
+
+	return metadata, nil
+}
+
+// setXMPProperty atribui o valor de uma propriedade XMP conhecida aos metadados
+// Alternativas de idioma usam o item chosen (x-default) ou o primeiro; sequências são unidas por "; "
+func setXMPProperty(metadata *appModel.DocumentMetadata, name xml.Name, values []string, chosen int) {
+	if len(values) == 0 {
+		return
+	}
+	single := values[0]
+	if chosen >= 0 && chosen < len(values) {
+		single = values[chosen]
+	}
+	single = strings.TrimSpace(single)
+
+	switch name.Space {
+	case xmpNamespaceDC:
+		switch name.Local {
+		case "title":
+			metadata.Title = single
+		case "description":
+			metadata.Subject = single
+		case "creator":
+			metadata.Author = strings.Join(values, "; ")
+		}
+	case xmpNamespacePDF:
+		switch name.Local {
+		case "Keywords":
+			metadata.Keywords = single
+		case "Producer":
+			metadata.Producer = single
+		}
+	case xmpNamespaceXMP:
+		switch name.Local {
+		case "CreatorTool":
+			metadata.Creator = single
+		case "CreateDate":
+			metadata.CreationDate = parseXMPDate(single)
+		case "ModifyDate":
+			metadata.ModDate = parseXMPDate(single)
+		}
+	case xmpNamespacePDFAID:
+		// part e conformance podem aparecer em qualquer ordem; o nível é montado como "2b"
+		part, level := splitPDFAConformance(metadata.PDFA)
+		switch name.Local {
+		case "part":
+			part = single
+		case "conformance":
+			level = strings.ToLower(single)
+		}
+		metadata.PDFA = appModel.PDFAConformance(part + level)
+	case xmpNamespacePDFX:
+		if single != "" {
+			if metadata.Custom == nil {
+				metadata.Custom = make(appModel.MetadataProperties)
+			}
+			metadata.Custom[xmpPropertyName(name.Local)] = single
+		}
+	}
+}
+
+// splitPDFAConformance separa a parte (dígitos iniciais) e o nível de conformidade de um nível PDF/A
+func splitPDFAConformance(conformance appModel.PDFAConformance) (string, string) {
+	value := string(conformance)
+	digits := len(value) - len(strings.TrimLeft(value, "0123456789"))
+	return value[:digits], value[digits:]
+}
+
+// xmpPropertyName decodifica os caracteres que o Acrobat escapa nos nomes de propriedades pdfx por não serem
+// válidos em nomes XML ("ↂ0020" = espaço)
+func xmpPropertyName(name string) string {
+	if !strings.ContainsRune(name, 'ↂ') {
+		return name
+	}
+
+	var decoded strings.Builder
+	runes := []rune(name)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == 'ↂ' && i+4 < len(runes) {
+			if code, err := strconv.ParseUint(string(runes[i+1:i+5]), 16, 32); err == nil {
+				decoded.WriteRune(rune(code))
+				i += 4
+				continue
+			}
+		}
+		decoded.WriteRune(runes[i])
+	}
+	return decoded.String()
+}
+
+// xmpEncodePropertyName escapa, como o Acrobat, os caracteres não permitidos em nomes XML
+func xmpEncodePropertyName(name string) string {
+	var encoded strings.Builder
+	for i, r := range []rune(name) {
+		valid := r == '_' || unicode.IsLetter(r) || (i > 0 && (r == '-' || r == '.' || unicode.IsDigit(r)))
+		if valid && r != 'ↂ' {
+			encoded.WriteRune(r)
+		} else {
+			fmt.Fprintf(&encoded, "ↂ%04X", r)
+		}
+	}
+	return encoded.String()
+}
+
+// parseXMPDate lê uma data XMP (ISO 8601 com precisão variável)
+func parseXMPDate(value string) *time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02T15:04Z07:00", "2006-01-02T15:04", "2006-01-02", "2006-01", "2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return &date
+		}
+	}
+	return nil
+}
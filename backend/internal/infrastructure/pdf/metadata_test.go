@@ -0,0 +1,73 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// setProducer grava o Producer no dicionário Info em uma atualização incremental, como um PDF
+// gerado por outro programa
+func setProducer(t *testing.T, filePath, producer string) {
+	t.Helper()
+
+	original, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("erro ao ler PDF: %v", err)
+	}
+	pdfCtx, err := api.ReadContext(bytes.NewReader(original), pdfcpuModel.NewDefaultConfiguration())
+	if err != nil {
+		t.Fatalf("erro ao ler PDF: %v", err)
+	}
+
+	update := newIncrementalUpdate(pdfCtx)
+	infoRef, err := update.add(types.Dict{"Producer": types.StringLiteral(producer)})
+	if err != nil {
+		t.Fatalf("erro ao criar dicionário Info: %v", err)
+	}
+	pdfCtx.Info = &infoRef
+
+	output, _, err := update.write(original)
+	if err != nil {
+		t.Fatalf("erro ao gravar atualização: %v", err)
+	}
+	if err := os.WriteFile(filePath, output, 0o644); err != nil {
+		t.Fatalf("erro ao salvar PDF: %v", err)
+	}
+}
+
+func TestMetadataStaysInSyncAfterRewrite(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	p := &PDFCPUProcessor{}
+	filePath := newBlankPDF(t, 2)
+	setProducer(t, filePath, "Microsoft Word")
+
+	metadata := appModel.DocumentMetadata{Title: "Contrato", Author: "Jurídico"}
+	if err := p.WriteMetadata(context.Background(), filePath, metadata); err != nil {
+		t.Fatalf("WriteMetadata: %v", err)
+	}
+	// A rotação reescreve o arquivo com o pdfcpu, que atualiza o Producer apenas no dicionário Info
+	if err := p.RotatePage(context.Background(), filePath, 1, 90); err != nil {
+		t.Fatalf("RotatePage: %v", err)
+	}
+
+	read, err := p.ReadMetadata(context.Background(), filePath)
+	if err != nil {
+		t.Fatalf("ReadMetadata: %v", err)
+	}
+	if !read.InSync() {
+		t.Errorf("metadados fora de sincronia: Info %+v, XMP %+v", read.Info, read.XMP)
+	}
+	if read.Info.Title != metadata.Title {
+		t.Errorf("título %q, esperado %q", read.Info.Title, metadata.Title)
+	}
+}
@@ -0,0 +1,490 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"math"
+	"os"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/unidoc/unipdf/v3/contentstream"
+	"go.uber.org/zap"
+	xdraw "golang.org/x/image/draw"
+)
+
+// Profundidade máxima de XObjects de formulário aninhados percorridos na busca por imagens
+const maxOptimizeFormDepth = 8
+
+// OptimizePDF reduz o tamanho do documento: reamostra e recomprime em JPEG as imagens exibidas acima da
+// resolução limite, comprime fluxos gravados sem compressão, remove miniaturas de páginas e dados privados
+// de aplicações, unifica fontes, imagens e content streams duplicados, descarta recursos não usados pelo
+// conteúdo (inclusive fontes) e objetos sem referência e grava os objetos em object streams
+// Fontes embutidas em uso são mantidas inteiras: não há geração de subset dos glifos usados
+// O arquivo só é substituído quando o resultado é menor que o original
+func (p *PDFCPUProcessor) OptimizePDF(ctx context.Context, filePath string, options appModel.OptimizeOptions) (*appModel.OptimizeReport, error) {
+	stat, err := os.Stat(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+	report := &appModel.OptimizeReport{SizeBefore: stat.Size(), SizeAfter: stat.Size()}
+
+	encrypted, err := isEncrypted(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if encrypted {
+		return nil, errors.New("documento protegido por senha; remova a proteção antes de otimizar")
+	}
+
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	resolutions, err := imageResolutions(pdfCtx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao analisar imagens: %w", err)
+	}
+
+	resampled := make(map[int]bool)
+	for objNr, dpi := range resolutions {
+		if dpi <= options.DownsampleAbove {
+			continue
+		}
+		done, err := downsampleImage(pdfCtx, objNr, options.ImageDPI/dpi, options.JPEGQuality, resampled)
+		if err != nil {
+			// Imagens que não podem ser decodificadas são mantidas como estão
+			logger.Logger.Warn("Erro ao reamostrar imagem",
+				zap.String("file", filePath),
+				zap.Int("object", objNr),
+				zap.Error(err),
+			)
+			continue
+		}
+		if done {
+			report.ImagesDownsampled++
+		}
+	}
+
+	report.StreamsCompressed = compressStreams(pdfCtx)
+
+	if err := stripPrivateData(pdfCtx); err != nil {
+		return nil, err
+	}
+
+	pdfCtx.Conf.OptimizeResourceDicts = true
+	pdfCtx.Conf.OptimizeDuplicateContentStreams = true
+	pdfCtx.Conf.WriteObjectStream = true
+	pdfCtx.Conf.WriteXRefStream = true
+	if err := api.OptimizeContext(pdfCtx); err != nil {
+		return nil, fmt.Errorf("erro ao otimizar PDF: %w", err)
+	}
+	if pdfCtx.Optimize != nil {
+		report.DuplicatesRemoved = len(pdfCtx.Optimize.DuplicateFonts) + len(pdfCtx.Optimize.DuplicateImages)
+	}
+
+	var optimized bytes.Buffer
+	if err := api.WriteContext(pdfCtx, &optimized); err != nil {
+		return nil, fmt.Errorf("erro ao gravar PDF otimizado: %w", err)
+	}
+
+	if int64(optimized.Len()) < report.SizeBefore {
+		if err := rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+			_, err := out.Write(optimized.Bytes())
+			return err
+		}); err != nil {
+			return nil, fmt.Errorf("erro ao salvar PDF: %w", err)
+		}
+		report.SizeAfter = int64(optimized.Len())
+	}
+
+	logger.Logger.Debug("PDF otimizado",
+		zap.String("file", filePath),
+		zap.Int64("size_before", report.SizeBefore),
+		zap.Int64("size_after", report.SizeAfter),
+		zap.Int("images_downsampled", report.ImagesDownsampled),
+	)
+
+	return report, nil
+}
+
+// imageResolutions retorna a resolução (pixels por polegada, no eixo de menor resolução) de cada imagem
+// exibida pelas páginas; imagens exibidas mais de uma vez ficam com a menor resolução (maior ampliação)
+func imageResolutions(pdfCtx *pdfcpuModel.Context) (map[int]float64, error) {
+	resolutions := make(map[int]float64)
+
+	for pageNum := 1; pageNum <= pdfCtx.PageCount; pageNum++ {
+		pageDict, _, inherited, err := pdfCtx.PageDict(pageNum, true)
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := pdfCtx.PageContent(pageDict, pageNum)
+		if err != nil {
+			if errors.Is(err, pdfcpuModel.ErrNoContent) {
+				continue
+			}
+			return nil, err
+		}
+
+		var resources types.Dict
+		if inherited != nil {
+			resources = inherited.Resources
+		}
+		if err := scanImagePlacements(pdfCtx, content, resources, identityMatrix, resolutions, 0); err != nil {
+			return nil, fmt.Errorf("página %d: %w", pageNum, err)
+		}
+	}
+
+	return resolutions, nil
+}
+
+// scanImagePlacements percorre um content stream acompanhando a matriz de transformação (q/Q/cm) e registra
+// a resolução de cada imagem desenhada com Do, inclusive em XObjects de formulário
+func scanImagePlacements(pdfCtx *pdfcpuModel.Context, content []byte, resources types.Dict, ctm matrix, resolutions map[int]float64, depth int) error {
+	operations, err := contentstream.NewContentStreamParser(string(content)).Parse()
+	if err != nil {
+		return err
+	}
+
+	var xobjects types.Dict
+	if resources != nil {
+		xobjects, _ = pdfCtx.DereferenceDict(resources["XObject"])
+	}
+
+	var stack []matrix
+	for _, op := range *operations {
+		switch op.Operand {
+		case "q":
+			stack = append(stack, ctm)
+
+		case "Q":
+			if len(stack) > 0 {
+				ctm = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+
+		case "cm":
+			if m, ok := matrixFromParams(op.Params); ok {
+				ctm = m.multiply(ctm)
+			}
+
+		case "Do":
+			name, ok := nameParam(op.Params, 0)
+			if !ok || xobjects == nil {
+				break
+			}
+			ref, ok := xobjects[string(name)].(types.IndirectRef)
+			if !ok {
+				break
+			}
+			streamDict, _, err := pdfCtx.DereferenceStreamDict(ref)
+			if err != nil || streamDict == nil || streamDict.Subtype() == nil {
+				break
+			}
+
+			switch *streamDict.Subtype() {
+			case "Image":
+				width, height := streamDict.IntEntry("Width"), streamDict.IntEntry("Height")
+				shownWidth, shownHeight := math.Hypot(ctm[0], ctm[1]), math.Hypot(ctm[2], ctm[3])
+				if width == nil || height == nil || shownWidth == 0 || shownHeight == 0 {
+					break
+				}
+				dpi := math.Min(float64(*width)*72/shownWidth, float64(*height)*72/shownHeight)
+				objNr := ref.ObjectNumber.Value()
+				if current, found := resolutions[objNr]; !found || dpi < current {
+					resolutions[objNr] = dpi
+				}
+
+			case "Form":
+				if depth >= maxOptimizeFormDepth {
+					break
+				}
+				if err := streamDict.Decode(); err != nil {
+					break
+				}
+
+				formMatrix := identityMatrix
+				if values := dictNumbers(pdfCtx, streamDict.Dict["Matrix"]); len(values) == 6 {
+					copy(formMatrix[:], values)
+				}
+				formResources := resources
+				if dict, err := pdfCtx.DereferenceDict(streamDict.Dict["Resources"]); err == nil && dict != nil {
+					formResources = dict
+				}
+
+				if err := scanImagePlacements(pdfCtx, streamDict.Content, formResources, formMatrix.multiply(ctm), resolutions, depth+1); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// downsampleImage reamostra a imagem pelo fator informado e a grava em JPEG, junto com a sua máscara
+// suave (SMask), quando o resultado é menor. Imagens fora de tons de cinza ou RGB de 8 bits, máscaras
+// e imagens com Decode ou máscara por cor são mantidas (retorna false)
+func downsampleImage(pdfCtx *pdfcpuModel.Context, objNr int, factor float64, quality int, resampled map[int]bool) (bool, error) {
+	if resampled[objNr] || factor >= 1 {
+		return false, nil
+	}
+
+	entry, found := pdfCtx.FindTableEntryLight(objNr)
+	if !found || entry.Object == nil {
+		return false, nil
+	}
+	streamDict, ok := entry.Object.(types.StreamDict)
+	if !ok {
+		return false, nil
+	}
+	if imageMask := streamDict.BooleanEntry("ImageMask"); imageMask != nil && *imageMask {
+		return false, nil
+	}
+	if streamDict.Dict["Decode"] != nil {
+		return false, nil
+	}
+	if _, colorKeyed := streamDict.Dict["Mask"].(types.Array); colorKeyed {
+		return false, nil
+	}
+	if imageComponents(pdfCtx, streamDict.Dict["ColorSpace"]) == 0 {
+		return false, nil
+	}
+
+	src, err := decodeImageStream(&streamDict)
+	if err != nil || src == nil {
+		return false, err
+	}
+
+	width, height := scaledSize(src.Bounds(), factor)
+	var dst xdraw.Image
+	if _, gray := src.(*image.Gray); gray {
+		dst = image.NewGray(image.Rect(0, 0, width, height))
+	} else {
+		dst = image.NewRGBA(image.Rect(0, 0, width, height))
+	}
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), xdraw.Src, nil)
+
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, dst, &jpeg.Options{Quality: quality}); err != nil {
+		return false, err
+	}
+	if encoded.Len() >= len(streamDict.Raw) {
+		return false, nil
+	}
+
+	dict := streamDict.Dict.Clone().(types.Dict)
+	dict["Width"] = types.Integer(width)
+	dict["Height"] = types.Integer(height)
+	dict["BitsPerComponent"] = types.Integer(8)
+	dict["Filter"] = types.Name(filter.DCT)
+	delete(dict, "DecodeParms")
+	entry.Object = encodedStream(dict, []types.PDFFilter{{Name: filter.DCT}}, encoded.Bytes())
+	resampled[objNr] = true
+
+	if ref, ok := dict["SMask"].(types.IndirectRef); ok {
+		if err := downsampleMask(pdfCtx, ref.ObjectNumber.Value(), factor, resampled); err != nil {
+			logger.Logger.Warn("Erro ao reamostrar máscara de imagem", zap.Int("object", objNr), zap.Error(err))
+		}
+	}
+
+	return true, nil
+}
+
+// downsampleMask reamostra uma máscara suave (tons de cinza) pelo fator informado, com compressão Flate
+func downsampleMask(pdfCtx *pdfcpuModel.Context, objNr int, factor float64, resampled map[int]bool) error {
+	if resampled[objNr] {
+		return nil
+	}
+	entry, found := pdfCtx.FindTableEntryLight(objNr)
+	if !found || entry.Object == nil {
+		return nil
+	}
+	streamDict, ok := entry.Object.(types.StreamDict)
+	if !ok || streamDict.Dict["Decode"] != nil {
+		return nil
+	}
+
+	src, err := decodeImageStream(&streamDict)
+	if err != nil {
+		return err
+	}
+	gray, ok := src.(*image.Gray)
+	if !ok {
+		return nil
+	}
+
+	width, height := scaledSize(gray.Bounds(), factor)
+	dst := image.NewGray(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), gray, gray.Bounds(), xdraw.Src, nil)
+
+	dict := streamDict.Dict.Clone().(types.Dict)
+	dict["Width"] = types.Integer(width)
+	dict["Height"] = types.Integer(height)
+	dict["BitsPerComponent"] = types.Integer(8)
+	dict["Filter"] = types.Name(filter.Flate)
+	delete(dict, "DecodeParms")
+
+	mask := types.NewStreamDict(dict, 0, nil, nil, []types.PDFFilter{{Name: filter.Flate}})
+	mask.Content = dst.Pix
+	if err := mask.Encode(); err != nil {
+		return err
+	}
+	entry.Object = mask
+	resampled[objNr] = true
+
+	return nil
+}
+
+// decodeImageStream decodifica uma imagem de 8 bits por componente em tons de cinza ou RGB: JPEG
+// (DCTDecode) ou amostras com filtros sem perdas. Retorna nil para os demais formatos
+func decodeImageStream(streamDict *types.StreamDict) (image.Image, error) {
+	for _, f := range streamDict.FilterPipeline {
+		switch f.Name {
+		case filter.DCT:
+			if len(streamDict.FilterPipeline) != 1 {
+				return nil, nil
+			}
+			img, err := jpeg.Decode(bytes.NewReader(streamDict.Raw))
+			if err != nil {
+				return nil, err
+			}
+			if _, cmyk := img.(*image.CMYK); cmyk {
+				return nil, nil
+			}
+			return img, nil
+		case filter.Flate, filter.LZW, filter.RunLength, filter.ASCII85, filter.ASCIIHex:
+		default:
+			return nil, nil
+		}
+	}
+
+	width, height := streamDict.IntEntry("Width"), streamDict.IntEntry("Height")
+	bpc := streamDict.IntEntry("BitsPerComponent")
+	if width == nil || height == nil || bpc == nil || *bpc != 8 || *width <= 0 || *height <= 0 {
+		return nil, nil
+	}
+
+	if err := streamDict.Decode(); err != nil {
+		return nil, err
+	}
+	pixels := *width * *height
+	rect := image.Rect(0, 0, *width, *height)
+
+	switch len(streamDict.Content) / pixels {
+	case 1:
+		gray := image.NewGray(rect)
+		copy(gray.Pix, streamDict.Content)
+		return gray, nil
+	case 3:
+		rgba := image.NewRGBA(rect)
+		for i := 0; i < pixels; i++ {
+			copy(rgba.Pix[i*4:i*4+3], streamDict.Content[i*3:i*3+3])
+			rgba.Pix[i*4+3] = 0xff
+		}
+		return rgba, nil
+	}
+	return nil, nil
+}
+
+// imageComponents retorna o número de componentes de cor de uma imagem em tons de cinza ou RGB
+// (DeviceGray, DeviceRGB ou ICCBased com 1 ou 3 componentes); 0 para os demais espaços de cor
+func imageComponents(pdfCtx *pdfcpuModel.Context, colorSpace types.Object) int {
+	obj, err := pdfCtx.Dereference(colorSpace)
+	if err != nil {
+		return 0
+	}
+
+	switch cs := obj.(type) {
+	case types.Name:
+		switch cs {
+		case "DeviceGray":
+			return 1
+		case "DeviceRGB":
+			return 3
+		}
+	case types.Array:
+		if len(cs) == 2 && cs[0] == types.Name("ICCBased") {
+			profile, _, err := pdfCtx.DereferenceStreamDict(cs[1])
+			if err != nil || profile == nil {
+				return 0
+			}
+			if n := profile.IntEntry("N"); n != nil && (*n == 1 || *n == 3) {
+				return *n
+			}
+		}
+	}
+	return 0
+}
+
+// scaledSize calcula as dimensões reamostradas (mínimo de 1 pixel)
+func scaledSize(bounds image.Rectangle, factor float64) (int, int) {
+	width := int(math.Max(1, math.Round(float64(bounds.Dx())*factor)))
+	height := int(math.Max(1, math.Round(float64(bounds.Dy())*factor)))
+	return width, height
+}
+
+// encodedStream cria um fluxo com os dados já codificados pelos filtros informados
+func encodedStream(dict types.Dict, filters []types.PDFFilter, raw []byte) types.StreamDict {
+	length := int64(len(raw))
+	dict["Length"] = types.Integer(length)
+	streamDict := types.NewStreamDict(dict, 0, &length, nil, filters)
+	streamDict.Raw = raw
+	return streamDict
+}
+
+// compressStreams comprime com Flate os fluxos gravados sem compressão (exceto metadados XMP, mantidos
+// legíveis por indexadores), quando o resultado é menor. Retorna a quantidade de fluxos comprimidos
+func compressStreams(pdfCtx *pdfcpuModel.Context) int {
+	compressed := 0
+	for _, entry := range pdfCtx.Table {
+		if entry == nil || entry.Free || entry.Object == nil {
+			continue
+		}
+		streamDict, ok := entry.Object.(types.StreamDict)
+		if !ok || len(streamDict.FilterPipeline) > 0 || len(streamDict.Raw) == 0 || streamDict.Type() != nil && *streamDict.Type() == "Metadata" {
+			continue
+		}
+
+		candidate := types.NewStreamDict(streamDict.Dict.Clone().(types.Dict), 0, nil, nil, []types.PDFFilter{{Name: filter.Flate}})
+		candidate.Content = streamDict.Raw
+		candidate.IsPageContent = streamDict.IsPageContent
+		if err := candidate.Encode(); err != nil || len(candidate.Raw) >= len(streamDict.Raw) {
+			continue
+		}
+		candidate.Dict["Filter"] = types.Name(filter.Flate)
+		entry.Object = candidate
+		compressed++
+	}
+	return compressed
+}
+
+// stripPrivateData remove as miniaturas das páginas (regeneradas pelos leitores) e os dados privados de
+// aplicações (PieceInfo) das páginas e do catálogo
+func stripPrivateData(pdfCtx *pdfcpuModel.Context) error {
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return fmt.Errorf("erro ao ler catálogo: %w", err)
+	}
+	delete(rootDict, "PieceInfo")
+
+	for pageNum := 1; pageNum <= pdfCtx.PageCount; pageNum++ {
+		pageDict, _, _, err := pdfCtx.PageDict(pageNum, false)
+		if err != nil {
+			return err
+		}
+		delete(pageDict, "Thumb")
+		delete(pageDict, "PieceInfo")
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package pdf
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/unidoc/unipdf/v3/common"
+	"github.com/unidoc/unipdf/v3/contentstream"
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+)
+
+// pageContentBuilder gera o conteúdo a ser acrescentado a uma página
+// Recebe a página (para registrar recursos) e sua altura, usada na conversão de coordenadas
+type pageContentBuilder func(page *model.PdfPage, pageHeight float64) (*contentstream.ContentCreator, error)
+
+// appendPageContent acrescenta conteúdo ao content stream de uma página e salva o PDF no mesmo caminho
+func appendPageContent(filePath string, pageNum int, build pageContentBuilder) error {
+	reader, file, page, numPages, err := openPage(filePath, pageNum)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// Obtém dimensões da página
+	pageRect, err := page.GetMediaBox()
+	if err != nil {
+		return fmt.Errorf("erro ao obter dimensões da página: %w", err)
+	}
+
+	// Gera o novo conteúdo
+	content, err := build(page, pageRect.Height())
+	if err != nil {
+		return err
+	}
+
+	// Obtém o content stream existente
+	contentStreams, err := page.GetContentStreams()
+	if err != nil {
+		return fmt.Errorf("erro ao obter content stream: %w", err)
+	}
+
+	// Isola o conteúdo existente para que mudanças de estado gráfico não afetem o novo conteúdo
+	contentStreams = append([]string{"q"}, contentStreams...)
+	contentStreams = append(contentStreams, "Q", string(content.Bytes()))
+
+	// Atualiza o content stream da página
+	if err := page.SetContentStreams(contentStreams, core.NewFlateEncoder()); err != nil {
+		return fmt.Errorf("erro ao atualizar content stream: %w", err)
+	}
+
+	return writeModifiedPage(reader, numPages, pageNum, page, filePath)
+}
+
+// openPage carrega o PDF e retorna a página informada, garantindo que ela possua recursos
+// O arquivo retornado deve ser fechado pelo chamador após a escrita
+func openPage(filePath string, pageNum int) (*model.PdfReader, *os.File, *model.PdfPage, int, error) {
+	reader, file, numPages, err := openReader(filePath)
+	if err != nil {
+		return nil, nil, nil, 0, err
+	}
+
+	// Valida número da página
+	if pageNum < 1 || pageNum > numPages {
+		file.Close()
+		return nil, nil, nil, 0, fmt.Errorf("página inválida: %d (PDF tem %d páginas)", pageNum, numPages)
+	}
+
+	// Obtém a página
+	page, err := reader.GetPage(pageNum)
+	if err != nil {
+		file.Close()
+		return nil, nil, nil, 0, fmt.Errorf("erro ao obter página %d: %w", pageNum, err)
+	}
+
+	if page.Resources == nil {
+		page.Resources = model.NewPdfPageResources()
+	}
+
+	return reader, file, page, numPages, nil
+}
+
+// openReader carrega o PDF com o unipdf e retorna o número de páginas
+// O arquivo retornado deve ser fechado pelo chamador
+func openReader(filePath string) (*model.PdfReader, *os.File, int, error) {
+	// Desabilita logs do unipdf para evitar poluição
+	common.SetLogger(common.NewConsoleLogger(common.LogLevelError))
+
+	// Carrega o PDF
+	reader, file, err := model.NewPdfReaderFromFile(filePath, nil)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("erro ao carregar PDF: %w", err)
+	}
+
+	numPages, err := reader.GetNumPages()
+	if err != nil {
+		file.Close()
+		return nil, nil, 0, fmt.Errorf("erro ao obter número de páginas: %w", err)
+	}
+
+	return reader, file, numPages, nil
+}
+
+// writeModifiedPage copia todas as páginas do reader, substituindo a página modificada,
+// e salva o resultado no caminho informado
+func writeModifiedPage(reader *model.PdfReader, numPages, pageNum int, page *model.PdfPage, filePath string) error {
+	writer := model.NewPdfWriter()
+
+	for i := 1; i <= numPages; i++ {
+		pageToAdd := page
+		if i != pageNum {
+			otherPage, err := reader.GetPage(i)
+			if err != nil {
+				return fmt.Errorf("erro ao obter página %d: %w", i, err)
+			}
+			pageToAdd = otherPage
+		}
+
+		if err := writer.AddPage(pageToAdd); err != nil {
+			return fmt.Errorf("erro ao adicionar página %d: %w", i, err)
+		}
+	}
+
+	// Preserva o formulário (AcroForm), cujos widgets continuam nas páginas
+	if reader.AcroForm != nil {
+		if err := writer.SetForms(reader.AcroForm); err != nil {
+			return fmt.Errorf("erro ao preservar formulário: %w", err)
+		}
+	}
+
+	// Salva em arquivo temporário primeiro
+	tempFile, err := os.CreateTemp("", "pdf_edit_*.pdf")
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+	}
+	tempPath := tempFile.Name()
+	tempFile.Close()
+	defer os.Remove(tempPath)
+
+	if err := writer.WriteToFile(tempPath); err != nil {
+		return fmt.Errorf("erro ao salvar PDF temporário: %w", err)
+	}
+
+	// Substitui o arquivo original pelo modificado; o writer não copia o dicionário Info, o XMP nem o
+	// outline, regravado com as mesmas páginas de destino
+	order := make([]int, numPages)
+	for i := range order {
+		order[i] = i + 1
+	}
+	return preserveMetadata(filePath, func() error {
+		return preserveBookmarks(filePath, order, func() error {
+			if err := os.Rename(tempPath, filePath); err != nil {
+				return fmt.Errorf("erro ao substituir arquivo: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// registerOpacity registra um ExtGState com a opacidade informada e retorna seu nome
+func registerOpacity(resources *model.PdfPageResources, opacity float64) (core.PdfObjectName, error) {
+	gsDict := core.MakeDict()
+	gsDict.Set("Type", core.MakeName("ExtGState"))
+	gsDict.Set("CA", core.MakeFloat(opacity)) // Opacidade do contorno
+	gsDict.Set("ca", core.MakeFloat(opacity)) // Opacidade do preenchimento
+
+	// Gera um nome único para o estado gráfico
+	var gsName core.PdfObjectName
+	for i := 1; ; i++ {
+		gsName = core.PdfObjectName(fmt.Sprintf("GS%d", i))
+		if !resources.HasExtGState(gsName) {
+			break
+		}
+	}
+
+	if err := resources.AddExtGState(gsName, core.MakeIndirectObject(gsDict)); err != nil {
+		return "", fmt.Errorf("erro ao registrar opacidade: %w", err)
+	}
+
+	return gsName, nil
+}
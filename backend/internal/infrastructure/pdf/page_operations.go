@@ -0,0 +1,252 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// As operações de página usam pdfcpu e sobrescrevem o arquivo informado
+// (outFile vazio faz o pdfcpu gravar em um temporário e renomear sobre o original)
+
+// RotatePage rotaciona uma página em múltiplos de 90 graus (sentido horário)
+func (p *PDFCPUProcessor) RotatePage(ctx context.Context, filePath string, pageNum, degrees int) error {
+	if degrees%90 != 0 {
+		return fmt.Errorf("rotação deve ser múltipla de 90 graus: %d", degrees)
+	}
+
+	if _, err := validatePageNumber(filePath, pageNum); err != nil {
+		return err
+	}
+
+	// Rotação de 0 (ou 360) graus não altera a página
+	if degrees%360 == 0 {
+		return nil
+	}
+
+	config := pdfcpuModel.NewDefaultConfiguration()
+	if err := api.RotateFile(filePath, "", degrees, []string{strconv.Itoa(pageNum)}, config); err != nil {
+		return fmt.Errorf("erro ao rotacionar página %d: %w", pageNum, err)
+	}
+
+	logger.Logger.Debug("Página rotacionada",
+		zap.String("file", filePath),
+		zap.Int("page", pageNum),
+		zap.Int("degrees", degrees),
+	)
+
+	return nil
+}
+
+// DeletePage remove uma página do PDF
+func (p *PDFCPUProcessor) DeletePage(ctx context.Context, filePath string, pageNum int) error {
+	numPages, err := validatePageNumber(filePath, pageNum)
+	if err != nil {
+		return err
+	}
+
+	if numPages == 1 {
+		return fmt.Errorf("não é possível remover a única página do PDF")
+	}
+
+	order := make([]int, 0, numPages-1)
+	for i := 1; i <= numPages; i++ {
+		if i != pageNum {
+			order = append(order, i)
+		}
+	}
+
+	config := pdfcpuModel.NewDefaultConfiguration()
+	if err := preserveMetadata(filePath, func() error {
+		return preserveBookmarks(filePath, order, func() error {
+			return api.RemovePagesFile(filePath, "", []string{strconv.Itoa(pageNum)}, config)
+		})
+	}); err != nil {
+		return fmt.Errorf("erro ao remover página %d: %w", pageNum, err)
+	}
+
+	logger.Logger.Debug("Página removida",
+		zap.String("file", filePath),
+		zap.Int("page", pageNum),
+	)
+
+	return nil
+}
+
+// MovePage move uma página para a posição informada (1 = primeira página)
+func (p *PDFCPUProcessor) MovePage(ctx context.Context, filePath string, pageNum, targetIndex int) error {
+	numPages, err := validatePageNumber(filePath, pageNum)
+	if err != nil {
+		return err
+	}
+
+	if targetIndex < 1 || targetIndex > numPages {
+		return fmt.Errorf("posição de destino inválida: %d (PDF tem %d páginas)", targetIndex, numPages)
+	}
+
+	if targetIndex == pageNum {
+		return nil
+	}
+
+	// Monta a nova ordem: remove a página e a reinsere na posição de destino
+	order := make([]int, 0, numPages)
+	for i := 1; i <= numPages; i++ {
+		if i != pageNum {
+			order = append(order, i)
+		}
+	}
+	order = append(order[:targetIndex-1], append([]int{pageNum}, order[targetIndex-1:]...)...)
+
+	if err := collectPages(filePath, order); err != nil {
+		return fmt.Errorf("erro ao mover página %d: %w", pageNum, err)
+	}
+
+	logger.Logger.Debug("Página movida",
+		zap.String("file", filePath),
+		zap.Int("page", pageNum),
+		zap.Int("target_index", targetIndex),
+	)
+
+	return nil
+}
+
+// DuplicatePage insere uma cópia da página logo após a original
+func (p *PDFCPUProcessor) DuplicatePage(ctx context.Context, filePath string, pageNum int) error {
+	numPages, err := validatePageNumber(filePath, pageNum)
+	if err != nil {
+		return err
+	}
+
+	order := make([]int, 0, numPages+1)
+	for i := 1; i <= numPages; i++ {
+		order = append(order, i)
+		if i == pageNum {
+			order = append(order, i)
+		}
+	}
+
+	if err := collectPages(filePath, order); err != nil {
+		return fmt.Errorf("erro ao duplicar página %d: %w", pageNum, err)
+	}
+
+	logger.Logger.Debug("Página duplicada",
+		zap.String("file", filePath),
+		zap.Int("page", pageNum),
+	)
+
+	return nil
+}
+
+// InsertBlankPage insere uma página em branco que passará a ocupar a posição informada
+// (numPages+1 insere no final). Dimensões em PDF points (72 DPI)
+func (p *PDFCPUProcessor) InsertBlankPage(ctx context.Context, filePath string, position int, width, height float64) error {
+	if width <= 0 || height <= 0 {
+		return fmt.Errorf("dimensões da página devem ser maiores que zero")
+	}
+
+	numPages, err := api.PageCountFile(filePath)
+	if err != nil {
+		return fmt.Errorf("erro ao obter número de páginas: %w", err)
+	}
+
+	if position < 1 || position > numPages+1 {
+		return fmt.Errorf("posição inválida: %d (PDF tem %d páginas)", position, numPages)
+	}
+
+	// Insere antes da página na posição, ou após a última quando a posição é o final
+	anchor, before := position, true
+	if position == numPages+1 {
+		anchor, before = numPages, false
+	}
+
+	pageConf := &pdfcpu.PageConfiguration{
+		PageDim: &types.Dim{Width: width, Height: height},
+		UserDim: true,
+		InpUnit: types.POINTS,
+	}
+
+	order := make([]int, 0, numPages+1)
+	for i := 1; i <= numPages; i++ {
+		order = append(order, i)
+	}
+	order = append(order[:position-1], append([]int{0}, order[position-1:]...)...)
+
+	config := pdfcpuModel.NewDefaultConfiguration()
+	if err := preserveMetadata(filePath, func() error {
+		return preserveBookmarks(filePath, order, func() error {
+			return api.InsertPagesFile(filePath, "", []string{strconv.Itoa(anchor)}, before, pageConf, config)
+		})
+	}); err != nil {
+		return fmt.Errorf("erro ao inserir página em branco: %w", err)
+	}
+
+	logger.Logger.Debug("Página em branco inserida",
+		zap.String("file", filePath),
+		zap.Int("position", position),
+		zap.Float64("width", width),
+		zap.Float64("height", height),
+	)
+
+	return nil
+}
+
+// SelectPages reescreve o PDF mantendo apenas as páginas informadas, na ordem informada
+func (p *PDFCPUProcessor) SelectPages(ctx context.Context, filePath string, pages []int) error {
+	if len(pages) == 0 {
+		return fmt.Errorf("nenhuma página selecionada")
+	}
+
+	for _, pageNum := range pages {
+		if _, err := validatePageNumber(filePath, pageNum); err != nil {
+			return err
+		}
+	}
+
+	if err := collectPages(filePath, pages); err != nil {
+		return fmt.Errorf("erro ao selecionar páginas: %w", err)
+	}
+
+	logger.Logger.Debug("Páginas selecionadas",
+		zap.String("file", filePath),
+		zap.Int("pages_count", len(pages)),
+	)
+
+	return nil
+}
+
+// validatePageNumber valida o número da página e retorna o total de páginas do PDF
+func validatePageNumber(filePath string, pageNum int) (int, error) {
+	numPages, err := api.PageCountFile(filePath)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao obter número de páginas: %w", err)
+	}
+
+	if pageNum < 1 || pageNum > numPages {
+		return 0, fmt.Errorf("página inválida: %d (PDF tem %d páginas)", pageNum, numPages)
+	}
+
+	return numPages, nil
+}
+
+// collectPages reescreve o PDF com as páginas na ordem informada (repetições duplicam páginas)
+// Os marcadores acompanham suas páginas (na primeira ocorrência de páginas repetidas) e os metadados são mantidos
+func collectPages(filePath string, order []int) error {
+	selection := make([]string, 0, len(order))
+	for _, pageNum := range order {
+		selection = append(selection, strconv.Itoa(pageNum))
+	}
+
+	config := pdfcpuModel.NewDefaultConfiguration()
+	return preserveMetadata(filePath, func() error {
+		return preserveBookmarks(filePath, order, func() error {
+			return api.CollectFile(filePath, "", selection, config)
+		})
+	})
+}
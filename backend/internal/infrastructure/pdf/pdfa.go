@@ -0,0 +1,1105 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/unidoc/unipdf/v3/contentstream"
+	"go.uber.org/zap"
+)
+
+// pdfaRule identifica uma regra PDF/A pela cláusula na ISO 19005-1 e na ISO 19005-2 (a ISO 19005-3 mantém
+// a numeração da parte 2). Cláusula vazia indica que a regra não se aplica à parte
+type pdfaRule struct {
+	part1 string
+	part2 string
+}
+
+var (
+	pdfaRuleHeader            = pdfaRule{"6.1.2", "6.1.2"}
+	pdfaRuleTrailer           = pdfaRule{"6.1.3", "6.1.3"}
+	pdfaRuleCrossReference    = pdfaRule{"6.1.4", ""}
+	pdfaRuleStreams           = pdfaRule{"6.1.7", "6.1.7.1"}
+	pdfaRuleFilters           = pdfaRule{"6.1.10", "6.1.7.2"}
+	pdfaRuleEmbeddedFiles     = pdfaRule{"6.1.11", "6.8"}
+	pdfaRuleOptionalContent   = pdfaRule{"6.1.13", "6.9"}
+	pdfaRuleVersion           = pdfaRule{"", "6.1.2"}
+	pdfaRuleOutputIntent      = pdfaRule{"6.2.2", "6.2.3"}
+	pdfaRuleDeviceColors      = pdfaRule{"6.2.3.3", "6.2.4.3"}
+	pdfaRuleImages            = pdfaRule{"6.2.4", "6.2.8"}
+	pdfaRuleXObjects          = pdfaRule{"6.2.5", "6.2.9"}
+	pdfaRuleTransferFunctions = pdfaRule{"6.2.8", "6.2.5"}
+	pdfaRuleFonts             = pdfaRule{"6.3.4", "6.2.11.4"}
+	pdfaRuleTransparency      = pdfaRule{"6.4", "6.2.10"}
+	pdfaRuleAnnotationTypes   = pdfaRule{"6.5.2", "6.3.1"}
+	pdfaRuleAnnotations       = pdfaRule{"6.5.3", "6.3.2"}
+	pdfaRuleAppearances       = pdfaRule{"6.5.3", "6.3.3"}
+	pdfaRuleActions           = pdfaRule{"6.6.1", "6.5.1"}
+	pdfaRuleAdditionalActions = pdfaRule{"6.6.2", "6.5.2"}
+	pdfaRuleMetadata          = pdfaRule{"6.7.2", "6.6.2.1"}
+	pdfaRuleMetadataSync      = pdfaRule{"6.7.3", ""}
+	pdfaRuleExtensionSchemas  = pdfaRule{"6.7.8", "6.6.2.3"}
+	pdfaRuleIdentification    = pdfaRule{"6.7.11", "6.6.4"}
+	pdfaRuleForms             = pdfaRule{"6.9", "6.4.1"}
+	pdfaRuleXFA               = pdfaRule{"6.9", "6.4.2"}
+)
+
+// pdfaStandardYears são os anos de publicação de cada parte da ISO 19005
+var pdfaStandardYears = map[int]string{1: "2005", 2: "2011", 3: "2012"}
+
+// clause retorna a cláusula da regra na parte informada
+func (r pdfaRule) clause(part int) string {
+	if part == 1 {
+		return r.part1
+	}
+	return r.part2
+}
+
+// pdfaForbiddenActions são os tipos de ação proibidos em todas as partes (ISO 19005-2, 6.5.1)
+var pdfaForbiddenActions = map[string]bool{
+	"Launch": true, "Sound": true, "Movie": true, "ResetForm": true, "ImportData": true, "JavaScript": true,
+	"Hide": true, "SetOCGState": true, "Rendition": true, "Trans": true, "GoTo3DView": true, "RichMediaExecute": true,
+}
+
+// pdfaNamedActions são as únicas ações Named permitidas
+var pdfaNamedActions = map[string]bool{"NextPage": true, "PrevPage": true, "FirstPage": true, "LastPage": true}
+
+// pdfa1Annotations são os tipos de anotação permitidos no PDF/A-1 (os do PDF 1.4, exceto som, filme e anexo)
+var pdfa1Annotations = map[string]bool{
+	"Text": true, "Link": true, "FreeText": true, "Line": true, "Square": true, "Circle": true, "Polygon": true,
+	"PolyLine": true, "Highlight": true, "Underline": true, "Squiggly": true, "StrikeOut": true, "Stamp": true,
+	"Ink": true, "Popup": true, "Widget": true, "PrinterMark": true, "TrapNet": true,
+}
+
+// pdfa2ForbiddenAnnotations são os tipos de anotação proibidos no PDF/A-2 e no PDF/A-3
+var pdfa2ForbiddenAnnotations = map[string]bool{"3D": true, "Sound": true, "Screen": true, "Movie": true, "RichMedia": true}
+
+// Flags de anotação (entrada F) proibidas pelo PDF/A, além de Hidden e NoView
+const (
+	annotationFlagInvisible    = 1 << 0
+	annotationFlagToggleNoView = 1 << 8
+)
+
+// pdfaHeaderPattern valida a primeira linha exigida pelo PDF/A: %PDF-1.n
+var pdfaHeaderPattern = regexp.MustCompile(`^%PDF-1\.[0-7](\r\n|\r|\n)`)
+
+// validPDFAHeader verifica o cabeçalho %PDF-1.n seguido de um comentário com ao menos quatro bytes
+// binários (acima de 127) na linha seguinte
+func validPDFAHeader(raw []byte) bool {
+	line := pdfaHeaderPattern.Find(raw)
+	if line == nil || len(raw) < len(line)+5 || raw[len(line)] != '%' {
+		return false
+	}
+	for _, b := range raw[len(line)+1 : len(line)+5] {
+		if b < 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// ConvertToPDFA converte o documento para o nível PDF/A informado: remove a criptografia (com a senha, quando
+// exigida), JavaScript, ações e anotações proibidas, arquivos anexos (exceto no PDF/A-3), transparência e
+// camadas (no PDF/A-1), embute substitutas para as fontes não embutidas, adiciona um OutputIntent sRGB e grava
+// o XMP com a identificação PDF/A. Retorna as violações corrigidas e as que permaneceram após a conversão
+func (p *PDFCPUProcessor) ConvertToPDFA(ctx context.Context, filePath string, conformance appModel.PDFAConformance, password string) (*appModel.PDFAConversionReport, error) {
+	if !conformance.Valid() {
+		return nil, fmt.Errorf("nível PDF/A inválido: %s", conformance)
+	}
+
+	report := &appModel.PDFAConversionReport{Conformance: conformance, Fixed: []appModel.PDFAViolation{}}
+
+	decrypted, err := p.DecryptPDF(ctx, filePath, password)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := readMetadata(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	audit := newPDFAAudit(pdfCtx, conformance, p.fonts)
+	if decrypted {
+		report.Fixed = append(report.Fixed, audit.violation(pdfaRuleTrailer, 0, 0, "documento criptografado"))
+	}
+	if err := audit.run(); err != nil {
+		return nil, err
+	}
+	report.Fixed = append(report.Fixed, audit.fixed...)
+	report.EmbeddedFonts = audit.embeddedFonts
+
+	// O PDF/A-1 segue o PDF 1.4: referências cruzadas em tabela e objetos fora de object streams
+	if conformance.Part() == 1 {
+		pdfCtx.Conf.WriteObjectStream = false
+		pdfCtx.Conf.WriteXRefStream = false
+	}
+
+	if err := rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		return api.WriteContext(pdfCtx, out)
+	}); err != nil {
+		return nil, fmt.Errorf("erro ao salvar PDF: %w", err)
+	}
+
+	// Info e XMP sincronizados com a identificação PDF/A, após a gravação (que atualiza o Producer)
+	metadata := before.Merged()
+	metadata.PDFA = conformance
+	if err := writeMetadata(filePath, metadata); err != nil {
+		return nil, err
+	}
+
+	validation, err := validatePDFA(filePath, conformance)
+	if err != nil {
+		return nil, err
+	}
+	report.Remaining = validation.Violations
+
+	logger.Logger.Debug("PDF convertido para PDF/A",
+		zap.String("file", filePath),
+		zap.String("conformance", string(conformance)),
+		zap.Int("fixed", len(report.Fixed)),
+		zap.Int("remaining", len(report.Remaining)),
+	)
+
+	return report, nil
+}
+
+// ValidatePDFA verifica o documento contra as regras PDF/A do nível informado, listando cada violação com
+// a cláusula da norma
+func (p *PDFCPUProcessor) ValidatePDFA(ctx context.Context, filePath string, conformance appModel.PDFAConformance) (*appModel.PDFAValidation, error) {
+	if !conformance.Valid() {
+		return nil, fmt.Errorf("nível PDF/A inválido: %s", conformance)
+	}
+	return validatePDFA(filePath, conformance)
+}
+
+// validatePDFA valida a estrutura do arquivo e executa a auditoria sem correções
+func validatePDFA(filePath string, conformance appModel.PDFAConformance) (*appModel.PDFAValidation, error) {
+	raw, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	validation := &appModel.PDFAValidation{Conformance: conformance}
+	pdfCtx, err := api.ReadContextFile(filePath)
+	if errors.Is(err, pdfcpu.ErrWrongPassword) {
+		audit := newPDFAAudit(nil, conformance, nil)
+		validation.Violations = []appModel.PDFAViolation{audit.violation(pdfaRuleTrailer, 0, 0, "documento criptografado")}
+		return validation, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	audit := newPDFAAudit(pdfCtx, conformance, nil)
+	audit.checkFile(raw)
+	if err := audit.run(); err != nil {
+		return nil, err
+	}
+
+	validation.Declared = audit.declared
+	validation.Violations = audit.violations
+	validation.Compliant = len(audit.violations) == 0
+	return validation, nil
+}
+
+// pdfaAudit percorre o documento verificando as regras PDF/A. Com fix, cada violação corrigível é corrigida
+// e registrada em fixed; as demais são registradas em violations
+type pdfaAudit struct {
+	ctx         *pdfcpuModel.Context
+	conformance appModel.PDFAConformance
+	part        int
+	fix         bool
+	fonts       *fontRegistry // Fontes substitutas (nil na validação)
+
+	intentComponents int // Componentes do perfil do OutputIntent PDF/A (0 sem OutputIntent)
+	declared         appModel.PDFAConformance
+
+	fixed         []appModel.PDFAViolation
+	violations    []appModel.PDFAViolation
+	embeddedFonts []string
+	fontFiles     map[string]types.IndirectRef
+	seen          map[string]bool
+	checked       map[int]bool // Fontes e estados gráficos indiretos já verificados
+}
+
+// newPDFAAudit cria a auditoria; com fonts não nulo, as violações corrigíveis são corrigidas
+func newPDFAAudit(pdfCtx *pdfcpuModel.Context, conformance appModel.PDFAConformance, fonts *fontRegistry) *pdfaAudit {
+	return &pdfaAudit{
+		ctx:         pdfCtx,
+		conformance: conformance,
+		part:        conformance.Part(),
+		fix:         fonts != nil,
+		fonts:       fonts,
+		fixed:       []appModel.PDFAViolation{},
+		violations:  []appModel.PDFAViolation{},
+		fontFiles:   make(map[string]types.IndirectRef),
+		seen:        make(map[string]bool),
+		checked:     make(map[int]bool),
+	}
+}
+
+// violation monta a violação com a referência à cláusula da parte auditada
+func (a *pdfaAudit) violation(rule pdfaRule, page, object int, description string) appModel.PDFAViolation {
+	return appModel.PDFAViolation{
+		Rule:        fmt.Sprintf("ISO 19005-%d:%s, %s", a.part, pdfaStandardYears[a.part], rule.clause(a.part)),
+		Description: description,
+		Page:        page,
+		Object:      object,
+	}
+}
+
+// check registra uma violação da regra (ignorada quando a regra não se aplica à parte). Na conversão, fix
+// corrige a violação; fix nulo ou com erro indica violação que não pode ser corrigida automaticamente
+func (a *pdfaAudit) check(rule pdfaRule, page, object int, description string, fix func() error) {
+	if rule.clause(a.part) == "" {
+		return
+	}
+
+	key := fmt.Sprintf("%s|%d|%d|%s", rule.clause(a.part), page, object, description)
+	if a.seen[key] {
+		return
+	}
+	a.seen[key] = true
+
+	if a.fix && fix != nil {
+		err := fix()
+		if err == nil {
+			a.fixed = append(a.fixed, a.violation(rule, page, object, description))
+			return
+		}
+		description += ": " + err.Error()
+	}
+	a.violations = append(a.violations, a.violation(rule, page, object, description))
+}
+
+// rewrittenLater é a correção das violações resolvidas pela regravação dos metadados após a conversão
+func rewrittenLater() error {
+	return nil
+}
+
+// remove retorna a correção que exclui as chaves do dicionário
+func remove(dict types.Dict, keys ...string) func() error {
+	return func() error {
+		for _, key := range keys {
+			delete(dict, key)
+		}
+		return nil
+	}
+}
+
+// name retorna o valor de um nome (direto ou indireto); vazio quando ausente
+func (a *pdfaAudit) name(obj types.Object) string {
+	if obj == nil {
+		return ""
+	}
+	resolved, err := a.ctx.Dereference(obj)
+	if err != nil {
+		return ""
+	}
+	if name, ok := resolved.(types.Name); ok {
+		return string(name)
+	}
+	return ""
+}
+
+// dict retorna um dicionário (direto ou indireto) e o número do objeto quando indireto
+func (a *pdfaAudit) dict(obj types.Object) (types.Dict, int) {
+	if obj == nil {
+		return nil, 0
+	}
+	dict, err := a.ctx.DereferenceDict(obj)
+	if err != nil || dict == nil {
+		return nil, 0
+	}
+	if ref, ok := obj.(types.IndirectRef); ok {
+		return dict, ref.ObjectNumber.Value()
+	}
+	return dict, 0
+}
+
+// checkFile verifica a sintaxe do arquivo gravado: cabeçalho e ausência de dados após o %%EOF final
+func (a *pdfaAudit) checkFile(raw []byte) {
+	if !validPDFAHeader(raw) {
+		a.check(pdfaRuleHeader, 0, 0, "cabeçalho diferente de %PDF-1.n seguido de comentário binário", nil)
+	}
+
+	if a.part == 1 {
+		end := bytes.LastIndex(raw, []byte("%%EOF"))
+		if end < 0 || len(bytes.TrimRight(raw[end+5:], "\r\n")) > 0 {
+			a.check(pdfaRuleTrailer, 0, 0, "dados após o marcador %%EOF", nil)
+		}
+	}
+}
+
+// run executa as verificações do documento, do OutputIntent, das páginas e de todos os objetos
+func (a *pdfaAudit) run() error {
+	rootDict, err := a.ctx.Catalog()
+	if err != nil {
+		return fmt.Errorf("erro ao ler catálogo do PDF: %w", err)
+	}
+
+	a.checkDocument(rootDict)
+	a.checkMetadata(rootDict)
+	a.checkOutputIntents(rootDict)
+	if err := a.checkPages(); err != nil {
+		return err
+	}
+	a.checkObjects()
+	return nil
+}
+
+// checkDocument verifica o trailer, a versão e as entradas do catálogo
+func (a *pdfaAudit) checkDocument(rootDict types.Dict) {
+	if a.ctx.Encrypt != nil {
+		a.check(pdfaRuleTrailer, 0, 0, "documento criptografado", nil)
+	}
+	if len(a.ctx.ID) == 0 {
+		// O identificador é gerado na gravação
+		a.check(pdfaRuleTrailer, 0, 0, "trailer sem identificador do arquivo (ID)", rewrittenLater)
+	}
+	if a.ctx.XRefTable.Version() > pdfcpuModel.V17 {
+		a.check(pdfaRuleVersion, 0, 0, "versão do PDF superior a 1.7", func() error {
+			v := pdfcpuModel.V17
+			a.ctx.HeaderVersion = &v
+			a.ctx.RootVersion = nil
+			delete(rootDict, "Version")
+			return nil
+		})
+	}
+	if a.part == 1 && (a.ctx.Read.UsingXRefStreams || a.ctx.Read.UsingObjectStreams) {
+		// A gravação do PDF/A-1 desativa os fluxos de referências e os object streams
+		a.check(pdfaRuleCrossReference, 0, 0, "referências cruzadas em fluxos ou objetos em object streams", rewrittenLater)
+	}
+
+	if names, _ := a.dict(rootDict["Names"]); names != nil {
+		if names["JavaScript"] != nil {
+			a.check(pdfaRuleActions, 0, 0, "JavaScript no nível do documento", remove(names, "JavaScript"))
+		}
+		if names["EmbeddedFiles"] != nil {
+			if a.part == 3 {
+				a.checkAssociatedFiles(rootDict, names["EmbeddedFiles"])
+			} else {
+				a.check(pdfaRuleEmbeddedFiles, 0, 0, "arquivos anexos ao documento", remove(names, "EmbeddedFiles"))
+			}
+		}
+	}
+
+	a.checkAction(rootDict, "OpenAction", 0, 0)
+
+	if rootDict["OCProperties"] != nil {
+		if a.part == 1 {
+			a.check(pdfaRuleOptionalContent, 0, 0, "camadas (optional content)", remove(rootDict, "OCProperties"))
+		} else if properties, _ := a.dict(rootDict["OCProperties"]); properties != nil {
+			configs := []types.Object{properties["D"]}
+			if array, err := a.ctx.DereferenceArray(properties["Configs"]); err == nil {
+				configs = append(configs, array...)
+			}
+			for _, obj := range configs {
+				config, objNr := a.dict(obj)
+				if config == nil {
+					continue
+				}
+				if dictText(a.ctx, config, "Name") == "" {
+					a.check(pdfaRuleOptionalContent, 0, objNr, "configuração de camadas sem nome", func() error {
+						config["Name"] = types.StringLiteral(fmt.Sprintf("Config %d", objNr))
+						return nil
+					})
+				}
+				if config["AS"] != nil {
+					a.check(pdfaRuleOptionalContent, 0, objNr, "configuração de camadas com estados automáticos (AS)", remove(config, "AS"))
+				}
+			}
+		}
+	}
+
+	if form, _ := a.dict(rootDict["AcroForm"]); form != nil {
+		if needAppearances, err := a.ctx.DereferenceBoolean(form["NeedAppearances"], pdfcpuModel.V10); err == nil && needAppearances != nil && *needAppearances {
+			a.check(pdfaRuleForms, 0, 0, "formulário com NeedAppearances", remove(form, "NeedAppearances"))
+		}
+		if form["XFA"] != nil {
+			a.check(pdfaRuleXFA, 0, 0, "formulário XFA", remove(form, "XFA"))
+		}
+	}
+}
+
+// checkMetadata verifica o pacote XMP: presença, identificação PDF/A, sincronia com o Info e esquema de
+// extensão das propriedades personalizadas. Na conversão, os metadados são regravados ao final
+func (a *pdfaAudit) checkMetadata(rootDict types.Dict) {
+	packet, err := xmpPacket(a.ctx)
+	if err != nil || packet == nil {
+		a.check(pdfaRuleMetadata, 0, 0, "pacote de metadados XMP ausente", rewrittenLater)
+		a.check(pdfaRuleIdentification, 0, 0, "identificação PDF/A (pdfaid) ausente no XMP", rewrittenLater)
+		return
+	}
+
+	if a.part == 1 {
+		if stream, _, err := a.ctx.DereferenceStreamDict(rootDict["Metadata"]); err == nil && stream != nil && stream.Dict["Filter"] != nil {
+			a.check(pdfaRuleMetadata, 0, 0, "fluxo de metadados XMP comprimido", rewrittenLater)
+		}
+	}
+
+	xmp, err := parseXMP(packet)
+	if err != nil {
+		a.check(pdfaRuleMetadata, 0, 0, "pacote XMP inválido", rewrittenLater)
+		return
+	}
+	a.declared = xmp.PDFA
+	if xmp.PDFA != a.conformance {
+		description := "identificação PDF/A (pdfaid) ausente no XMP"
+		if xmp.PDFA != "" {
+			description = fmt.Sprintf("XMP declara o nível PDF/A %s", xmp.PDFA)
+		}
+		a.check(pdfaRuleIdentification, 0, 0, description, rewrittenLater)
+	}
+
+	if len(xmp.Custom) > 0 && !bytes.Contains(packet, []byte(xmpNamespacePDFASchema)) {
+		a.check(pdfaRuleExtensionSchemas, 0, 0, "propriedades personalizadas do XMP sem esquema de extensão", rewrittenLater)
+	}
+
+	if info, err := infoDict(a.ctx); err == nil && info != nil {
+		// O PDF/A exige também o Producer equivalente, que InSync não compara
+		metadata := appModel.PDFMetadata{Info: readInfoDict(a.ctx, info), XMP: xmp}
+		if !metadata.InSync() || metadata.Info.Producer != xmp.Producer {
+			a.check(pdfaRuleMetadataSync, 0, 0, "dicionário Info diferente do pacote XMP", rewrittenLater)
+		}
+	}
+}
+
+// checkOutputIntents verifica o OutputIntent PDF/A e registra o número de componentes do seu perfil,
+// usado na verificação das cores dependentes de dispositivo. Na ausência, adiciona um OutputIntent sRGB
+func (a *pdfaAudit) checkOutputIntents(rootDict types.Dict) {
+	intents, _ := a.ctx.DereferenceArray(rootDict["OutputIntents"])
+
+	var pdfaProfile types.Object
+	for _, obj := range intents {
+		intent, objNr := a.dict(obj)
+		if intent == nil || a.name(intent["S"]) != "GTS_PDFA1" {
+			continue
+		}
+		profile, _, err := a.ctx.DereferenceStreamDict(intent["DestOutputProfile"])
+		if err != nil || profile == nil {
+			continue
+		}
+		if n := profile.IntEntry("N"); n != nil && pdfaProfile == nil {
+			a.intentComponents = *n
+			pdfaProfile = intent["DestOutputProfile"]
+		}
+		if a.part == 1 {
+			if err := profile.Decode(); err == nil && len(profile.Content) > 8 && profile.Content[8] > 2 {
+				a.check(pdfaRuleOutputIntent, 0, objNr, "perfil ICC do OutputIntent com versão superior a 2", nil)
+			}
+		}
+	}
+
+	if pdfaProfile == nil {
+		a.check(pdfaRuleOutputIntent, 0, 0, "OutputIntent PDF/A com perfil ICC ausente", func() error {
+			ref, err := a.addOutputIntent(rootDict, intents)
+			pdfaProfile = ref
+			return err
+		})
+		intents, _ = a.ctx.DereferenceArray(rootDict["OutputIntents"])
+	}
+
+	// No PDF/A-2 e no PDF/A-3, todos os OutputIntents devem usar o mesmo perfil (ex.: PDF/X e PDF/A)
+	if a.part == 1 || pdfaProfile == nil {
+		return
+	}
+	kept := make(types.Array, 0, len(intents))
+	for _, obj := range intents {
+		intent, _ := a.dict(obj)
+		if intent != nil && intent["DestOutputProfile"] != nil && !sameObject(intent["DestOutputProfile"], pdfaProfile) {
+			continue
+		}
+		kept = append(kept, obj)
+	}
+	if len(kept) != len(intents) {
+		a.check(pdfaRuleOutputIntent, 0, 0, "OutputIntents com perfis ICC diferentes", func() error {
+			rootDict["OutputIntents"] = kept
+			return nil
+		})
+	}
+}
+
+// sameObject compara duas referências indiretas (ou dois objetos diretos pela representação)
+func sameObject(a, b types.Object) bool {
+	refA, okA := a.(types.IndirectRef)
+	refB, okB := b.(types.IndirectRef)
+	if okA && okB {
+		return refA.ObjectNumber == refB.ObjectNumber
+	}
+	return a.PDFString() == b.PDFString()
+}
+
+// addOutputIntent adiciona um OutputIntent GTS_PDFA1 com o perfil sRGB e retorna a referência do perfil
+func (a *pdfaAudit) addOutputIntent(rootDict types.Dict, intents types.Array) (types.Object, error) {
+	profile := types.NewStreamDict(types.Dict{"N": types.Integer(3)}, 0, nil, nil, []types.PDFFilter{{Name: filter.Flate}})
+	profile.Content = srgbICCProfile()
+	if err := profile.Encode(); err != nil {
+		return nil, err
+	}
+	profile.Dict["Filter"] = types.Name(filter.Flate)
+
+	profileRef, err := a.ctx.IndRefForNewObject(profile)
+	if err != nil {
+		return nil, err
+	}
+	intentRef, err := a.ctx.IndRefForNewObject(types.Dict{
+		"Type":                      types.Name("OutputIntent"),
+		"S":                         types.Name("GTS_PDFA1"),
+		"OutputConditionIdentifier": types.StringLiteral(srgbOutputCondition),
+		"RegistryName":              types.StringLiteral(srgbRegistryName),
+		"Info":                      types.StringLiteral(srgbOutputCondition),
+		"DestOutputProfile":         *profileRef,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rootDict["OutputIntents"] = append(append(types.Array{}, intents...), *intentRef)
+	a.intentComponents = 3
+	return *profileRef, nil
+}
+
+// checkAssociatedFiles verifica os arquivos anexos do PDF/A-3: relação com o documento (AFRelationship),
+// nome Unicode, tipo MIME e associação ao catálogo (AF)
+func (a *pdfaAudit) checkAssociatedFiles(rootDict types.Dict, tree types.Object) {
+	associated, _ := a.ctx.DereferenceArray(rootDict["AF"])
+	listed := make(map[string]bool)
+	for _, obj := range associated {
+		if ref, ok := obj.(types.IndirectRef); ok {
+			listed[ref.String()] = true
+		}
+	}
+
+	for _, obj := range nameTreeValues(a.ctx, tree, 0) {
+		spec, objNr := a.dict(obj)
+		if spec == nil {
+			continue
+		}
+		if spec["AFRelationship"] == nil {
+			a.check(pdfaRuleEmbeddedFiles, 0, objNr, "arquivo anexo sem relação com o documento (AFRelationship)", func() error {
+				spec["AFRelationship"] = types.Name("Unspecified")
+				return nil
+			})
+		}
+		if spec["UF"] == nil && spec["F"] != nil {
+			a.check(pdfaRuleEmbeddedFiles, 0, objNr, "arquivo anexo sem nome Unicode (UF)", func() error {
+				spec["UF"] = spec["F"]
+				return nil
+			})
+		}
+		if files, _ := a.dict(spec["EF"]); files != nil {
+			if stream, _, err := a.ctx.DereferenceStreamDict(files["F"]); err == nil && stream != nil && stream.Dict["Subtype"] == nil {
+				a.check(pdfaRuleEmbeddedFiles, 0, objNr, "arquivo anexo sem tipo MIME (Subtype)", func() error {
+					stream.Dict["Subtype"] = types.Name("application/octet-stream")
+					return nil
+				})
+			}
+		}
+		if ref, ok := obj.(types.IndirectRef); ok && !listed[ref.String()] {
+			a.check(pdfaRuleEmbeddedFiles, 0, objNr, "arquivo anexo não associado ao documento (AF)", func() error {
+				associated = append(associated, ref)
+				rootDict["AF"] = append(types.Array{}, associated...)
+				listed[ref.String()] = true
+				return nil
+			})
+		}
+	}
+}
+
+// nameTreeValues retorna os valores de uma árvore de nomes (Names e Kids)
+func nameTreeValues(pdfCtx *pdfcpuModel.Context, obj types.Object, depth int) []types.Object {
+	node, err := pdfCtx.DereferenceDict(obj)
+	if err != nil || node == nil || depth > 16 {
+		return nil
+	}
+
+	var values []types.Object
+	if names, err := pdfCtx.DereferenceArray(node["Names"]); err == nil {
+		for i := 1; i < len(names); i += 2 {
+			values = append(values, names[i])
+		}
+	}
+	if kids, err := pdfCtx.DereferenceArray(node["Kids"]); err == nil {
+		for _, kid := range kids {
+			values = append(values, nameTreeValues(pdfCtx, kid, depth+1)...)
+		}
+	}
+	return values
+}
+
+// checkPages verifica os grupos de transparência, as anotações e as cores do conteúdo de cada página
+func (a *pdfaAudit) checkPages() error {
+	for pageNum := 1; pageNum <= a.ctx.PageCount; pageNum++ {
+		pageDict, _, _, err := a.ctx.PageDict(pageNum, false)
+		if err != nil {
+			return fmt.Errorf("erro ao ler página %d: %w", pageNum, err)
+		}
+
+		if a.part == 1 && pageDict["Group"] != nil {
+			a.check(pdfaRuleTransparency, pageNum, 0, "grupo de transparência na página", remove(pageDict, "Group"))
+		}
+
+		a.checkAnnotations(pageDict, pageNum)
+
+		content, err := a.ctx.PageContent(pageDict, pageNum)
+		if err == nil {
+			a.checkContentColors(content, pageNum, 0)
+		}
+	}
+	return nil
+}
+
+// checkAnnotations verifica tipo, sinalizadores, opacidade e aparência das anotações da página,
+// removendo as anotações de tipos proibidos
+func (a *pdfaAudit) checkAnnotations(pageDict types.Dict, pageNum int) {
+	annots, err := a.ctx.DereferenceArray(pageDict["Annots"])
+	if err != nil || len(annots) == 0 {
+		return
+	}
+
+	kept := make(types.Array, 0, len(annots))
+	for _, obj := range annots {
+		annot, objNr := a.dict(obj)
+		if annot == nil {
+			kept = append(kept, obj)
+			continue
+		}
+		subtype := a.name(annot["Subtype"])
+
+		forbidden := (a.part == 1 && !pdfa1Annotations[subtype]) ||
+			(a.part > 1 && pdfa2ForbiddenAnnotations[subtype]) ||
+			(a.part == 2 && subtype == "FileAttachment")
+		if forbidden {
+			removed := false
+			a.check(pdfaRuleAnnotationTypes, pageNum, objNr, fmt.Sprintf("anotação do tipo %s", subtype), func() error {
+				removed = true
+				return nil
+			})
+			if removed {
+				continue
+			}
+		}
+		kept = append(kept, obj)
+
+		flags := 0
+		if value, err := a.ctx.DereferenceNumber(annot["F"]); err == nil {
+			flags = int(value)
+		}
+		wanted := (flags | annotationFlagPrint) &^ (annotationFlagInvisible | annotationFlagHidden | annotationFlagNoView | annotationFlagToggleNoView)
+		if (annot["F"] == nil && subtype != "Popup") || (annot["F"] != nil && flags != wanted) {
+			a.check(pdfaRuleAnnotations, pageNum, objNr, fmt.Sprintf("anotação %s não imprimível ou oculta", subtype), func() error {
+				annot["F"] = types.Integer(wanted)
+				return nil
+			})
+		}
+
+		if a.part == 1 {
+			if opacity, err := a.ctx.DereferenceNumber(annot["CA"]); err == nil && opacity != 1 {
+				a.check(pdfaRuleAnnotations, pageNum, objNr, fmt.Sprintf("anotação %s com opacidade (CA)", subtype), remove(annot, "CA"))
+			}
+		}
+
+		appearance, _ := a.dict(annot["AP"])
+		if appearance != nil && (appearance["D"] != nil || appearance["R"] != nil) {
+			a.check(pdfaRuleAppearances, pageNum, objNr, fmt.Sprintf("anotação %s com aparências além da normal (N)", subtype), remove(appearance, "D", "R"))
+		}
+		if a.part > 1 && subtype != "Popup" && subtype != "Link" && (appearance == nil || appearance["N"] == nil) {
+			if rect, ok := dictRect(a.ctx, annot, "Rect"); ok && rect.urx > rect.llx && rect.ury > rect.lly {
+				var fix func() error
+				if subtype == "Text" {
+					fix = func() error {
+						return a.addNoteAppearance(annot, rect)
+					}
+				}
+				a.check(pdfaRuleAppearances, pageNum, objNr, fmt.Sprintf("anotação %s sem aparência", subtype), fix)
+			}
+		}
+	}
+
+	if len(kept) != len(annots) {
+		if ref, ok := pageDict["Annots"].(types.IndirectRef); ok {
+			if entry, found := a.ctx.FindTableEntryForIndRef(&ref); found {
+				entry.Object = kept
+				return
+			}
+		}
+		pageDict["Annots"] = kept
+	}
+}
+
+// addNoteAppearance cria a aparência do ícone de nota adesiva para uma anotação Text, na cor da anotação
+// (amarelo quando ausente)
+func (a *pdfaAudit) addNoteAppearance(annot types.Dict, rect bbox) error {
+	rgb := "1 1 0"
+	if color := dictNumbers(a.ctx, annot["C"]); len(color) == 3 {
+		rgb = fmt.Sprintf("%s %s %s", formatNumber(color[0]), formatNumber(color[1]), formatNumber(color[2]))
+	}
+
+	writer := &annotationWriter{ctx: a.ctx}
+	resources := types.Dict{"ExtGState": types.Dict{"GS0": types.Dict{"Type": types.Name("ExtGState")}}}
+	appearance, err := writer.appearance(noteAppearance(rect, rgb), rect, resources)
+	if err != nil {
+		return err
+	}
+	annot["AP"] = types.Dict{"N": appearance}
+	return nil
+}
+
+// checkObjects verifica todos os objetos do documento: ações, fluxos, XObjects e recursos
+func (a *pdfaAudit) checkObjects() {
+	objNrs := make([]int, 0, len(a.ctx.Table))
+	for objNr, entry := range a.ctx.Table {
+		if entry != nil && !entry.Free && entry.Object != nil {
+			objNrs = append(objNrs, objNr)
+		}
+	}
+	sort.Ints(objNrs)
+
+	for _, objNr := range objNrs {
+		entry := a.ctx.Table[objNr]
+		switch obj := entry.Object.(type) {
+		case types.Dict:
+			a.checkDict(obj, objNr)
+		case types.StreamDict:
+			a.checkDict(obj.Dict, objNr)
+			a.checkStream(entry, obj, objNr)
+		}
+	}
+}
+
+// checkDict verifica ações, ações adicionais, camadas e recursos de um dicionário
+func (a *pdfaAudit) checkDict(dict types.Dict, objNr int) {
+	if dict["AA"] != nil {
+		a.check(pdfaRuleAdditionalActions, 0, objNr, "ações adicionais (AA)", remove(dict, "AA"))
+	}
+	a.checkAction(dict, "A", 0, objNr)
+	a.checkAction(dict, "Next", 0, objNr)
+
+	if a.part == 1 && dict["OC"] != nil {
+		a.check(pdfaRuleOptionalContent, 0, objNr, "objeto associado a camada (OC)", remove(dict, "OC"))
+	}
+
+	if resources, _ := a.dict(dict["Resources"]); resources != nil {
+		a.checkResources(resources)
+	}
+}
+
+// checkAction verifica a ação da chave informada (ou cada ação de um array, em Next)
+func (a *pdfaAudit) checkAction(dict types.Dict, key string, pageNum, objNr int) {
+	if dict[key] == nil {
+		return
+	}
+
+	forbidden := func(action types.Dict) string {
+		kind := a.name(action["S"])
+		if pdfaForbiddenActions[kind] {
+			return kind
+		}
+		if kind == "Named" && !pdfaNamedActions[a.name(action["N"])] {
+			return "Named " + a.name(action["N"])
+		}
+		return ""
+	}
+
+	if actions, err := a.ctx.DereferenceArray(dict[key]); err == nil && actions != nil {
+		kept := make(types.Array, 0, len(actions))
+		for _, obj := range actions {
+			action, _ := a.dict(obj)
+			if action != nil {
+				if kind := forbidden(action); kind != "" {
+					removed := false
+					a.check(pdfaRuleActions, pageNum, objNr, fmt.Sprintf("ação %s", kind), func() error {
+						removed = true
+						return nil
+					})
+					if removed {
+						continue
+					}
+				}
+			}
+			kept = append(kept, obj)
+		}
+		if len(kept) != len(actions) {
+			dict[key] = kept
+		}
+		return
+	}
+
+	action, _ := a.dict(dict[key])
+	if action == nil {
+		return
+	}
+	if kind := forbidden(action); kind != "" {
+		a.check(pdfaRuleActions, pageNum, objNr, fmt.Sprintf("ação %s", kind), remove(dict, key))
+	}
+}
+
+// checkStream verifica arquivos externos, filtros e as regras de imagens e XObjects de formulário
+func (a *pdfaAudit) checkStream(entry *pdfcpuModel.XRefTableEntry, stream types.StreamDict, objNr int) {
+	if stream.Dict["F"] != nil || stream.Dict["FFilter"] != nil || stream.Dict["FDecodeParms"] != nil {
+		a.check(pdfaRuleStreams, 0, objNr, "fluxo com conteúdo em arquivo externo", remove(stream.Dict, "F", "FFilter", "FDecodeParms"))
+	}
+
+	for _, f := range stream.FilterPipeline {
+		switch f.Name {
+		case filter.LZW:
+			a.check(pdfaRuleFilters, 0, objNr, "fluxo com compressão LZW", func() error {
+				return recompressStream(entry, stream)
+			})
+		case filter.JPX:
+			if a.part == 1 {
+				a.check(pdfaRuleFilters, 0, objNr, "imagem JPEG 2000 (JPXDecode)", nil)
+			}
+		}
+	}
+
+	switch a.name(stream.Dict["Subtype"]) {
+	case "Image":
+		if interpolate := stream.BooleanEntry("Interpolate"); interpolate != nil && *interpolate {
+			a.check(pdfaRuleImages, 0, objNr, "imagem com interpolação (Interpolate)", remove(stream.Dict, "Interpolate"))
+		}
+		if stream.Dict["Alternates"] != nil || stream.Dict["OPI"] != nil {
+			a.check(pdfaRuleImages, 0, objNr, "imagem com versões alternativas (Alternates ou OPI)", remove(stream.Dict, "Alternates", "OPI"))
+		}
+		if a.part == 1 && stream.Dict["SMask"] != nil {
+			a.check(pdfaRuleTransparency, 0, objNr, "imagem com máscara suave (SMask)", remove(stream.Dict, "SMask"))
+		}
+		a.checkColorSpace(stream.Dict["ColorSpace"], 0, objNr)
+
+	case "Form":
+		if stream.Dict["OPI"] != nil || stream.Dict["Ref"] != nil {
+			a.check(pdfaRuleXObjects, 0, objNr, "XObject de formulário com OPI ou referência externa (Ref)", remove(stream.Dict, "OPI", "Ref"))
+		}
+		if a.name(stream.Dict["Subtype2"]) == "PS" {
+			a.check(pdfaRuleXObjects, 0, objNr, "XObject PostScript", nil)
+		}
+		if a.part == 1 && stream.Dict["Group"] != nil {
+			a.check(pdfaRuleTransparency, 0, objNr, "grupo de transparência em XObject", remove(stream.Dict, "Group"))
+		}
+		if err := stream.Decode(); err == nil {
+			a.checkContentColors(stream.Content, 0, objNr)
+		}
+
+	case "PS":
+		a.check(pdfaRuleXObjects, 0, objNr, "XObject PostScript", nil)
+	}
+}
+
+// recompressStream decodifica o fluxo e o grava novamente com Flate; fluxos de imagem com filtros com perdas
+// (DCT, JPX, CCITT, JBIG2) não podem ser decodificados sem recompressão da imagem
+func recompressStream(entry *pdfcpuModel.XRefTableEntry, stream types.StreamDict) error {
+	for _, f := range stream.FilterPipeline {
+		switch f.Name {
+		case filter.Flate, filter.LZW, filter.RunLength, filter.ASCII85, filter.ASCIIHex:
+		default:
+			return fmt.Errorf("filtro %s combinado com LZW", f.Name)
+		}
+	}
+	if err := stream.Decode(); err != nil {
+		return err
+	}
+
+	dict := stream.Dict.Clone().(types.Dict)
+	delete(dict, "DecodeParms")
+	recompressed := types.NewStreamDict(dict, 0, nil, nil, []types.PDFFilter{{Name: filter.Flate}})
+	recompressed.Content = stream.Content
+	recompressed.IsPageContent = stream.IsPageContent
+	if err := recompressed.Encode(); err != nil {
+		return err
+	}
+	recompressed.Dict["Filter"] = types.Name(filter.Flate)
+	entry.Object = recompressed
+	return nil
+}
+
+// checkResources verifica os estados gráficos e as fontes de um dicionário de recursos
+func (a *pdfaAudit) checkResources(resources types.Dict) {
+	if states, _ := a.dict(resources["ExtGState"]); states != nil {
+		for _, obj := range states {
+			state, objNr := a.dict(obj)
+			if state == nil || (objNr > 0 && a.checked[objNr]) {
+				continue
+			}
+			if objNr > 0 {
+				a.checked[objNr] = true
+			}
+			a.checkGraphicsState(state, objNr)
+		}
+	}
+
+	if fonts, _ := a.dict(resources["Font"]); fonts != nil {
+		for _, obj := range fonts {
+			font, objNr := a.dict(obj)
+			if font == nil || (objNr > 0 && a.checked[objNr]) {
+				continue
+			}
+			if objNr > 0 {
+				a.checked[objNr] = true
+			}
+			a.checkFont(font, objNr)
+		}
+	}
+}
+
+// checkGraphicsState verifica funções de transferência e, no PDF/A-1, transparência no estado gráfico
+func (a *pdfaAudit) checkGraphicsState(state types.Dict, objNr int) {
+	if state["TR"] != nil {
+		a.check(pdfaRuleTransferFunctions, 0, objNr, "função de transferência (TR)", remove(state, "TR"))
+	}
+	if state["TR2"] != nil && a.name(state["TR2"]) != "Default" {
+		a.check(pdfaRuleTransferFunctions, 0, objNr, "função de transferência (TR2) diferente de Default", func() error {
+			state["TR2"] = types.Name("Default")
+			return nil
+		})
+	}
+
+	if a.part != 1 {
+		return
+	}
+	if state["SMask"] != nil && a.name(state["SMask"]) != "None" {
+		a.check(pdfaRuleTransparency, 0, objNr, "máscara suave (SMask) no estado gráfico", func() error {
+			state["SMask"] = types.Name("None")
+			return nil
+		})
+	}
+	for _, key := range []string{"CA", "ca"} {
+		if opacity, err := a.ctx.DereferenceNumber(state[key]); err == nil && opacity != 1 {
+			a.check(pdfaRuleTransparency, 0, objNr, fmt.Sprintf("opacidade (%s) no estado gráfico", key), func() error {
+				state[key] = types.Float(1)
+				return nil
+			})
+		}
+	}
+	if mode := a.name(state["BM"]); mode != "" && mode != "Normal" && mode != "Compatible" {
+		a.check(pdfaRuleTransparency, 0, objNr, fmt.Sprintf("modo de mesclagem %s no estado gráfico", mode), func() error {
+			state["BM"] = types.Name("Normal")
+			return nil
+		})
+	}
+}
+
+// checkFont verifica se o programa da fonte está embutido; fontes simples não embutidas recebem uma substituta
+func (a *pdfaAudit) checkFont(font types.Dict, objNr int) {
+	subtype := a.name(font["Subtype"])
+	baseFont := a.name(font["BaseFont"])
+
+	switch subtype {
+	case "Type3":
+		return
+
+	case "Type0":
+		descendants, _ := a.ctx.DereferenceArray(font["DescendantFonts"])
+		if len(descendants) == 0 {
+			return
+		}
+		descendant, _ := a.dict(descendants[0])
+		if descendant == nil {
+			return
+		}
+		if descriptor, _ := a.dict(descendant["FontDescriptor"]); !fontProgramEmbedded(descriptor) {
+			a.check(pdfaRuleFonts, 0, objNr, fmt.Sprintf("fonte composta %s não embutida", baseFont), nil)
+		}
+
+	default:
+		descriptor, _ := a.dict(font["FontDescriptor"])
+		if fontProgramEmbedded(descriptor) {
+			return
+		}
+
+		var fix func() error
+		flags := 0
+		if descriptor != nil {
+			if value, err := a.ctx.DereferenceNumber(descriptor["Flags"]); err == nil {
+				flags = int(value)
+			}
+		}
+		symbolic := flags&fontFlagSymbolic != 0 && flags&fontFlagNonsymbolic == 0
+		name := strings.ToLower(baseFont)
+		if !symbolic && !strings.Contains(name, "symbol") && !strings.Contains(name, "dingbats") {
+			fix = func() error {
+				substitution, err := a.embedSubstituteFont(font)
+				if err != nil {
+					return err
+				}
+				a.embeddedFonts = append(a.embeddedFonts, substitution)
+				return nil
+			}
+		}
+		a.check(pdfaRuleFonts, 0, objNr, fmt.Sprintf("fonte %s não embutida", baseFont), fix)
+	}
+}
+
+// fontProgramEmbedded verifica se o descritor de fonte contém o programa da fonte
+func fontProgramEmbedded(descriptor types.Dict) bool {
+	return descriptor != nil && (descriptor["FontFile"] != nil || descriptor["FontFile2"] != nil || descriptor["FontFile3"] != nil)
+}
+
+// checkColorSpace verifica se o espaço de cor de uma imagem é um espaço de dispositivo incompatível com o
+// perfil do OutputIntent
+func (a *pdfaAudit) checkColorSpace(colorSpace types.Object, pageNum, objNr int) {
+	obj, err := a.ctx.Dereference(colorSpace)
+	if err != nil {
+		return
+	}
+	if array, ok := obj.(types.Array); ok && len(array) >= 2 && (array[0] == types.Name("Indexed") || array[0] == types.Name("I")) {
+		obj, _ = a.ctx.Dereference(array[1])
+	}
+	if name, ok := obj.(types.Name); ok {
+		a.checkDeviceColors(name == "DeviceCMYK", name == "DeviceRGB", pageNum, objNr)
+	}
+}
+
+// checkContentColors verifica os operadores de cor de dispositivo de um content stream
+func (a *pdfaAudit) checkContentColors(content []byte, pageNum, objNr int) {
+	operations, err := contentstream.NewContentStreamParser(string(content)).Parse()
+	if err != nil {
+		return
+	}
+
+	cmyk, rgb := false, false
+	for _, op := range *operations {
+		switch op.Operand {
+		case "k", "K":
+			cmyk = true
+		case "rg", "RG":
+			rgb = true
+		case "cs", "CS":
+			if name, ok := nameParam(op.Params, 0); ok {
+				cmyk = cmyk || name == "DeviceCMYK"
+				rgb = rgb || name == "DeviceRGB"
+			}
+		}
+	}
+	a.checkDeviceColors(cmyk, rgb, pageNum, objNr)
+}
+
+// checkDeviceColors registra o uso de cores RGB ou CMYK de dispositivo sem OutputIntent do mesmo espaço
+// (tons de cinza são aceitos com qualquer OutputIntent)
+func (a *pdfaAudit) checkDeviceColors(cmyk, rgb bool, pageNum, objNr int) {
+	if cmyk && a.intentComponents != 4 {
+		a.check(pdfaRuleDeviceColors, pageNum, objNr, "cores DeviceCMYK sem OutputIntent CMYK", nil)
+	}
+	if rgb && a.intentComponents != 3 {
+		a.check(pdfaRuleDeviceColors, pageNum, objNr, "cores DeviceRGB sem OutputIntent RGB", nil)
+	}
+}
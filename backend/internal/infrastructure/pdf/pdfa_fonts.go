@@ -0,0 +1,349 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/pdfcpu/pdfcpu/pkg/filter"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"github.com/unidoc/unipdf/v3/core"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/gobolditalic"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/gomono"
+	"golang.org/x/image/font/gofont/gomonobold"
+	"golang.org/x/image/font/gofont/gomonobolditalic"
+	"golang.org/x/image/font/gofont/gomonoitalic"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Flags do descritor de fonte (ISO 32000-1, 9.8.2)
+const (
+	fontFlagFixedPitch  = 1 << 0
+	fontFlagSerif       = 1 << 1
+	fontFlagSymbolic    = 1 << 2
+	fontFlagNonsymbolic = 1 << 5
+	fontFlagItalic      = 1 << 6
+	fontFlagForceBold   = 1 << 18
+)
+
+// substituteFamilies são as famílias instaladas preferidas para substituir fontes não embutidas, por classe
+// As primeiras de cada lista têm as mesmas métricas das fontes padrão Helvetica, Times e Courier
+var substituteFamilies = map[string][]string{
+	"sans":  {"liberation sans", "arimo", "arial", "helvetica", "dejavu sans", "noto sans"},
+	"serif": {"liberation serif", "tinos", "times new roman", "dejavu serif", "noto serif"},
+	"mono":  {"liberation mono", "cousine", "courier new", "dejavu sans mono", "noto sans mono"},
+}
+
+// ligatures mapeia sequências de texto às ligaduras Unicode correspondentes (códigos com ToUnicode "fi" etc.)
+var ligatures = map[string]rune{"ff": 'ﬀ', "fi": 'ﬁ', "fl": 'ﬂ', "ffi": 'ﬃ', "ffl": 'ﬄ'}
+
+// substituteFace escolhe a fonte que substitui uma fonte não embutida: a família de mesmo nome quando
+// instalada, uma família compatível da mesma classe (sem serifa, com serifa ou monoespaçada) ou, na falta
+// delas e para variações itálicas, as Go fonts
+func (r *fontRegistry) substituteFace(baseFont string, flags int, bold bool) (*fontFace, error) {
+	name := strings.ToLower(baseFont)
+	mono := flags&fontFlagFixedPitch != 0 || strings.Contains(name, "courier") || strings.Contains(name, "mono")
+	serif := !mono && (flags&fontFlagSerif != 0 || strings.Contains(name, "times") || strings.Contains(name, "roman") ||
+		strings.Contains(name, "georgia") || strings.Contains(name, "garamond") ||
+		(strings.Contains(name, "serif") && !strings.Contains(name, "sans")))
+	italic := flags&fontFlagItalic != 0 || strings.Contains(name, "italic") || strings.Contains(name, "oblique")
+	bold = bold || flags&fontFlagForceBold != 0
+	for _, marker := range []string{"bold", "black", "heavy", "semibold", "demi"} {
+		bold = bold || strings.Contains(name, marker)
+	}
+
+	weight := FontWeightNormal
+	if bold {
+		weight = FontWeightBold
+	}
+
+	// O registro não contém variações itálicas
+	if !italic {
+		class := "sans"
+		if mono {
+			class = "mono"
+		} else if serif {
+			class = "serif"
+		}
+		for _, family := range append(fontFamilyCandidates(baseFont), substituteFamilies[class]...) {
+			if r.hasFamily(family) && family != DefaultFontFamily {
+				return r.face(family, weight)
+			}
+		}
+	}
+
+	var data []byte
+	switch {
+	case mono && bold && italic:
+		data = gomonobolditalic.TTF
+	case mono && bold:
+		data = gomonobold.TTF
+	case mono && italic:
+		data = gomonoitalic.TTF
+	case mono:
+		data = gomono.TTF
+	case bold && italic:
+		data = gobolditalic.TTF
+	case bold:
+		data = gobold.TTF
+	case italic:
+		data = goitalic.TTF
+	default:
+		data = goregular.TTF
+	}
+
+	parsed, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("fonte inválida: %w", err)
+	}
+	family := DefaultFontFamily
+	if mono {
+		family += " mono"
+	}
+	if italic {
+		weight += " italic"
+	}
+	return &fontFace{family: family, weight: weight, data: data, sfnt: parsed}, nil
+}
+
+// fontFamilyCandidates deriva nomes de família de um BaseFont ("Arial,Bold" e "ArialMT" -> "arial";
+// "TimesNewRomanPSMT" -> "times new roman")
+func fontFamilyCandidates(baseFont string) []string {
+	name := baseFont
+	if i := strings.IndexAny(name, ",-"); i > 0 {
+		name = name[:i]
+	}
+	for _, suffix := range []string{"PSMT", "PS", "MT"} {
+		name = strings.TrimSuffix(name, suffix)
+	}
+
+	var words strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			words.WriteByte(' ')
+		}
+		words.WriteRune(r)
+	}
+
+	candidates := []string{normalizeFontFamily(name)}
+	if spaced := normalizeFontFamily(words.String()); spaced != candidates[0] {
+		candidates = append(candidates, spaced)
+	}
+	return candidates
+}
+
+// embedSubstituteFont transforma uma fonte simples não embutida em uma fonte TrueType com a substituta
+// embutida: larguras medidas na substituta e codificação WinAnsi com Differences para os códigos cujo
+// caractere difere do WinAnsi, mantendo o texto exibido e extraído. Retorna a descrição da substituição
+func (a *pdfaAudit) embedSubstituteFont(fontDict types.Dict) (string, error) {
+	pdfCtx := a.ctx
+	baseFont := a.name(fontDict["BaseFont"])
+	if i := strings.IndexByte(baseFont, '+'); i == 6 {
+		baseFont = baseFont[i+1:]
+	}
+
+	flags := 0
+	bold := false
+	if descriptor, err := pdfCtx.DereferenceDict(fontDict["FontDescriptor"]); err == nil && descriptor != nil {
+		if value, err := pdfCtx.DereferenceNumber(descriptor["Flags"]); err == nil {
+			flags = int(value)
+		}
+		if value, err := pdfCtx.DereferenceNumber(descriptor["FontWeight"]); err == nil {
+			bold = value >= 600
+		}
+	}
+
+	face, err := a.fonts.substituteFace(baseFont, flags, bold)
+	if err != nil {
+		return "", err
+	}
+
+	// Caractere de cada código pela codificação (e ToUnicode) da fonte original
+	original := contentFontFromObject(unipdfObject(pdfCtx, fontDict, 0))
+	widths := make(types.Array, 256)
+	differences := types.Array{}
+	lastDifference := -2
+	for code := 0; code < 256; code++ {
+		text := original.text([]byte{byte(code)})
+		r, ok := ligatures[text]
+		if !ok {
+			runes := []rune(text)
+			if len(runes) != 1 {
+				widths[code] = types.Integer(0)
+				continue
+			}
+			r = runes[0]
+		}
+
+		widths[code] = types.Integer(int(math.Round(face.advance(r) * 1000)))
+		if charmap.Windows1252.DecodeByte(byte(code)) == r && code >= 32 {
+			continue
+		}
+
+		index, err := face.sfnt.GlyphIndex(nil, r)
+		if err != nil || index == 0 {
+			continue
+		}
+		glyphName, _ := face.sfnt.GlyphName(nil, index)
+		if glyphName == "" {
+			glyphName = fmt.Sprintf("uni%04X", r)
+		}
+		if code != lastDifference+1 {
+			differences = append(differences, types.Integer(code))
+		}
+		differences = append(differences, types.Name(glyphName))
+		lastDifference = code
+	}
+
+	fontFile, err := a.fontFile(face)
+	if err != nil {
+		return "", err
+	}
+
+	descriptorFlags := fontFlagNonsymbolic
+	if strings.Contains(face.family, "mono") {
+		descriptorFlags |= fontFlagFixedPitch
+	}
+	if strings.Contains(face.family, "serif") && !strings.Contains(face.family, "sans") {
+		descriptorFlags |= fontFlagSerif
+	}
+	italicAngle := 0
+	if strings.Contains(face.weight, "italic") {
+		descriptorFlags |= fontFlagItalic
+		italicAngle = -12
+	}
+	stemV := 80
+	if strings.HasPrefix(face.weight, FontWeightBold) {
+		descriptorFlags |= fontFlagForceBold
+		stemV = 140
+	}
+
+	bbox := types.NewNumberArray(0, -200, 1000, 900)
+	if bounds, err := face.sfnt.Bounds(nil, metricsPPEM, font.HintingNone); err == nil {
+		bbox = types.NewIntegerArray(bounds.Min.X.Round(), -bounds.Max.Y.Round(), bounds.Max.X.Round(), -bounds.Min.Y.Round())
+	}
+	ascent, descent, capHeight := 800, -200, 700
+	if metrics, err := face.sfnt.Metrics(nil, metricsPPEM, font.HintingNone); err == nil {
+		ascent, descent, capHeight = metrics.Ascent.Round(), -metrics.Descent.Round(), metrics.CapHeight.Round()
+	}
+
+	descriptorRef, err := pdfCtx.IndRefForNewObject(types.Dict{
+		"Type":        types.Name("FontDescriptor"),
+		"FontName":    types.Name(baseFont),
+		"Flags":       types.Integer(descriptorFlags),
+		"FontBBox":    bbox,
+		"ItalicAngle": types.Integer(italicAngle),
+		"Ascent":      types.Integer(ascent),
+		"Descent":     types.Integer(descent),
+		"CapHeight":   types.Integer(capHeight),
+		"StemV":       types.Integer(stemV),
+		"FontFile2":   fontFile,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	fontDict["Subtype"] = types.Name("TrueType")
+	fontDict["FirstChar"] = types.Integer(0)
+	fontDict["LastChar"] = types.Integer(255)
+	fontDict["Widths"] = widths
+	fontDict["FontDescriptor"] = *descriptorRef
+	if len(differences) > 0 {
+		fontDict["Encoding"] = types.Dict{
+			"Type":         types.Name("Encoding"),
+			"BaseEncoding": types.Name("WinAnsiEncoding"),
+			"Differences":  differences,
+		}
+	} else {
+		fontDict["Encoding"] = types.Name("WinAnsiEncoding")
+	}
+
+	return fmt.Sprintf("%s -> %s (%s)", baseFont, face.family, face.weight), nil
+}
+
+// fontFile grava (uma única vez por substituta) o programa TrueType completo comprimido com Flate
+func (a *pdfaAudit) fontFile(face *fontFace) (types.IndirectRef, error) {
+	if ref, ok := a.fontFiles[face.family+"|"+face.weight]; ok {
+		return ref, nil
+	}
+
+	stream := types.NewStreamDict(types.Dict{"Length1": types.Integer(len(face.data))}, 0, nil, nil, []types.PDFFilter{{Name: filter.Flate}})
+	stream.Content = face.data
+	if err := stream.Encode(); err != nil {
+		return types.IndirectRef{}, err
+	}
+	stream.Dict["Filter"] = types.Name(filter.Flate)
+
+	ref, err := a.ctx.IndRefForNewObject(stream)
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+	a.fontFiles[face.family+"|"+face.weight] = *ref
+	return *ref, nil
+}
+
+// unipdfObject converte um objeto do pdfcpu para o modelo do unipdf, resolvendo referências indiretas
+// Usado para reaproveitar a decodificação de fontes do unipdf (codificações, Differences e ToUnicode)
+func unipdfObject(pdfCtx *pdfcpuModel.Context, obj types.Object, depth int) core.PdfObject {
+	if depth > 8 {
+		return core.MakeNull()
+	}
+
+	switch o := obj.(type) {
+	case types.IndirectRef:
+		resolved, err := pdfCtx.Dereference(o)
+		if err != nil {
+			return core.MakeNull()
+		}
+		return unipdfObject(pdfCtx, resolved, depth+1)
+	case types.Name:
+		return core.MakeName(string(o))
+	case types.Integer:
+		return core.MakeInteger(int64(o))
+	case types.Float:
+		return core.MakeFloat(float64(o))
+	case types.Boolean:
+		return core.MakeBool(bool(o))
+	case types.StringLiteral:
+		data, err := types.Unescape(o.Value())
+		if err != nil {
+			return core.MakeString(o.Value())
+		}
+		return core.MakeStringFromBytes(data)
+	case types.HexLiteral:
+		data, err := o.Bytes()
+		if err != nil {
+			return core.MakeNull()
+		}
+		return core.MakeStringFromBytes(data)
+	case types.Array:
+		array := core.MakeArray()
+		for _, element := range o {
+			array.Append(unipdfObject(pdfCtx, element, depth+1))
+		}
+		return array
+	case types.Dict:
+		dict := core.MakeDict()
+		for key, value := range o {
+			dict.Set(core.PdfObjectName(key), unipdfObject(pdfCtx, value, depth+1))
+		}
+		return dict
+	case types.StreamDict:
+		if err := o.Decode(); err != nil {
+			return core.MakeNull()
+		}
+		stream, err := core.MakeStream(o.Content, core.NewRawEncoder())
+		if err != nil {
+			return core.MakeNull()
+		}
+		return stream
+	}
+	return core.MakeNull()
+}
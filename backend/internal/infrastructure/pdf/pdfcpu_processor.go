@@ -3,6 +3,7 @@ package pdf
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"image"
 	"image/jpeg"
@@ -14,21 +15,33 @@ import (
 	appModel "github.com/editor-pdf/backend/internal/model"
 	"github.com/editor-pdf/backend/pkg/logger"
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
 	"github.com/unidoc/unipdf/v3/common"
 	"github.com/unidoc/unipdf/v3/contentstream"
-	"github.com/unidoc/unipdf/v3/core"
 	"github.com/unidoc/unipdf/v3/model"
-	"github.com/unidoc/unipdf/v3/render"
 	"go.uber.org/zap"
 )
 
 // PDFCPUProcessor implementa PDFProcessor usando a biblioteca pdfcpu
-type PDFCPUProcessor struct{}
+type PDFCPUProcessor struct {
+	fonts *fontRegistry
+}
 
 // NewPDFCPUProcessor cria uma nova instância de PDFCPUProcessor
-func NewPDFCPUProcessor() (domain.PDFProcessor, error) {
-	return &PDFCPUProcessor{}, nil
+// fontsPath é o diretório com fontes TrueType/OpenType adicionais (opcional)
+func NewPDFCPUProcessor(fontsPath string) (domain.PDFProcessor, error) {
+	fonts, err := newFontRegistry(fontsPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao carregar fontes: %w", err)
+	}
+
+	// Sem as fontes no pdfcpu, carimbos e campos de formulário aceitam apenas texto WinAnsi
+	if err := fonts.installUserFonts(); err != nil {
+		logger.Logger.Warn("Fontes Unicode indisponíveis no pdfcpu", zap.Error(err))
+	}
+
+	return &PDFCPUProcessor{fonts: fonts}, nil
 }
 
 // ValidatePDF valida se um arquivo é um PDF válido usando magic bytes
@@ -56,7 +69,11 @@ func (p *PDFCPUProcessor) ValidatePDF(ctx context.Context, data []byte) error {
 	}
 
 	// Tenta ler o PDF com pdfcpu (validação básica)
+	// Documentos que exigem senha para abrir não podem ser validados sem ela
 	if _, err := api.ReadContextFile(tempFile.Name()); err != nil {
+		if errors.Is(err, pdfcpu.ErrWrongPassword) {
+			return errors.New("PDF protegido por senha")
+		}
 		return fmt.Errorf("PDF inválido: %w", err)
 	}
 
@@ -88,153 +105,23 @@ func (p *PDFCPUProcessor) ExtractPages(ctx context.Context, filePath string) ([]
 }
 
 // AddText adiciona texto a uma página específica do PDF
-func (p *PDFCPUProcessor) AddText(ctx context.Context, filePath string, pageNum int, x, y float64, text string, fontSize float64) error {
-	// Desabilita logs do unipdf para evitar poluição
-	common.SetLogger(common.NewConsoleLogger(common.LogLevelError))
-
-	// Carrega o PDF
-	reader, file, err := model.NewPdfReaderFromFile(filePath, nil)
-	if err != nil {
-		return fmt.Errorf("erro ao carregar PDF: %w", err)
-	}
-	defer file.Close()
-
-	// Valida número da página
-	numPages, err := reader.GetNumPages()
-	if err != nil {
-		return fmt.Errorf("erro ao obter número de páginas: %w", err)
-	}
-
-	if pageNum < 1 || pageNum > numPages {
-		return fmt.Errorf("página inválida: %d (PDF tem %d páginas)", pageNum, numPages)
-	}
-
-	// Obtém a página (GetPage usa índice baseado em 1)
-	page, err := reader.GetPage(pageNum)
-	if err != nil {
-		return fmt.Errorf("erro ao obter página %d: %w", pageNum, err)
-	}
-
-	// Obtém dimensões da página
-	pageRect, err := page.GetMediaBox()
+// O texto é escrito com fontes TrueType embutidas (subset) e mapa ToUnicode, suportando Unicode completo
+func (p *PDFCPUProcessor) AddText(ctx context.Context, filePath string, pageNum int, x, y float64, text string, opts appModel.TextOptions) error {
+	err := appendPageContent(filePath, pageNum, func(page *model.PdfPage, pageHeight float64) (*contentstream.ContentCreator, error) {
+		// Converte coordenadas: y=0 é no bottom no PDF, então precisamos inverter
+		// Se y é fornecido do topo, converter: y_pdf = pageHeight - y
+		return p.buildTextContent(page, x, pageHeight-y, text, opts)
+	})
 	if err != nil {
-		return fmt.Errorf("erro ao obter dimensões da página: %w", err)
-	}
-
-	// Converte coordenadas: y=0 é no bottom no PDF, então precisamos inverter
-	// Se y é fornecido do topo, converter: y_pdf = pageHeight - y
-	pageHeight := pageRect.Height()
-	yPdf := pageHeight - y
-
-	// Obtém o content stream existente (retorna []string)
-	contentStreams, err := page.GetContentStreams()
-	if err != nil {
-		return fmt.Errorf("erro ao obter content stream: %w", err)
-	}
-
-	// Cria um novo content stream com o texto
-	contentCreator := contentstream.NewContentCreator()
-	contentCreator.Add_q() // Save graphics state
-
-	// Configura fonte (Helvetica por padrão)
-	contentCreator.Add_BT()               // Begin text object
-	contentCreator.Add_Tf("F1", fontSize) // Set font and size
-	contentCreator.Add_Td(x, yPdf)        // Move to position
-
-	// Adiciona o texto (precisa ser PdfObjectString)
-	textObj := core.MakeString(text)
-	contentCreator.Add_Tj(*textObj)
-	contentCreator.Add_ET() // End text object
-	contentCreator.Add_Q()  // Restore graphics state
-
-	// Adiciona o novo conteúdo ao content stream existente
-	newContent := string(contentCreator.Bytes())
-	contentStreams = append(contentStreams, newContent)
-
-	// Atualiza o content stream da página
-	if err := page.SetContentStreams(contentStreams, core.NewFlateEncoder()); err != nil {
-		return fmt.Errorf("erro ao atualizar content stream: %w", err)
-	}
-
-	// Adiciona fonte Helvetica se não existir
-	resources := page.Resources
-	if resources == nil {
-		resources = model.NewPdfPageResources()
-		page.Resources = resources
-	}
-
-	// Obtém ou cria o Font dictionary
-	var fontDict *core.PdfObjectDictionary
-	if resources.Font == nil {
-		fontDict = core.MakeDict()
-		resources.Font = fontDict
-	} else {
-		// Tenta fazer type assertion
-		if dict, ok := resources.Font.(*core.PdfObjectDictionary); ok {
-			fontDict = dict
-		} else {
-			// Se não for dict, cria um novo
-			fontDict = core.MakeDict()
-			resources.Font = fontDict
-		}
-	}
-
-	// Cria fonte Helvetica se não existir
-	if fontDict.Get("F1") == nil {
-		helveticaFontDict := core.MakeDict()
-		helveticaFontDict.Set("Type", core.MakeName("Font"))
-		helveticaFontDict.Set("Subtype", core.MakeName("Type1"))
-		helveticaFontDict.Set("BaseFont", core.MakeName("Helvetica"))
-
-		fontObj := core.MakeIndirectObject(helveticaFontDict)
-		fontDict.Set("F1", fontObj)
-	}
-
-	// Cria um novo writer e copia todas as páginas
-	writer := model.NewPdfWriter()
-
-	// Copia todas as páginas, modificando apenas a página especificada
-	for i := 1; i <= numPages; i++ {
-		var pageToAdd *model.PdfPage
-		if i == pageNum {
-			// Usa a página modificada
-			pageToAdd = page
-		} else {
-			// Copia a página original
-			otherPage, err := reader.GetPage(i)
-			if err != nil {
-				return fmt.Errorf("erro ao obter página %d: %w", i, err)
-			}
-			pageToAdd = otherPage
-		}
-
-		if err := writer.AddPage(pageToAdd); err != nil {
-			return fmt.Errorf("erro ao adicionar página %d: %w", i, err)
-		}
-	}
-
-	// Salva em arquivo temporário primeiro
-	tempFile, err := os.CreateTemp("", "pdf_edit_*.pdf")
-	if err != nil {
-		return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
-	}
-	tempPath := tempFile.Name()
-	tempFile.Close()
-	defer os.Remove(tempPath)
-
-	if err := writer.WriteToFile(tempPath); err != nil {
-		return fmt.Errorf("erro ao salvar PDF temporário: %w", err)
-	}
-
-	// Substitui o arquivo original pelo modificado
-	if err := os.Rename(tempPath, filePath); err != nil {
-		return fmt.Errorf("erro ao substituir arquivo: %w", err)
+		return err
 	}
 
 	logger.Logger.Debug("Texto adicionado ao PDF",
 		zap.String("file", filePath),
 		zap.Int("page", pageNum),
 		zap.String("text", text),
+		zap.String("font_family", opts.FontFamily),
+		zap.String("font_weight", opts.FontWeight),
 		zap.Float64("x", x),
 		zap.Float64("y", y),
 	)
@@ -243,189 +130,42 @@ func (p *PDFCPUProcessor) AddText(ctx context.Context, filePath string, pageNum
 }
 
 // AddImage adiciona uma imagem a uma página específica do PDF
+// PNGs transparentes recebem soft mask e JPEGs são embutidos sem recompressão
 func (p *PDFCPUProcessor) AddImage(ctx context.Context, filePath string, pageNum int, x, y, width, height float64, imagePath string) error {
-	// Desabilita logs do unipdf para evitar poluição
-	common.SetLogger(common.NewConsoleLogger(common.LogLevelError))
-
 	// Valida se o arquivo de imagem existe
 	if _, err := os.Stat(imagePath); os.IsNotExist(err) {
 		return fmt.Errorf("arquivo de imagem não encontrado: %s", imagePath)
 	}
 
-	// Carrega o PDF
-	reader, file, err := model.NewPdfReaderFromFile(filePath, nil)
+	// Cria o XObject antes de abrir o PDF para falhar cedo em imagens inválidas
+	ximg, err := loadImageXObject(imagePath)
 	if err != nil {
-		return fmt.Errorf("erro ao carregar PDF: %w", err)
+		return err
 	}
-	defer file.Close()
 
-	// Valida número da página
-	numPages, err := reader.GetNumPages()
-	if err != nil {
-		return fmt.Errorf("erro ao obter número de páginas: %w", err)
-	}
-
-	if pageNum < 1 || pageNum > numPages {
-		return fmt.Errorf("página inválida: %d (PDF tem %d páginas)", pageNum, numPages)
-	}
+	err = appendPageContent(filePath, pageNum, func(page *model.PdfPage, pageHeight float64) (*contentstream.ContentCreator, error) {
+		// Converte coordenadas: y=0 é no bottom no PDF
+		yPdf := pageHeight - y - height // Ajusta para que y seja do topo
 
-	// Obtém a página
-	page, err := reader.GetPage(pageNum)
-	if err != nil {
-		return fmt.Errorf("erro ao obter página %d: %w", pageNum, err)
-	}
-
-	// Obtém dimensões da página
-	pageRect, err := page.GetMediaBox()
-	if err != nil {
-		return fmt.Errorf("erro ao obter dimensões da página: %w", err)
-	}
-
-	// Converte coordenadas: y=0 é no bottom no PDF
-	pageHeight := pageRect.Height()
-	yPdf := pageHeight - y - height // Ajusta para que y seja do topo
-
-	// Carrega a imagem usando image.Decode
-	imgFile, err := os.Open(imagePath)
-	if err != nil {
-		return fmt.Errorf("erro ao abrir arquivo de imagem: %w", err)
-	}
-	defer imgFile.Close()
-
-	goImg, _, err := image.Decode(imgFile)
-	if err != nil {
-		return fmt.Errorf("erro ao decodificar imagem: %w", err)
-	}
-
-	// Converte image.Image do Go para model.Image do unipdf
-	bounds := goImg.Bounds()
-	imgWidth := int64(bounds.Dx())
-	imgHeight := int64(bounds.Dy())
-
-	// Extrai os dados da imagem
-	var imgData []byte
-	switch img := goImg.(type) {
-	case *image.RGBA:
-		imgData = img.Pix
-	case *image.NRGBA:
-		imgData = img.Pix
-	default:
-		// Converte para RGBA se necessário
-		rgba := image.NewRGBA(bounds)
-		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
-			for x := bounds.Min.X; x < bounds.Max.X; x++ {
-				rgba.Set(x, y, goImg.At(x, y))
-			}
+		// Adiciona a imagem aos recursos da página com um nome único
+		imageName := page.Resources.GenerateXObjectName()
+		if err := page.Resources.SetXObjectImageByName(imageName, ximg); err != nil {
+			return nil, fmt.Errorf("erro ao registrar imagem: %w", err)
 		}
-		imgData = rgba.Pix
-	}
-
-	// Cria model.Image
-	pdfImg := &model.Image{
-		Width:            imgWidth,
-		Height:           imgHeight,
-		BitsPerComponent: 8,
-		ColorComponents:  3, // RGB
-		Data:             imgData,
-	}
-
-	// Cria um XObject de imagem
-	// Usa DeviceRGB como colorspace e FlateEncoder
-	colorspace := model.NewPdfColorspaceDeviceRGB()
-	encoder := core.NewFlateEncoder()
-	ximg, err := model.NewXObjectImageFromImage(pdfImg, colorspace, encoder)
-	if err != nil {
-		return fmt.Errorf("erro ao criar XObject de imagem: %w", err)
-	}
-
-	// Adiciona a imagem aos recursos da página
-	resources := page.Resources
-	if resources == nil {
-		resources = model.NewPdfPageResources()
-		page.Resources = resources
-	}
 
-	// Obtém ou cria o XObject dictionary
-	var xObjectDict *core.PdfObjectDictionary
-	if resources.XObject == nil {
-		xObjectDict = core.MakeDict()
-		resources.XObject = xObjectDict
-	} else {
-		if dict, ok := resources.XObject.(*core.PdfObjectDictionary); ok {
-			xObjectDict = dict
-		} else {
-			xObjectDict = core.MakeDict()
-			resources.XObject = xObjectDict
-		}
-	}
+		contentCreator := contentstream.NewContentCreator()
+		contentCreator.Add_q() // Save graphics state
 
-	// Gera um nome único para a imagem
-	imageName := fmt.Sprintf("Img%d", len(xObjectDict.Keys())+1)
-	imageNameObj := core.MakeName(imageName)
-	xObjectDict.Set(*imageNameObj, ximg.ToPdfObject())
+		// Posiciona e dimensiona a imagem
+		// Matrix: [width 0 0 height x y] cm
+		contentCreator.Add_cm(width, 0, 0, height, x, yPdf) // cm = concat matrix
+		contentCreator.Add_Do(imageName)                    // Do = draw object
+		contentCreator.Add_Q()                              // Restore graphics state
 
-	// Obtém o content stream existente
-	contentStreams, err := page.GetContentStreams()
+		return contentCreator, nil
+	})
 	if err != nil {
-		return fmt.Errorf("erro ao obter content stream: %w", err)
-	}
-
-	// Cria um novo content stream com a imagem
-	contentCreator := contentstream.NewContentCreator()
-	contentCreator.Add_q() // Save graphics state
-
-	// Posiciona e dimensiona a imagem
-	// Matrix: [width 0 0 height x y] cm
-	contentCreator.Add_cm(width, 0, 0, height, x, yPdf) // cm = concat matrix
-	contentCreator.Add_Do(*imageNameObj)                // Do = draw object
-	contentCreator.Add_Q()                              // Restore graphics state
-
-	// Adiciona o novo conteúdo ao content stream existente
-	newContent := string(contentCreator.Bytes())
-	contentStreams = append(contentStreams, newContent)
-
-	// Atualiza o content stream da página
-	if err := page.SetContentStreams(contentStreams, core.NewFlateEncoder()); err != nil {
-		return fmt.Errorf("erro ao atualizar content stream: %w", err)
-	}
-
-	// Cria um novo writer e copia todas as páginas
-	writer := model.NewPdfWriter()
-
-	// Copia todas as páginas, modificando apenas a página especificada
-	for i := 1; i <= numPages; i++ {
-		var pageToAdd *model.PdfPage
-		if i == pageNum {
-			pageToAdd = page
-		} else {
-			otherPage, err := reader.GetPage(i)
-			if err != nil {
-				return fmt.Errorf("erro ao obter página %d: %w", i, err)
-			}
-			pageToAdd = otherPage
-		}
-
-		if err := writer.AddPage(pageToAdd); err != nil {
-			return fmt.Errorf("erro ao adicionar página %d: %w", i, err)
-		}
-	}
-
-	// Salva em arquivo temporário primeiro
-	tempFile, err := os.CreateTemp("", "pdf_edit_*.pdf")
-	if err != nil {
-		return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
-	}
-	tempPath := tempFile.Name()
-	tempFile.Close()
-	defer os.Remove(tempPath)
-
-	if err := writer.WriteToFile(tempPath); err != nil {
-		return fmt.Errorf("erro ao salvar PDF temporário: %w", err)
-	}
-
-	// Substitui o arquivo original pelo modificado
-	if err := os.Rename(tempPath, filePath); err != nil {
-		return fmt.Errorf("erro ao substituir arquivo: %w", err)
+		return err
 	}
 
 	logger.Logger.Debug("Imagem adicionada ao PDF",
@@ -462,8 +202,9 @@ func (p *PDFCPUProcessor) MergePDFs(ctx context.Context, outputPath string, inpu
 	return nil
 }
 
-// GeneratePreview gera uma preview (imagem) de uma página específica do PDF
-func (p *PDFCPUProcessor) GeneratePreview(ctx context.Context, filePath string, pageNum int) ([]byte, error) {
+// GeneratePreview gera uma preview (imagem) de uma página específica do PDF, com a resolução, o formato,
+// o recorte ou o tamanho de miniatura informados
+func (p *PDFCPUProcessor) GeneratePreview(ctx context.Context, filePath string, pageNum int, options appModel.PreviewOptions) ([]byte, error) {
 	// Desabilita logs do unipdf para evitar poluição
 	common.SetLogger(common.NewConsoleLogger(common.LogLevelError))
 
@@ -490,42 +231,37 @@ func (p *PDFCPUProcessor) GeneratePreview(ctx context.Context, filePath string,
 		return nil, fmt.Errorf("erro ao obter página %d: %w", pageNum, err)
 	}
 
-	// Obtém dimensões da página para calcular largura de saída
-	pageRect, err := page.GetMediaBox()
-	if err != nil {
-		return nil, fmt.Errorf("erro ao obter dimensões da página: %w", err)
+	// Renderiza a miniatura, a região ou a página inteira (150 DPI quando não informados DPI nem largura)
+	var img image.Image
+	switch {
+	case options.ThumbnailSize > 0:
+		img, err = renderThumbnail(page, options.ThumbnailSize)
+	case options.Clip != nil:
+		if err = clipPage(page, *options.Clip); err == nil {
+			img, _, err = renderPageImage(page, options.DPI, options.Width)
+		}
+	default:
+		img, _, err = renderPageImage(page, options.DPI, options.Width)
 	}
-
-	// Calcula largura de saída para 150 DPI (72 points = 1 inch)
-	// 150 DPI = 150 pixels por inch = 150/72 pixels por point
-	dpi := 150.0
-	pointsPerInch := 72.0
-	pixelsPerPoint := dpi / pointsPerInch
-	outputWidth := int(pageRect.Width() * pixelsPerPoint)
-
-	// Cria um renderer
-	device := render.NewImageDevice()
-	device.OutputWidth = outputWidth
-
-	// Renderiza a página
-	img, err := device.Render(page)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao renderizar página: %w", err)
+		return nil, err
 	}
 
-	// Converte para bytes PNG
-	var buf bytes.Buffer
-	if err := png.Encode(&buf, img); err != nil {
-		return nil, fmt.Errorf("erro ao codificar imagem PNG: %w", err)
+	data, err := encodePageImage(img, 0, options.Format, options.JPEGQuality, false)
+	if err != nil {
+		return nil, err
 	}
 
 	logger.Logger.Debug("Preview gerado com sucesso",
 		zap.String("file", filePath),
 		zap.Int("page", pageNum),
-		zap.Int("size_bytes", buf.Len()),
+		zap.String("format", string(options.Format)),
+		zap.Int("width", img.Bounds().Dx()),
+		zap.Int("height", img.Bounds().Dy()),
+		zap.Int("size_bytes", len(data)),
 	)
 
-	return buf.Bytes(), nil
+	return data, nil
 }
 
 // ProcessEdits processa múltiplas edições em um PDF
@@ -567,8 +303,27 @@ func (p *PDFCPUProcessor) ProcessEdits(ctx context.Context, inputPath, outputPat
 				fontSize = 12.0 // Tamanho padrão
 			}
 
+			opacity := 1.0 // Opaco por padrão
+			if edit.Opacity != nil {
+				opacity = *edit.Opacity
+			}
+
+			textOptions := appModel.TextOptions{
+				FontFamily: edit.FontFamily,
+				FontWeight: edit.FontWeight,
+				FontSize:   fontSize,
+				Color:      edit.FillColor,
+				Rotation:   edit.Rotation,
+				Opacity:    opacity,
+				LineHeight: edit.LineHeight,
+				Align:      appModel.TextAlign(edit.Align),
+				Width:      edit.Width,
+				Height:     edit.Height,
+				Overflow:   appModel.TextOverflow(edit.Overflow),
+			}
+
 			// Aplica a edição de texto
-			if err := p.AddText(ctx, tempPath, edit.Page, edit.X, edit.Y, edit.Content, fontSize); err != nil {
+			if err := p.AddText(ctx, tempPath, edit.Page, edit.X, edit.Y, edit.Content, textOptions); err != nil {
 				return fmt.Errorf("erro ao adicionar texto na edição %d: %w", i+1, err)
 			}
 
@@ -590,7 +345,128 @@ func (p *PDFCPUProcessor) ProcessEdits(ctx context.Context, inputPath, outputPat
 			}
 
 		case "drawing":
-			return fmt.Errorf("edição %d: tipo 'drawing' ainda não está implementado", i+1)
+			// Valida campos obrigatórios
+			if edit.Shape == "" {
+				return fmt.Errorf("edição %d: forma do desenho não pode ser vazia", i+1)
+			}
+			opacity := 1.0 // Opaco por padrão
+			if edit.Opacity != nil {
+				opacity = *edit.Opacity
+			}
+
+			drawing := appModel.Drawing{
+				Shape:       appModel.DrawingShape(edit.Shape),
+				X:           edit.X,
+				Y:           edit.Y,
+				Width:       edit.Width,
+				Height:      edit.Height,
+				Points:      edit.Points,
+				StrokeColor: edit.StrokeColor,
+				FillColor:   edit.FillColor,
+				LineWidth:   edit.LineWidth,
+				DashPattern: edit.DashPattern,
+				Opacity:     opacity,
+			}
+
+			// Aplica a edição de desenho
+			if err := p.AddDrawing(ctx, tempPath, edit.Page, drawing); err != nil {
+				return fmt.Errorf("erro ao adicionar desenho na edição %d: %w", i+1, err)
+			}
+
+		case "rotate_page":
+			if err := p.RotatePage(ctx, tempPath, edit.Page, int(edit.Rotation)); err != nil {
+				return fmt.Errorf("erro ao rotacionar página na edição %d: %w", i+1, err)
+			}
+
+		case "delete_page":
+			if err := p.DeletePage(ctx, tempPath, edit.Page); err != nil {
+				return fmt.Errorf("erro ao remover página na edição %d: %w", i+1, err)
+			}
+
+		case "move_page":
+			if err := p.MovePage(ctx, tempPath, edit.Page, edit.TargetPage); err != nil {
+				return fmt.Errorf("erro ao mover página na edição %d: %w", i+1, err)
+			}
+
+		case "duplicate_page":
+			if err := p.DuplicatePage(ctx, tempPath, edit.Page); err != nil {
+				return fmt.Errorf("erro ao duplicar página na edição %d: %w", i+1, err)
+			}
+
+		case "insert_page":
+			// Tamanho padrão A4 em PDF points
+			width, height := edit.Width, edit.Height
+			if width <= 0 {
+				width = 595
+			}
+			if height <= 0 {
+				height = 842
+			}
+			if err := p.InsertBlankPage(ctx, tempPath, edit.Page, width, height); err != nil {
+				return fmt.Errorf("erro ao inserir página na edição %d: %w", i+1, err)
+			}
+
+		case "redact":
+			// Tarja preta com texto branco por padrão; fill=false remove o conteúdo sem pintar a região
+			redaction := appModel.Redaction{
+				X:           edit.X,
+				Y:           edit.Y,
+				Width:       edit.Width,
+				Height:      edit.Height,
+				FillColor:   "#000000",
+				OverlayText: edit.Content,
+				TextColor:   "#FFFFFF",
+				FontFamily:  edit.FontFamily,
+				FontSize:    edit.FontSize,
+			}
+			if edit.Fill != nil && !*edit.Fill {
+				redaction.FillColor = ""
+				redaction.TextColor = "#000000"
+			} else if edit.FillColor != "" {
+				redaction.FillColor = edit.FillColor
+			}
+			if edit.TextColor != "" {
+				redaction.TextColor = edit.TextColor
+			}
+
+			if _, err := p.Redact(ctx, tempPath, edit.Page, redaction); err != nil {
+				return fmt.Errorf("erro ao redigir região na edição %d: %w", i+1, err)
+			}
+
+		case "form_field":
+			definition := appModel.FormFieldDefinition{
+				Name:         edit.Name,
+				Type:         appModel.FormFieldType(edit.FieldType),
+				Page:         edit.Page,
+				X:            edit.X,
+				Y:            edit.Y,
+				Width:        edit.Width,
+				Height:       edit.Height,
+				DefaultValue: edit.DefaultValue,
+				ExportValue:  edit.ExportValue,
+				Options:      edit.Options,
+				Editable:     edit.Editable,
+				Multiline:    edit.Multiline,
+				MaxLength:    edit.MaxLength,
+				ReadOnly:     edit.ReadOnly,
+				Required:     edit.Required,
+				FontFamily:   edit.FontFamily,
+				FontWeight:   edit.FontWeight,
+				FontSize:     edit.FontSize,
+			}
+			if err := p.AddFormField(ctx, tempPath, definition); err != nil {
+				return fmt.Errorf("erro ao criar campo de formulário na edição %d: %w", i+1, err)
+			}
+
+		case "form_fill":
+			if err := p.FillForm(ctx, tempPath, edit.Fields); err != nil {
+				return fmt.Errorf("erro ao preencher formulário na edição %d: %w", i+1, err)
+			}
+
+		case "form_flatten":
+			if err := p.FlattenForm(ctx, tempPath); err != nil {
+				return fmt.Errorf("erro ao achatar formulário na edição %d: %w", i+1, err)
+			}
 
 		default:
 			return fmt.Errorf("edição %d: tipo de edição desconhecido: %s", i+1, edit.Type)
@@ -620,7 +496,7 @@ func (p *PDFCPUProcessor) ProcessEdits(ctx context.Context, inputPath, outputPat
 
 // EditInstruction representa uma instrução de edição
 type EditInstruction struct {
-	Type     string                 `json:"type"` // "text", "image", "drawing"
+	Type     string                 `json:"type"` // "text", "image", "drawing", "rotate_page", "delete_page", "move_page", "duplicate_page", "insert_page", "redact", "form_field", "form_fill", "form_flatten"
 	Page     int                    `json:"page"`
 	X        float64                `json:"x"`
 	Y        float64                `json:"y"`
@@ -629,6 +505,45 @@ type EditInstruction struct {
 	Content  string                 `json:"content,omitempty"`
 	FontSize float64                `json:"fontSize,omitempty"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+
+	// Campos de texto (type=text)
+	FontFamily string  `json:"fontFamily,omitempty"`
+	FontWeight string  `json:"fontWeight,omitempty"`
+	Rotation   float64 `json:"rotation,omitempty"`
+	LineHeight float64 `json:"lineHeight,omitempty"`
+	Align      string  `json:"align,omitempty"`
+	Overflow   string  `json:"overflow,omitempty"`
+
+	// Campos de páginas (move_page)
+	TargetPage int `json:"targetPage,omitempty"`
+
+	// Campos de desenho (type=drawing)
+	Shape       string           `json:"shape,omitempty"`
+	Points      []appModel.Point `json:"points,omitempty"`
+	StrokeColor string           `json:"strokeColor,omitempty"`
+	FillColor   string           `json:"fillColor,omitempty"`
+	LineWidth   float64          `json:"lineWidth,omitempty"`
+	DashPattern []float64        `json:"dashPattern,omitempty"`
+	Opacity     *float64         `json:"opacity,omitempty"`
+
+	// Campos de redação (type=redact)
+	Fill      *bool  `json:"fill,omitempty"`
+	TextColor string `json:"textColor,omitempty"`
+
+	// Campos de criação de formulário (type=form_field)
+	FieldType    string   `json:"fieldType,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	DefaultValue string   `json:"defaultValue,omitempty"`
+	ExportValue  string   `json:"exportValue,omitempty"`
+	Options      []string `json:"options,omitempty"`
+	Editable     bool     `json:"editable,omitempty"`
+	Multiline    bool     `json:"multiline,omitempty"`
+	MaxLength    int      `json:"maxLength,omitempty"`
+	ReadOnly     bool     `json:"readOnly,omitempty"`
+	Required     bool     `json:"required,omitempty"`
+
+	// Campos de formulário (type=form_fill)
+	Fields map[string]interface{} `json:"fields,omitempty"`
 }
 
 // Helper function para converter imagem para bytes
@@ -0,0 +1,723 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/unidoc/unipdf/v3/contentstream"
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+	"go.uber.org/zap"
+)
+
+// maxFormDepth limita a recursão em form XObjects aninhados
+const maxFormDepth = 8
+
+// Redact remove definitivamente o conteúdo de uma região da página: glifos, pixels de imagens,
+// caminhos vetoriais e anotações que a interceptam. Em seguida pinta o preenchimento e o texto
+// sobreposto opcionais. Retorna um relatório com a quantidade de elementos removidos
+func (p *PDFCPUProcessor) Redact(ctx context.Context, filePath string, pageNum int, redaction appModel.Redaction) (*appModel.RedactionReport, error) {
+	if redaction.Width <= 0 || redaction.Height <= 0 {
+		return nil, fmt.Errorf("região de redação requer largura e altura maiores que zero")
+	}
+
+	reader, file, page, numPages, err := openPage(filePath, pageNum)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	mediaBox, err := page.GetMediaBox()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter dimensões da página: %w", err)
+	}
+
+	// Converte a região (origem no topo) para o espaço do PDF, considerando a origem da MediaBox
+	region := bbox{
+		llx: mediaBox.Llx + redaction.X,
+		lly: mediaBox.Ury - redaction.Y - redaction.Height,
+		urx: mediaBox.Llx + redaction.X + redaction.Width,
+		ury: mediaBox.Ury - redaction.Y,
+	}
+
+	report := &appModel.RedactionReport{
+		Page:   pageNum,
+		X:      redaction.X,
+		Y:      redaction.Y,
+		Width:  redaction.Width,
+		Height: redaction.Height,
+	}
+	r := &redactor{region: region, report: report, fonts: make(fontCache), removed: make(map[core.PdfObject]bool)}
+
+	// Recursos compartilhados com outras páginas não podem ser alterados: a página recebe uma cópia
+	page.Resources = copyResources(page.Resources)
+
+	contentStreams, err := page.GetContentStreams()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao obter content stream: %w", err)
+	}
+	content := strings.Join(contentStreams, "\n")
+
+	ops, err := contentstream.NewContentStreamParser(content).Parse()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao interpretar content stream: %w", err)
+	}
+
+	redactedOps, changed, err := r.redactContent(ops, page.Resources, newContentState(), 0)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		content = string(redactedOps.Bytes())
+	}
+
+	if err := r.redactAnnotations(page); err != nil {
+		return nil, err
+	}
+
+	// Campos de formulário cujos widgets foram removidos guardam o valor em /V e também são removidos
+	if reader.AcroForm != nil && len(r.removed) > 0 {
+		reader.AcroForm.Fields = pruneFormFields(reader.AcroForm.Fields, r.removed)
+	}
+
+	// Preenchimento e texto sobreposto
+	overlay, err := p.buildRedactionOverlay(page, region, redaction)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := page.SetContentStreams([]string{"q", content, "Q", overlay}, core.NewFlateEncoder()); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar content stream: %w", err)
+	}
+
+	if err := writeModifiedPage(reader, numPages, pageNum, page, filePath); err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Debug("Região redigida",
+		zap.String("file", filePath),
+		zap.Int("page", pageNum),
+		zap.Int("glyphs_removed", report.GlyphsRemoved),
+		zap.Int("images_redacted", report.ImagesRedacted),
+		zap.Int("images_removed", report.ImagesRemoved),
+		zap.Int("paths_removed", report.PathsRemoved),
+		zap.Int("annotations_removed", report.AnnotationsRemoved),
+	)
+
+	return report, nil
+}
+
+// buildRedactionOverlay gera o preenchimento e o texto pintados sobre a região redigida
+func (p *PDFCPUProcessor) buildRedactionOverlay(page *model.PdfPage, region bbox, redaction appModel.Redaction) (string, error) {
+	var overlay strings.Builder
+
+	if redaction.FillColor != "" {
+		fill, err := parseHexColor(redaction.FillColor)
+		if err != nil {
+			return "", err
+		}
+
+		contentCreator := contentstream.NewContentCreator()
+		contentCreator.Add_q()
+		contentCreator.Add_rg(fill.R, fill.G, fill.B)
+		contentCreator.Add_re(region.llx, region.lly, region.urx-region.llx, region.ury-region.lly)
+		contentCreator.Add_f()
+		contentCreator.Add_Q()
+		overlay.Write(contentCreator.Bytes())
+	}
+
+	if redaction.OverlayText != "" {
+		fontSize := redaction.FontSize
+		if fontSize <= 0 {
+			fontSize = 10
+		}
+
+		// O texto é centralizado e reduzido para caber na região
+		text, err := p.buildTextContent(page, region.llx, region.ury, redaction.OverlayText, appModel.TextOptions{
+			FontFamily: redaction.FontFamily,
+			FontSize:   fontSize,
+			Color:      redaction.TextColor,
+			Opacity:    1,
+			Align:      appModel.TextAlignCenter,
+			Width:      redaction.Width,
+			Height:     redaction.Height,
+			Overflow:   appModel.TextOverflowShrink,
+		})
+		if err != nil {
+			return "", err
+		}
+		overlay.Write(text.Bytes())
+	}
+
+	return overlay.String(), nil
+}
+
+// redactor remove de content streams tudo o que intercepta uma região
+type redactor struct {
+	region  bbox // Em espaço do dispositivo (espaço padrão da página)
+	report  *appModel.RedactionReport
+	fonts   fontCache
+	removed map[core.PdfObject]bool // Anotações removidas da página
+}
+
+// redactContent percorre as operações de um content stream removendo o conteúdo da região
+// Retorna as novas operações e se houve alteração
+func (r *redactor) redactContent(ops *contentstream.ContentStreamOperations, resources *model.PdfPageResources, state contentState, depth int) (*contentstream.ContentStreamOperations, bool, error) {
+	out := make(contentstream.ContentStreamOperations, 0, len(*ops))
+	changed := false
+
+	var (
+		stack       []contentState
+		cursor      = textCursor{tm: identityMatrix, tlm: identityMatrix}
+		path        = emptyBBox
+		pathStart   = -1 // Índice em out da primeira operação do caminho em construção
+		pendingClip bool
+		marked      []*contentstream.ContentStreamOperation // Marked content abertos (BMC/BDC)
+		dirtyMarked = make(map[*contentstream.ContentStreamOperation]bool)
+	)
+
+	for _, op := range *ops {
+		if state.applyTextOperator(op, &cursor, r.fonts, resources) {
+			out = append(out, op)
+			continue
+		}
+
+		switch op.Operand {
+		case "q":
+			stack = append(stack, state)
+
+		case "Q":
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+
+		case "cm":
+			if m, ok := matrixFromParams(op.Params); ok {
+				state.ctm = m.multiply(state.ctm)
+			}
+
+		case "w":
+			if value, ok := numberParam(op.Params, 0); ok {
+				state.lineWidth = value
+			}
+
+		// Construção de caminhos
+		case "m", "l", "c", "v", "y", "re":
+			if pathStart < 0 {
+				pathStart = len(out)
+			}
+			path = extendPath(path, op, state.ctm)
+
+		case "W", "W*":
+			pendingClip = true
+
+		// Pintura de caminhos
+		case "S", "s", "f", "F", "f*", "B", "B*", "b", "b*", "n":
+			if pendingClip {
+				state.clip = state.clip.intersection(path)
+			}
+
+			painted := path
+			if strokesPath(op.Operand) {
+				painted = painted.expand(state.lineWidth * state.ctm.scale() / 2)
+			}
+
+			remove := op.Operand != "n" && pathStart >= 0 && painted.intersects(r.region)
+			clip := pendingClip
+			start := pathStart
+			path, pathStart, pendingClip = emptyBBox, -1, false
+
+			if remove {
+				r.report.PathsRemoved++
+				changed = true
+				if clip {
+					// Mantém o caminho como recorte, sem pintá-lo
+					out = append(out, &contentstream.ContentStreamOperation{Operand: "n"})
+				} else {
+					out = out[:start]
+				}
+				continue
+			}
+
+		// Sombreamentos pintam toda a área de recorte
+		case "sh":
+			if state.clip.intersects(r.region) {
+				r.report.PathsRemoved++
+				changed = true
+				continue
+			}
+
+		// Imagens inline ocupam o quadrado unitário
+		case "BI":
+			if unitSquare.transform(state.ctm).intersects(r.region) {
+				r.report.ImagesRemoved++
+				changed = true
+				continue
+			}
+
+		case "Do":
+			name, ok := nameParam(op.Params, 0)
+			if !ok {
+				break
+			}
+
+			remove, xobjectChanged, err := r.redactXObject(resources, name, state, depth)
+			if err != nil {
+				return nil, false, err
+			}
+			if xobjectChanged {
+				changed = true
+			}
+			if remove {
+				changed = true
+				continue
+			}
+
+		// Exibição de texto
+		case "Tj", "TJ", "'", "\"":
+			var prefix []*contentstream.ContentStreamOperation
+			var shown core.PdfObject
+
+			switch op.Operand {
+			case "'":
+				cursor.newLine(0, -state.leading)
+				prefix = append(prefix, &contentstream.ContentStreamOperation{Operand: "T*"})
+				shown = objectParam(op.Params, 0)
+			case "\"":
+				if value, ok := numberParam(op.Params, 0); ok {
+					state.wordSpacing = value
+				}
+				if value, ok := numberParam(op.Params, 1); ok {
+					state.charSpacing = value
+				}
+				cursor.newLine(0, -state.leading)
+				prefix = append(prefix,
+					&contentstream.ContentStreamOperation{Operand: "Tw", Params: []core.PdfObject{core.MakeFloat(state.wordSpacing)}},
+					&contentstream.ContentStreamOperation{Operand: "Tc", Params: []core.PdfObject{core.MakeFloat(state.charSpacing)}},
+					&contentstream.ContentStreamOperation{Operand: "T*"},
+				)
+				shown = objectParam(op.Params, 2)
+			default:
+				shown = objectParam(op.Params, 0)
+			}
+
+			redacted, removed := r.redactText(shown, &cursor.tm, state)
+			if removed == 0 {
+				break
+			}
+
+			r.report.GlyphsRemoved += removed
+			changed = true
+			for _, markedOp := range marked {
+				dirtyMarked[markedOp] = true
+			}
+
+			out = append(out, prefix...)
+			out = append(out, &contentstream.ContentStreamOperation{Operand: "TJ", Params: []core.PdfObject{redacted}})
+			continue
+
+		// Marked content
+		case "BMC", "BDC":
+			marked = append(marked, op)
+
+		case "EMC":
+			if len(marked) > 0 {
+				marked = marked[:len(marked)-1]
+			}
+		}
+
+		out = append(out, op)
+	}
+
+	// Textos alternativos de trechos com glifos removidos também poderiam revelar o conteúdo
+	for op := range dirtyMarked {
+		if properties, ok := core.GetDict(objectParam(op.Params, 1)); ok {
+			properties.Remove("ActualText")
+			properties.Remove("Alt")
+			properties.Remove("E")
+		}
+	}
+
+	return &out, changed, nil
+}
+
+// redactText remove os glifos da região de uma string (Tj) ou array (TJ), avançando a matriz de texto
+// Glifos removidos viram deslocamentos no array TJ, mantendo a posição dos glifos seguintes
+func (r *redactor) redactText(shown core.PdfObject, tm *matrix, state contentState) (*core.PdfObjectArray, int) {
+	font := state.currentFont()
+
+	var elements []core.PdfObject
+	if array, ok := core.GetArray(shown); ok {
+		elements = array.Elements()
+	} else {
+		elements = []core.PdfObject{shown}
+	}
+
+	result := core.MakeArray()
+	var pending []byte
+	adjustment := 0.0
+	removed := 0
+
+	flushText := func() {
+		if len(pending) > 0 {
+			result.Append(core.MakeStringFromBytes(pending))
+			pending = nil
+		}
+	}
+	flushAdjustment := func() {
+		if adjustment != 0 {
+			result.Append(core.MakeFloat(adjustment))
+			adjustment = 0
+		}
+	}
+
+	fontSize, hScale := state.fontSize, state.hScale
+	for _, element := range elements {
+		if data, ok := core.GetStringBytes(element); ok {
+			for _, glyph := range font.glyphs(data) {
+				advance := state.glyphAdvance(glyph)
+				glyphBox := state.glyphBox(glyph, font, *tm)
+
+				if fontSize != 0 && hScale != 0 && glyphBox.intersects(r.region) {
+					removed++
+					flushText()
+					adjustment -= advance * 1000 / (fontSize * hScale)
+				} else {
+					flushAdjustment()
+					pending = append(pending, glyph.bytes...)
+				}
+
+				*tm = translateMatrix(advance, 0).multiply(*tm)
+			}
+			continue
+		}
+
+		if value, err := core.GetNumberAsFloat(element); err == nil {
+			flushText()
+			adjustment += value
+			*tm = translateMatrix(-value/1000*fontSize*hScale, 0).multiply(*tm)
+		}
+	}
+
+	flushText()
+	flushAdjustment()
+
+	return result, removed
+}
+
+// redactXObject trata o operador Do: imagens são apagadas na região (ou removidas) e form XObjects
+// são reescritos recursivamente. Retorna se o operador deve ser removido e se algum recurso mudou
+func (r *redactor) redactXObject(resources *model.PdfPageResources, name core.PdfObjectName, state contentState, depth int) (bool, bool, error) {
+	_, xobjectType := resources.GetXObjectByName(name)
+
+	switch xobjectType {
+	case model.XObjectTypeImage:
+		imageBox := unitSquare.transform(state.ctm)
+		if !imageBox.intersects(r.region) {
+			return false, false, nil
+		}
+
+		// Imagem inteiramente dentro da região: remove o desenho
+		if r.region.contains(imageBox) {
+			r.report.ImagesRemoved++
+			return true, false, nil
+		}
+
+		ximg, err := resources.GetXObjectImageByName(name)
+		if err == nil {
+			var redacted *model.XObjectImage
+			redacted, err = redactImagePixels(ximg, state.ctm, r.region)
+			if err == nil {
+				if err := resources.SetXObjectImageByName(name, redacted); err != nil {
+					return false, false, fmt.Errorf("erro ao substituir imagem redigida: %w", err)
+				}
+				r.report.ImagesRedacted++
+				return false, true, nil
+			}
+		}
+
+		// Imagens que não podem ser decodificadas (ex.: JBIG2, JPX) são removidas por completo
+		logger.Logger.Debug("Imagem removida por não poder ser redigida", zap.String("name", string(name)), zap.Error(err))
+		r.report.ImagesRemoved++
+		return true, false, nil
+
+	case model.XObjectTypeForm:
+		xform, err := resources.GetXObjectFormByName(name)
+		if err != nil || xform == nil {
+			return false, false, nil
+		}
+
+		formCTM, formBox := formPlacement(xform, state.ctm)
+		if !formBox.intersects(r.region) {
+			return false, false, nil
+		}
+
+		// Formulários aninhados demais são removidos por completo
+		if depth >= maxFormDepth {
+			r.report.PathsRemoved++
+			return true, false, nil
+		}
+
+		content, err := xform.GetContentStream()
+		if err != nil {
+			return false, false, fmt.Errorf("erro ao ler form XObject %s: %w", name, err)
+		}
+
+		ops, err := contentstream.NewContentStreamParser(string(content)).Parse()
+		if err != nil {
+			return false, false, fmt.Errorf("erro ao interpretar form XObject %s: %w", name, err)
+		}
+
+		// Formulários sem recursos próprios herdam os da página
+		formResources := xform.Resources
+		if formResources == nil {
+			formResources = resources
+		}
+		formResources = copyResources(formResources)
+
+		formState := state
+		formState.ctm = formCTM
+		formState.clip = state.clip.intersection(formBox)
+
+		redactedOps, changed, err := r.redactContent(ops, formResources, formState, depth+1)
+		if err != nil || !changed {
+			return false, false, err
+		}
+
+		// Cria um novo form XObject para não alterar o original, que pode ser usado por outras páginas
+		redactedForm := model.NewXObjectForm()
+		redactedForm.FormType = xform.FormType
+		redactedForm.BBox = xform.BBox
+		redactedForm.Matrix = xform.Matrix
+		redactedForm.Group = xform.Group
+		redactedForm.OC = xform.OC
+		redactedForm.Resources = formResources
+		if err := redactedForm.SetContentStream(redactedOps.Bytes(), core.NewFlateEncoder()); err != nil {
+			return false, false, fmt.Errorf("erro ao gravar form XObject redigido: %w", err)
+		}
+
+		if err := resources.SetXObjectFormByName(name, redactedForm); err != nil {
+			return false, false, fmt.Errorf("erro ao substituir form XObject redigido: %w", err)
+		}
+
+		return false, true, nil
+	}
+
+	return false, false, nil
+}
+
+// redactAnnotations remove as anotações que interceptam a região, junto com seus popups
+func (r *redactor) redactAnnotations(page *model.PdfPage) error {
+	annotations, err := page.GetAnnotations()
+	if err != nil {
+		return fmt.Errorf("erro ao obter anotações: %w", err)
+	}
+
+	removed := r.removed
+	var kept []*model.PdfAnnotation
+	for _, annotation := range annotations {
+		if values, ok := core.GetArray(annotation.Rect); ok {
+			if rect, err := model.NewPdfRectangle(*values); err == nil {
+				annotationBox := bbox{
+					math.Min(rect.Llx, rect.Urx), math.Min(rect.Lly, rect.Ury),
+					math.Max(rect.Llx, rect.Urx), math.Max(rect.Lly, rect.Ury),
+				}
+				if annotationBox.intersects(r.region) {
+					removed[annotation.GetContainingPdfObject()] = true
+					continue
+				}
+			}
+		}
+		kept = append(kept, annotation)
+	}
+
+	// Popups exibem o conteúdo da anotação de origem em outra posição da página
+	var result []*model.PdfAnnotation
+	for _, annotation := range kept {
+		if popup, ok := annotation.GetContext().(*model.PdfAnnotationPopup); ok && popup.Parent != nil {
+			if removed[core.ResolveReference(popup.Parent)] || removed[popup.Parent] {
+				removed[annotation.GetContainingPdfObject()] = true
+				continue
+			}
+		}
+		result = append(result, annotation)
+	}
+
+	if len(removed) > 0 {
+		r.report.AnnotationsRemoved += len(removed)
+		page.SetAnnotations(result)
+	}
+
+	return nil
+}
+
+// pruneFormFields remove dos campos os widgets removidos e descarta os campos que ficaram sem widgets
+func pruneFormFields(fields *[]*model.PdfField, removed map[core.PdfObject]bool) *[]*model.PdfField {
+	if fields == nil {
+		return nil
+	}
+
+	var prune func(fields []*model.PdfField, depth int) []*model.PdfField
+	prune = func(fields []*model.PdfField, depth int) []*model.PdfField {
+		kept := make([]*model.PdfField, 0, len(fields))
+		for _, field := range fields {
+			hadWidgets := len(field.Annotations) > 0 || len(field.Kids) > 0
+
+			widgets := make([]*model.PdfAnnotationWidget, 0, len(field.Annotations))
+			for _, widget := range field.Annotations {
+				if !removed[widget.GetContainingPdfObject()] {
+					widgets = append(widgets, widget)
+				}
+			}
+			field.Annotations = widgets
+
+			if depth < maxFieldDepth {
+				field.Kids = prune(field.Kids, depth+1)
+			}
+
+			if hadWidgets && len(field.Annotations) == 0 && len(field.Kids) == 0 {
+				continue
+			}
+			kept = append(kept, field)
+		}
+		return kept
+	}
+
+	result := prune(*fields, 0)
+	return &result
+}
+
+// redactImagePixels cria uma cópia da imagem com os pixels da região zerados
+// A soft mask, se houver, é apagada na mesma região
+func redactImagePixels(ximg *model.XObjectImage, ctm matrix, region bbox) (*model.XObjectImage, error) {
+	if isImageMask, ok := core.GetBoolVal(ximg.ImageMask); ok && isImageMask {
+		return nil, fmt.Errorf("máscaras de imagem não são suportadas")
+	}
+
+	inverse, ok := ctm.inverse()
+	if !ok {
+		return nil, fmt.Errorf("matriz de transformação da imagem é degenerada")
+	}
+
+	img, err := ximg.ToImage()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao decodificar imagem: %w", err)
+	}
+
+	// Região no espaço da imagem: o quadrado unitário com a linha 0 no topo
+	unit := region.transform(inverse).intersection(unitSquare)
+	width, height := int(img.Width), int(img.Height)
+	col0 := clampInt(int(math.Floor(unit.llx*float64(width))), 0, width)
+	col1 := clampInt(int(math.Ceil(unit.urx*float64(width))), 0, width)
+	row0 := clampInt(int(math.Floor((1-unit.ury)*float64(height))), 0, height)
+	row1 := clampInt(int(math.Ceil((1-unit.lly)*float64(height))), 0, height)
+
+	samples := img.GetSamples()
+	components := img.ColorComponents
+	for row := row0; row < row1; row++ {
+		for col := col0; col < col1; col++ {
+			offset := (row*width + col) * components
+			for c := 0; c < components && offset+c < len(samples); c++ {
+				samples[offset+c] = 0
+			}
+		}
+	}
+	img.SetSamples(samples)
+
+	redacted, err := model.NewXObjectImageFromImage(img, ximg.ColorSpace, core.NewFlateEncoder())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao recriar imagem: %w", err)
+	}
+	redacted.Decode = ximg.Decode
+	redacted.Intent = ximg.Intent
+	redacted.Interpolate = ximg.Interpolate
+	redacted.SMaskInData = ximg.SMaskInData
+
+	// Máscaras por cor (array) são mantidas; máscaras em imagem (stencil) não são suportadas
+	if _, isStream := core.GetStream(ximg.Mask); isStream {
+		return nil, fmt.Errorf("imagens com máscara explícita não são suportadas")
+	}
+	redacted.Mask = ximg.Mask
+
+	if smask, ok := core.GetStream(ximg.SMask); ok {
+		smaskImage, err := model.NewXObjectImageFromStream(smask)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler máscara de transparência: %w", err)
+		}
+		redactedSMask, err := redactImagePixels(smaskImage, ctm, region)
+		if err != nil {
+			return nil, err
+		}
+		redacted.SMask = redactedSMask.ToPdfObject()
+	}
+
+	return redacted, nil
+}
+
+// copyResources cria um dicionário de recursos próprio, com cópia do dicionário de XObjects,
+// para que substituições não afetem outras páginas ou formulários que compartilham os recursos
+func copyResources(resources *model.PdfPageResources) *model.PdfPageResources {
+	copied := model.NewPdfPageResources()
+	if resources == nil {
+		return copied
+	}
+
+	copied.ExtGState = resources.ExtGState
+	copied.ColorSpace = resources.ColorSpace
+	copied.Pattern = resources.Pattern
+	copied.Shading = resources.Shading
+	copied.Font = resources.Font
+	copied.ProcSet = resources.ProcSet
+	copied.Properties = resources.Properties
+
+	if xobjects, ok := core.GetDict(resources.XObject); ok {
+		xobjectsCopy := core.MakeDict()
+		for _, key := range xobjects.Keys() {
+			xobjectsCopy.Set(key, xobjects.Get(key))
+		}
+		copied.XObject = xobjectsCopy
+	}
+
+	return copied
+}
+
+// extendPath amplia a caixa do caminho com os pontos de uma operação de construção
+func extendPath(path bbox, op *contentstream.ContentStreamOperation, ctm matrix) bbox {
+	values, err := core.GetNumbersAsFloat(op.Params)
+	if err != nil {
+		return path
+	}
+
+	if op.Operand == "re" && len(values) == 4 {
+		rect := bbox{values[0], values[1], values[0] + values[2], values[1] + values[3]}
+		for _, corner := range [][2]float64{{rect.llx, rect.lly}, {rect.urx, rect.lly}, {rect.urx, rect.ury}, {rect.llx, rect.ury}} {
+			path = path.extend(ctm.transform(corner[0], corner[1]))
+		}
+		return path
+	}
+
+	// Pontos de controle de curvas também entram na caixa (limite conservador)
+	for i := 0; i+1 < len(values); i += 2 {
+		path = path.extend(ctm.transform(values[i], values[i+1]))
+	}
+	return path
+}
+
+// strokesPath verifica se o operador de pintura contorna o caminho
+func strokesPath(operand string) bool {
+	switch operand {
+	case "S", "s", "B", "B*", "b", "b*":
+		return true
+	}
+	return false
+}
+
+// clampInt limita um valor ao intervalo [low, high]
+func clampInt(value, low, high int) int {
+	return max(low, min(value, high))
+}
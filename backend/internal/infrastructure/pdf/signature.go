@@ -0,0 +1,471 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/internal/infrastructure/signing"
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+// Dicionário de assinatura PAdES (ETSI EN 319 142-1)
+const (
+	signatureFilter    = "Adobe.PPKLite"
+	signatureSubFilter = "ETSI.CAdES.detached"
+	signatureFieldName = "Assinatura" // Prefixo dos nomes gerados (Assinatura1, Assinatura2, ...)
+)
+
+// Espaço reservado em /Contents para a estrutura CMS, além dos certificados incluídos
+const (
+	signatureBaseSize      = 8192
+	signatureTimestampSize = 8192
+)
+
+// Largura reservada para cada valor do ByteRange, preenchido após a gravação
+const byteRangeDigits = 10
+
+// Flags do AcroForm e da anotação de assinatura
+const (
+	sigFlagsSignaturesExist = 1
+	sigFlagsAppendOnly      = 2
+	annotationFlagLocked    = 1 << 7
+)
+
+// Aparição visível da assinatura
+const (
+	signatureMaxFontSize = 10.0
+	signatureDateLayout  = "02/01/2006 15:04:05 -07:00"
+)
+
+// SignPDF aplica uma assinatura PAdES-B (ETSI.CAdES.detached) em uma atualização incremental,
+// preservando os bytes das revisões anteriores. Retorna o nome do campo assinado
+func (p *PDFCPUProcessor) SignPDF(ctx context.Context, filePath string, identity *appModel.SigningIdentity, options appModel.SignatureOptions, tsa domain.TimestampAuthority) (string, error) {
+	original, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	// Sem validação: o contexto é usado apenas para localizar e alterar objetos, sem reescrever o arquivo
+	pdfCtx, err := api.ReadContext(bytes.NewReader(original), pdfcpuModel.NewDefaultConfiguration())
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+	if pdfCtx.Encrypt != nil {
+		return "", errors.New("documento criptografado não pode ser assinado")
+	}
+	if err := pdfCtx.EnsurePageCount(); err != nil {
+		return "", fmt.Errorf("erro ao ler páginas do PDF: %w", err)
+	}
+
+	update := newIncrementalUpdate(pdfCtx)
+	signingTime := time.Now()
+
+	// Reserva /Contents para a assinatura: estrutura CMS, cadeia de certificados e carimbo do tempo
+	reserved := signatureBaseSize + len(identity.Certificate.Raw)
+	for _, cert := range identity.Chain {
+		reserved += len(cert.Raw)
+	}
+	if tsa != nil {
+		reserved += signatureTimestampSize
+	}
+
+	sigBody, byteRangeIndex, contentsIndex := signatureDictionary(identity, options, signingTime, reserved)
+	sigRef, err := update.addRaw(sigBody)
+	if err != nil {
+		return "", err
+	}
+
+	fieldName, err := addSignatureField(update, identity, options, signingTime, sigRef)
+	if err != nil {
+		return "", err
+	}
+
+	output, offsets, err := update.write(original)
+	if err != nil {
+		return "", fmt.Errorf("erro ao gravar atualização incremental: %w", err)
+	}
+
+	// Posições absolutas do ByteRange e do /Contents dentro do arquivo
+	sigNr := sigRef.ObjectNumber.Value()
+	bodyStart := offsets[sigNr] + len(fmt.Sprintf("%d 0 obj\n", sigNr))
+	contentsStart := bodyStart + contentsIndex
+	contentsEnd := contentsStart + 2*reserved + 2
+
+	byteRange := fmt.Sprintf("[0 %d %d %d]", contentsStart, contentsEnd, len(output)-contentsEnd)
+	placeholder := len(fmt.Sprintf("[0 %s %s %s]", strings.Repeat("0", byteRangeDigits), strings.Repeat("0", byteRangeDigits), strings.Repeat("0", byteRangeDigits)))
+	if len(byteRange) > placeholder {
+		return "", errors.New("documento muito grande para assinatura")
+	}
+	copy(output[bodyStart+byteRangeIndex:], byteRange+strings.Repeat(" ", placeholder-len(byteRange)))
+
+	digest := sha256.New()
+	digest.Write(output[:contentsStart])
+	digest.Write(output[contentsEnd:])
+
+	signature, err := signing.CreateDetachedSignature(ctx, digest.Sum(nil), identity, tsa)
+	if err != nil {
+		return "", err
+	}
+	if len(signature) > reserved {
+		return "", fmt.Errorf("assinatura excede o espaço reservado (%d de %d bytes)", len(signature), reserved)
+	}
+	hex.Encode(output[contentsStart+1:], signature)
+
+	if err := rewriteFile(filePath, func(_ *os.File, out *os.File) error {
+		_, err := out.Write(output)
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("erro ao salvar PDF: %w", err)
+	}
+
+	logger.Logger.Debug("Documento assinado",
+		zap.String("file", filePath),
+		zap.String("field", fieldName),
+		zap.String("signer", identity.Certificate.Subject.CommonName),
+		zap.Bool("timestamp", tsa != nil),
+	)
+
+	return fieldName, nil
+}
+
+// signatureDictionary serializa o dicionário de assinatura com ByteRange e /Contents reservados
+// Retorna o corpo e as posições, relativas ao corpo, do ByteRange e do '<' de /Contents
+func signatureDictionary(identity *appModel.SigningIdentity, options appModel.SignatureOptions, signingTime time.Time, reserved int) ([]byte, int, int) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<</Type/Sig/Filter/%s/SubFilter/%s", signatureFilter, signatureSubFilter)
+
+	buf.WriteString("/ByteRange")
+	byteRangeIndex := buf.Len()
+	fmt.Fprintf(&buf, "[0 %s %s %s]", strings.Repeat("0", byteRangeDigits), strings.Repeat("0", byteRangeDigits), strings.Repeat("0", byteRangeDigits))
+
+	buf.WriteString("/Contents")
+	contentsIndex := buf.Len()
+	buf.WriteString("<")
+	buf.WriteString(strings.Repeat("0", 2*reserved))
+	buf.WriteString(">")
+
+	buf.WriteString("/M")
+	buf.WriteString(types.StringLiteral(types.DateString(signingTime)).PDFString())
+	if name := identity.Certificate.Subject.CommonName; name != "" {
+		buf.WriteString("/Name")
+		buf.WriteString(pdfTextString(name).PDFString())
+	}
+	for _, entry := range [][2]string{{"Reason", options.Reason}, {"Location", options.Location}, {"ContactInfo", options.ContactInfo}} {
+		if entry[1] != "" {
+			fmt.Fprintf(&buf, "/%s%s", entry[0], pdfTextString(entry[1]).PDFString())
+		}
+	}
+	buf.WriteString(">>")
+
+	return buf.Bytes(), byteRangeIndex, contentsIndex
+}
+
+// addSignatureField vincula a assinatura a um campo de assinatura vazio existente ou cria um novo campo
+// com o widget na página informada; o AcroForm passa a indicar a presença de assinaturas (SigFlags)
+func addSignatureField(update *incrementalUpdate, identity *appModel.SigningIdentity, options appModel.SignatureOptions, signingTime time.Time, sigRef types.IndirectRef) (string, error) {
+	pdfCtx := update.ctx
+
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler catálogo do PDF: %w", err)
+	}
+
+	acroForm, err := pdfCtx.DereferenceDict(rootDict["AcroForm"])
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler formulário: %w", err)
+	}
+	switch ref := rootDict["AcroForm"].(type) {
+	case types.IndirectRef:
+		update.touch(ref)
+	case nil:
+		acroForm = types.Dict{"Fields": types.Array{}}
+		acroFormRef, err := update.add(acroForm)
+		if err != nil {
+			return "", err
+		}
+		rootDict.Update("AcroForm", acroFormRef)
+		update.touch(*pdfCtx.Root)
+	default:
+		update.touch(*pdfCtx.Root)
+	}
+	acroForm.Update("SigFlags", types.Integer(sigFlagsSignaturesExist|sigFlagsAppendOnly))
+
+	fields, err := pdfCtx.DereferenceArray(acroForm["Fields"])
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler campos do formulário: %w", err)
+	}
+
+	lines := signatureLines(identity, options, signingTime)
+
+	name := options.FieldName
+	if name != "" {
+		existing, err := findTopLevelField(pdfCtx, fields, name)
+		if err != nil {
+			return "", err
+		}
+		if existing != nil {
+			return name, signExistingField(update, *existing, name, lines, sigRef)
+		}
+	} else {
+		for i := 1; ; i++ {
+			name = fmt.Sprintf("%s%d", signatureFieldName, i)
+			existing, err := findTopLevelField(pdfCtx, fields, name)
+			if err != nil {
+				return "", err
+			}
+			if existing == nil {
+				break
+			}
+		}
+	}
+
+	page := options.Page
+	if page == 0 {
+		page = 1
+	}
+	if page < 1 || page > pdfCtx.PageCount {
+		return "", fmt.Errorf("página inválida: %d (PDF tem %d páginas)", page, pdfCtx.PageCount)
+	}
+
+	pageDict, pageRef, inherited, err := pdfCtx.PageDict(page, false)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler página %d: %w", page, err)
+	}
+	if inherited == nil || inherited.MediaBox == nil {
+		return "", fmt.Errorf("página %d não possui MediaBox", page)
+	}
+
+	// Assinatura invisível: widget com retângulo vazio, ainda vinculado à página
+	rect := bbox{}
+	if options.Width > 0 && options.Height > 0 {
+		mediaBox := inherited.MediaBox
+		rect = bbox{
+			llx: mediaBox.LL.X + options.X,
+			lly: mediaBox.UR.Y - options.Y - options.Height,
+			urx: mediaBox.LL.X + options.X + options.Width,
+			ury: mediaBox.UR.Y - options.Y,
+		}
+	}
+
+	apRef, err := addSignatureAppearance(update, lines, rect.urx-rect.llx, rect.ury-rect.lly)
+	if err != nil {
+		return "", err
+	}
+
+	fieldRef, err := update.add(types.Dict{
+		"Type":    types.Name("Annot"),
+		"Subtype": types.Name("Widget"),
+		"FT":      types.Name("Sig"),
+		"T":       pdfTextString(name),
+		"V":       sigRef,
+		"F":       types.Integer(annotationFlagPrint | annotationFlagLocked),
+		"P":       *pageRef,
+		"Rect":    types.NewNumberArray(rect.llx, rect.lly, rect.urx, rect.ury),
+		"AP":      types.Dict{"N": apRef},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if ref, ok := acroForm["Fields"].(types.IndirectRef); ok {
+		update.touch(ref)
+		if entry, found := pdfCtx.FindTableEntryForIndRef(&ref); found {
+			entry.Object = append(fields, fieldRef)
+		}
+	} else {
+		acroForm.Update("Fields", append(fields, fieldRef))
+	}
+
+	annots, err := pdfCtx.DereferenceArray(pageDict["Annots"])
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler anotações da página: %w", err)
+	}
+	if ref, ok := pageDict["Annots"].(types.IndirectRef); ok {
+		update.touch(ref)
+		if entry, found := pdfCtx.FindTableEntryForIndRef(&ref); found {
+			entry.Object = append(annots, fieldRef)
+		}
+	} else {
+		pageDict.Update("Annots", append(annots, fieldRef))
+		update.touch(*pageRef)
+	}
+
+	return name, nil
+}
+
+// findTopLevelField procura um campo de primeiro nível pelo nome parcial
+func findTopLevelField(pdfCtx *pdfcpuModel.Context, fields types.Array, name string) (*types.IndirectRef, error) {
+	for _, fieldObj := range fields {
+		fieldDict, err := pdfCtx.DereferenceDict(fieldObj)
+		if err != nil || fieldDict == nil {
+			continue
+		}
+		partial, err := pdfCtx.DereferenceText(fieldDict["T"])
+		if err != nil || partial != name {
+			continue
+		}
+		if ref, ok := fieldObj.(types.IndirectRef); ok {
+			return &ref, nil
+		}
+		return nil, fmt.Errorf("já existe um campo com o nome %s", name)
+	}
+	return nil, nil
+}
+
+// signExistingField assina um campo de assinatura ainda não assinado, usando o retângulo do seu widget
+func signExistingField(update *incrementalUpdate, fieldRef types.IndirectRef, name string, lines []string, sigRef types.IndirectRef) error {
+	pdfCtx := update.ctx
+
+	fieldDict, err := pdfCtx.DereferenceDict(fieldRef)
+	if err != nil {
+		return fmt.Errorf("erro ao ler campo %s: %w", name, err)
+	}
+	if fieldType, _ := pdfCtx.DereferenceName(fieldDict["FT"], pdfcpuModel.V10, nil); fieldType.Value() != "Sig" {
+		return fmt.Errorf("já existe um campo com o nome %s", name)
+	}
+	if _, found := fieldDict.Find("V"); found {
+		return fmt.Errorf("o campo %s já está assinado", name)
+	}
+
+	fieldDict.Update("V", sigRef)
+	update.touch(fieldRef)
+
+	// Widget mesclado ao campo ou único filho
+	widgetDict, widgetRef := fieldDict, fieldRef
+	if !isWidget(fieldDict) {
+		kids, err := pdfCtx.DereferenceArray(fieldDict["Kids"])
+		if err != nil || len(kids) != 1 {
+			return fmt.Errorf("campo de assinatura %s deve ter exatamente um widget", name)
+		}
+		ref, ok := kids[0].(types.IndirectRef)
+		if !ok {
+			return fmt.Errorf("campo de assinatura %s deve ter exatamente um widget", name)
+		}
+		if widgetDict, err = pdfCtx.DereferenceDict(ref); err != nil || widgetDict == nil {
+			return fmt.Errorf("erro ao ler widget do campo %s", name)
+		}
+		widgetRef = ref
+	}
+
+	rect, _ := dictRect(pdfCtx, widgetDict, "Rect")
+	apRef, err := addSignatureAppearance(update, lines, rect.urx-rect.llx, rect.ury-rect.lly)
+	if err != nil {
+		return err
+	}
+	widgetDict.Update("AP", types.Dict{"N": apRef})
+	update.touch(widgetRef)
+
+	return nil
+}
+
+// signatureLines monta o texto da aparição visível da assinatura
+func signatureLines(identity *appModel.SigningIdentity, options appModel.SignatureOptions, signingTime time.Time) []string {
+	signer := identity.Certificate.Subject.CommonName
+	if signer == "" {
+		signer = identity.Certificate.Subject.String()
+	}
+
+	lines := []string{"Assinado digitalmente por", signer, "Data: " + signingTime.Format(signatureDateLayout)}
+	if options.Reason != "" {
+		lines = append(lines, "Motivo: "+options.Reason)
+	}
+	if options.Location != "" {
+		lines = append(lines, "Local: "+options.Location)
+	}
+	return lines
+}
+
+// addSignatureAppearance cria a aparição do widget; retângulos vazios recebem uma aparição vazia
+func addSignatureAppearance(update *incrementalUpdate, lines []string, width, height float64) (types.IndirectRef, error) {
+	width, height = math.Max(width, 0), math.Max(height, 0)
+	selected := fieldFonts["helvetica|normal"]
+
+	var content []byte
+	resources := types.Dict{}
+	if width > 0 && height > 0 {
+		var err error
+		if content, err = signatureAppearance(lines, selected, width, height); err != nil {
+			return types.IndirectRef{}, err
+		}
+
+		fontRef, err := update.add(types.Dict{
+			"Type":     types.Name("Font"),
+			"Subtype":  types.Name("Type1"),
+			"BaseFont": types.Name(selected.baseFont),
+			"Encoding": types.Name("WinAnsiEncoding"),
+		})
+		if err != nil {
+			return types.IndirectRef{}, err
+		}
+		resources["Font"] = types.Dict{selected.resName: fontRef}
+	}
+
+	streamDict, err := update.ctx.NewStreamDictForBuf(content)
+	if err != nil {
+		return types.IndirectRef{}, err
+	}
+	streamDict.Insert("Type", types.Name("XObject"))
+	streamDict.Insert("Subtype", types.Name("Form"))
+	streamDict.Insert("BBox", types.NewNumberArray(0, 0, width, height))
+	streamDict.Insert("Resources", resources)
+	if err := streamDict.Encode(); err != nil {
+		return types.IndirectRef{}, err
+	}
+
+	return update.add(*streamDict)
+}
+
+// signatureAppearance gera o texto da assinatura com fonte ajustada à altura e à largura do retângulo
+func signatureAppearance(lines []string, selected fieldFont, width, height float64) ([]byte, error) {
+	encoded := make([]string, len(lines))
+	widest := 0.0
+	for i, line := range lines {
+		var err error
+		if encoded[i], err = encodeWinAnsi(line); err != nil {
+			// Caracteres fora do WinAnsi são substituídos para não impedir a assinatura
+			encoded[i], _ = encodeWinAnsi(strings.Map(func(r rune) rune {
+				if _, err := encodeWinAnsi(string(r)); err != nil {
+					return '?'
+				}
+				return r
+			}, line))
+		}
+		widest = math.Max(widest, font.TextWidth(encoded[i], selected.baseFont, 1000)/1000)
+	}
+
+	fontSize := math.Min(signatureMaxFontSize, (height-2*fieldPadding)/(float64(len(lines))*fieldLineHeight))
+	if widest > 0 {
+		fontSize = math.Min(fontSize, (width-2*fieldPadding)/widest)
+	}
+	fontSize = math.Max(fontSize, 1)
+	leading := fontSize * fieldLineHeight
+
+	var content strings.Builder
+	fmt.Fprintf(&content, "q\n0 0 %s %s re W n\nBT\n/%s %s Tf\n0 g\n%s TL\n%s %s Td\n",
+		formatNumber(width), formatNumber(height), selected.resName, formatNumber(fontSize),
+		formatNumber(leading), formatNumber(fieldPadding), formatNumber(height-fieldPadding-fontSize))
+	for i, line := range encoded {
+		if i > 0 {
+			content.WriteString("T*\n")
+		}
+		fmt.Fprintf(&content, "(%s) Tj\n", escapePDFString(line))
+	}
+	content.WriteString("ET\nQ\n")
+
+	return []byte(content.String()), nil
+}
@@ -0,0 +1,355 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/editor-pdf/backend/internal/infrastructure/signing"
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// Formatos de assinatura verificáveis (valores de SubFilter)
+const (
+	subFilterCAdES         = "ETSI.CAdES.detached"
+	subFilterPKCS7Detached = "adbe.pkcs7.detached"
+	subFilterPKCS7SHA1     = "adbe.pkcs7.sha1"
+	subFilterDocTimestamp  = "ETSI.RFC3161"
+)
+
+// errByteRangeMismatch indica que o ByteRange não corresponde ao arquivo: os bytes assinados foram alterados
+var errByteRangeMismatch = errors.New("ByteRange não corresponde ao arquivo")
+
+// signatureField é um campo de assinatura assinado encontrado no formulário
+type signatureField struct {
+	name     string
+	sigDict  types.Dict
+	widgets  []types.Object
+	fieldObj types.Object
+}
+
+// VerifySignatures verifica todas as assinaturas do documento: integridade do conteúdo assinado,
+// cadeia de certificados até as raízes confiáveis, carimbo do tempo e alterações posteriores
+func (p *PDFCPUProcessor) VerifySignatures(ctx context.Context, filePath string, roots *x509.CertPool) ([]appModel.SignatureVerification, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	pdfCtx, err := api.ReadContext(bytes.NewReader(data), pdfcpuModel.NewDefaultConfiguration())
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF: %w", err)
+	}
+
+	if err := pdfCtx.EnsurePageCount(); err != nil {
+		return nil, fmt.Errorf("erro ao ler páginas do PDF: %w", err)
+	}
+
+	fields, err := collectSignatureFields(pdfCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := widgetPages(pdfCtx)
+
+	results := make([]appModel.SignatureVerification, 0, len(fields))
+	for _, field := range fields {
+		result := verifySignatureField(pdfCtx, data, field, roots)
+
+		for _, widgetObj := range field.widgets {
+			if ref, ok := widgetObj.(types.IndirectRef); ok {
+				result.Page = pages[ref.ObjectNumber.Value()]
+			}
+			if widgetDict, err := pdfCtx.DereferenceDict(widgetObj); err == nil && widgetDict != nil {
+				if rect, ok := dictRect(pdfCtx, widgetDict, "Rect"); ok {
+					result.Visible = rect.urx-rect.llx > 0 && rect.ury-rect.lly > 0
+				}
+			}
+			break
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// collectSignatureFields percorre a árvore de campos e retorna os campos de assinatura com valor
+func collectSignatureFields(pdfCtx *pdfcpuModel.Context) ([]signatureField, error) {
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler catálogo do PDF: %w", err)
+	}
+
+	acroForm, err := pdfCtx.DereferenceDict(rootDict["AcroForm"])
+	if err != nil || acroForm == nil {
+		return nil, nil
+	}
+
+	fields, err := pdfCtx.DereferenceArray(acroForm["Fields"])
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler campos do formulário: %w", err)
+	}
+
+	var result []signatureField
+	var walk func(fields types.Array, parentName, parentType string, depth int)
+	walk = func(fields types.Array, parentName, parentType string, depth int) {
+		if depth > maxFieldDepth {
+			return
+		}
+		for _, fieldObj := range fields {
+			fieldDict, err := pdfCtx.DereferenceDict(fieldObj)
+			if err != nil || fieldDict == nil {
+				continue
+			}
+
+			name := parentName
+			if partial, err := pdfCtx.DereferenceText(fieldDict["T"]); err == nil && partial != "" {
+				if name != "" {
+					name += "."
+				}
+				name += partial
+			}
+
+			fieldType := parentType
+			if ft, err := pdfCtx.DereferenceName(fieldDict["FT"], pdfcpuModel.V10, nil); err == nil && ft != "" {
+				fieldType = ft.Value()
+			}
+
+			kids, _ := pdfCtx.DereferenceArray(fieldDict["Kids"])
+			var childFields, widgets types.Array
+			for _, kid := range kids {
+				kidDict, err := pdfCtx.DereferenceDict(kid)
+				if err != nil || kidDict == nil {
+					continue
+				}
+				if _, hasName := kidDict.Find("T"); !hasName && isWidget(kidDict) {
+					widgets = append(widgets, kid)
+				} else {
+					childFields = append(childFields, kid)
+				}
+			}
+			if isWidget(fieldDict) {
+				widgets = append(widgets, fieldObj)
+			}
+
+			if fieldType == "Sig" {
+				if sigDict, err := pdfCtx.DereferenceDict(fieldDict["V"]); err == nil && sigDict != nil {
+					result = append(result, signatureField{name: name, sigDict: sigDict, widgets: widgets, fieldObj: fieldObj})
+				}
+			}
+
+			walk(childFields, name, fieldType, depth+1)
+		}
+	}
+	walk(fields, "", "", 0)
+
+	return result, nil
+}
+
+// widgetPages associa o número de objeto de cada anotação à página em que aparece
+func widgetPages(pdfCtx *pdfcpuModel.Context) map[int]int {
+	pages := make(map[int]int)
+	for pageNum := 1; pageNum <= pdfCtx.PageCount; pageNum++ {
+		pageDict, _, _, err := pdfCtx.PageDict(pageNum, false)
+		if err != nil || pageDict == nil {
+			continue
+		}
+		annots, err := pdfCtx.DereferenceArray(pageDict["Annots"])
+		if err != nil {
+			continue
+		}
+		for _, annot := range annots {
+			if ref, ok := annot.(types.IndirectRef); ok {
+				pages[ref.ObjectNumber.Value()] = pageNum
+			}
+		}
+	}
+	return pages
+}
+
+// verifySignatureField verifica uma assinatura: ByteRange, estrutura CMS ou carimbo do documento,
+// cadeia de certificados e cobertura do arquivo
+func verifySignatureField(pdfCtx *pdfcpuModel.Context, data []byte, field signatureField, roots *x509.CertPool) appModel.SignatureVerification {
+	sigDict := field.sigDict
+	result := appModel.SignatureVerification{FieldName: field.name}
+
+	if subFilter, err := pdfCtx.DereferenceName(sigDict["SubFilter"], pdfcpuModel.V10, nil); err == nil {
+		result.SubFilter = subFilter.Value()
+	}
+	if name, err := pdfCtx.DereferenceText(sigDict["Name"]); err == nil {
+		result.SignerName = name
+	}
+	result.Reason, _ = pdfCtx.DereferenceText(sigDict["Reason"])
+	result.Location, _ = pdfCtx.DereferenceText(sigDict["Location"])
+	if m, err := pdfCtx.DereferenceText(sigDict["M"]); err == nil && m != "" {
+		if signingTime, ok := types.DateTime(m, true); ok {
+			result.SigningTime = &signingTime
+		}
+	}
+
+	fail := func(format string, args ...interface{}) appModel.SignatureVerification {
+		result.Errors = append(result.Errors, fmt.Sprintf(format, args...))
+		return result
+	}
+
+	signedContent, end, err := byteRangeContent(pdfCtx, sigDict, data)
+	if err != nil {
+		if errors.Is(err, errByteRangeMismatch) {
+			result.ModifiedAfterSigning = true
+			result.IntegrityValid = false
+		}
+		return fail("%s", err)
+	}
+
+	// Bytes após a revisão assinada (exceto quebras de linha finais) indicam atualizações posteriores
+	result.CoversWholeDocument = len(bytes.TrimSpace(data[end:])) == 0
+	result.ModifiedAfterSigning = !result.CoversWholeDocument
+
+	contents, err := signatureContents(sigDict)
+	if err != nil {
+		return fail("%s", err)
+	}
+
+	switch result.SubFilter {
+	case subFilterCAdES, subFilterPKCS7Detached, subFilterPKCS7SHA1:
+		check, err := signing.VerifySignature(contents, signedContent, roots)
+		if err != nil {
+			return fail("%s", err)
+		}
+
+		result.IntegrityValid = check.IntegrityErr == nil
+		if check.IntegrityErr != nil {
+			result.Errors = append(result.Errors, check.IntegrityErr.Error())
+		}
+		result.CertificateTrusted = check.TrustErr == nil
+		if check.TrustErr != nil {
+			result.Errors = append(result.Errors, "cadeia de certificados não confiável: "+check.TrustErr.Error())
+		}
+		if check.Signer.Subject.CommonName != "" {
+			result.SignerName = check.Signer.Subject.CommonName
+		}
+		result.Certificates = certificateInfos(append([]*x509.Certificate{check.Signer}, withoutCertificate(check.Chain, check.Signer)...))
+		if check.Timestamp != nil {
+			result.Timestamp = timestampInfo(check.Timestamp)
+			result.Errors = append(result.Errors, timestampErrors(check.Timestamp)...)
+		}
+
+	case subFilterDocTimestamp:
+		check := signing.VerifyTimestampToken(contents, signedContent, roots)
+		result.IntegrityValid = check.IntegrityErr == nil
+		result.CertificateTrusted = check.TrustErr == nil
+		result.Timestamp = timestampInfo(check)
+		result.Errors = append(result.Errors, timestampErrors(check)...)
+		if check.Authority != nil {
+			result.SignerName = check.Authority.Subject.CommonName
+			result.Certificates = certificateInfos([]*x509.Certificate{check.Authority})
+		}
+
+	default:
+		return fail("formato de assinatura não suportado: %s", result.SubFilter)
+	}
+
+	result.Valid = result.IntegrityValid && result.CertificateTrusted &&
+		(result.Timestamp == nil || (result.Timestamp.Valid && result.Timestamp.Trusted))
+
+	return result
+}
+
+// byteRangeContent valida o ByteRange e retorna os bytes assinados e o fim da revisão assinada
+// O intervalo excluído deve corresponder exatamente à string hexadecimal de /Contents
+func byteRangeContent(pdfCtx *pdfcpuModel.Context, sigDict types.Dict, data []byte) ([]byte, int, error) {
+	byteRange, err := pdfCtx.DereferenceArray(sigDict["ByteRange"])
+	if err != nil || len(byteRange) != 4 {
+		return nil, 0, fmt.Errorf("ByteRange ausente ou inválido")
+	}
+
+	values := make([]int, 4)
+	for i, obj := range byteRange {
+		value, err := pdfCtx.DereferenceInteger(obj)
+		if err != nil || value == nil || value.Value() < 0 {
+			return nil, 0, fmt.Errorf("ByteRange ausente ou inválido")
+		}
+		values[i] = value.Value()
+	}
+
+	start1, length1, start2, length2 := values[0], values[1], values[2], values[3]
+	end := start2 + length2
+	if start1 != 0 || length1 >= start2 || end > len(data) {
+		return nil, 0, errByteRangeMismatch
+	}
+	if data[length1] != '<' || data[start2-1] != '>' {
+		return nil, 0, fmt.Errorf("%w: o intervalo não exclui exatamente o valor da assinatura", errByteRangeMismatch)
+	}
+
+	signed := make([]byte, 0, length1+length2)
+	signed = append(signed, data[:length1]...)
+	signed = append(signed, data[start2:end]...)
+	return signed, end, nil
+}
+
+// signatureContents decodifica o valor de /Contents (string hexadecimal ou literal)
+func signatureContents(sigDict types.Dict) ([]byte, error) {
+	contents, err := sigDict.StringEntryBytes("Contents")
+	if err != nil || len(contents) == 0 {
+		return nil, fmt.Errorf("valor da assinatura ausente ou inválido")
+	}
+	return contents, nil
+}
+
+// timestampInfo converte o resultado da verificação do carimbo do tempo
+func timestampInfo(check *signing.TimestampCheck) *appModel.SignatureTimestamp {
+	info := &appModel.SignatureTimestamp{
+		Time:    check.Time,
+		Valid:   check.IntegrityErr == nil,
+		Trusted: check.TrustErr == nil,
+	}
+	if check.Authority != nil {
+		info.Authority = check.Authority.Subject.CommonName
+	}
+	return info
+}
+
+// timestampErrors descreve as falhas do carimbo do tempo
+func timestampErrors(check *signing.TimestampCheck) []string {
+	var errs []string
+	if check.IntegrityErr != nil {
+		errs = append(errs, "carimbo do tempo inválido: "+check.IntegrityErr.Error())
+	} else if check.TrustErr != nil {
+		errs = append(errs, "autoridade de carimbo do tempo não confiável: "+check.TrustErr.Error())
+	}
+	return errs
+}
+
+// certificateInfos converte certificados X.509 para o modelo da resposta
+func certificateInfos(certs []*x509.Certificate) []appModel.CertificateInfo {
+	infos := make([]appModel.CertificateInfo, 0, len(certs))
+	for _, cert := range certs {
+		infos = append(infos, appModel.CertificateInfo{
+			Subject:      cert.Subject.String(),
+			Issuer:       cert.Issuer.String(),
+			SerialNumber: cert.SerialNumber.Text(16),
+			NotBefore:    cert.NotBefore.UTC().Truncate(time.Second),
+			NotAfter:     cert.NotAfter.UTC().Truncate(time.Second),
+		})
+	}
+	return infos
+}
+
+// withoutCertificate remove o certificado informado da lista
+func withoutCertificate(certs []*x509.Certificate, excluded *x509.Certificate) []*x509.Certificate {
+	result := make([]*x509.Certificate, 0, len(certs))
+	for _, cert := range certs {
+		if !cert.Equal(excluded) {
+			result = append(result, cert)
+		}
+	}
+	return result
+}
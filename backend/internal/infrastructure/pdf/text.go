@@ -0,0 +1,271 @@
+package pdf
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/unidoc/unipdf/v3/contentstream"
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+)
+
+const (
+	// defaultLineHeight é o espaçamento entre linhas padrão (multiplicador do tamanho da fonte)
+	defaultLineHeight = 1.2
+
+	// minShrinkFontSize é o menor tamanho de fonte usado pelo ajuste automático (shrink)
+	minShrinkFontSize = 4.0
+
+	// shrinkStep é o decremento do tamanho de fonte a cada tentativa de ajuste
+	shrinkStep = 0.5
+)
+
+// embeddedFont associa uma fonte embutida ao nome usado no content stream
+type embeddedFont struct {
+	name core.PdfObjectName
+	font *model.PdfFont
+}
+
+// textLine representa uma linha já posicionada, dividida em trechos por fonte
+type textLine struct {
+	runs    []textRun
+	encoded [][]byte
+	width   float64 // Em PDF points
+}
+
+// validateTextOptions valida as opções de formatação de texto
+func validateTextOptions(opts appModel.TextOptions) error {
+	if opts.FontSize <= 0 {
+		return fmt.Errorf("tamanho da fonte deve ser maior que zero")
+	}
+	if opts.Opacity < 0 || opts.Opacity > 1 {
+		return fmt.Errorf("opacidade deve estar entre 0 e 1")
+	}
+	if opts.LineHeight < 0 {
+		return fmt.Errorf("altura de linha não pode ser negativa")
+	}
+	if opts.Width < 0 || opts.Height < 0 {
+		return fmt.Errorf("dimensões da caixa de texto não podem ser negativas")
+	}
+
+	switch opts.Align {
+	case "", appModel.TextAlignLeft, appModel.TextAlignCenter, appModel.TextAlignRight:
+	default:
+		return fmt.Errorf("alinhamento de texto desconhecido: %s", opts.Align)
+	}
+
+	switch opts.Overflow {
+	case "", appModel.TextOverflowVisible, appModel.TextOverflowClip, appModel.TextOverflowShrink:
+	default:
+		return fmt.Errorf("comportamento de overflow desconhecido: %s", opts.Overflow)
+	}
+
+	return nil
+}
+
+// buildTextContent gera os operadores de texto, embutindo as fontes utilizadas na página
+// As coordenadas (x, yPdf) já estão no sistema do PDF (origem na base da página). Sem caixa,
+// indicam a linha de base da primeira linha; com caixa (Width > 0), o canto superior esquerdo
+func (p *PDFCPUProcessor) buildTextContent(page *model.PdfPage, x, yPdf float64, text string, opts appModel.TextOptions) (*contentstream.ContentCreator, error) {
+	if err := validateTextOptions(opts); err != nil {
+		return nil, err
+	}
+
+	primary, err := p.fonts.face(opts.FontFamily, opts.FontWeight)
+	if err != nil {
+		return nil, err
+	}
+
+	lineHeight := opts.LineHeight
+	if lineHeight <= 0 {
+		lineHeight = defaultLineHeight
+	}
+
+	// Quebra o texto em linhas, reduzindo a fonte se necessário
+	fontSize := opts.FontSize
+	lines := p.wrapText(text, primary, fontSize, opts.Width)
+	if opts.Overflow == appModel.TextOverflowShrink && opts.Width > 0 && opts.Height > 0 {
+		for fontSize > minShrinkFontSize && textBlockHeight(len(lines), fontSize, lineHeight, primary) > opts.Height {
+			fontSize = math.Max(fontSize-shrinkStep, minShrinkFontSize)
+			lines = p.wrapText(text, primary, fontSize, opts.Width)
+		}
+	}
+
+	// Divide cada linha em trechos e codifica, registrando os glifos usados em cada fonte
+	fonts := make(map[*fontFace]*embeddedFont)
+	layout := make([]textLine, 0, len(lines))
+	for _, lineText := range lines {
+		runs, err := p.fonts.splitRuns(lineText, opts.FontFamily, opts.FontWeight)
+		if err != nil {
+			return nil, err
+		}
+
+		line := textLine{runs: runs, width: p.fonts.measure(lineText, primary) * fontSize}
+		for _, run := range runs {
+			embedded, ok := fonts[run.face]
+			if !ok {
+				pdfFont, err := run.face.newPdfFont()
+				if err != nil {
+					return nil, err
+				}
+				embedded = &embeddedFont{font: pdfFont}
+				fonts[run.face] = embedded
+			}
+			line.encoded = append(line.encoded, embedded.font.Encoder().Encode(run.text))
+		}
+		layout = append(layout, line)
+	}
+
+	// Reduz cada fonte aos glifos usados e registra nos recursos da página
+	for _, embedded := range fonts {
+		if err := embedded.font.SubsetRegistered(); err != nil {
+			return nil, fmt.Errorf("erro ao gerar subset da fonte: %w", err)
+		}
+
+		embedded.name = generateFontName(page.Resources)
+		if err := page.Resources.SetFontByName(embedded.name, embedded.font.ToPdfObject()); err != nil {
+			return nil, fmt.Errorf("erro ao registrar fonte: %w", err)
+		}
+	}
+
+	color := rgbColor{}
+	if opts.Color != "" {
+		color, err = parseHexColor(opts.Color)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	contentCreator := contentstream.NewContentCreator()
+	contentCreator.Add_q() // Save graphics state
+
+	// Aplica opacidade através de um ExtGState
+	if opts.Opacity < 1 {
+		gsName, err := registerOpacity(page.Resources, opts.Opacity)
+		if err != nil {
+			return nil, err
+		}
+		contentCreator.Add_gs(gsName)
+	}
+
+	// Move a origem para o ponto de ancoragem e aplica a rotação em torno dele
+	radians := opts.Rotation * math.Pi / 180
+	cos, sin := math.Cos(radians), math.Sin(radians)
+	contentCreator.Add_cm(cos, sin, -sin, cos, x, yPdf)
+
+	hasBox := opts.Width > 0
+	if hasBox && opts.Height > 0 && opts.Overflow == appModel.TextOverflowClip {
+		contentCreator.Add_re(0, -opts.Height, opts.Width, opts.Height)
+		contentCreator.Add_W()
+		contentCreator.Add_n()
+	}
+
+	// Na caixa, a primeira linha de base fica abaixo do topo pela altura ascendente da fonte
+	baseline := 0.0
+	if hasBox {
+		baseline = -primary.ascent() * fontSize
+	}
+	leading := lineHeight * fontSize
+
+	contentCreator.Add_rg(color.R, color.G, color.B)
+	contentCreator.Add_BT() // Begin text object
+
+	for i, line := range layout {
+		contentCreator.Add_Tm(1, 0, 0, 1, alignOffset(opts.Align, line.width, opts.Width), baseline-float64(i)*leading)
+
+		// Cada trecho troca a fonte se necessário; Tj avança a posição automaticamente
+		for j, run := range line.runs {
+			contentCreator.Add_Tf(fonts[run.face].name, fontSize)
+			contentCreator.Add_Tj(*core.MakeHexString(string(line.encoded[j])))
+		}
+	}
+
+	contentCreator.Add_ET() // End text object
+	contentCreator.Add_Q()  // Restore graphics state
+
+	return contentCreator, nil
+}
+
+// wrapText divide o texto em linhas. Quebras de linha explícitas são sempre respeitadas;
+// com maxWidth > 0, as linhas são quebradas por palavra (ou por caractere, se a palavra não couber)
+func (p *PDFCPUProcessor) wrapText(text string, primary *fontFace, fontSize, maxWidth float64) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	paragraphs := strings.Split(text, "\n")
+	if maxWidth <= 0 {
+		return paragraphs
+	}
+
+	maxEm := maxWidth / fontSize
+	var lines []string
+	for _, paragraph := range paragraphs {
+		words := strings.FieldsFunc(paragraph, unicode.IsSpace)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		current := ""
+		for _, word := range words {
+			candidate := word
+			if current != "" {
+				candidate = current + " " + word
+			}
+
+			if p.fonts.measure(candidate, primary) <= maxEm {
+				current = candidate
+				continue
+			}
+
+			if current != "" {
+				lines = append(lines, current)
+			}
+
+			// Palavra maior que a linha: quebra por caractere
+			current = ""
+			for _, char := range word {
+				if current != "" && p.fonts.measure(current+string(char), primary) > maxEm {
+					lines = append(lines, current)
+					current = ""
+				}
+				current += string(char)
+			}
+		}
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// textBlockHeight calcula a altura ocupada por um bloco de linhas
+func textBlockHeight(lineCount int, fontSize, lineHeight float64, primary *fontFace) float64 {
+	if lineCount == 0 {
+		return 0
+	}
+	return primary.ascent()*fontSize + float64(lineCount-1)*lineHeight*fontSize
+}
+
+// alignOffset calcula o deslocamento horizontal de uma linha conforme o alinhamento
+// Sem caixa (boxWidth = 0), o alinhamento é relativo ao ponto de ancoragem
+func alignOffset(align appModel.TextAlign, lineWidth, boxWidth float64) float64 {
+	switch align {
+	case appModel.TextAlignCenter:
+		return (boxWidth - lineWidth) / 2
+	case appModel.TextAlignRight:
+		return boxWidth - lineWidth
+	default:
+		return 0
+	}
+}
+
+// generateFontName gera um nome de fonte ainda não usado nos recursos da página
+func generateFontName(resources *model.PdfPageResources) core.PdfObjectName {
+	for i := 1; ; i++ {
+		name := core.PdfObjectName(fmt.Sprintf("F%d", i))
+		if !resources.HasFontByName(name) {
+			return name
+		}
+	}
+}
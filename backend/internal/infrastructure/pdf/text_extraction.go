@@ -0,0 +1,381 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/unidoc/unipdf/v3/contentstream"
+	"github.com/unidoc/unipdf/v3/core"
+	"github.com/unidoc/unipdf/v3/model"
+	"go.uber.org/zap"
+)
+
+const (
+	// Distância entre glifos, em frações do tamanho da fonte, a partir da qual começa uma nova palavra
+	wordGapRatio = 0.15
+	// Deslocamento perpendicular à linha de base, em frações do tamanho da fonte, que inicia uma nova linha
+	lineShiftRatio = 0.5
+)
+
+// ExtractText extrai o texto das páginas informadas (todas quando vazio), com a posição
+// de cada linha e palavra em PDF points e origem no topo esquerdo da página
+func (p *PDFCPUProcessor) ExtractText(ctx context.Context, filePath string, pages []int) ([]appModel.PageText, error) {
+	reader, file, numPages, err := openReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	if len(pages) == 0 {
+		pages = make([]int, numPages)
+		for i := range pages {
+			pages[i] = i + 1
+		}
+	}
+
+	result := make([]appModel.PageText, 0, len(pages))
+	for _, pageNum := range pages {
+		if pageNum < 1 || pageNum > numPages {
+			return nil, fmt.Errorf("página inválida: %d (PDF tem %d páginas)", pageNum, numPages)
+		}
+
+		lines, mediaBox, err := readPageLines(reader, pageNum)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, toPageText(pageNum, lines, mediaBox))
+	}
+
+	logger.Logger.Debug("Texto extraído",
+		zap.String("file", filePath),
+		zap.Int("pages_count", len(result)),
+	)
+
+	return result, nil
+}
+
+// extractedGlyph é um glifo posicionado em espaço do dispositivo
+type extractedGlyph struct {
+	text    string
+	quad    quad
+	origin  [2]float64 // Início do glifo na linha de base
+	dir     [2]float64 // Direção unitária da linha de base
+	size    float64    // Tamanho da fonte em espaço do dispositivo
+	advance float64    // Deslocamento ao longo da linha de base
+}
+
+// extractedWord agrupa glifos consecutivos sem espaço entre si
+type extractedWord struct {
+	glyphs []extractedGlyph
+}
+
+// extractedLine agrupa palavras que compartilham a mesma linha de base
+type extractedLine struct {
+	words []extractedWord
+}
+
+// readPageLines interpreta o content stream de uma página e agrupa seus glifos em linhas e palavras
+func readPageLines(reader *model.PdfReader, pageNum int) ([]extractedLine, *model.PdfRectangle, error) {
+	page, err := reader.GetPage(pageNum)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao obter página %d: %w", pageNum, err)
+	}
+
+	mediaBox, err := page.GetMediaBox()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao obter dimensões da página %d: %w", pageNum, err)
+	}
+
+	contentStreams, err := page.GetContentStreams()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao obter content stream da página %d: %w", pageNum, err)
+	}
+
+	ops, err := contentstream.NewContentStreamParser(strings.Join(contentStreams, "\n")).Parse()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao interpretar content stream da página %d: %w", pageNum, err)
+	}
+
+	resources := page.Resources
+	if resources == nil {
+		resources = model.NewPdfPageResources()
+	}
+
+	e := &textExtractor{fonts: make(fontCache)}
+	e.extract(ops, resources, newContentState(), 0)
+
+	return layoutText(e.glyphs), mediaBox, nil
+}
+
+// textExtractor coleta os glifos exibidos por um content stream
+type textExtractor struct {
+	fonts  fontCache
+	glyphs []extractedGlyph
+}
+
+// extract percorre as operações acumulando os glifos; form XObjects são interpretados recursivamente
+func (e *textExtractor) extract(ops *contentstream.ContentStreamOperations, resources *model.PdfPageResources, state contentState, depth int) {
+	var stack []contentState
+	cursor := textCursor{tm: identityMatrix, tlm: identityMatrix}
+
+	for _, op := range *ops {
+		if state.applyTextOperator(op, &cursor, e.fonts, resources) {
+			continue
+		}
+
+		switch op.Operand {
+		case "q":
+			stack = append(stack, state)
+
+		case "Q":
+			if len(stack) > 0 {
+				state = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+
+		case "cm":
+			if m, ok := matrixFromParams(op.Params); ok {
+				state.ctm = m.multiply(state.ctm)
+			}
+
+		case "Tj", "TJ":
+			e.showText(objectParam(op.Params, 0), &cursor, state)
+
+		case "'":
+			cursor.newLine(0, -state.leading)
+			e.showText(objectParam(op.Params, 0), &cursor, state)
+
+		case "\"":
+			if value, ok := numberParam(op.Params, 0); ok {
+				state.wordSpacing = value
+			}
+			if value, ok := numberParam(op.Params, 1); ok {
+				state.charSpacing = value
+			}
+			cursor.newLine(0, -state.leading)
+			e.showText(objectParam(op.Params, 2), &cursor, state)
+
+		case "Do":
+			name, ok := nameParam(op.Params, 0)
+			if !ok || depth >= maxFormDepth {
+				break
+			}
+
+			xform, err := resources.GetXObjectFormByName(name)
+			if err != nil || xform == nil {
+				break
+			}
+
+			content, err := xform.GetContentStream()
+			if err != nil {
+				break
+			}
+			formOps, err := contentstream.NewContentStreamParser(string(content)).Parse()
+			if err != nil {
+				break
+			}
+
+			// Formulários sem recursos próprios herdam os da página
+			formResources := xform.Resources
+			if formResources == nil {
+				formResources = resources
+			}
+
+			formState := state
+			formState.ctm, _ = formPlacement(xform, state.ctm)
+			e.extract(formOps, formResources, formState, depth+1)
+		}
+	}
+}
+
+// showText posiciona os glifos de uma string (Tj) ou array (TJ), avançando a matriz de texto
+func (e *textExtractor) showText(shown core.PdfObject, cursor *textCursor, state contentState) {
+	font := state.currentFont()
+
+	var elements []core.PdfObject
+	if array, ok := core.GetArray(shown); ok {
+		elements = array.Elements()
+	} else {
+		elements = []core.PdfObject{shown}
+	}
+
+	for _, element := range elements {
+		if data, ok := core.GetStringBytes(element); ok {
+			for _, glyph := range font.glyphs(data) {
+				advance := state.glyphAdvance(glyph)
+
+				if state.fontSize != 0 && state.hScale != 0 {
+					trm := cursor.tm.multiply(state.ctm)
+					ox, oy := trm.transform(0, state.rise)
+					dx, dy := trm.transform(1, state.rise)
+					sx, sy := trm.transform(0, state.rise+state.fontSize)
+					ex, ey := trm.transform(advance, state.rise)
+
+					length := math.Hypot(dx-ox, dy-oy)
+					if length > 0 {
+						e.glyphs = append(e.glyphs, extractedGlyph{
+							text:    font.text(glyph.bytes),
+							quad:    state.glyphQuad(glyph, font, cursor.tm),
+							origin:  [2]float64{ox, oy},
+							dir:     [2]float64{(dx - ox) / length, (dy - oy) / length},
+							size:    math.Hypot(sx-ox, sy-oy),
+							advance: math.Hypot(ex-ox, ey-oy),
+						})
+					}
+				}
+
+				cursor.tm = translateMatrix(advance, 0).multiply(cursor.tm)
+			}
+			continue
+		}
+
+		if value, err := core.GetNumberAsFloat(element); err == nil {
+			cursor.tm = translateMatrix(-value/1000*state.fontSize*state.hScale, 0).multiply(cursor.tm)
+		}
+	}
+}
+
+// layoutText agrupa os glifos, na ordem do content stream, em linhas e palavras
+// Espaços apenas separam palavras; glifos sem mapeamento Unicode são ignorados
+func layoutText(glyphs []extractedGlyph) []extractedLine {
+	var lines []extractedLine
+	var prev *extractedGlyph
+	breakWord := false
+
+	for i := range glyphs {
+		glyph := &glyphs[i]
+		if glyph.text == "" {
+			continue
+		}
+		if strings.TrimSpace(glyph.text) == "" {
+			// Espaços sobrepostos ao glifo anterior (ex.: partes de ligaduras) não separam palavras
+			if prev != nil {
+				if continues, gap := continuesLine(prev, glyph); continues && gap < -wordGapRatio*prev.size {
+					continue
+				}
+			}
+			breakWord = true
+			continue
+		}
+
+		newLine, gap := true, 0.0
+		if prev != nil {
+			var continues bool
+			continues, gap = continuesLine(prev, glyph)
+			newLine = !continues
+		}
+
+		switch {
+		case newLine:
+			lines = append(lines, extractedLine{words: []extractedWord{{}}})
+		case breakWord || gap > wordGapRatio*math.Max(prev.size, glyph.size):
+			line := &lines[len(lines)-1]
+			line.words = append(line.words, extractedWord{})
+		}
+
+		line := &lines[len(lines)-1]
+		word := &line.words[len(line.words)-1]
+		word.glyphs = append(word.glyphs, *glyph)
+
+		prev, breakWord = glyph, false
+	}
+
+	return lines
+}
+
+// continuesLine verifica se o glifo continua a linha do glifo anterior e retorna o espaço entre eles
+func continuesLine(prev, glyph *extractedGlyph) (bool, float64) {
+	// Mudança de direção (texto rotacionado) sempre inicia nova linha
+	if prev.dir[0]*glyph.dir[0]+prev.dir[1]*glyph.dir[1] < 0.99 {
+		return false, 0
+	}
+
+	size := math.Max(prev.size, glyph.size)
+	dx, dy := glyph.origin[0]-prev.origin[0], glyph.origin[1]-prev.origin[1]
+
+	// Deslocamento perpendicular à linha de base
+	if math.Abs(dx*prev.dir[1]-dy*prev.dir[0]) > lineShiftRatio*size {
+		return false, 0
+	}
+
+	// Espaço ao longo da linha de base; retrocessos grandes indicam uma nova linha
+	gap := dx*prev.dir[0] + dy*prev.dir[1] - prev.advance
+	if gap < -size {
+		return false, 0
+	}
+
+	return true, gap
+}
+
+// text retorna o texto da palavra
+func (w extractedWord) text() string {
+	var text strings.Builder
+	for _, glyph := range w.glyphs {
+		text.WriteString(glyph.text)
+	}
+	return text.String()
+}
+
+// bbox retorna a caixa da palavra em espaço do dispositivo
+func (w extractedWord) bbox() bbox {
+	result := emptyBBox
+	for _, glyph := range w.glyphs {
+		box := glyph.quad.bbox()
+		result = result.extend(box.llx, box.lly).extend(box.urx, box.ury)
+	}
+	return result
+}
+
+// toPageText converte as linhas de uma página para o modelo da aplicação
+func toPageText(pageNum int, lines []extractedLine, mediaBox *model.PdfRectangle) appModel.PageText {
+	pageText := appModel.PageText{
+		Page:   pageNum,
+		Width:  mediaBox.Width(),
+		Height: mediaBox.Height(),
+		Lines:  make([]appModel.TextLine, 0, len(lines)),
+	}
+
+	lineTexts := make([]string, 0, len(lines))
+	for _, line := range lines {
+		outLine := appModel.TextLine{Words: make([]appModel.TextWord, 0, len(line.words))}
+		lineBox := emptyBBox
+		wordTexts := make([]string, 0, len(line.words))
+
+		for _, word := range line.words {
+			box := word.bbox()
+			lineBox = lineBox.extend(box.llx, box.lly).extend(box.urx, box.ury)
+
+			text := word.text()
+			wordTexts = append(wordTexts, text)
+			outLine.Words = append(outLine.Words, appModel.TextWord{Text: text, TextBox: toTextBox(box, mediaBox)})
+		}
+
+		outLine.Text = strings.Join(wordTexts, " ")
+		outLine.TextBox = toTextBox(lineBox, mediaBox)
+		pageText.Lines = append(pageText.Lines, outLine)
+		lineTexts = append(lineTexts, outLine.Text)
+	}
+
+	pageText.Text = strings.Join(lineTexts, "\n")
+	return pageText
+}
+
+// toTextBox converte uma caixa em espaço do dispositivo para coordenadas com origem no topo esquerdo
+func toTextBox(box bbox, mediaBox *model.PdfRectangle) appModel.TextBox {
+	return appModel.TextBox{
+		X:      roundPoints(box.llx - mediaBox.Llx),
+		Y:      roundPoints(mediaBox.Ury - box.ury),
+		Width:  roundPoints(box.urx - box.llx),
+		Height: roundPoints(box.ury - box.lly),
+	}
+}
+
+// roundPoints arredonda coordenadas para centésimos de point
+func roundPoints(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,245 @@
+package pdf
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/unidoc/unipdf/v3/model"
+	"go.uber.org/zap"
+	"golang.org/x/text/unicode/norm"
+)
+
+// SearchText busca uma frase (ou expressão regular) nas páginas do PDF, ignorando maiúsculas/minúsculas
+// e acentos. Cada ocorrência traz os quadriláteros do trecho encontrado, um por linha
+func (p *PDFCPUProcessor) SearchText(ctx context.Context, filePath string, search appModel.TextSearch) ([]appModel.SearchHit, error) {
+	pattern, err := searchPattern(search.Query, search.Regex)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, file, numPages, err := openReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	pages := search.Pages
+	if len(pages) == 0 {
+		pages = make([]int, numPages)
+		for i := range pages {
+			pages[i] = i + 1
+		}
+	}
+
+	hits := make([]appModel.SearchHit, 0)
+	for _, pageNum := range pages {
+		if pageNum < 1 || pageNum > numPages {
+			return nil, fmt.Errorf("página inválida: %d (PDF tem %d páginas)", pageNum, numPages)
+		}
+
+		lines, mediaBox, err := readPageLines(reader, pageNum)
+		if err != nil {
+			return nil, err
+		}
+
+		index := newSearchIndex(lines)
+		for _, match := range pattern.FindAllStringIndex(index.text, -1) {
+			hit, ok := index.hit(match[0], match[1], pageNum, mediaBox)
+			if !ok {
+				continue
+			}
+
+			hits = append(hits, hit)
+			if search.Limit > 0 && len(hits) >= search.Limit {
+				return hits, nil
+			}
+		}
+	}
+
+	logger.Logger.Debug("Busca de texto concluída",
+		zap.String("file", filePath),
+		zap.Int("pages_count", len(pages)),
+		zap.Int("hits_count", len(hits)),
+	)
+
+	return hits, nil
+}
+
+// searchPattern compila a consulta para ser aplicada ao texto sem acentos, ignorando maiúsculas/minúsculas
+// Frases toleram qualquer quantidade de espaços (e quebras de linha) entre as palavras
+func searchPattern(query string, isRegex bool) (*regexp.Regexp, error) {
+	query = foldAccents(query)
+
+	if !isRegex {
+		words := strings.Fields(query)
+		if len(words) == 0 {
+			return nil, fmt.Errorf("consulta de busca vazia")
+		}
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		query = strings.Join(words, `\s+`)
+	}
+
+	pattern, err := regexp.Compile("(?i)" + query)
+	if err != nil {
+		return nil, fmt.Errorf("expressão regular inválida: %w", err)
+	}
+
+	return pattern, nil
+}
+
+// foldedLetters são as letras sem decomposição canônica que equivalem a letras latinas básicas
+// (a decomposição não separa o traço de Ł nem a barra de Ø, por exemplo)
+var foldedLetters = map[rune]string{
+	'Ł': "L", 'ł': "l",
+	'Ø': "O", 'ø': "o",
+	'Đ': "D", 'đ': "d",
+	'Ħ': "H", 'ħ': "h",
+	'ı': "i",
+	'ß': "ss", 'ẞ': "SS",
+	'Æ': "AE", 'æ': "ae",
+	'Œ': "OE", 'œ': "oe",
+}
+
+// foldAccents remove os acentos (marcas combinantes após a decomposição canônica) e substitui as letras
+// de foldedLetters; o resultado pode ser mais longo que o texto (ß vira ss)
+func foldAccents(text string) string {
+	var folded strings.Builder
+	for _, r := range norm.NFD.String(text) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if letters, ok := foldedLetters[r]; ok {
+			folded.WriteString(letters)
+			continue
+		}
+		folded.WriteRune(r)
+	}
+	return folded.String()
+}
+
+// glyphRef localiza um glifo nas linhas extraídas (line < 0 indica um separador inserido)
+type glyphRef struct {
+	line, word, glyph int
+}
+
+// searchIndex é o texto pesquisável de uma página, com a origem de cada byte
+// Cada glifo é dobrado isoladamente, então todos os bytes da sua forma dobrada (ß vira ss) apontam para ele
+type searchIndex struct {
+	lines []extractedLine
+	text  string
+	refs  []glyphRef // Um por byte de text
+}
+
+// newSearchIndex monta o texto sem acentos da página, separando palavras e linhas por espaço
+func newSearchIndex(lines []extractedLine) *searchIndex {
+	index := &searchIndex{lines: lines}
+	var text strings.Builder
+	separator := glyphRef{line: -1}
+
+	add := func(value string, ref glyphRef) {
+		text.WriteString(value)
+		for range len(value) {
+			index.refs = append(index.refs, ref)
+		}
+	}
+
+	for li, line := range lines {
+		if li > 0 {
+			add(" ", separator)
+		}
+		for wi, word := range line.words {
+			if wi > 0 {
+				add(" ", separator)
+			}
+			for gi, glyph := range word.glyphs {
+				add(foldAccents(glyph.text), glyphRef{line: li, word: wi, glyph: gi})
+			}
+		}
+	}
+
+	index.text = text.String()
+	return index
+}
+
+// glyph retorna o glifo referenciado
+func (index *searchIndex) glyph(ref glyphRef) extractedGlyph {
+	return index.lines[ref.line].words[ref.word].glyphs[ref.glyph]
+}
+
+// hit converte o trecho [start, end) do texto em uma ocorrência com um quadrilátero por linha
+func (index *searchIndex) hit(start, end, pageNum int, mediaBox *model.PdfRectangle) (appModel.SearchHit, bool) {
+	type segment struct{ first, last glyphRef }
+
+	var (
+		segments     []segment
+		text         strings.Builder
+		prev         = glyphRef{line: -1}
+		pendingSpace bool
+	)
+
+	for _, ref := range index.refs[start:end] {
+		if ref.line < 0 {
+			pendingSpace = text.Len() > 0
+			continue
+		}
+		if ref == prev {
+			continue
+		}
+
+		if pendingSpace {
+			text.WriteByte(' ')
+			pendingSpace = false
+		}
+		text.WriteString(index.glyph(ref).text)
+		prev = ref
+
+		if len(segments) == 0 || segments[len(segments)-1].first.line != ref.line {
+			segments = append(segments, segment{first: ref, last: ref})
+		} else {
+			segments[len(segments)-1].last = ref
+		}
+	}
+
+	if len(segments) == 0 {
+		return appModel.SearchHit{}, false
+	}
+
+	hit := appModel.SearchHit{
+		Page:  pageNum,
+		Text:  text.String(),
+		Quads: make([]appModel.Quad, 0, len(segments)),
+		Rects: make([]appModel.TextBox, 0, len(segments)),
+	}
+
+	for _, seg := range segments {
+		first, last := index.glyph(seg.first), index.glyph(seg.last)
+
+		// Lado inicial do primeiro glifo e lado final do último, preservando a rotação do texto
+		q := quad{first.quad[0], last.quad[1], last.quad[2], first.quad[3]}
+
+		hit.Quads = append(hit.Quads, appModel.Quad{
+			toPoint(q[3], mediaBox),
+			toPoint(q[2], mediaBox),
+			toPoint(q[1], mediaBox),
+			toPoint(q[0], mediaBox),
+		})
+		hit.Rects = append(hit.Rects, toTextBox(q.bbox(), mediaBox))
+	}
+
+	return hit, true
+}
+
+// toPoint converte um ponto em espaço do dispositivo para coordenadas com origem no topo esquerdo
+func toPoint(point [2]float64, mediaBox *model.PdfRectangle) appModel.Point {
+	return appModel.Point{
+		X: roundPoints(point[0] - mediaBox.Llx),
+		Y: roundPoints(mediaBox.Ury - point[1]),
+	}
+}
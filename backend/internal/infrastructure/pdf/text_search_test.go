@@ -0,0 +1,112 @@
+package pdf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/unidoc/unipdf/v3/model"
+	"go.uber.org/zap"
+)
+
+// searchLine monta uma linha extraída com glifos de 10 points de largura, um por caractere
+func searchLine(text string) []extractedLine {
+	var line extractedLine
+	x := 0.0
+	for _, word := range strings.Fields(text) {
+		var extracted extractedWord
+		for _, r := range word {
+			extracted.glyphs = append(extracted.glyphs, extractedGlyph{
+				text: string(r),
+				quad: quad{{x, 0}, {x + 10, 0}, {x + 10, 10}, {x, 10}},
+			})
+			x += 10
+		}
+		line.words = append(line.words, extracted)
+		x += 10 // Espaço entre as palavras
+	}
+	return []extractedLine{line}
+}
+
+func TestSearchTextFoldsLetters(t *testing.T) {
+	mediaBox := &model.PdfRectangle{Llx: 0, Lly: 0, Urx: 600, Ury: 100}
+
+	tests := []struct {
+		name      string
+		pageText  string
+		query     string
+		regex     bool
+		wantText  string
+		wantX     float64
+		wantWidth float64
+	}{
+		{"ł literal", "Sr. Łukasz Żółć", "lukasz", false, "Łukasz", 40, 60},
+		{"ł na consulta", "Sr. Lukasz Zolc", "Łukasz", false, "Lukasz", 40, 60},
+		{"ł regex", "Sr. Łukasz Żółć", `luk\w+\s+zolc`, true, "Łukasz Żółć", 40, 110},
+		{"ß literal", "Die Straße nach", "strasse", false, "Straße", 40, 60},
+		{"ß na consulta", "Die Strasse nach", "Straße", false, "Strasse", 40, 70},
+		{"ß parcial", "Die Straße nach", "stras", false, "Straß", 40, 50},
+		{"ß regex", "Die Straße nach", `stras+e\s+nach`, true, "Straße nach", 40, 110},
+		{"ø literal", "Ponte de Øresund", "oresund", false, "Øresund", 90, 70},
+		{"ø regex", "Ponte de Øresund", `de\s+o\w+`, true, "de Øresund", 60, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := searchPattern(tt.query, tt.regex)
+			if err != nil {
+				t.Fatalf("searchPattern(%q): %v", tt.query, err)
+			}
+
+			index := newSearchIndex(searchLine(tt.pageText))
+			matches := pattern.FindAllStringIndex(index.text, -1)
+			if len(matches) != 1 {
+				t.Fatalf("%d ocorrências de %q em %q, esperada 1", len(matches), tt.query, tt.pageText)
+			}
+
+			hit, ok := index.hit(matches[0][0], matches[0][1], 1, mediaBox)
+			if !ok {
+				t.Fatalf("ocorrência sem glifos")
+			}
+			if hit.Text != tt.wantText {
+				t.Errorf("texto %q, esperado %q", hit.Text, tt.wantText)
+			}
+			if len(hit.Rects) != 1 || hit.Rects[0].X != tt.wantX || hit.Rects[0].Width != tt.wantWidth {
+				t.Errorf("retângulos %+v, esperado X=%v e largura %v", hit.Rects, tt.wantX, tt.wantWidth)
+			}
+		})
+	}
+}
+
+func TestSearchTextFindsFoldedLettersInPDF(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	processor, err := NewPDFCPUProcessor("")
+	if err != nil {
+		t.Fatalf("erro ao criar processador: %v", err)
+	}
+	p := processor.(*PDFCPUProcessor)
+
+	filePath := newBlankPDF(t, 1)
+	watermark := appModel.Watermark{Text: "Łukasz Straße Øresund", Position: appModel.WatermarkCenter, Opacity: 1}
+	if err := p.AddWatermark(context.Background(), filePath, nil, watermark); err != nil {
+		t.Fatalf("AddWatermark: %v", err)
+	}
+
+	for _, search := range []appModel.TextSearch{
+		{Query: "lukasz"},
+		{Query: "strasse"},
+		{Query: "oresund"},
+		{Query: `lukasz\s+stras+e`, Regex: true},
+	} {
+		hits, err := p.SearchText(context.Background(), filePath, search)
+		if err != nil {
+			t.Fatalf("SearchText(%q): %v", search.Query, err)
+		}
+		if len(hits) != 1 {
+			t.Errorf("%d ocorrências de %q, esperada 1", len(hits), search.Query)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strings"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/font"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+	pdffont "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/font"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+)
+
+// textStampStyle define a fonte, a cor e o alinhamento das linhas de um carimbo de texto
+type textStampStyle struct {
+	fontFamily string  // helvetica, times ou courier
+	fontWeight string  // normal ou bold
+	fontSize   float64 // Em PDF points
+	color      string  // Hex (#RRGGBB), vazio = preto
+	align      appModel.TextAlign
+}
+
+// stampRun é um trecho de uma linha do carimbo desenhado com uma única fonte
+type stampRun struct {
+	resName string // Nome da fonte nos recursos da página
+	text    string // Já codificado e escapado para o content stream
+}
+
+// stampLayout são as linhas do carimbo com as métricas usadas no posicionamento
+type stampLayout struct {
+	lines   [][]stampRun
+	widths  []float64
+	ascent  float64
+	descent float64
+	leading float64
+	fonts   types.Dict
+}
+
+// textStampPDF desenha o texto em um PDF de uma página do tamanho exato do bloco de texto, aplicado
+// depois como carimbo PDF do pdfcpu (que cuida de posição, escala, rotação e opacidade)
+// O carimbo de texto do pdfcpu não é usado: ele substitui %p, %P, %t e %v no texto e descarta
+// caracteres fora do Latin-1. Textos representáveis em WinAnsi usam a fonte padrão escolhida;
+// os demais, a fonte Unicode do servidor no mesmo peso, com as fontes adicionais como fallback
+func (p *PDFCPUProcessor) textStampPDF(text string, style textStampStyle) ([]byte, error) {
+	selected, err := resolveFieldFont(style.fontFamily, style.fontWeight)
+	if err != nil {
+		return nil, err
+	}
+	if style.fontSize <= 0 {
+		return nil, fmt.Errorf("tamanho de fonte inválido: %.1f", style.fontSize)
+	}
+
+	color := rgbColor{}
+	if style.color != "" {
+		if color, err = parseHexColor(style.color); err != nil {
+			return nil, err
+		}
+	}
+
+	pdfCtx, err := pdfcpu.CreateContextWithXRefTable(nil, &types.Dim{Width: 1, Height: 1})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar carimbo: %w", err)
+	}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	var layout *stampLayout
+	if encoded, encodeErr := encodeWinAnsi(text); encodeErr == nil {
+		layout, err = standardStampLayout(pdfCtx, strings.Split(encoded, "\n"), selected, style.fontSize)
+	} else {
+		layout, err = p.fonts.unicodeStampLayout(pdfCtx, strings.Split(text, "\n"), style)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	width := 0.0
+	for _, lineWidth := range layout.widths {
+		width = math.Max(width, lineWidth)
+	}
+	// Apenas linhas vazias: não há o que desenhar
+	if width <= 0 {
+		return nil, fmt.Errorf("texto do carimbo não pode ser vazio")
+	}
+	height := layout.ascent + layout.descent + float64(len(layout.lines)-1)*layout.leading
+
+	// O modo de renderização é explícito: o carimbo não deve herdar o estado de quem o desenha
+	var content strings.Builder
+	fmt.Fprintf(&content, "q\n%s %s %s rg\nBT\n0 Tr\n", formatNumber(color.R), formatNumber(color.G), formatNumber(color.B))
+	// Td é relativo ao início da linha anterior
+	lineX, lineY := 0.0, 0.0
+	for i, line := range layout.lines {
+		x := alignOffset(style.align, layout.widths[i], width)
+		y := height - layout.ascent - float64(i)*layout.leading
+		fmt.Fprintf(&content, "%s %s Td\n", formatNumber(x-lineX), formatNumber(y-lineY))
+		lineX, lineY = x, y
+		for _, run := range line {
+			fmt.Fprintf(&content, "/%s %s Tf\n(%s) Tj\n", run.resName, formatNumber(style.fontSize), run.text)
+		}
+	}
+	content.WriteString("ET\nQ\n")
+
+	if err := addStampPage(pdfCtx, []byte(content.String()), width, height, layout.fonts); err != nil {
+		return nil, fmt.Errorf("erro ao gerar carimbo: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := api.WriteContext(pdfCtx, &buf); err != nil {
+		return nil, fmt.Errorf("erro ao gerar carimbo: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// standardStampLayout prepara as linhas (já codificadas em WinAnsi) com uma fonte padrão do PDF, sem embutir
+// A altura das linhas segue a caixa da fonte, como nos carimbos de texto do pdfcpu
+func standardStampLayout(pdfCtx *pdfcpuModel.Context, lines []string, selected fieldFont, fontSize float64) (*stampLayout, error) {
+	fontRef, err := pdfCtx.IndRefForNewObject(types.Dict{
+		"Type":     types.Name("Font"),
+		"Subtype":  types.Name("Type1"),
+		"BaseFont": types.Name(selected.baseFont),
+		"Encoding": types.Name("WinAnsiEncoding"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	layout := &stampLayout{
+		ascent:  font.Ascent(selected.baseFont, 1000) / 1000 * fontSize,
+		descent: font.Descent(selected.baseFont, 1000) / 1000 * fontSize,
+		leading: font.LineHeight(selected.baseFont, 1000) / 1000 * fontSize,
+		fonts:   types.Dict{selected.resName: *fontRef},
+	}
+	for _, line := range lines {
+		layout.lines = append(layout.lines, []stampRun{{resName: selected.resName, text: escapePDFString(line)}})
+		layout.widths = append(layout.widths, font.TextWidth(line, selected.baseFont, 1000)/1000*fontSize)
+	}
+	return layout, nil
+}
+
+// unicodeStampLayout prepara as linhas com as fontes do servidor instaladas no pdfcpu, que as embute
+// como fontes compostas com subset dos glifos usados
+func (r *fontRegistry) unicodeStampLayout(pdfCtx *pdfcpuModel.Context, lines []string, style textStampStyle) (*stampLayout, error) {
+	primary, err := r.face(DefaultFontFamily, style.fontWeight)
+	if err != nil {
+		return nil, err
+	}
+
+	layout := &stampLayout{
+		ascent:  primary.ascent() * style.fontSize,
+		descent: primary.descent() * style.fontSize,
+		leading: defaultLineHeight * style.fontSize,
+		fonts:   types.Dict{},
+	}
+
+	resNames := make(map[*fontFace]string)
+	for _, line := range lines {
+		runs, err := r.splitRuns(line, DefaultFontFamily, style.fontWeight)
+		if err != nil {
+			return nil, err
+		}
+
+		var stampRuns []stampRun
+		for _, run := range runs {
+			if run.face.userFont == "" {
+				return nil, fmt.Errorf("texto contém caracteres não suportados pelas fontes padrão do PDF e a fonte %s não está disponível", run.face.family)
+			}
+			resName, ok := resNames[run.face]
+			if !ok {
+				resName = fmt.Sprintf("F%d", len(resNames)+1)
+				resNames[run.face] = resName
+			}
+			// Códigos de glifo (Identity-H); o pdfcpu registra os glifos usados para o subset
+			encoded := pdfcpuModel.PrepBytes(pdfCtx.XRefTable, run.text, run.face.userFont, true, false, false)
+			stampRuns = append(stampRuns, stampRun{resName: resName, text: encoded})
+		}
+
+		layout.lines = append(layout.lines, stampRuns)
+		layout.widths = append(layout.widths, r.measure(line, primary)*style.fontSize)
+	}
+
+	// As fontes são criadas depois do texto para que o subset contenha todos os glifos
+	for face, resName := range resNames {
+		fontRef, err := pdffont.EnsureFontDict(pdfCtx.XRefTable, face.userFont, "", "", false, nil)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao embutir fonte %s: %w", face.family, err)
+		}
+		layout.fonts[resName] = *fontRef
+	}
+
+	return layout, nil
+}
+
+// addStampPage cria a única página do carimbo com o conteúdo e as fontes informadas
+func addStampPage(pdfCtx *pdfcpuModel.Context, content []byte, width, height float64, fonts types.Dict) error {
+	rootDict, err := pdfCtx.Catalog()
+	if err != nil {
+		return err
+	}
+	pagesRef := rootDict.IndirectRefEntry("Pages")
+	if pagesRef == nil {
+		return fmt.Errorf("árvore de páginas ausente")
+	}
+	pagesDict, err := pdfCtx.DereferenceDict(*pagesRef)
+	if err != nil {
+		return err
+	}
+
+	streamDict, err := pdfCtx.NewStreamDictForBuf(content)
+	if err != nil {
+		return err
+	}
+	if err := streamDict.Encode(); err != nil {
+		return err
+	}
+	contentRef, err := pdfCtx.IndRefForNewObject(*streamDict)
+	if err != nil {
+		return err
+	}
+
+	mediaBox := types.NewNumberArray(0, 0, width, height)
+	pageRef, err := pdfCtx.IndRefForNewObject(types.Dict{
+		"Type":      types.Name("Page"),
+		"Parent":    *pagesRef,
+		"MediaBox":  mediaBox,
+		"Resources": types.Dict{"Font": fonts},
+		"Contents":  *contentRef,
+	})
+	if err != nil {
+		return err
+	}
+
+	pagesDict.Update("MediaBox", mediaBox)
+	pagesDict.Update("Kids", types.Array{*pageRef})
+	pagesDict.Update("Count", types.Integer(1))
+	pdfCtx.PageCount = 1
+	return nil
+}
@@ -0,0 +1,147 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	pdfcpuModel "github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
+	"go.uber.org/zap"
+)
+
+const (
+	// Distância das bordas da página para as marcas d'água posicionadas nos cantos, em PDF points
+	watermarkCornerMargin = 18.0
+
+	// Tamanho de fonte do carimbo de texto; o tamanho final segue a escala relativa à página
+	watermarkFontSize = 24.0
+
+	// Cor padrão das marcas d'água de texto
+	watermarkDefaultColor = "#808080"
+)
+
+// watermarkAnchors mapeia as posições para as âncoras do pdfcpu, o deslocamento a partir da borda
+// e o alinhamento das linhas de texto (o mesmo que o pdfcpu deduz da âncora)
+var watermarkAnchors = map[appModel.WatermarkPosition]struct {
+	anchor types.Anchor
+	dx, dy float64
+	align  appModel.TextAlign
+}{
+	appModel.WatermarkCenter:      {types.Center, 0, 0, appModel.TextAlignCenter},
+	appModel.WatermarkDiagonal:    {types.Center, 0, 0, appModel.TextAlignCenter},
+	appModel.WatermarkTopLeft:     {types.TopLeft, watermarkCornerMargin, -watermarkCornerMargin, appModel.TextAlignLeft},
+	appModel.WatermarkTopRight:    {types.TopRight, -watermarkCornerMargin, -watermarkCornerMargin, appModel.TextAlignRight},
+	appModel.WatermarkBottomLeft:  {types.BottomLeft, watermarkCornerMargin, watermarkCornerMargin, appModel.TextAlignLeft},
+	appModel.WatermarkBottomRight: {types.BottomRight, -watermarkCornerMargin, watermarkCornerMargin, appModel.TextAlignRight},
+}
+
+// AddWatermark aplica uma marca d'água de texto ou imagem às páginas informadas (todas quando vazio)
+// usando o mecanismo de marcas d'água do pdfcpu (XObject de formulário compartilhado entre as páginas)
+// O texto é desenhado como carimbo PDF (ver textStampPDF), preservando % e caracteres fora do WinAnsi
+func (p *PDFCPUProcessor) AddWatermark(ctx context.Context, filePath string, pages []int, watermark appModel.Watermark) error {
+	for _, pageNum := range pages {
+		if _, err := validatePageNumber(filePath, pageNum); err != nil {
+			return err
+		}
+	}
+
+	wm, err := p.buildWatermark(watermark)
+	if err != nil {
+		return err
+	}
+
+	var selection []string
+	for _, pageNum := range pages {
+		selection = append(selection, strconv.Itoa(pageNum))
+	}
+
+	config := pdfcpuModel.NewDefaultConfiguration()
+	if err := api.AddWatermarksFile(filePath, "", selection, wm, config); err != nil {
+		return fmt.Errorf("erro ao aplicar marca d'água: %w", err)
+	}
+
+	logger.Logger.Debug("Marca d'água aplicada",
+		zap.String("file", filePath),
+		zap.Int("pages_count", len(pages)),
+		zap.Bool("image", watermark.ImagePath != ""),
+		zap.String("position", string(watermark.Position)),
+		zap.Bool("background", watermark.Background),
+	)
+
+	return nil
+}
+
+// buildWatermark converte as opções para a configuração de marca d'água do pdfcpu
+func (p *PDFCPUProcessor) buildWatermark(watermark appModel.Watermark) (*pdfcpuModel.Watermark, error) {
+	position := watermark.Position
+	if position == "" {
+		position = appModel.WatermarkCenter
+	}
+	anchor, ok := watermarkAnchors[position]
+	if !ok {
+		return nil, fmt.Errorf("posição de marca d'água inválida: %s", position)
+	}
+
+	onTop := !watermark.Background
+
+	var wm *pdfcpuModel.Watermark
+	var err error
+	if watermark.ImagePath != "" {
+		wm, err = api.ImageWatermark(watermark.ImagePath, "", onTop, false, types.POINTS)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao carregar imagem da marca d'água: %w", err)
+		}
+	} else {
+		if watermark.Text == "" {
+			return nil, fmt.Errorf("texto da marca d'água não pode ser vazio")
+		}
+
+		textColor := watermark.Color
+		if textColor == "" {
+			textColor = watermarkDefaultColor
+		}
+
+		stamp, err := p.textStampPDF(watermark.Text, textStampStyle{
+			fontFamily: watermark.FontFamily,
+			fontWeight: watermark.FontWeight,
+			fontSize:   watermarkFontSize,
+			color:      textColor,
+			align:      anchor.align,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		wm, err = api.PDFWatermarkForReadSeeker(bytes.NewReader(stamp), 1, "", onTop, false, types.POINTS)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao preparar texto da marca d'água: %w", err)
+		}
+	}
+
+	wm.Pos = anchor.anchor
+	wm.Dx = anchor.dx
+	wm.Dy = anchor.dy
+
+	// Sem rotação explícita a marca é horizontal, exceto na posição diagonal
+	wm.UserRotOrDiagonal = true
+	wm.Diagonal = pdfcpuModel.NoDiagonal
+	switch {
+	case watermark.Rotation != nil:
+		wm.Rotation = *watermark.Rotation
+	case position == appModel.WatermarkDiagonal:
+		wm.Diagonal = pdfcpuModel.DiagonalLLToUR
+	}
+
+	wm.Opacity = watermark.Opacity
+	if watermark.Scale > 0 {
+		wm.Scale = watermark.Scale
+	}
+	wm.ScaleAbs = false
+
+	return wm, nil
+}
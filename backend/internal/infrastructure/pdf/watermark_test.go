@@ -0,0 +1,38 @@
+package pdf
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appModel "github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+func TestAddWatermarkKeepsLiteralText(t *testing.T) {
+	logger.Logger = zap.NewNop()
+
+	processor, err := NewPDFCPUProcessor("")
+	if err != nil {
+		t.Fatalf("erro ao criar processador: %v", err)
+	}
+	p := processor.(*PDFCPUProcessor)
+
+	for _, text := range []string{"Desconto 50%p", "Łódź %P"} {
+		filePath := newBlankPDF(t, 1)
+		watermark := appModel.Watermark{Text: text, Position: appModel.WatermarkDiagonal, Opacity: 0.5}
+
+		if err := p.AddWatermark(context.Background(), filePath, nil, watermark); err != nil {
+			t.Fatalf("AddWatermark(%q): %v", text, err)
+		}
+
+		texts, err := p.ExtractText(context.Background(), filePath, nil)
+		if err != nil {
+			t.Fatalf("ExtractText: %v", err)
+		}
+		if len(texts) != 1 || !strings.Contains(texts[0].Text, text) {
+			t.Errorf("texto %q não encontrado em %+v", text, texts)
+		}
+	}
+}
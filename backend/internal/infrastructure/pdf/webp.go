@@ -0,0 +1,644 @@
+package pdf
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"sort"
+)
+
+// Codificador WebP sem perdas (VP8L, RFC 9649). Usa as transformações de subtração do verde e de predição
+// por blocos, referências LZ77 e códigos de prefixo (Huffman) canônicos, sem cache de cores
+
+const (
+	vp8lSignature     = 0x2f
+	vp8lMaxDimension  = 1 << 14
+	vp8lPredictorBits = 4 // Blocos de predição de 16x16 pixels
+
+	vp8lTransformPredictor     = 0
+	vp8lTransformSubtractGreen = 2
+
+	vp8lLiteralCodes  = 256
+	vp8lLengthCodes   = 24
+	vp8lDistanceCodes = 40
+	vp8lMaxLength     = 4096
+	vp8lMaxCodeLength = 15
+
+	// Distâncias de 1 a 120 são códigos de vizinhança 2D; as demais são a distância + 120
+	vp8lPlaneCodes = 120
+
+	// Busca de repetições: janela, tamanho mínimo e tentativas por posição
+	vp8lWindowBits = 16
+	vp8lMinMatch   = 3
+	vp8lMaxChain   = 16
+	vp8lHashBits   = 16
+)
+
+// vp8lCodeLengthOrder é a ordem em que os comprimentos do código de comprimentos são gravados
+var vp8lCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// vp8lPredictorModes são os modos de predição avaliados em cada bloco (os que não usam o pixel acima à direita)
+var vp8lPredictorModes = [...]int{1, 2, 7, 11, 12}
+
+// encodeWebP grava a imagem em WebP sem perdas
+func encodeWebP(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width < 1 || height < 1 || width > vp8lMaxDimension || height > vp8lMaxDimension {
+		return errors.New("dimensões inválidas para WebP")
+	}
+
+	argb, hasAlpha := argbPixels(img)
+
+	bw := &vp8lBitWriter{}
+	bw.write(vp8lSignature, 8)
+	bw.write(uint32(width-1), 14)
+	bw.write(uint32(height-1), 14)
+	if hasAlpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3) // Versão
+
+	// Transformações, na ordem em que são aplicadas (o decodificador as desfaz na ordem inversa)
+	subtractGreen(argb)
+	bw.write(1, 1)
+	bw.write(vp8lTransformSubtractGreen, 2)
+
+	modes, tilesX := predictResiduals(argb, width, height)
+	bw.write(1, 1)
+	bw.write(vp8lTransformPredictor, 2)
+	bw.write(vp8lPredictorBits-2, 3)
+	writeEntropyImage(bw, modes, tilesX, false)
+	bw.write(0, 1) // Fim das transformações
+
+	writeEntropyImage(bw, argb, width, true)
+	data := bw.bytes()
+
+	// Contêiner RIFF com um único chunk VP8L (tamanho par)
+	var out bytes.Buffer
+	padding := len(data) % 2
+	out.WriteString("RIFF")
+	binary.Write(&out, binary.LittleEndian, uint32(4+8+len(data)+padding))
+	out.WriteString("WEBPVP8L")
+	binary.Write(&out, binary.LittleEndian, uint32(len(data)))
+	out.Write(data)
+	if padding != 0 {
+		out.WriteByte(0)
+	}
+
+	_, err := w.Write(out.Bytes())
+	return err
+}
+
+// argbPixels converte a imagem em pixels ARGB não pré-multiplicados, indicando se há transparência
+func argbPixels(img image.Image) ([]uint32, bool) {
+	bounds := img.Bounds()
+	pixels := make([]uint32, 0, bounds.Dx()*bounds.Dy())
+	hasAlpha := false
+
+	switch src := img.(type) {
+	case *image.Gray:
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			row := src.Pix[src.PixOffset(bounds.Min.X, y):]
+			for x := 0; x < bounds.Dx(); x++ {
+				v := uint32(row[x])
+				pixels = append(pixels, 0xff000000|v<<16|v<<8|v)
+			}
+		}
+	default:
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+				if c.A != 0xff {
+					hasAlpha = true
+				}
+				pixels = append(pixels, uint32(c.A)<<24|uint32(c.R)<<16|uint32(c.G)<<8|uint32(c.B))
+			}
+		}
+	}
+	return pixels, hasAlpha
+}
+
+// subtractGreen subtrai o verde dos canais vermelho e azul
+func subtractGreen(pixels []uint32) {
+	for i, p := range pixels {
+		green := (p >> 8) & 0xff
+		red := ((p >> 16) - green) & 0xff
+		blue := (p - green) & 0xff
+		pixels[i] = p&0xff00ff00 | red<<16 | blue
+	}
+}
+
+// predictResiduals substitui cada pixel pela diferença para a sua predição, escolhendo em cada bloco o modo com
+// os menores resíduos. Retorna a imagem de modos (um pixel por bloco) e a sua largura
+func predictResiduals(pixels []uint32, width, height int) ([]uint32, int) {
+	tileSize := 1 << vp8lPredictorBits
+	tilesX := (width + tileSize - 1) / tileSize
+	tilesY := (height + tileSize - 1) / tileSize
+	modes := make([]uint32, tilesX*tilesY)
+
+	// As predições usam os pixels originais: escolhe os modos antes de alterar a imagem
+	for ty := 0; ty < tilesY; ty++ {
+		for tx := 0; tx < tilesX; tx++ {
+			bestMode, bestCost := vp8lPredictorModes[0], -1
+			for _, mode := range vp8lPredictorModes {
+				cost := 0
+				for y := ty * tileSize; y < min((ty+1)*tileSize, height); y++ {
+					for x := tx * tileSize; x < min((tx+1)*tileSize, width); x++ {
+						i := y*width + x
+						cost += residualCost(subPixels(pixels[i], predict(pixels, width, x, y, mode)))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					bestMode, bestCost = mode, cost
+				}
+			}
+			modes[ty*tilesX+tx] = 0xff000000 | uint32(bestMode)<<8
+		}
+	}
+
+	residuals := make([]uint32, len(pixels))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			mode := int(modes[(y>>vp8lPredictorBits)*tilesX+x>>vp8lPredictorBits]>>8) & 0x0f
+			i := y*width + x
+			residuals[i] = subPixels(pixels[i], predict(pixels, width, x, y, mode))
+		}
+	}
+	copy(pixels, residuals)
+
+	return modes, tilesX
+}
+
+// predict calcula a predição de um pixel; a primeira linha usa o pixel à esquerda e a primeira coluna, o de cima
+func predict(pixels []uint32, width, x, y, mode int) uint32 {
+	i := y*width + x
+	switch {
+	case x == 0 && y == 0:
+		return 0xff000000
+	case y == 0:
+		return pixels[i-1]
+	case x == 0:
+		return pixels[i-width]
+	}
+
+	left, top, topLeft := pixels[i-1], pixels[i-width], pixels[i-width-1]
+	switch mode {
+	case 1:
+		return left
+	case 2:
+		return top
+	case 7:
+		// Average2(L, T)
+		return mapChannels(func(index int) uint32 {
+			return (channel(left, index) + channel(top, index)) / 2
+		})
+	case 11:
+		// Select: o vizinho mais próximo do gradiente L + T - TL
+		if channelDistance(topLeft, top) < channelDistance(topLeft, left) {
+			return left
+		}
+		return top
+	case 12:
+		// ClampAddSubtractFull(L, T, TL)
+		return mapChannels(func(index int) uint32 {
+			v := int(channel(left, index)) + int(channel(top, index)) - int(channel(topLeft, index))
+			return uint32(min(max(v, 0), 255))
+		})
+	}
+	return 0xff000000
+}
+
+// channel retorna o canal do pixel ARGB pelo deslocamento em bytes (0 = azul, 3 = alfa)
+func channel(p uint32, index int) uint32 {
+	return (p >> (8 * index)) & 0xff
+}
+
+// mapChannels monta um pixel calculando cada canal
+func mapChannels(fn func(index int) uint32) uint32 {
+	var out uint32
+	for index := 0; index < 4; index++ {
+		out |= (fn(index) & 0xff) << (8 * index)
+	}
+	return out
+}
+
+// channelDistance soma as diferenças absolutas entre os canais de dois pixels
+func channelDistance(a, b uint32) int {
+	distance := 0
+	for index := 0; index < 4; index++ {
+		d := int(channel(a, index)) - int(channel(b, index))
+		distance += max(d, -d)
+	}
+	return distance
+}
+
+// subPixels subtrai dois pixels canal a canal (módulo 256)
+func subPixels(a, b uint32) uint32 {
+	var out uint32
+	for index := 0; index < 4; index++ {
+		out |= ((channel(a, index) - channel(b, index)) & 0xff) << (8 * index)
+	}
+	return out
+}
+
+// residualCost estima o custo de um resíduo pela magnitude dos canais com sinal
+func residualCost(residual uint32) int {
+	cost := 0
+	for index := 0; index < 4; index++ {
+		v := int(int8(channel(residual, index)))
+		cost += max(v, -v)
+	}
+	return cost
+}
+
+// vp8lToken é um pixel literal (distance == 0) ou uma referência de value pixels a distance pixels
+type vp8lToken struct {
+	value    uint32
+	distance uint32
+}
+
+// findMatches divide os pixels em literais e referências LZ77 (busca gulosa em cadeias de hash, testando antes o
+// pixel à esquerda e o de cima)
+func findMatches(pixels []uint32, width int) []vp8lToken {
+	const window = 1 << vp8lWindowBits
+	head := make([]int32, 1<<vp8lHashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	prev := make([]int32, window)
+
+	hash := func(i int) uint32 {
+		return (pixels[i]*0x9e3779b1 ^ pixels[i+1]*0x85ebca6b) >> (32 - vp8lHashBits)
+	}
+	insert := func(i int) {
+		if i+1 < len(pixels) {
+			h := hash(i)
+			prev[i&(window-1)] = head[h]
+			head[h] = int32(i)
+		}
+	}
+	matchLength := func(i, candidate int) int {
+		limit := min(len(pixels)-i, vp8lMaxLength)
+		n := 0
+		for n < limit && pixels[candidate+n] == pixels[i+n] {
+			n++
+		}
+		return n
+	}
+
+	var tokens []vp8lToken
+	for i := 0; i < len(pixels); {
+		bestLength, bestDistance := 0, 0
+		for _, distance := range [2]int{1, width} {
+			if distance <= i {
+				if n := matchLength(i, i-distance); n > bestLength {
+					bestLength, bestDistance = n, distance
+				}
+			}
+		}
+		if i+1 < len(pixels) && bestLength < vp8lMaxLength {
+			candidate := int(head[hash(i)])
+			for chain := 0; chain < vp8lMaxChain && candidate >= 0 && i-candidate < window; chain++ {
+				if n := matchLength(i, candidate); n > bestLength {
+					bestLength, bestDistance = n, i-candidate
+				}
+				next := int(prev[candidate&(window-1)])
+				if next >= candidate {
+					break
+				}
+				candidate = next
+			}
+		}
+
+		if bestLength < vp8lMinMatch {
+			tokens = append(tokens, vp8lToken{value: pixels[i]})
+			insert(i)
+			i++
+			continue
+		}
+
+		tokens = append(tokens, vp8lToken{value: uint32(bestLength), distance: uint32(bestDistance)})
+		for end := i + bestLength; i < end; i++ {
+			insert(i)
+		}
+	}
+	return tokens
+}
+
+// distanceCode converte a distância em pixels no código gravado: vizinho de cima e da esquerda têm códigos curtos
+func distanceCode(distance, width int) int {
+	switch distance {
+	case width:
+		return 1
+	case 1:
+		return 2
+	}
+	return distance + vp8lPlaneCodes
+}
+
+// prefixEncode divide um valor (>= 1) em código de prefixo, quantidade de bits extras e bits extras
+func prefixEncode(value int) (int, int, int) {
+	d := value - 1
+	if d < 4 {
+		return d, 0, 0
+	}
+	high := 31
+	for d>>high == 0 {
+		high--
+	}
+	second := (d >> (high - 1)) & 1
+	extraBits := high - 1
+	return 2*high + second, extraBits, d & (1<<extraBits - 1)
+}
+
+// writeEntropyImage grava uma imagem com códigos de prefixo: a principal (topLevel) ou a de uma transformação
+func writeEntropyImage(bw *vp8lBitWriter, pixels []uint32, width int, topLevel bool) {
+	tokens := findMatches(pixels, width)
+
+	// Histogramas dos cinco alfabetos: verde + comprimentos, vermelho, azul, alfa e distâncias
+	green := make([]int, vp8lLiteralCodes+vp8lLengthCodes)
+	red := make([]int, vp8lLiteralCodes)
+	blue := make([]int, vp8lLiteralCodes)
+	alpha := make([]int, vp8lLiteralCodes)
+	distance := make([]int, vp8lDistanceCodes)
+	for _, token := range tokens {
+		if token.distance == 0 {
+			green[channel(token.value, 1)]++
+			red[channel(token.value, 2)]++
+			blue[channel(token.value, 0)]++
+			alpha[channel(token.value, 3)]++
+			continue
+		}
+		lengthCode, _, _ := prefixEncode(int(token.value))
+		green[vp8lLiteralCodes+lengthCode]++
+		distCode, _, _ := prefixEncode(distanceCode(int(token.distance), width))
+		distance[distCode]++
+	}
+
+	bw.write(0, 1) // Sem cache de cores
+	if topLevel {
+		bw.write(0, 1) // Um único grupo de códigos para a imagem inteira
+	}
+
+	codes := [5]*huffmanCode{}
+	for i, histogram := range [][]int{green, red, blue, alpha, distance} {
+		codes[i] = newHuffmanCode(histogram, vp8lMaxCodeLength)
+		writeHuffmanCode(bw, codes[i])
+	}
+
+	for _, token := range tokens {
+		if token.distance == 0 {
+			codes[0].writeSymbol(bw, int(channel(token.value, 1)))
+			codes[1].writeSymbol(bw, int(channel(token.value, 2)))
+			codes[2].writeSymbol(bw, int(channel(token.value, 0)))
+			codes[3].writeSymbol(bw, int(channel(token.value, 3)))
+			continue
+		}
+		lengthCode, extraBits, extra := prefixEncode(int(token.value))
+		codes[0].writeSymbol(bw, vp8lLiteralCodes+lengthCode)
+		bw.write(uint32(extra), extraBits)
+
+		distCode, extraBits, extra := prefixEncode(distanceCode(int(token.distance), width))
+		codes[4].writeSymbol(bw, distCode)
+		bw.write(uint32(extra), extraBits)
+	}
+}
+
+// huffmanCode é um código de prefixo canônico; com um único símbolo, os símbolos não ocupam bits
+type huffmanCode struct {
+	lengths []uint8  // Comprimentos gravados no cabeçalho do código
+	codes   []uint32 // Códigos com os bits invertidos (o fluxo VP8L é lido a partir do bit menos significativo)
+	single  bool
+}
+
+// newHuffmanCode cria o código de prefixo para as frequências, limitando o comprimento dos códigos
+func newHuffmanCode(freqs []int, maxLength int) *huffmanCode {
+	code := &huffmanCode{lengths: huffmanLengths(freqs, maxLength), codes: make([]uint32, len(freqs))}
+
+	used := 0
+	for _, length := range code.lengths {
+		if length > 0 {
+			used++
+		}
+	}
+	if used == 0 {
+		code.lengths[0] = 1
+	}
+	if used <= 1 {
+		code.single = true
+		return code
+	}
+
+	// Códigos canônicos, como em codeLengthsToCodes do decodificador
+	var count [vp8lMaxCodeLength + 1]uint32
+	for _, length := range code.lengths {
+		count[length]++
+	}
+	count[0] = 0
+	var next [vp8lMaxCodeLength + 1]uint32
+	current := uint32(0)
+	for length := 1; length <= vp8lMaxCodeLength; length++ {
+		current = (current + count[length-1]) << 1
+		next[length] = current
+	}
+	for symbol, length := range code.lengths {
+		if length > 0 {
+			code.codes[symbol] = reverseBits(next[length], int(length))
+			next[length]++
+		}
+	}
+	return code
+}
+
+// writeSymbol grava o código do símbolo
+func (c *huffmanCode) writeSymbol(bw *vp8lBitWriter, symbol int) {
+	if !c.single {
+		bw.write(c.codes[symbol], int(c.lengths[symbol]))
+	}
+}
+
+// huffmanLengths calcula os comprimentos dos códigos de Huffman; acima do limite, as frequências são reduzidas
+// (aproximando-as) até que a árvore caiba
+func huffmanLengths(freqs []int, maxLength int) []uint8 {
+	counts := append([]int(nil), freqs...)
+	for {
+		lengths, depth := huffmanTree(counts)
+		if depth <= maxLength {
+			return lengths
+		}
+		for i, count := range counts {
+			if count > 0 {
+				counts[i] = count>>1 | 1
+			}
+		}
+	}
+}
+
+// huffmanTree monta a árvore de Huffman e retorna o comprimento do código de cada símbolo e a profundidade máxima
+func huffmanTree(freqs []int) ([]uint8, int) {
+	lengths := make([]uint8, len(freqs))
+
+	var leaves []int
+	for symbol, freq := range freqs {
+		if freq > 0 {
+			leaves = append(leaves, symbol)
+		}
+	}
+	if len(leaves) == 1 {
+		lengths[leaves[0]] = 1
+		return lengths, 1
+	}
+	if len(leaves) == 0 {
+		return lengths, 0
+	}
+	sort.SliceStable(leaves, func(i, j int) bool { return freqs[leaves[i]] < freqs[leaves[j]] })
+
+	// Nós 0..n-1 são as folhas; os internos são criados em ordem crescente de peso (duas filas)
+	n := len(leaves)
+	weight := make([]int, 0, 2*n-1)
+	parent := make([]int, 2*n-1)
+	for _, symbol := range leaves {
+		weight = append(weight, freqs[symbol])
+	}
+	leafIndex, internalIndex := 0, n
+	pick := func() int {
+		if leafIndex < n && (internalIndex >= len(weight) || weight[leafIndex] <= weight[internalIndex]) {
+			leafIndex++
+			return leafIndex - 1
+		}
+		internalIndex++
+		return internalIndex - 1
+	}
+	for len(weight) < 2*n-1 {
+		a, b := pick(), pick()
+		parent[a], parent[b] = len(weight), len(weight)
+		weight = append(weight, weight[a]+weight[b])
+	}
+
+	// Profundidades: a raiz é o último nó; cada nó é mais profundo que o pai, criado depois dele
+	depth := make([]int, 2*n-1)
+	maxDepth := 0
+	for node := 2*n - 3; node >= 0; node-- {
+		depth[node] = depth[parent[node]] + 1
+		if node < n {
+			lengths[leaves[node]] = uint8(min(depth[node], 255))
+			maxDepth = max(maxDepth, depth[node])
+		}
+	}
+	return lengths, maxDepth
+}
+
+// writeHuffmanCode grava os comprimentos do código (formato normal), comprimidos com o código de comprimentos:
+// 0-15 literais, 16 repete o último comprimento não nulo, 17 e 18 repetem zeros
+func writeHuffmanCode(bw *vp8lBitWriter, code *huffmanCode) {
+	type clToken struct{ symbol, extra int }
+	var tokens []clToken
+	for i := 0; i < len(code.lengths); {
+		length := int(code.lengths[i])
+		run := 1
+		for i+run < len(code.lengths) && int(code.lengths[i+run]) == length {
+			run++
+		}
+		i += run
+
+		if length == 0 {
+			for run > 0 {
+				switch {
+				case run >= 11:
+					n := min(run, 138)
+					tokens = append(tokens, clToken{18, n - 11})
+					run -= n
+				case run >= 3:
+					tokens = append(tokens, clToken{17, run - 3})
+					run = 0
+				default:
+					tokens = append(tokens, clToken{0, 0})
+					run--
+				}
+			}
+			continue
+		}
+
+		tokens = append(tokens, clToken{length, 0})
+		run--
+		for run >= 3 {
+			n := min(run, 6)
+			tokens = append(tokens, clToken{16, n - 3})
+			run -= n
+		}
+		for ; run > 0; run-- {
+			tokens = append(tokens, clToken{length, 0})
+		}
+	}
+
+	freqs := make([]int, len(vp8lCodeLengthOrder))
+	for _, token := range tokens {
+		freqs[token.symbol]++
+	}
+	lengthCode := newHuffmanCode(freqs, 7)
+
+	count := 4
+	for i, symbol := range vp8lCodeLengthOrder {
+		if lengthCode.lengths[symbol] > 0 {
+			count = max(count, i+1)
+		}
+	}
+
+	bw.write(0, 1) // Código normal
+	bw.write(uint32(count-4), 4)
+	for _, symbol := range vp8lCodeLengthOrder[:count] {
+		bw.write(uint32(lengthCode.lengths[symbol]), 3)
+	}
+	bw.write(0, 1) // Comprimentos de todo o alfabeto
+
+	extraBits := map[int]int{16: 2, 17: 3, 18: 7}
+	for _, token := range tokens {
+		lengthCode.writeSymbol(bw, token.symbol)
+		if bits, ok := extraBits[token.symbol]; ok {
+			bw.write(uint32(token.extra), bits)
+		}
+	}
+}
+
+// reverseBits inverte os n bits menos significativos de v
+func reverseBits(v uint32, n int) uint32 {
+	var out uint32
+	for i := 0; i < n; i++ {
+		out = out<<1 | v&1
+		v >>= 1
+	}
+	return out
+}
+
+// vp8lBitWriter grava bits a partir do menos significativo de cada byte
+type vp8lBitWriter struct {
+	buf   []byte
+	bits  uint64
+	nBits int
+}
+
+// write grava os n bits menos significativos de v
+func (w *vp8lBitWriter) write(v uint32, n int) {
+	w.bits |= uint64(v&(1<<n-1)) << w.nBits
+	w.nBits += n
+	for w.nBits >= 8 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits >>= 8
+		w.nBits -= 8
+	}
+}
+
+// bytes completa o último byte e retorna o fluxo gravado
+func (w *vp8lBitWriter) bytes() []byte {
+	if w.nBits > 0 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits, w.nBits = 0, 0
+	}
+	return w.buf
+}
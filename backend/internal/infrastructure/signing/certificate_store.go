@@ -0,0 +1,188 @@
+package signing
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Organização dos certificados no diretório configurado
+const (
+	organizationCertificateFile = "organization.p12" // Certificado da organização, usado como padrão
+	userCertificatesDir         = "users"            // Certificados dos usuários: users/<user_id>.p12
+)
+
+// FileCertificateStore implementa CertificateStore com arquivos PKCS#12 em disco
+type FileCertificateStore struct {
+	basePath             string
+	organizationPassword string
+	roots                *x509.CertPool
+}
+
+// NewFileCertificateStore cria uma nova instância de FileCertificateStore
+// As raízes confiáveis são lidas de trustStorePath (arquivo ou diretório com certificados PEM ou DER);
+// additionalRoots permite confiar em certificados gerados em processo, como o da autoridade local
+func NewFileCertificateStore(basePath, organizationPassword, trustStorePath string, additionalRoots ...*x509.Certificate) (domain.CertificateStore, error) {
+	roots, err := loadTrustStore(trustStorePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, cert := range additionalRoots {
+		roots.AddCert(cert)
+	}
+
+	return &FileCertificateStore{
+		basePath:             basePath,
+		organizationPassword: organizationPassword,
+		roots:                roots,
+	}, nil
+}
+
+// Identity carrega o certificado do usuário ou, na ausência, o da organização
+func (s *FileCertificateStore) Identity(ctx context.Context, userID uuid.UUID, password string) (*model.SigningIdentity, error) {
+	owner := model.CertificateOwnerUser
+	data, err := os.ReadFile(filepath.Join(s.basePath, userCertificatesDir, userID.String()+".p12"))
+	if errors.Is(err, os.ErrNotExist) {
+		owner = model.CertificateOwnerOrganization
+		password = s.organizationPassword
+		data, err = os.ReadFile(filepath.Join(s.basePath, organizationCertificateFile))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, errors.New("certificado de assinatura não encontrado")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler certificado de assinatura: %w", err)
+	}
+
+	privateKey, cert, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+			return nil, errors.New("senha do certificado inválida")
+		}
+		logger.Logger.Warn("Certificado de assinatura inválido",
+			zap.String("user_id", userID.String()),
+			zap.String("owner", string(owner)),
+			zap.Error(err),
+		)
+		return nil, errors.New("certificado de assinatura inválido")
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("certificado de assinatura inválido")
+	}
+
+	now := time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, errors.New("certificado de assinatura fora do período de validade")
+	}
+	if cert.KeyUsage != 0 && cert.KeyUsage&(x509.KeyUsageDigitalSignature|x509.KeyUsageContentCommitment) == 0 {
+		return nil, errors.New("certificado de assinatura não permite assinatura digital")
+	}
+
+	return &model.SigningIdentity{
+		PrivateKey:  signer,
+		Certificate: cert,
+		Chain:       chain,
+		Owner:       owner,
+	}, nil
+}
+
+// TrustedRoots retorna as autoridades certificadoras do repositório confiável
+func (s *FileCertificateStore) TrustedRoots() *x509.CertPool {
+	return s.roots
+}
+
+// loadTrustStore lê os certificados do repositório confiável; a ausência do caminho resulta em repositório vazio
+func loadTrustStore(path string) (*x509.CertPool, error) {
+	roots := x509.NewCertPool()
+	if path == "" {
+		return roots, nil
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		logger.Logger.Warn("Repositório de certificados confiáveis não encontrado", zap.String("path", path))
+		return roots, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao acessar repositório de certificados confiáveis: %w", err)
+	}
+
+	files := []string{path}
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao listar repositório de certificados confiáveis: %w", err)
+		}
+		files = files[:0]
+		for _, entry := range entries {
+			switch strings.ToLower(filepath.Ext(entry.Name())) {
+			case ".pem", ".crt", ".cer", ".der":
+				files = append(files, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+
+	count := 0
+	for _, file := range files {
+		certs, err := readCertificates(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, cert := range certs {
+			roots.AddCert(cert)
+		}
+		count += len(certs)
+	}
+
+	logger.Logger.Info("Repositório de certificados confiáveis carregado",
+		zap.String("path", path),
+		zap.Int("certificates", count),
+	)
+
+	return roots, nil
+}
+
+// readCertificates lê os certificados de um arquivo PEM (um ou mais blocos) ou DER
+func readCertificates(file string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler certificado confiável %s: %w", file, err)
+	}
+
+	if !strings.Contains(string(data), "-----BEGIN") {
+		certs, err := x509.ParseCertificates(data)
+		if err != nil {
+			return nil, fmt.Errorf("certificado confiável inválido %s: %w", file, err)
+		}
+		return certs, nil
+	}
+
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificado confiável inválido %s: %w", file, err)
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
@@ -0,0 +1,525 @@
+package signing
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/internal/model"
+)
+
+// Identificadores de objeto usados nas estruturas CMS (RFC 5652), ESS (RFC 5035) e RFC 3161
+var (
+	oidData               = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+	oidSignedData         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidTSTInfo            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 4}
+	oidAttrContentType    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 3}
+	oidAttrMessageDigest  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidAttrSigningTime    = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidAttrSigningCertV2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 47}
+	oidAttrTimestampToken = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 2, 14}
+
+	oidSHA1   = asn1.ObjectIdentifier{1, 3, 14, 3, 2, 26}
+	oidSHA256 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 1}
+	oidSHA384 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 2}
+	oidSHA512 = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 2, 3}
+
+	oidRSAEncryption   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 1}
+	oidSHA1WithRSA     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 5}
+	oidRSAPSS          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}
+	oidSHA256WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 11}
+	oidSHA384WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 12}
+	oidSHA512WithRSA   = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 13}
+	oidECPublicKey     = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidECDSAWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 1}
+	oidECDSAWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 2}
+	oidECDSAWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 3}
+	oidECDSAWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 10045, 4, 3, 4}
+)
+
+// Algoritmos de resumo aceitos na verificação
+var digestAlgorithms = []struct {
+	oid  asn1.ObjectIdentifier
+	hash crypto.Hash
+}{
+	{oidSHA1, crypto.SHA1},
+	{oidSHA256, crypto.SHA256},
+	{oidSHA384, crypto.SHA384},
+	{oidSHA512, crypto.SHA512},
+}
+
+// contentInfo é o envelope de nível superior de uma mensagem CMS
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+// signedData é a estrutura SignedData (RFC 5652, seção 5.1)
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	EncapContentInfo encapsulatedContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+// encapsulatedContentInfo contém o conteúdo assinado; ausente em assinaturas destacadas
+type encapsulatedContentInfo struct {
+	EContentType asn1.ObjectIdentifier
+	EContent     []byte `asn1:"explicit,optional,tag:0"`
+}
+
+// signerInfo é a estrutura SignerInfo (RFC 5652, seção 5.3)
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue // IssuerAndSerialNumber ou [0] SubjectKeyIdentifier
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttrs        asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttrs      asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// issuerAndSerial identifica o certificado do signatário pelo emissor e número de série
+type issuerAndSerial struct {
+	Issuer       asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// attribute é um atributo assinado ou não assinado; os valores são mantidos codificados
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue
+}
+
+// essCertIDv2 identifica o certificado do signatário no atributo signing-certificate-v2
+// O algoritmo de resumo é omitido por ser o padrão (SHA-256)
+type essCertIDv2 struct {
+	CertHash     []byte
+	IssuerSerial essIssuerSerial
+}
+
+// essIssuerSerial contém o emissor (GeneralNames com directoryName) e o número de série
+type essIssuerSerial struct {
+	Issuer       []asn1.RawValue
+	SerialNumber *big.Int
+}
+
+// cmsSigner reúne a chave e os certificados usados para gerar uma mensagem SignedData
+type cmsSigner struct {
+	key   crypto.Signer
+	cert  *x509.Certificate
+	chain []*x509.Certificate
+}
+
+// CreateDetachedSignature gera uma assinatura CMS destacada no perfil CAdES-BES (PAdES-B)
+// sobre o resumo SHA-256 do conteúdo assinado
+// Com uma autoridade de carimbo do tempo, o carimbo sobre o valor da assinatura é incluído
+// como atributo não assinado (PAdES-B-T)
+func CreateDetachedSignature(ctx context.Context, digest []byte, identity *model.SigningIdentity, tsa domain.TimestampAuthority) ([]byte, error) {
+	signer := cmsSigner{key: identity.PrivateKey, cert: identity.Certificate, chain: identity.Chain}
+
+	var unsigned func(signature []byte) ([]attribute, error)
+	if tsa != nil {
+		unsigned = func(signature []byte) ([]attribute, error) {
+			imprint := sha256.Sum256(signature)
+			token, err := tsa.Timestamp(ctx, imprint[:])
+			if err != nil {
+				return nil, fmt.Errorf("erro ao obter carimbo do tempo: %w", err)
+			}
+			return []attribute{{Type: oidAttrTimestampToken, Values: setOf(token)}}, nil
+		}
+	}
+
+	return signer.sign(oidData, digest, nil, nil, unsigned)
+}
+
+// sign gera a mensagem SignedData com os atributos content-type, message-digest e
+// signing-certificate-v2, além de extraSigned; content é incluído na mensagem quando não for nil
+func (s cmsSigner) sign(contentType asn1.ObjectIdentifier, digest, content []byte, extraSigned []attribute, unsigned func(signature []byte) ([]attribute, error)) ([]byte, error) {
+	signatureAlgorithm, err := signatureAlgorithmFor(s.key.Public())
+	if err != nil {
+		return nil, err
+	}
+
+	certHash := sha256.Sum256(s.cert.Raw)
+	signingCert, err := asn1.Marshal(struct{ Certs []essCertIDv2 }{
+		Certs: []essCertIDv2{{
+			CertHash: certHash[:],
+			IssuerSerial: essIssuerSerial{
+				Issuer:       []asn1.RawValue{{Class: asn1.ClassContextSpecific, Tag: 4, IsCompound: true, Bytes: s.cert.RawIssuer}},
+				SerialNumber: s.cert.SerialNumber,
+			},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	contentTypeValue, err := asn1.Marshal(contentType)
+	if err != nil {
+		return nil, err
+	}
+	digestValue, err := asn1.Marshal(digest)
+	if err != nil {
+		return nil, err
+	}
+
+	signedAttrs, err := marshalAttributes(append([]attribute{
+		{Type: oidAttrContentType, Values: setOf(contentTypeValue)},
+		{Type: oidAttrMessageDigest, Values: setOf(digestValue)},
+		{Type: oidAttrSigningCertV2, Values: setOf(signingCert)},
+	}, extraSigned...))
+	if err != nil {
+		return nil, err
+	}
+
+	// A assinatura cobre a codificação DER dos atributos como SET OF (RFC 5652, seção 5.4)
+	signedAttrsDER, err := asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: signedAttrs})
+	if err != nil {
+		return nil, err
+	}
+	attrsDigest := sha256.Sum256(signedAttrsDER)
+	signature, err := s.key.Sign(rand.Reader, attrsDigest[:], crypto.SHA256)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao assinar: %w", err)
+	}
+
+	sid, err := asn1.Marshal(issuerAndSerial{
+		Issuer:       asn1.RawValue{FullBytes: s.cert.RawIssuer},
+		SerialNumber: s.cert.SerialNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	info := signerInfo{
+		Version:            1,
+		SID:                asn1.RawValue{FullBytes: sid},
+		DigestAlgorithm:    pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue},
+		SignedAttrs:        asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedAttrs},
+		SignatureAlgorithm: signatureAlgorithm,
+		Signature:          signature,
+	}
+
+	if unsigned != nil {
+		attrs, err := unsigned(signature)
+		if err != nil {
+			return nil, err
+		}
+		unsignedAttrs, err := marshalAttributes(attrs)
+		if err != nil {
+			return nil, err
+		}
+		info.UnsignedAttrs = asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 1, IsCompound: true, Bytes: unsignedAttrs}
+	}
+
+	var certificates []byte
+	for _, cert := range append([]*x509.Certificate{s.cert}, s.chain...) {
+		certificates = append(certificates, cert.Raw...)
+	}
+
+	sd := signedData{
+		Version:          1,
+		DigestAlgorithms: []pkix.AlgorithmIdentifier{info.DigestAlgorithm},
+		EncapContentInfo: encapsulatedContentInfo{EContentType: contentType, EContent: content},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: certificates},
+		SignerInfos:      []signerInfo{info},
+	}
+	if !contentType.Equal(oidData) {
+		// RFC 5652: versão 3 quando o conteúdo encapsulado não é id-data
+		sd.Version = 3
+	}
+
+	inner, err := asn1.Marshal(sd)
+	if err != nil {
+		return nil, err
+	}
+
+	return asn1.Marshal(contentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: inner},
+	})
+}
+
+// signatureAlgorithmFor retorna o algoritmo de assinatura com SHA-256 para o tipo de chave
+func signatureAlgorithmFor(publicKey crypto.PublicKey) (pkix.AlgorithmIdentifier, error) {
+	switch publicKey.(type) {
+	case *rsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidSHA256WithRSA, Parameters: asn1.NullRawValue}, nil
+	case *ecdsa.PublicKey:
+		return pkix.AlgorithmIdentifier{Algorithm: oidECDSAWithSHA256}, nil
+	}
+	return pkix.AlgorithmIdentifier{}, errors.New("tipo de chave não suportado para assinatura (use RSA ou ECDSA)")
+}
+
+// setOf envolve um valor já codificado em um SET com um único elemento
+func setOf(value []byte) asn1.RawValue {
+	return asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: value}
+}
+
+// marshalAttributes codifica os atributos na ordem exigida pelo DER para SET OF
+// Retorna apenas o conteúdo, sem a tag e o comprimento do conjunto
+func marshalAttributes(attrs []attribute) ([]byte, error) {
+	encoded := make([][]byte, 0, len(attrs))
+	for _, attr := range attrs {
+		der, err := asn1.Marshal(attr)
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, der)
+	}
+	sort.Slice(encoded, func(i, j int) bool { return bytes.Compare(encoded[i], encoded[j]) < 0 })
+	return bytes.Join(encoded, nil), nil
+}
+
+// signedMessage é uma mensagem SignedData decodificada, com um único signatário
+type signedMessage struct {
+	contentType   asn1.ObjectIdentifier
+	content       []byte
+	certificates  []*x509.Certificate
+	signer        signerInfo
+	signerCert    *x509.Certificate
+	signedAttrs   []attribute
+	unsignedAttrs []attribute
+}
+
+// parseSignedMessage decodifica uma mensagem CMS SignedData e localiza o certificado do signatário
+// Bytes após a estrutura (preenchimento de /Contents) são ignorados
+func parseSignedMessage(der []byte) (*signedMessage, error) {
+	var info contentInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("estrutura CMS inválida: %w", err)
+	}
+	if !info.ContentType.Equal(oidSignedData) {
+		return nil, errors.New("estrutura CMS não é do tipo SignedData")
+	}
+
+	var sd signedData
+	if _, err := asn1.Unmarshal(info.Content.Bytes, &sd); err != nil {
+		return nil, fmt.Errorf("SignedData inválido: %w", err)
+	}
+	if len(sd.SignerInfos) != 1 {
+		return nil, fmt.Errorf("esperado um signatário, encontrados %d", len(sd.SignerInfos))
+	}
+
+	message := &signedMessage{
+		contentType: sd.EncapContentInfo.EContentType,
+		content:     sd.EncapContentInfo.EContent,
+		signer:      sd.SignerInfos[0],
+	}
+
+	if len(sd.Certificates.Bytes) > 0 {
+		certificates, err := x509.ParseCertificates(sd.Certificates.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificados inválidos na assinatura: %w", err)
+		}
+		message.certificates = certificates
+	}
+
+	var err error
+	if message.signedAttrs, err = parseAttributes(message.signer.SignedAttrs.Bytes); err != nil {
+		return nil, fmt.Errorf("atributos assinados inválidos: %w", err)
+	}
+	if message.unsignedAttrs, err = parseAttributes(message.signer.UnsignedAttrs.Bytes); err != nil {
+		return nil, fmt.Errorf("atributos não assinados inválidos: %w", err)
+	}
+
+	message.signerCert = message.findSignerCertificate()
+	if message.signerCert == nil {
+		return nil, errors.New("certificado do signatário não incluído na assinatura")
+	}
+
+	return message, nil
+}
+
+// parseAttributes decodifica o conteúdo de um SET OF Attribute
+func parseAttributes(data []byte) ([]attribute, error) {
+	var attrs []attribute
+	for rest := data; len(rest) > 0; {
+		var attr attribute
+		var err error
+		if rest, err = asn1.Unmarshal(rest, &attr); err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attr)
+	}
+	return attrs, nil
+}
+
+// attributeValue retorna o primeiro valor codificado do atributo
+func attributeValue(attrs []attribute, oid asn1.ObjectIdentifier) ([]byte, bool) {
+	for _, attr := range attrs {
+		if !attr.Type.Equal(oid) {
+			continue
+		}
+		var value asn1.RawValue
+		if _, err := asn1.Unmarshal(attr.Values.Bytes, &value); err != nil {
+			return nil, false
+		}
+		return value.FullBytes, true
+	}
+	return nil, false
+}
+
+// findSignerCertificate localiza o certificado pelo emissor e número de série ou pelo identificador da chave
+func (m *signedMessage) findSignerCertificate() *x509.Certificate {
+	sid := m.signer.SID
+
+	if sid.Class == asn1.ClassContextSpecific && sid.Tag == 0 {
+		for _, cert := range m.certificates {
+			if bytes.Equal(cert.SubjectKeyId, sid.Bytes) {
+				return cert
+			}
+		}
+		return nil
+	}
+
+	var id issuerAndSerial
+	if _, err := asn1.Unmarshal(sid.FullBytes, &id); err != nil {
+		return nil
+	}
+	for _, cert := range m.certificates {
+		if bytes.Equal(cert.RawIssuer, id.Issuer.FullBytes) && cert.SerialNumber.Cmp(id.SerialNumber) == 0 {
+			return cert
+		}
+	}
+	return nil
+}
+
+// digestHash retorna a função de resumo declarada pelo signatário
+func (m *signedMessage) digestHash() (crypto.Hash, error) {
+	for _, algorithm := range digestAlgorithms {
+		if m.signer.DigestAlgorithm.Algorithm.Equal(algorithm.oid) {
+			return algorithm.hash, nil
+		}
+	}
+	return 0, fmt.Errorf("algoritmo de resumo não suportado: %s", m.signer.DigestAlgorithm.Algorithm)
+}
+
+// verify confere o resumo do conteúdo (atributo message-digest) e a assinatura do signatário
+func (m *signedMessage) verify(content []byte) error {
+	hash, err := m.digestHash()
+	if err != nil {
+		return err
+	}
+	if !hash.Available() {
+		return fmt.Errorf("algoritmo de resumo indisponível: %s", hash)
+	}
+
+	algorithm, err := x509SignatureAlgorithm(m.signer.SignatureAlgorithm.Algorithm, hash)
+	if err != nil {
+		return err
+	}
+
+	signed := content
+	if len(m.signedAttrs) > 0 {
+		h := hash.New()
+		h.Write(content)
+
+		digestValue, ok := attributeValue(m.signedAttrs, oidAttrMessageDigest)
+		if !ok {
+			return errors.New("atributo message-digest ausente")
+		}
+		var digest []byte
+		if _, err := asn1.Unmarshal(digestValue, &digest); err != nil {
+			return fmt.Errorf("atributo message-digest inválido: %w", err)
+		}
+		if !bytes.Equal(digest, h.Sum(nil)) {
+			return errors.New("o resumo do conteúdo não confere com o assinado")
+		}
+
+		contentTypeValue, ok := attributeValue(m.signedAttrs, oidAttrContentType)
+		var contentType asn1.ObjectIdentifier
+		if !ok {
+			return errors.New("atributo content-type ausente")
+		}
+		if _, err := asn1.Unmarshal(contentTypeValue, &contentType); err != nil || !contentType.Equal(m.contentType) {
+			return errors.New("atributo content-type não confere com o conteúdo")
+		}
+
+		if signed, err = asn1.Marshal(asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true, Bytes: m.signer.SignedAttrs.Bytes}); err != nil {
+			return err
+		}
+	}
+
+	if err := m.signerCert.CheckSignature(algorithm, signed, m.signer.Signature); err != nil {
+		return fmt.Errorf("assinatura inválida: %w", err)
+	}
+	return nil
+}
+
+// verifyChain valida a cadeia do signatário até uma das raízes confiáveis no instante informado
+func (m *signedMessage) verifyChain(roots *x509.CertPool, at time.Time, usage x509.ExtKeyUsage) ([]*x509.Certificate, error) {
+	if roots == nil {
+		roots = x509.NewCertPool()
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range m.certificates {
+		if cert != m.signerCert {
+			intermediates.AddCert(cert)
+		}
+	}
+
+	chains, err := m.signerCert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return chains[0], nil
+}
+
+// x509SignatureAlgorithm converte o algoritmo de assinatura do SignerInfo para o equivalente em crypto/x509
+func x509SignatureAlgorithm(oid asn1.ObjectIdentifier, hash crypto.Hash) (x509.SignatureAlgorithm, error) {
+	byHash := func(algorithms map[crypto.Hash]x509.SignatureAlgorithm) (x509.SignatureAlgorithm, error) {
+		if algorithm, ok := algorithms[hash]; ok {
+			return algorithm, nil
+		}
+		return x509.UnknownSignatureAlgorithm, fmt.Errorf("combinação de algoritmos não suportada: %s com %s", oid, hash)
+	}
+
+	switch {
+	case oid.Equal(oidRSAEncryption), oid.Equal(oidSHA1WithRSA), oid.Equal(oidSHA256WithRSA),
+		oid.Equal(oidSHA384WithRSA), oid.Equal(oidSHA512WithRSA):
+		return byHash(map[crypto.Hash]x509.SignatureAlgorithm{
+			crypto.SHA1:   x509.SHA1WithRSA,
+			crypto.SHA256: x509.SHA256WithRSA,
+			crypto.SHA384: x509.SHA384WithRSA,
+			crypto.SHA512: x509.SHA512WithRSA,
+		})
+	case oid.Equal(oidRSAPSS):
+		return byHash(map[crypto.Hash]x509.SignatureAlgorithm{
+			crypto.SHA256: x509.SHA256WithRSAPSS,
+			crypto.SHA384: x509.SHA384WithRSAPSS,
+			crypto.SHA512: x509.SHA512WithRSAPSS,
+		})
+	case oid.Equal(oidECPublicKey), oid.Equal(oidECDSAWithSHA1), oid.Equal(oidECDSAWithSHA256),
+		oid.Equal(oidECDSAWithSHA384), oid.Equal(oidECDSAWithSHA512):
+		return byHash(map[crypto.Hash]x509.SignatureAlgorithm{
+			crypto.SHA1:   x509.ECDSAWithSHA1,
+			crypto.SHA256: x509.ECDSAWithSHA256,
+			crypto.SHA384: x509.ECDSAWithSHA384,
+			crypto.SHA512: x509.ECDSAWithSHA512,
+		})
+	}
+	return x509.UnknownSignatureAlgorithm, fmt.Errorf("algoritmo de assinatura não suportado: %s", oid)
+}
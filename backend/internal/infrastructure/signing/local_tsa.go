@@ -0,0 +1,161 @@
+package signing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/editor-pdf/backend/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Política declarada nos carimbos da autoridade local (arco 2.999, reservado para exemplos)
+var localTimestampPolicy = asn1.ObjectIdentifier{2, 999, 3161, 1}
+
+// Validade do certificado gerado para a autoridade local
+const localTimestampValidity = 10 * 365 * 24 * time.Hour
+
+// LocalTimestampAuthority é uma autoridade de carimbo do tempo em processo, para desenvolvimento e testes
+// A chave e o certificado autoassinado são gerados na inicialização; o certificado deve estar entre as
+// raízes confiáveis para que os carimbos sejam aceitos na verificação
+type LocalTimestampAuthority struct {
+	signer cmsSigner
+}
+
+// NewLocalTimestampAuthority cria uma autoridade local com uma chave ECDSA P-256 recém-gerada
+func NewLocalTimestampAuthority() (*LocalTimestampAuthority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar chave da autoridade de carimbo do tempo: %w", err)
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: "Editor PDF Local TSA", Organization: []string{"Editor PDF"}},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(localTimestampValidity),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageTimeStamping},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar certificado da autoridade de carimbo do tempo: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LocalTimestampAuthority{signer: cmsSigner{key: key, cert: cert}}, nil
+}
+
+// Certificate retorna o certificado da autoridade, a ser incluído no repositório confiável
+func (a *LocalTimestampAuthority) Certificate() *x509.Certificate {
+	return a.signer.cert
+}
+
+// Timestamp emite um carimbo do tempo sobre o resumo SHA-256 informado
+func (a *LocalTimestampAuthority) Timestamp(ctx context.Context, digest []byte) ([]byte, error) {
+	return a.issue(messageImprint{
+		HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue},
+		HashedMessage: digest,
+	}, nil)
+}
+
+// ServeHTTP atende requisições RFC 3161 sobre HTTP, permitindo usar a autoridade local como URL de TSA
+func (a *LocalTimestampAuthority) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "método não permitido", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxTimestampResponseSize))
+	if err != nil {
+		http.Error(w, "erro ao ler requisição", http.StatusBadRequest)
+		return
+	}
+
+	var request timeStampReq
+	if _, err := asn1.Unmarshal(body, &request); err != nil {
+		http.Error(w, "requisição de carimbo do tempo inválida", http.StatusBadRequest)
+		return
+	}
+
+	token, err := a.issue(request.MessageImprint, request.Nonce)
+	if err != nil {
+		logger.Logger.Warn("Erro ao emitir carimbo do tempo local", zap.Error(err))
+		http.Error(w, "erro ao emitir carimbo do tempo", http.StatusInternalServerError)
+		return
+	}
+
+	response, err := asn1.Marshal(timeStampResp{
+		Status:         pkiStatusInfo{Status: 0},
+		TimeStampToken: asn1.RawValue{FullBytes: token},
+	})
+	if err != nil {
+		http.Error(w, "erro ao codificar resposta", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", timestampReplyContentType)
+	_, _ = w.Write(response)
+}
+
+// issue gera o TimeStampToken com o horário atual
+func (a *LocalTimestampAuthority) issue(imprint messageImprint, nonce *big.Int) ([]byte, error) {
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	genTime := time.Now().UTC().Truncate(time.Second)
+	info, err := asn1.Marshal(tstInfo{
+		Version:        1,
+		Policy:         localTimestampPolicy,
+		MessageImprint: imprint,
+		SerialNumber:   serial,
+		GenTime:        genTime,
+		Accuracy:       tstAccuracy{Seconds: 1},
+		Nonce:          nonce,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// signing-time repete genTime para verificadores que não leem o TSTInfo
+	signingTime, err := asn1.Marshal(genTime)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(info)
+	return a.signer.sign(oidTSTInfo, digest[:], info, []attribute{{Type: oidAttrSigningTime, Values: setOf(signingTime)}}, nil)
+}
+
+// randomSerial gera um número de série aleatório de 128 bits
+func randomSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar número de série: %w", err)
+	}
+	return serial, nil
+}
@@ -0,0 +1,211 @@
+package signing
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/editor-pdf/backend/internal/domain"
+)
+
+// Tipos de conteúdo do protocolo de carimbo do tempo sobre HTTP (RFC 3161, seção 3.4)
+const (
+	timestampQueryContentType = "application/timestamp-query"
+	timestampReplyContentType = "application/timestamp-reply"
+)
+
+// Tamanho máximo aceito para a resposta da autoridade de carimbo do tempo
+const maxTimestampResponseSize = 1 << 20
+
+// messageImprint é o resumo carimbado e o algoritmo usado
+type messageImprint struct {
+	HashAlgorithm pkix.AlgorithmIdentifier
+	HashedMessage []byte
+}
+
+// timeStampReq é a requisição de carimbo do tempo (RFC 3161, seção 2.4.1)
+type timeStampReq struct {
+	Version        int
+	MessageImprint messageImprint
+	ReqPolicy      asn1.ObjectIdentifier `asn1:"optional"`
+	Nonce          *big.Int              `asn1:"optional"`
+	CertReq        bool                  `asn1:"optional,default:false"`
+	Extensions     asn1.RawValue         `asn1:"optional,tag:0"`
+}
+
+// pkiStatusInfo é a situação da resposta; 0 (concedido) e 1 (concedido com modificações) incluem o token
+type pkiStatusInfo struct {
+	Status       int
+	StatusString asn1.RawValue  `asn1:"optional"`
+	FailInfo     asn1.BitString `asn1:"optional"`
+}
+
+// timeStampResp é a resposta da autoridade (RFC 3161, seção 2.4.2)
+type timeStampResp struct {
+	Status         pkiStatusInfo
+	TimeStampToken asn1.RawValue `asn1:"optional"`
+}
+
+// tstInfo é o conteúdo assinado de um TimeStampToken
+type tstInfo struct {
+	Version        int
+	Policy         asn1.ObjectIdentifier
+	MessageImprint messageImprint
+	SerialNumber   *big.Int
+	GenTime        time.Time     `asn1:"generalized"`
+	Accuracy       tstAccuracy   `asn1:"optional"`
+	Ordering       bool          `asn1:"optional,default:false"`
+	Nonce          *big.Int      `asn1:"optional"`
+	TSA            asn1.RawValue `asn1:"optional,tag:0"`
+	Extensions     asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+// tstAccuracy é a precisão declarada do horário
+type tstAccuracy struct {
+	Seconds int `asn1:"optional"`
+	Millis  int `asn1:"optional,tag:0"`
+	Micros  int `asn1:"optional,tag:1"`
+}
+
+// RFC3161Client obtém carimbos do tempo de uma autoridade externa via HTTP
+type RFC3161Client struct {
+	url        string
+	httpClient *http.Client
+}
+
+// NewRFC3161Client cria uma nova instância de RFC3161Client
+func NewRFC3161Client(url string, timeout time.Duration) domain.TimestampAuthority {
+	return &RFC3161Client{
+		url:        url,
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+// Timestamp envia a requisição com o resumo SHA-256 e confere o token recebido
+func (c *RFC3161Client) Timestamp(ctx context.Context, digest []byte) ([]byte, error) {
+	nonce, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := asn1.Marshal(timeStampReq{
+		Version: 1,
+		MessageImprint: messageImprint{
+			HashAlgorithm: pkix.AlgorithmIdentifier{Algorithm: oidSHA256, Parameters: asn1.NullRawValue},
+			HashedMessage: digest,
+		},
+		Nonce:   nonce,
+		CertReq: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(request))
+	if err != nil {
+		return nil, fmt.Errorf("URL da autoridade de carimbo do tempo inválida: %w", err)
+	}
+	httpRequest.Header.Set("Content-Type", timestampQueryContentType)
+
+	httpResponse, err := c.httpClient.Do(httpRequest)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao contatar a autoridade de carimbo do tempo: %w", err)
+	}
+	defer httpResponse.Body.Close()
+
+	if httpResponse.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("autoridade de carimbo do tempo respondeu com status HTTP %d", httpResponse.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(httpResponse.Body, maxTimestampResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler resposta da autoridade de carimbo do tempo: %w", err)
+	}
+
+	var response timeStampResp
+	if _, err := asn1.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("resposta de carimbo do tempo inválida: %w", err)
+	}
+	if response.Status.Status > 1 || len(response.TimeStampToken.FullBytes) == 0 {
+		return nil, fmt.Errorf("autoridade de carimbo do tempo recusou a requisição (status %d)", response.Status.Status)
+	}
+
+	token := response.TimeStampToken.FullBytes
+	info, _, err := parseTimestampToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(info.MessageImprint.HashedMessage, digest) {
+		return nil, errors.New("carimbo do tempo recebido não corresponde ao resumo enviado")
+	}
+	if info.Nonce == nil || info.Nonce.Cmp(nonce) != 0 {
+		return nil, errors.New("carimbo do tempo recebido não corresponde à requisição (nonce)")
+	}
+
+	return token, nil
+}
+
+// parseTimestampToken decodifica um TimeStampToken e o seu TSTInfo
+func parseTimestampToken(token []byte) (*tstInfo, *signedMessage, error) {
+	message, err := parseSignedMessage(token)
+	if err != nil {
+		return nil, nil, fmt.Errorf("carimbo do tempo inválido: %w", err)
+	}
+	if !message.contentType.Equal(oidTSTInfo) {
+		return nil, nil, errors.New("carimbo do tempo inválido: conteúdo não é TSTInfo")
+	}
+
+	var info tstInfo
+	if _, err := asn1.Unmarshal(message.content, &info); err != nil {
+		return nil, nil, fmt.Errorf("carimbo do tempo inválido: %w", err)
+	}
+
+	return &info, message, nil
+}
+
+// VerifyTimestampToken verifica um carimbo do tempo sobre os dados informados: o resumo carimbado,
+// a assinatura da autoridade e a sua cadeia (com uso estendido de carimbo do tempo) no horário carimbado
+func VerifyTimestampToken(token, data []byte, roots *x509.CertPool) *TimestampCheck {
+	info, message, err := parseTimestampToken(token)
+	if err != nil {
+		return &TimestampCheck{IntegrityErr: err, TrustErr: err}
+	}
+
+	check := &TimestampCheck{Time: info.GenTime, Authority: message.signerCert}
+
+	check.IntegrityErr = message.verify(message.content)
+	if check.IntegrityErr == nil {
+		check.IntegrityErr = checkImprint(info.MessageImprint, data)
+	}
+
+	if _, err := message.verifyChain(roots, info.GenTime, x509.ExtKeyUsageTimeStamping); err != nil {
+		check.TrustErr = err
+	}
+
+	return check
+}
+
+// checkImprint confere o resumo carimbado com os dados
+func checkImprint(imprint messageImprint, data []byte) error {
+	for _, algorithm := range digestAlgorithms {
+		if !imprint.HashAlgorithm.Algorithm.Equal(algorithm.oid) {
+			continue
+		}
+		h := algorithm.hash.New()
+		h.Write(data)
+		if !bytes.Equal(h.Sum(nil), imprint.HashedMessage) {
+			return errors.New("carimbo do tempo não corresponde aos dados carimbados")
+		}
+		return nil
+	}
+	return fmt.Errorf("algoritmo de resumo do carimbo do tempo não suportado: %s", imprint.HashAlgorithm.Algorithm)
+}
@@ -0,0 +1,70 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/x509"
+	"errors"
+	"time"
+)
+
+// SignatureCheck é o resultado da verificação de uma assinatura CMS
+type SignatureCheck struct {
+	Signer       *x509.Certificate
+	Chain        []*x509.Certificate // Cadeia validada ou, quando não confiável, os certificados incluídos
+	IntegrityErr error               // nil quando o resumo e a assinatura conferem
+	TrustErr     error               // nil quando a cadeia termina em uma raiz confiável
+	Timestamp    *TimestampCheck
+}
+
+// TimestampCheck é o resultado da verificação de um carimbo do tempo
+type TimestampCheck struct {
+	Time         time.Time
+	Authority    *x509.Certificate
+	IntegrityErr error
+	TrustErr     error
+}
+
+// VerifySignature verifica uma assinatura CMS sobre o conteúdo assinado (intervalos do ByteRange)
+// Aceita assinaturas destacadas e assinaturas adbe.pkcs7.sha1, cujo conteúdo encapsulado é o resumo
+// SHA-1 do conteúdo. A cadeia é validada no horário do carimbo do tempo, quando válido, ou no horário atual
+func VerifySignature(signature, content []byte, roots *x509.CertPool) (*SignatureCheck, error) {
+	message, err := parseSignedMessage(signature)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &SignatureCheck{
+		Signer: message.signerCert,
+		Chain:  message.certificates,
+	}
+
+	signedContent := content
+	if message.content != nil {
+		digest := sha1.Sum(content)
+		if !bytes.Equal(message.content, digest[:]) {
+			check.IntegrityErr = errors.New("o resumo do conteúdo não confere com o assinado")
+		}
+		signedContent = message.content
+	}
+	if check.IntegrityErr == nil {
+		check.IntegrityErr = message.verify(signedContent)
+	}
+
+	validationTime := time.Now()
+	if token, ok := attributeValue(message.unsignedAttrs, oidAttrTimestampToken); ok {
+		check.Timestamp = VerifyTimestampToken(token, message.signer.Signature, roots)
+		if check.Timestamp.IntegrityErr == nil && check.Timestamp.TrustErr == nil {
+			validationTime = check.Timestamp.Time
+		}
+	}
+
+	chain, err := message.verifyChain(roots, validationTime, x509.ExtKeyUsageAny)
+	if err != nil {
+		check.TrustErr = err
+	} else {
+		check.Chain = chain
+	}
+
+	return check, nil
+}
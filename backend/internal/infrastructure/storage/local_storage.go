@@ -80,6 +80,22 @@ func (s *LocalStorage) Read(ctx context.Context, filePath string) ([]byte, error
 	return data, nil
 }
 
+// Open abre um arquivo do storage para leitura
+func (s *LocalStorage) Open(ctx context.Context, filePath string) (io.ReadSeekCloser, error) {
+	// Sanitiza o caminho para prevenir path traversal
+	filePath = sanitizePath(filePath)
+
+	file, err := os.Open(filepath.Join(s.basePath, filePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("arquivo não encontrado: %s", filePath)
+		}
+		return nil, fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+
+	return file, nil
+}
+
 // Delete remove um arquivo do storage
 func (s *LocalStorage) Delete(ctx context.Context, filePath string) error {
 	// Sanitiza o caminho
@@ -1,7 +1,9 @@
 package model
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,3 +18,95 @@ type AuditLog struct {
 	Metadata   json.RawMessage `db:"metadata"`
 	CreatedAt  time.Time       `db:"created_at"`
 }
+
+// AnnotationType define o tipo de uma anotação de PDF (comentário em camada separada do conteúdo)
+type AnnotationType string
+
+const (
+	AnnotationHighlight AnnotationType = "highlight" // /Highlight sobre trechos de texto (quads)
+	AnnotationUnderline AnnotationType = "underline" // /Underline
+	AnnotationStrikeOut AnnotationType = "strikeout" // /StrikeOut
+	AnnotationNote      AnnotationType = "note"      // Nota adesiva (/Text) ancorada em (x, y)
+	AnnotationFreeText  AnnotationType = "free_text" // Texto livre (/FreeText) dentro do retângulo
+	AnnotationInk       AnnotationType = "ink"       // Traço livre (/Ink)
+)
+
+// IsTextMarkup indica se o tipo marca trechos de texto por quadriláteros
+func (t AnnotationType) IsTextMarkup() bool {
+	return t == AnnotationHighlight || t == AnnotationUnderline || t == AnnotationStrikeOut
+}
+
+// Annotation representa uma anotação de PDF persistida por documento e página
+// Coordenadas em PDF points com origem no topo esquerdo da página; o retângulo contém toda a anotação
+type Annotation struct {
+	ID         uuid.UUID       `db:"id" json:"id"`
+	DocumentID uuid.UUID       `db:"document_id" json:"document_id"`
+	UserID     uuid.UUID       `db:"user_id" json:"user_id"`
+	Page       int             `db:"page" json:"page"`
+	Type       AnnotationType  `db:"type" json:"type"`
+	X          float64         `db:"x" json:"x"`
+	Y          float64         `db:"y" json:"y"`
+	Width      float64         `db:"width" json:"width"`
+	Height     float64         `db:"height" json:"height"`
+	Quads      AnnotationQuads `db:"quads" json:"quads,omitempty"`       // Trechos marcados (highlight, underline, strikeout)
+	InkList    InkStrokes      `db:"ink_list" json:"ink_list,omitempty"` // Traços (ink)
+	Author     string          `db:"author" json:"author"`
+	Contents   string          `db:"contents" json:"contents"`
+	Color      string          `db:"color" json:"color"` // #RRGGBB; no texto livre, a cor do texto
+	Opacity    float64         `db:"opacity" json:"opacity"`
+	LineWidth  float64         `db:"line_width" json:"line_width,omitempty"` // Espessura do traço (ink)
+	FontSize   float64         `db:"font_size" json:"font_size,omitempty"`   // Tamanho da fonte (free_text)
+	CreatedAt  time.Time       `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// AnnotationQuads é a lista de quadriláteros de uma anotação, armazenada como JSONB
+type AnnotationQuads []Quad
+
+// Value serializa os quadriláteros para o banco
+func (q AnnotationQuads) Value() (driver.Value, error) {
+	return jsonValue(q)
+}
+
+// Scan lê os quadriláteros do banco
+func (q *AnnotationQuads) Scan(src interface{}) error {
+	return scanJSON(src, q)
+}
+
+// InkStrokes é a lista de traços de uma anotação de tinta, armazenada como JSONB
+type InkStrokes [][]Point
+
+// Value serializa os traços para o banco
+func (s InkStrokes) Value() (driver.Value, error) {
+	return jsonValue(s)
+}
+
+// Scan lê os traços do banco
+func (s *InkStrokes) Scan(src interface{}) error {
+	return scanJSON(src, s)
+}
+
+// jsonValue serializa uma lista como JSON, usando lista vazia para nil
+func jsonValue(value interface{}) (driver.Value, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	if string(data) == "null" {
+		return []byte("[]"), nil
+	}
+	return data, nil
+}
+
+// scanJSON lê uma coluna JSON ([]byte ou string)
+func scanJSON(src interface{}, dest interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(data, dest)
+	case string:
+		return json.Unmarshal([]byte(data), dest)
+	}
+	return fmt.Errorf("tipo incompatível para coluna JSON: %T", src)
+}
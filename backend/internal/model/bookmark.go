@@ -0,0 +1,44 @@
+package model
+
+// BookmarkView define como a página de destino de um marcador é exibida
+type BookmarkView string
+
+const (
+	BookmarkViewFit       BookmarkView = "fit"        // Página inteira (/Fit)
+	BookmarkViewFitWidth  BookmarkView = "fit_width"  // Largura da página, a partir de top (/FitH)
+	BookmarkViewFitHeight BookmarkView = "fit_height" // Altura da página, a partir de left (/FitV)
+	BookmarkViewXYZ       BookmarkView = "xyz"        // Posição (left, top) com zoom (/XYZ)
+)
+
+// Bookmark representa um marcador (item do sumário/outline) do PDF
+// Posições de destino em PDF points com origem no topo esquerdo da página
+type Bookmark struct {
+	Title    string       `json:"title"`
+	Page     int          `json:"page"` // Página de destino (0 quando o marcador não aponta para uma página)
+	View     BookmarkView `json:"view,omitempty"`
+	Left     *float64     `json:"left,omitempty"` // Nulo mantém a posição atual do leitor
+	Top      *float64     `json:"top,omitempty"`
+	Zoom     float64      `json:"zoom,omitempty"` // Fator de zoom de xyz (1 = 100%); 0 mantém o zoom atual
+	Open     bool         `json:"open"`           // Filhos expandidos ao abrir o documento
+	Bold     bool         `json:"bold,omitempty"`
+	Italic   bool         `json:"italic,omitempty"`
+	Color    string       `json:"color,omitempty"` // #RRGGBB; vazio = cor padrão do leitor
+	Path     string       `json:"path,omitempty"`  // Posição na árvore (ex.: "2.1"), usada nas edições
+	Children []Bookmark   `json:"children,omitempty"`
+}
+
+// Heading representa um título detectado no texto do documento, candidato a marcador
+type Heading struct {
+	Page     int     `json:"page"`
+	Level    int     `json:"level"` // 1 = maior fonte entre os títulos
+	Title    string  `json:"title"`
+	Top      float64 `json:"top"` // Topo da linha em PDF points, com origem no topo da página
+	FontSize float64 `json:"font_size"`
+}
+
+// HeadingOptions contém os parâmetros da detecção de títulos por tamanho de fonte
+type HeadingOptions struct {
+	MaxLevel      int     // Quantidade de níveis (tamanhos de fonte distintos) considerados títulos
+	MinSizeRatio  float64 // Razão mínima entre a fonte do título e a fonte do corpo do texto
+	MaxTitleWords int     // Linhas com mais palavras são consideradas texto corrido
+}
@@ -16,14 +16,41 @@ const (
 )
 
 // Document representa um documento PDF
+// Os metadados descritivos (Title, Author etc.) espelham os do PDF da versão atual para permitir buscas
 type Document struct {
-	ID        uuid.UUID      `db:"id"`
-	UserID    uuid.UUID      `db:"user_id"`
-	FilePath  string         `db:"file_path"`
-	Checksum  string         `db:"checksum"`
-	Version   int            `db:"version"`
-	Status    DocumentStatus `db:"status"`
-	PageCount int            `db:"page_count"`
-	CreatedAt time.Time      `db:"created_at"`
-	UpdatedAt time.Time      `db:"updated_at"`
+	ID               uuid.UUID          `db:"id"`
+	UserID           uuid.UUID          `db:"user_id"`
+	FilePath         string             `db:"file_path"`
+	OriginalFilename string             `db:"original_filename"` // Nome do arquivo enviado (ou derivado, em documentos gerados)
+	Checksum         string             `db:"checksum"`
+	Version          int                `db:"version"`
+	Status           DocumentStatus     `db:"status"`
+	PageCount        int                `db:"page_count"`
+	Title            string             `db:"title"`
+	Author           string             `db:"author"`
+	Subject          string             `db:"subject"`
+	Keywords         string             `db:"keywords"`
+	Creator          string             `db:"creator"`
+	Producer         string             `db:"producer"`
+	CustomMetadata   MetadataProperties `db:"custom_metadata"`
+	PDFAConformance  PDFAConformance    `db:"pdfa_conformance"` // Nível PDF/A validado da versão atual (vazio quando não conforme)
+	CreatedAt        time.Time          `db:"created_at"`
+	UpdatedAt        time.Time          `db:"updated_at"`
+}
+
+// DocumentFilter define os filtros da listagem de documentos
+type DocumentFilter struct {
+	Search string // Palavras do nome original e dos metadados (título, autor, assunto e palavras-chave)
+	PDFA   string // Nível PDF/A (1b, 2b ou 3b), "any" para qualquer nível ou "none" para não conformes
+}
+
+// SetMetadata copia os metadados descritivos para o documento
+func (d *Document) SetMetadata(metadata DocumentMetadata) {
+	d.Title = metadata.Title
+	d.Author = metadata.Author
+	d.Subject = metadata.Subject
+	d.Keywords = metadata.Keywords
+	d.Creator = metadata.Creator
+	d.Producer = metadata.Producer
+	d.CustomMetadata = metadata.Custom
 }
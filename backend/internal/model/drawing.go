@@ -0,0 +1,39 @@
+package model
+
+// DrawingShape representa o tipo de forma vetorial de um desenho
+type DrawingShape string
+
+const (
+	DrawingShapeLine      DrawingShape = "line"
+	DrawingShapeRectangle DrawingShape = "rectangle"
+	DrawingShapeEllipse   DrawingShape = "ellipse"
+	DrawingShapePolygon   DrawingShape = "polygon"
+	DrawingShapePolyline  DrawingShape = "polyline" // Traço livre (freehand)
+)
+
+// Point representa um ponto em PDF points, com origem no topo esquerdo da página
+type Point struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// Drawing representa uma forma vetorial a ser desenhada em uma página
+// Coordenadas em PDF points (72 DPI), com y medido a partir do topo da página
+type Drawing struct {
+	Shape DrawingShape `json:"shape"`
+
+	// Caixa delimitadora (usada por rectangle e ellipse)
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+
+	// Vértices (usados por line, polygon e polyline)
+	Points []Point `json:"points,omitempty"`
+
+	StrokeColor string    `json:"strokeColor,omitempty"` // Hex (#RRGGBB), vazio = sem contorno
+	FillColor   string    `json:"fillColor,omitempty"`   // Hex (#RRGGBB), vazio = sem preenchimento
+	LineWidth   float64   `json:"lineWidth"`
+	DashPattern []float64 `json:"dashPattern,omitempty"` // Comprimentos alternados de traço e espaço
+	Opacity     float64   `json:"opacity"`               // Entre 0 e 1
+}
@@ -0,0 +1,18 @@
+package model
+
+// PDFPermissions indica as operações permitidas a quem abre o documento com a senha de usuário
+type PDFPermissions struct {
+	Print    bool `json:"print"`
+	Copy     bool `json:"copy"`     // Copiar e extrair texto e imagens
+	Modify   bool `json:"modify"`   // Alterar o conteúdo e montar o documento (inserir, girar, excluir páginas)
+	Annotate bool `json:"annotate"` // Adicionar anotações e preencher formulários
+}
+
+// EncryptionOptions contém as opções de proteção de um documento (AES-256)
+// A senha de proprietário libera todas as operações; a de usuário, quando vazia, permite abrir
+// o documento sem senha, mas apenas com as permissões informadas
+type EncryptionOptions struct {
+	UserPassword  string
+	OwnerPassword string
+	Permissions   PDFPermissions
+}
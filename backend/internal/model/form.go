@@ -0,0 +1,91 @@
+package model
+
+// FormFieldType representa o tipo de um campo de formulário (AcroForm)
+type FormFieldType string
+
+const (
+	FormFieldText      FormFieldType = "text"
+	FormFieldDate      FormFieldType = "date" // Campo de texto com formatação de data
+	FormFieldCheckbox  FormFieldType = "checkbox"
+	FormFieldRadio     FormFieldType = "radio"
+	FormFieldCombo     FormFieldType = "combo" // Lista suspensa
+	FormFieldList      FormFieldType = "list"
+	FormFieldButton    FormFieldType = "button" // Botão de ação, sem valor
+	FormFieldSignature FormFieldType = "signature"
+)
+
+// FormField representa um campo de formulário e seus widgets (aparições nas páginas)
+type FormField struct {
+	Name  string        `json:"name"` // Nome completo (ex.: "endereco.cidade"), usado para preencher o campo
+	Type  FormFieldType `json:"type"`
+	Label string        `json:"label,omitempty"` // Nome alternativo exibido ao usuário (TU)
+
+	// Valor atual: string (texto, data, radio, combo e list simples), bool (checkbox e assinatura)
+	// ou []string (list com seleção múltipla)
+	Value        interface{} `json:"value"`
+	DefaultValue interface{} `json:"default_value,omitempty"`
+
+	Options     []FormOption `json:"options,omitempty"`
+	ReadOnly    bool         `json:"read_only"`
+	Required    bool         `json:"required"`
+	Multiline   bool         `json:"multiline,omitempty"`
+	MultiSelect bool         `json:"multi_select,omitempty"`
+	Editable    bool         `json:"editable,omitempty"`    // Combo que aceita valores fora das opções
+	MaxLength   int          `json:"max_length,omitempty"`  // 0 = sem limite
+	DateFormat  string       `json:"date_format,omitempty"` // Ex.: "dd/mm/yyyy"
+
+	Widgets []FormWidget `json:"widgets"`
+}
+
+// FormOption representa uma opção de radio, combo ou list
+type FormOption struct {
+	Value string `json:"value"`           // Valor exportado
+	Label string `json:"label,omitempty"` // Texto exibido, quando diferente do valor
+}
+
+// FormWidget representa a aparição de um campo em uma página
+// Retângulo em PDF points (72 DPI), com (X, Y) no canto superior esquerdo
+type FormWidget struct {
+	Page int `json:"page"`
+	TextBox
+	OnState string `json:"on_state,omitempty"` // Valor do estado marcado (checkbox e radio)
+}
+
+// FormDataFormat representa um formato de intercâmbio de valores de formulário
+type FormDataFormat string
+
+const (
+	FormDataFDF  FormDataFormat = "fdf"
+	FormDataXFDF FormDataFormat = "xfdf"
+)
+
+// FormFieldDefinition descreve um campo de formulário a ser criado em uma página
+// Coordenadas em PDF points (72 DPI), com (X, Y) no canto superior esquerdo do widget
+type FormFieldDefinition struct {
+	Name   string
+	Type   FormFieldType // text, checkbox, radio, combo ou signature
+	Page   int
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+
+	// Valor inicial: texto (text e combo), "true"/"false" (checkbox) ou a opção selecionada do grupo (radio)
+	DefaultValue string
+
+	// Valor exportado pelo botão quando marcado (checkbox, padrão "Yes"; radio, obrigatório)
+	// Botões de radio com o mesmo nome formam um grupo
+	ExportValue string
+
+	Options   []string // Opções do combo
+	Editable  bool     // Combo que aceita valores fora das opções
+	Multiline bool
+	MaxLength int // 0 = sem limite
+	ReadOnly  bool
+	Required  bool
+
+	// Fonte padrão do PDF usada pelo campo (helvetica, times ou courier) e tamanho (0 = automático)
+	FontFamily string
+	FontWeight string
+	FontSize   float64
+}
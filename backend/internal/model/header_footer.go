@@ -0,0 +1,31 @@
+package model
+
+// HeaderFooterSlots representa os textos das posições esquerda, central e direita de um cabeçalho ou rodapé
+// Posições vazias não são desenhadas; quebras de linha separam linhas
+type HeaderFooterSlots struct {
+	Left   string `json:"left,omitempty"`
+	Center string `json:"center,omitempty"`
+	Right  string `json:"right,omitempty"`
+}
+
+// IsEmpty indica se nenhuma posição possui texto
+func (s HeaderFooterSlots) IsEmpty() bool {
+	return s.Left == "" && s.Center == "" && s.Right == ""
+}
+
+// PageHeaderFooter representa o cabeçalho e o rodapé de uma página, com os marcadores já resolvidos
+type PageHeaderFooter struct {
+	Page   int
+	Header HeaderFooterSlots
+	Footer HeaderFooterSlots
+}
+
+// HeaderFooterStyle define a fonte e as margens dos cabeçalhos e rodapés, em PDF points
+type HeaderFooterStyle struct {
+	FontFamily string  // helvetica, times ou courier
+	FontWeight string  // normal ou bold
+	FontSize   float64 // Tamanho da fonte
+	Color      string  // Cor do texto (#RRGGBB)
+	MarginX    float64 // Distância das bordas laterais (posições esquerda e direita)
+	MarginY    float64 // Distância das bordas superior (cabeçalho) e inferior (rodapé)
+}
@@ -0,0 +1,45 @@
+package model
+
+// ImagePageSize define o tamanho das páginas geradas a partir de imagens
+type ImagePageSize string
+
+const (
+	ImagePageFit    ImagePageSize = "fit" // Página do tamanho da imagem, pela resolução gravada no arquivo
+	ImagePageA4     ImagePageSize = "a4"
+	ImagePageLetter ImagePageSize = "letter"
+)
+
+// Dimensions retorna largura e altura da página em retrato, em PDF points (false para "fit")
+func (s ImagePageSize) Dimensions() (float64, float64, bool) {
+	switch s {
+	case ImagePageA4:
+		return 595.28, 841.89, true
+	case ImagePageLetter:
+		return 612, 792, true
+	}
+	return 0, 0, false
+}
+
+// ImageOrientation define a orientação das páginas de tamanho fixo (A4 e Letter)
+type ImageOrientation string
+
+const (
+	ImageOrientationAuto      ImageOrientation = "auto" // Paisagem para imagens mais largas que altas
+	ImageOrientationPortrait  ImageOrientation = "portrait"
+	ImageOrientationLandscape ImageOrientation = "landscape"
+)
+
+// ImageConversionOptions contém as opções de montagem de um PDF a partir de imagens
+type ImageConversionOptions struct {
+	PageSize        ImagePageSize
+	Orientation     ImageOrientation
+	Margin          float64 // Em PDF points, em todos os lados
+	AutoRotate      bool    // Aplica a orientação EXIF/TIFF (fotos de celular)
+	JPEGPassthrough bool    // Embute JPEGs sem recompressão; caso contrário, são decodificados e gravados sem perdas
+}
+
+// ImageFile é uma imagem (PNG, JPEG ou TIFF, inclusive com várias páginas) enviada para conversão em PDF
+type ImageFile struct {
+	Name string
+	Data []byte
+}
@@ -0,0 +1,49 @@
+package model
+
+// ImageFormat define o formato das imagens geradas a partir das páginas
+type ImageFormat string
+
+const (
+	ImageFormatPNG  ImageFormat = "png"
+	ImageFormatJPEG ImageFormat = "jpeg"
+	ImageFormatTIFF ImageFormat = "tiff" // Compressão Deflate; várias páginas em um único arquivo com MultiPage
+	ImageFormatWebP ImageFormat = "webp" // Sem perdas (VP8L)
+)
+
+// ContentType retorna o MIME type do formato
+func (f ImageFormat) ContentType() string {
+	switch f {
+	case ImageFormatJPEG:
+		return "image/jpeg"
+	case ImageFormatTIFF:
+		return "image/tiff"
+	case ImageFormatWebP:
+		return "image/webp"
+	}
+	return "image/png"
+}
+
+// Extension retorna a extensão de arquivo do formato, sem o ponto
+func (f ImageFormat) Extension() string {
+	if f == ImageFormatJPEG {
+		return "jpg"
+	}
+	return string(f)
+}
+
+// ImageExportOptions contém as opções de renderização e codificação das páginas em imagens
+type ImageExportOptions struct {
+	Format      ImageFormat
+	DPI         float64 // Resolução da renderização; ignorada quando Width > 0
+	Width       int     // Largura de cada imagem em pixels (a altura segue a proporção da página)
+	JPEGQuality int     // Qualidade JPEG (1 a 100)
+	Grayscale   bool
+	Transparent bool // O fundo branco da página fica transparente (PNG e TIFF)
+	MultiPage   bool // TIFF: todas as páginas em um único arquivo
+}
+
+// PageImage é uma imagem gerada a partir de uma ou mais páginas (várias apenas em TIFF multipágina)
+type PageImage struct {
+	Pages []int
+	Data  []byte
+}
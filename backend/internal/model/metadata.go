@@ -0,0 +1,109 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// DocumentMetadata representa os metadados descritivos de um PDF
+type DocumentMetadata struct {
+	Title        string             `json:"title"`
+	Author       string             `json:"author"`
+	Subject      string             `json:"subject"`
+	Keywords     string             `json:"keywords"`
+	Creator      string             `json:"creator"`  // Aplicação que criou o conteúdo original
+	Producer     string             `json:"producer"` // Aplicação que gerou o PDF
+	CreationDate *time.Time         `json:"creation_date,omitempty"`
+	ModDate      *time.Time         `json:"mod_date,omitempty"`
+	Custom       MetadataProperties `json:"custom,omitempty"` // Propriedades personalizadas (nome -> valor)
+	PDFA         PDFAConformance    `json:"pdfa,omitempty"`   // Nível PDF/A declarado no XMP (pdfaid)
+}
+
+// PDFMetadata contém os metadados lidos do dicionário Info e do pacote XMP de um PDF
+type PDFMetadata struct {
+	Info DocumentMetadata  `json:"info"`
+	XMP  *DocumentMetadata `json:"xmp,omitempty"` // Nulo quando o PDF não possui pacote XMP
+}
+
+// Merged retorna os metadados do dicionário Info, completados pelos do XMP quando ausentes
+func (m PDFMetadata) Merged() DocumentMetadata {
+	merged := m.Info
+	if m.XMP == nil {
+		return merged
+	}
+
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&merged.Title, m.XMP.Title},
+		{&merged.Author, m.XMP.Author},
+		{&merged.Subject, m.XMP.Subject},
+		{&merged.Keywords, m.XMP.Keywords},
+		{&merged.Creator, m.XMP.Creator},
+		{&merged.Producer, m.XMP.Producer},
+	} {
+		if *field.target == "" {
+			*field.target = field.value
+		}
+	}
+	if merged.CreationDate == nil {
+		merged.CreationDate = m.XMP.CreationDate
+	}
+	if merged.ModDate == nil {
+		merged.ModDate = m.XMP.ModDate
+	}
+	merged.PDFA = m.XMP.PDFA
+
+	if len(m.XMP.Custom) > 0 {
+		custom := make(MetadataProperties, len(merged.Custom)+len(m.XMP.Custom))
+		for name, value := range m.XMP.Custom {
+			custom[name] = value
+		}
+		for name, value := range merged.Custom {
+			custom[name] = value
+		}
+		merged.Custom = custom
+	}
+
+	return merged
+}
+
+// InSync verifica se o pacote XMP existe e contém os mesmos valores editáveis do dicionário Info
+// Producer e as datas não são comparados: as ferramentas que reescrevem o PDF os atualizam apenas no Info
+func (m PDFMetadata) InSync() bool {
+	if m.XMP == nil {
+		return false
+	}
+	info, xmp := m.Info, *m.XMP
+	if info.Title != xmp.Title || info.Author != xmp.Author || info.Subject != xmp.Subject ||
+		info.Keywords != xmp.Keywords || info.Creator != xmp.Creator {
+		return false
+	}
+	if len(info.Custom) != len(xmp.Custom) {
+		return false
+	}
+	for name, value := range info.Custom {
+		if xmp.Custom[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// MetadataProperties são propriedades personalizadas de metadados, armazenadas como JSONB
+type MetadataProperties map[string]string
+
+// Value serializa as propriedades para o banco, usando objeto vazio para nil
+func (p MetadataProperties) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p)
+}
+
+// Scan lê as propriedades do banco
+func (p *MetadataProperties) Scan(src interface{}) error {
+	return scanJSON(src, p)
+}
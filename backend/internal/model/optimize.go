@@ -0,0 +1,39 @@
+package model
+
+// OptimizePreset define o perfil de otimização de um documento
+type OptimizePreset string
+
+const (
+	OptimizeScreen OptimizePreset = "screen" // Leitura em tela: menor tamanho
+	OptimizeEbook  OptimizePreset = "ebook"  // Leitura em tela e impressão doméstica
+	OptimizePrint  OptimizePreset = "print"  // Impressão: preserva a qualidade das imagens
+)
+
+// OptimizeOptions contém os parâmetros de reamostragem e recompressão das imagens
+type OptimizeOptions struct {
+	ImageDPI        float64 // Resolução das imagens reamostradas
+	DownsampleAbove float64 // Apenas imagens exibidas acima desta resolução são reamostradas
+	JPEGQuality     int     // Qualidade JPEG das imagens reamostradas (1 a 100)
+}
+
+// optimizePresets são os parâmetros de cada perfil; imagens até 1,5x a resolução alvo são mantidas
+var optimizePresets = map[OptimizePreset]OptimizeOptions{
+	OptimizeScreen: {ImageDPI: 72, DownsampleAbove: 108, JPEGQuality: 60},
+	OptimizeEbook:  {ImageDPI: 150, DownsampleAbove: 225, JPEGQuality: 75},
+	OptimizePrint:  {ImageDPI: 300, DownsampleAbove: 450, JPEGQuality: 90},
+}
+
+// Options retorna os parâmetros do perfil (false quando o perfil não existe)
+func (p OptimizePreset) Options() (OptimizeOptions, bool) {
+	options, ok := optimizePresets[p]
+	return options, ok
+}
+
+// OptimizeReport resume o resultado de uma otimização
+type OptimizeReport struct {
+	SizeBefore        int64 `json:"size_before"` // Em bytes
+	SizeAfter         int64 `json:"size_after"`  // Em bytes; igual a SizeBefore quando não houve redução
+	ImagesDownsampled int   `json:"images_downsampled"`
+	StreamsCompressed int   `json:"streams_compressed"` // Fluxos gravados sem compressão no original
+	DuplicatesRemoved int   `json:"duplicates_removed"` // Fontes e imagens duplicadas substituídas por uma única cópia
+}
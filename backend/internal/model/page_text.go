@@ -0,0 +1,32 @@
+package model
+
+// TextBox é um retângulo em PDF points (72 DPI), com (X, Y) no canto superior esquerdo,
+// no mesmo sistema de coordenadas usado para inserir texto
+type TextBox struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// TextWord representa uma palavra extraída de uma página
+type TextWord struct {
+	Text string `json:"text"`
+	TextBox
+}
+
+// TextLine representa uma linha de texto extraída de uma página
+type TextLine struct {
+	Text string `json:"text"`
+	TextBox
+	Words []TextWord `json:"words"`
+}
+
+// PageText representa o texto extraído de uma página
+type PageText struct {
+	Page   int        `json:"page"`
+	Width  float64    `json:"width"`  // em PDF points (72 DPI)
+	Height float64    `json:"height"` // em PDF points (72 DPI)
+	Text   string     `json:"text"`   // Linhas separadas por \n
+	Lines  []TextLine `json:"lines,omitempty"`
+}
@@ -0,0 +1,47 @@
+package model
+
+// PDFAConformance define a parte e o nível de conformidade PDF/A (ISO 19005)
+type PDFAConformance string
+
+const (
+	PDFA1B PDFAConformance = "1b" // ISO 19005-1: PDF 1.4, sem transparência nem camadas
+	PDFA2B PDFAConformance = "2b" // ISO 19005-2: PDF 1.7, com transparência, camadas e JPEG 2000
+	PDFA3B PDFAConformance = "3b" // ISO 19005-3: PDF/A-2 com arquivos anexos de qualquer formato
+)
+
+// Valid verifica se o nível é suportado
+func (c PDFAConformance) Valid() bool {
+	return c == PDFA1B || c == PDFA2B || c == PDFA3B
+}
+
+// Part retorna a parte da ISO 19005 (1, 2 ou 3)
+func (c PDFAConformance) Part() int {
+	if !c.Valid() {
+		return 0
+	}
+	return int(c[0] - '0')
+}
+
+// PDFAViolation descreve uma regra PDF/A não atendida (ou corrigida na conversão)
+type PDFAViolation struct {
+	Rule        string `json:"rule"` // Referência à cláusula da norma (ex.: "ISO 19005-2:2011, 6.2.11.4")
+	Description string `json:"description"`
+	Page        int    `json:"page,omitempty"`   // Página afetada, quando a regra se aplica a uma página
+	Object      int    `json:"object,omitempty"` // Número do objeto afetado, quando aplicável
+}
+
+// PDFAValidation é o resultado da validação de um documento contra um nível PDF/A
+type PDFAValidation struct {
+	Conformance PDFAConformance `json:"conformance"`
+	Declared    PDFAConformance `json:"declared,omitempty"` // Nível declarado no XMP (pdfaid), vazio quando ausente
+	Compliant   bool            `json:"compliant"`
+	Violations  []PDFAViolation `json:"violations"`
+}
+
+// PDFAConversionReport resume uma conversão para PDF/A: as violações corrigidas e as que permaneceram
+type PDFAConversionReport struct {
+	Conformance   PDFAConformance `json:"conformance"`
+	Fixed         []PDFAViolation `json:"fixed"`
+	Remaining     []PDFAViolation `json:"remaining"`
+	EmbeddedFonts []string        `json:"embedded_fonts,omitempty"` // Fontes não embutidas substituídas (nome original -> substituta)
+}
@@ -0,0 +1,20 @@
+package model
+
+// PreviewClip é uma região da página exibida (com a rotação aplicada), em PDF points (72 DPI),
+// com (X, Y) no canto superior esquerdo da região
+type PreviewClip struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// PreviewOptions contém as opções de renderização da preview de uma página
+type PreviewOptions struct {
+	Format        ImageFormat  // PNG, JPEG ou WebP
+	DPI           float64      // Resolução; ignorada quando Width > 0 (padrão: 150 DPI)
+	Width         int          // Largura da imagem em pixels
+	JPEGQuality   int          // Qualidade JPEG (1 a 100)
+	Clip          *PreviewClip // Renderiza apenas a região; DPI e Width se aplicam à região
+	ThumbnailSize int          // Maior que zero: miniatura que cabe em um quadrado deste tamanho (ignora DPI, Width e Clip)
+}
@@ -0,0 +1,34 @@
+package model
+
+// Redaction representa uma região da página cujo conteúdo deve ser removido definitivamente
+// Coordenadas em PDF points (72 DPI), com (X, Y) no canto superior esquerdo da região
+type Redaction struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+
+	// Preenchimento pintado sobre a região após a remoção (vazio = sem preenchimento)
+	FillColor string
+
+	// Texto opcional sobreposto à região (ex.: "CONFIDENCIAL")
+	OverlayText string
+	TextColor   string
+	FontFamily  string
+	FontSize    float64
+}
+
+// RedactionReport resume o conteúdo removido de uma região
+// Não contém o conteúdo removido, apenas contagens, para não reintroduzir dados sensíveis no log de auditoria
+type RedactionReport struct {
+	Page               int     `json:"page"`
+	X                  float64 `json:"x"`
+	Y                  float64 `json:"y"`
+	Width              float64 `json:"width"`
+	Height             float64 `json:"height"`
+	GlyphsRemoved      int     `json:"glyphs_removed"`
+	ImagesRedacted     int     `json:"images_redacted"` // Imagens com pixels apagados na região
+	ImagesRemoved      int     `json:"images_removed"`  // Imagens removidas por completo
+	PathsRemoved       int     `json:"paths_removed"`   // Caminhos vetoriais e sombreamentos
+	AnnotationsRemoved int     `json:"annotations_removed"`
+}
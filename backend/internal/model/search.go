@@ -0,0 +1,24 @@
+package model
+
+// TextSearch representa uma busca de texto em um PDF
+// A busca ignora maiúsculas/minúsculas e acentos
+type TextSearch struct {
+	Query string
+	Regex bool  // Query é uma expressão regular (sintaxe RE2) em vez de uma frase
+	Pages []int // Páginas pesquisadas, na ordem (vazio = todas)
+	Limit int   // Quantidade máxima de ocorrências (0 = sem limite)
+}
+
+// Quad representa um quadrilátero em PDF points, com origem no topo esquerdo da página
+// Os cantos seguem a orientação do texto: superior esquerdo, superior direito,
+// inferior direito e inferior esquerdo (texto rotacionado gera quadriláteros rotacionados)
+type Quad [4]Point
+
+// SearchHit representa uma ocorrência encontrada na busca
+// Uma ocorrência que atravessa linhas gera um quadrilátero (e um retângulo) por linha
+type SearchHit struct {
+	Page  int       `json:"page"`
+	Text  string    `json:"text"` // Trecho encontrado, como extraído do PDF
+	Quads []Quad    `json:"quads"`
+	Rects []TextBox `json:"rects"` // Retângulos alinhados aos eixos que contêm cada quadrilátero
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"crypto"
+	"crypto/x509"
+	"time"
+)
+
+// CertificateOwner indica a quem pertence o certificado usado na assinatura
+type CertificateOwner string
+
+const (
+	CertificateOwnerUser         CertificateOwner = "user"
+	CertificateOwnerOrganization CertificateOwner = "organization"
+)
+
+// SigningIdentity representa a chave privada e a cadeia de certificados de um signatário,
+// carregadas de um arquivo PKCS#12 mantido no servidor
+type SigningIdentity struct {
+	PrivateKey  crypto.Signer
+	Certificate *x509.Certificate
+	Chain       []*x509.Certificate // Certificados intermediários (sem o do signatário)
+	Owner       CertificateOwner
+}
+
+// SignatureOptions contém as opções de uma assinatura digital PAdES
+// Com Width e Height maiores que zero a assinatura é visível no retângulo informado,
+// com (X, Y) no canto superior esquerdo em PDF points
+type SignatureOptions struct {
+	FieldName   string // Campo de assinatura; um campo de assinatura vazio existente é reutilizado
+	Reason      string
+	Location    string
+	ContactInfo string
+	Page        int
+	X           float64
+	Y           float64
+	Width       float64
+	Height      float64
+}
+
+// SignatureVerification representa o resultado da verificação de uma assinatura do documento
+// Valid indica integridade, cadeia confiável e carimbo do tempo válido (quando presente);
+// alterações posteriores à assinatura são informadas em ModifiedAfterSigning
+type SignatureVerification struct {
+	FieldName   string     `json:"field_name"`
+	Page        int        `json:"page,omitempty"`
+	Visible     bool       `json:"visible"`
+	SubFilter   string     `json:"sub_filter"`
+	SignerName  string     `json:"signer_name,omitempty"`
+	SigningTime *time.Time `json:"signing_time,omitempty"` // Horário declarado pelo signatário (entrada M)
+	Reason      string     `json:"reason,omitempty"`
+	Location    string     `json:"location,omitempty"`
+
+	Valid                bool `json:"valid"`
+	IntegrityValid       bool `json:"integrity_valid"`     // Resumo e assinatura conferem com o conteúdo assinado
+	CertificateTrusted   bool `json:"certificate_trusted"` // Cadeia termina em uma autoridade do repositório confiável
+	CoversWholeDocument  bool `json:"covers_whole_document"`
+	ModifiedAfterSigning bool `json:"modified_after_signing"` // Há atualizações incrementais após a assinatura
+
+	Timestamp    *SignatureTimestamp `json:"timestamp,omitempty"`
+	Certificates []CertificateInfo   `json:"certificates,omitempty"` // Certificado do signatário seguido da cadeia
+	Errors       []string            `json:"errors,omitempty"`
+}
+
+// SignatureTimestamp representa o carimbo do tempo (RFC 3161) de uma assinatura
+type SignatureTimestamp struct {
+	Time      time.Time `json:"time"`
+	Authority string    `json:"authority,omitempty"`
+	Valid     bool      `json:"valid"`
+	Trusted   bool      `json:"trusted"`
+}
+
+// CertificateInfo representa os dados de um certificado X.509
+type CertificateInfo struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+}
@@ -0,0 +1,38 @@
+package model
+
+// TextAlign representa o alinhamento horizontal de um texto
+type TextAlign string
+
+const (
+	TextAlignLeft   TextAlign = "left"
+	TextAlignCenter TextAlign = "center"
+	TextAlignRight  TextAlign = "right"
+)
+
+// TextOverflow representa o comportamento quando o texto não cabe na caixa
+type TextOverflow string
+
+const (
+	TextOverflowVisible TextOverflow = "visible" // O texto ultrapassa a caixa
+	TextOverflowClip    TextOverflow = "clip"    // O texto é cortado nos limites da caixa
+	TextOverflowShrink  TextOverflow = "shrink"  // A fonte é reduzida até o texto caber
+)
+
+// TextOptions contém as opções de formatação de um texto inserido no PDF
+type TextOptions struct {
+	FontFamily string  `json:"fontFamily,omitempty"` // Vazio = fonte Unicode padrão do servidor
+	FontWeight string  `json:"fontWeight,omitempty"` // "normal" ou "bold"
+	FontSize   float64 `json:"fontSize"`
+
+	Color      string    `json:"color,omitempty"` // Hex (#RRGGBB), vazio = preto
+	Rotation   float64   `json:"rotation"`        // Em graus, sentido anti-horário, em torno de (x, y)
+	Opacity    float64   `json:"opacity"`         // Entre 0 e 1
+	LineHeight float64   `json:"lineHeight"`      // Multiplicador do tamanho da fonte
+	Align      TextAlign `json:"align,omitempty"`
+
+	// Caixa de texto: com Width > 0, (x, y) passa a ser o canto superior esquerdo da caixa
+	// e o texto é quebrado em linhas dentro da largura. Height = 0 não limita a altura
+	Width    float64      `json:"width"`
+	Height   float64      `json:"height"`
+	Overflow TextOverflow `json:"overflow,omitempty"`
+}
@@ -0,0 +1,28 @@
+package model
+
+// WatermarkPosition define o posicionamento da marca d'água na página
+type WatermarkPosition string
+
+const (
+	WatermarkCenter      WatermarkPosition = "center"
+	WatermarkDiagonal    WatermarkPosition = "diagonal" // Centralizada, ao longo da diagonal inferior esquerda-superior direita
+	WatermarkTopLeft     WatermarkPosition = "top_left"
+	WatermarkTopRight    WatermarkPosition = "top_right"
+	WatermarkBottomLeft  WatermarkPosition = "bottom_left"
+	WatermarkBottomRight WatermarkPosition = "bottom_right"
+)
+
+// Watermark representa uma marca d'água (ou carimbo) de texto ou imagem aplicada a várias páginas
+// Com ImagePath informado a marca é a imagem; caso contrário, o texto (quebras de linha separam linhas)
+type Watermark struct {
+	Text       string
+	ImagePath  string
+	Position   WatermarkPosition
+	Rotation   *float64 // Graus no sentido anti-horário; nil = horizontal (ou a diagonal da página em "diagonal")
+	Opacity    float64  // 0 a 1
+	Scale      float64  // Largura relativa à página (0 < scale <= 1)
+	Color      string   // Cor do texto (#RRGGBB)
+	FontFamily string   // helvetica, times ou courier
+	FontWeight string
+	Background bool // Atrás do conteúdo da página; caso contrário, sobre o conteúdo (carimbo)
+}
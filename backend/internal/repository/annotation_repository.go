@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// annotationRepository implementa AnnotationRepository usando sqlx
+type annotationRepository struct {
+	db *sqlx.DB
+}
+
+// NewAnnotationRepository cria uma nova instância de AnnotationRepository
+func NewAnnotationRepository(db *sqlx.DB) domain.AnnotationRepository {
+	return &annotationRepository{db: db}
+}
+
+// Create cria uma nova anotação
+// Datas já preenchidas (anotações importadas de PDF ou XFDF) são mantidas
+func (r *annotationRepository) Create(ctx context.Context, annotation *model.Annotation) error {
+	query := `
+		INSERT INTO annotations (id, document_id, user_id, page, type, x, y, width, height, quads, ink_list,
+		                         author, contents, color, opacity, line_width, font_size, created_at, updated_at)
+		VALUES (:id, :document_id, :user_id, :page, :type, :x, :y, :width, :height, :quads, :ink_list,
+		        :author, :contents, :color, :opacity, :line_width, :font_size, :created_at, :updated_at)
+	`
+
+	now := time.Now()
+	if annotation.CreatedAt.IsZero() {
+		annotation.CreatedAt = now
+	}
+	if annotation.UpdatedAt.IsZero() {
+		annotation.UpdatedAt = annotation.CreatedAt
+	}
+
+	if annotation.ID == uuid.Nil {
+		annotation.ID = uuid.New()
+	}
+
+	_, err := r.db.NamedExecContext(ctx, query, annotation)
+	return err
+}
+
+// FindByID busca uma anotação por ID
+func (r *annotationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Annotation, error) {
+	var annotation model.Annotation
+	query := `
+		SELECT id, document_id, user_id, page, type, x, y, width, height, quads, ink_list,
+		       author, contents, color, opacity, line_width, font_size, created_at, updated_at
+		FROM annotations
+		WHERE id = $1
+	`
+
+	err := r.db.GetContext(ctx, &annotation, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &annotation, nil
+}
+
+// FindByDocumentID busca as anotações de um documento, ordenadas por página e data de criação
+func (r *annotationRepository) FindByDocumentID(ctx context.Context, documentID uuid.UUID, page int) ([]*model.Annotation, error) {
+	annotations := []*model.Annotation{}
+	query := `
+		SELECT id, document_id, user_id, page, type, x, y, width, height, quads, ink_list,
+		       author, contents, color, opacity, line_width, font_size, created_at, updated_at
+		FROM annotations
+		WHERE document_id = $1 AND ($2 = 0 OR page = $2)
+		ORDER BY page, created_at, id
+	`
+
+	err := r.db.SelectContext(ctx, &annotations, query, documentID, page)
+	if err != nil {
+		return nil, err
+	}
+
+	return annotations, nil
+}
+
+// Update atualiza uma anotação
+func (r *annotationRepository) Update(ctx context.Context, annotation *model.Annotation) error {
+	query := `
+		UPDATE annotations
+		SET page = :page, x = :x, y = :y, width = :width, height = :height, quads = :quads,
+		    ink_list = :ink_list, author = :author, contents = :contents, color = :color,
+		    opacity = :opacity, line_width = :line_width, font_size = :font_size, updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	annotation.UpdatedAt = time.Now()
+
+	_, err := r.db.NamedExecContext(ctx, query, annotation)
+	return err
+}
+
+// Delete remove uma anotação
+func (r *annotationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM annotations WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
@@ -11,6 +11,18 @@ import (
 	"github.com/jmoiron/sqlx"
 )
 
+// documentColumns lista as colunas lidas de documents
+const documentColumns = `id, user_id, file_path, original_filename, checksum, version, status, page_count,
+		title, author, subject, keywords, creator, producer, custom_metadata, pdfa_conformance, created_at, updated_at`
+
+// documentSearchVector é o texto pesquisável do documento (mesma expressão do índice idx_documents_metadata_search)
+const documentSearchVector = `to_tsvector('simple', original_filename || ' ' || title || ' ' || author || ' ' || subject || ' ' || keywords)`
+
+// documentFilterCondition aplica os filtros da listagem: $1 busca textual e $2 nível PDF/A
+const documentFilterCondition = `($1::text = '' OR ` + documentSearchVector + ` @@ plainto_tsquery('simple', $1))
+		  AND ($2::text = '' OR ($2 = 'any' AND pdfa_conformance <> '') OR ($2 = 'none' AND pdfa_conformance = '')
+		       OR pdfa_conformance = $2)`
+
 // documentRepository implementa DocumentRepository usando sqlx
 type documentRepository struct {
 	db *sqlx.DB
@@ -24,8 +36,12 @@ func NewDocumentRepository(db *sqlx.DB) domain.DocumentRepository {
 // Create cria um novo documento
 func (r *documentRepository) Create(ctx context.Context, document *model.Document) error {
 	query := `
-		INSERT INTO documents (id, user_id, file_path, checksum, version, status, page_count, created_at, updated_at)
-		VALUES (:id, :user_id, :file_path, :checksum, :version, :status, :page_count, :created_at, :updated_at)
+		INSERT INTO documents (id, user_id, file_path, original_filename, checksum, version, status, page_count,
+		                       title, author, subject, keywords, creator, producer, custom_metadata, pdfa_conformance,
+		                       created_at, updated_at)
+		VALUES (:id, :user_id, :file_path, :original_filename, :checksum, :version, :status, :page_count,
+		        :title, :author, :subject, :keywords, :creator, :producer, :custom_metadata, :pdfa_conformance,
+		        :created_at, :updated_at)
 	`
 
 	now := time.Now()
@@ -56,7 +72,7 @@ func (r *documentRepository) Create(ctx context.Context, document *model.Documen
 func (r *documentRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Document, error) {
 	var document model.Document
 	query := `
-		SELECT id, user_id, file_path, checksum, version, status, page_count, created_at, updated_at 
+		SELECT ` + documentColumns + `
 		FROM documents 
 		WHERE id = $1
 	`
@@ -74,24 +90,25 @@ func (r *documentRepository) FindByID(ctx context.Context, id uuid.UUID) (*model
 
 // FindByUserID busca todos os documentos de um usuário
 // NOTA: Como não há autenticação, lista todos os documentos (ignora userID)
-func (r *documentRepository) FindByUserID(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*model.Document, int, error) {
+func (r *documentRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter model.DocumentFilter, limit, offset int) ([]*model.Document, int, error) {
 	var documents []*model.Document
 	query := `
-		SELECT id, user_id, file_path, checksum, version, status, page_count, created_at, updated_at 
+		SELECT ` + documentColumns + `
 		FROM documents 
+		WHERE ` + documentFilterCondition + `
 		ORDER BY created_at DESC 
-		LIMIT $1 OFFSET $2
+		LIMIT $3 OFFSET $4
 	`
 
-	err := r.db.SelectContext(ctx, &documents, query, limit, offset)
+	err := r.db.SelectContext(ctx, &documents, query, filter.Search, filter.PDFA, limit, offset)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	// Conta total de documentos
 	var total int
-	countQuery := `SELECT COUNT(*) FROM documents`
-	err = r.db.GetContext(ctx, &total, countQuery)
+	countQuery := `SELECT COUNT(*) FROM documents WHERE ` + documentFilterCondition
+	err = r.db.GetContext(ctx, &total, countQuery, filter.Search, filter.PDFA)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -103,8 +120,10 @@ func (r *documentRepository) FindByUserID(ctx context.Context, userID uuid.UUID,
 func (r *documentRepository) Update(ctx context.Context, document *model.Document) error {
 	query := `
 		UPDATE documents 
-		SET file_path = :file_path, checksum = :checksum, version = :version, 
-		    status = :status, page_count = :page_count, updated_at = :updated_at
+		SET file_path = :file_path, original_filename = :original_filename, checksum = :checksum, version = :version, 
+		    status = :status, page_count = :page_count, title = :title, author = :author, subject = :subject,
+		    keywords = :keywords, creator = :creator, producer = :producer, custom_metadata = :custom_metadata,
+		    pdfa_conformance = :pdfa_conformance, updated_at = :updated_at
 		WHERE id = :id
 	`
 
@@ -0,0 +1,468 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Valores padrão das anotações, em PDF points
+const (
+	defaultNoteSize            = 24.0
+	defaultAnnotationLineWidth = 2.0
+	defaultAnnotationFontSize  = 12.0
+)
+
+// defaultAnnotationColors define a cor padrão de cada tipo de anotação
+var defaultAnnotationColors = map[model.AnnotationType]string{
+	model.AnnotationHighlight: "#FFFF00",
+	model.AnnotationUnderline: "#00A000",
+	model.AnnotationStrikeOut: "#FF0000",
+	model.AnnotationNote:      "#FFFF00",
+	model.AnnotationFreeText:  "#000000",
+	model.AnnotationInk:       "#FF0000",
+}
+
+// ListAnnotations lista as anotações do documento, de uma página (page > 0) ou de todas
+func (uc *DocumentUseCase) ListAnnotations(ctx context.Context, documentID, userID uuid.UUID, page int) (*dto.AnnotationListResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	annotations, err := uc.annotationRepo.FindByDocumentID(ctx, documentID, page)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar anotações: %w", err)
+	}
+
+	items := make([]model.Annotation, 0, len(annotations))
+	for _, annotation := range annotations {
+		items = append(items, *annotation)
+	}
+
+	return &dto.AnnotationListResponse{
+		DocumentID:  document.ID.String(),
+		Version:     document.Version,
+		Annotations: items,
+		Total:       len(items),
+	}, nil
+}
+
+// CreateAnnotation cria uma anotação no documento; o PDF só é alterado por ApplyAnnotations
+func (uc *DocumentUseCase) CreateAnnotation(ctx context.Context, documentID, userID uuid.UUID, req dto.AnnotationRequest) (*model.Annotation, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation := &model.Annotation{
+		ID:         uuid.New(),
+		DocumentID: documentID,
+		UserID:     userID,
+	}
+	if err := buildAnnotation(annotation, req, document.PageCount); err != nil {
+		return nil, err
+	}
+
+	if err := uc.annotationRepo.Create(ctx, annotation); err != nil {
+		return nil, fmt.Errorf("erro ao criar anotação: %w", err)
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "ANNOTATION_CREATE", map[string]interface{}{
+		"annotation_id": annotation.ID.String(),
+		"type":          annotation.Type,
+		"page":          annotation.Page,
+	})
+
+	return annotation, nil
+}
+
+// UpdateAnnotation substitui os dados de uma anotação do documento, mantendo ID e data de criação
+func (uc *DocumentUseCase) UpdateAnnotation(ctx context.Context, documentID, annotationID, userID uuid.UUID, req dto.AnnotationRequest) (*model.Annotation, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	annotation, err := uc.findDocumentAnnotation(ctx, documentID, annotationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := buildAnnotation(annotation, req, document.PageCount); err != nil {
+		return nil, err
+	}
+
+	if err := uc.annotationRepo.Update(ctx, annotation); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar anotação: %w", err)
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "ANNOTATION_UPDATE", map[string]interface{}{
+		"annotation_id": annotation.ID.String(),
+		"type":          annotation.Type,
+		"page":          annotation.Page,
+	})
+
+	return annotation, nil
+}
+
+// DeleteAnnotation remove uma anotação do documento
+func (uc *DocumentUseCase) DeleteAnnotation(ctx context.Context, documentID, annotationID, userID uuid.UUID) error {
+	if _, err := uc.findOwnedDocument(ctx, documentID, userID); err != nil {
+		return err
+	}
+
+	annotation, err := uc.findDocumentAnnotation(ctx, documentID, annotationID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.annotationRepo.Delete(ctx, annotation.ID); err != nil {
+		return fmt.Errorf("erro ao remover anotação: %w", err)
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "ANNOTATION_DELETE", map[string]interface{}{
+		"annotation_id": annotation.ID.String(),
+		"type":          annotation.Type,
+		"page":          annotation.Page,
+	})
+
+	return nil
+}
+
+// ApplyAnnotations grava as anotações do documento no PDF como anotações nativas, gerando uma nova versão
+// As anotações dos tipos suportados já existentes no PDF são substituídas pelas do documento
+func (uc *DocumentUseCase) ApplyAnnotations(ctx context.Context, documentID, userID uuid.UUID) (*dto.DocumentResponse, error) {
+	if _, err := uc.findOwnedDocument(ctx, documentID, userID); err != nil {
+		return nil, err
+	}
+
+	annotations, err := uc.documentAnnotations(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+
+	document, err := uc.transformDocument(ctx, documentID, userID, "annotations", func(filePath string) error {
+		return uc.pdfProcessor.WriteAnnotations(ctx, filePath, annotations)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "ANNOTATIONS_APPLY", map[string]interface{}{
+		"version":           document.Version,
+		"annotations_count": len(annotations),
+	})
+
+	logger.Logger.Info("Anotações gravadas no documento",
+		zap.String("document_id", documentID.String()),
+		zap.Int("version", document.Version),
+		zap.Int("annotations_count", len(annotations)),
+	)
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+	return uc.toDocumentResponse(document, fileURL), nil
+}
+
+// ExportAnnotations exporta as anotações do documento em XFDF
+// Retorna o conteúdo e o nome sugerido para o arquivo
+func (uc *DocumentUseCase) ExportAnnotations(ctx context.Context, documentID, userID uuid.UUID) ([]byte, string, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	annotations, err := uc.documentAnnotations(ctx, documentID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	data, err := uc.pdfProcessor.ExportAnnotationsXFDF(ctx, fullPath, annotations)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao exportar anotações: %w", err)
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "ANNOTATIONS_EXPORT", map[string]interface{}{
+		"version":           document.Version,
+		"annotations_count": len(annotations),
+	})
+
+	filename := fmt.Sprintf("%s_v%d_annotations.xfdf", document.ID.String(), document.Version)
+	return data, filename, nil
+}
+
+// ImportAnnotations importa as anotações de um XFDF para o documento
+// Anotações cujo nome é o ID de uma anotação do documento a atualizam; as demais são criadas
+func (uc *DocumentUseCase) ImportAnnotations(ctx context.Context, documentID, userID uuid.UUID, data []byte) (*dto.ImportAnnotationsResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	annotations, err := uc.pdfProcessor.ParseAnnotationsXFDF(ctx, fullPath, data)
+	if err != nil {
+		logger.Logger.Warn("XFDF de anotações inválido",
+			zap.String("document_id", documentID.String()),
+			zap.Error(err),
+		)
+		return nil, errors.New("arquivo XFDF de anotações inválido")
+	}
+
+	created, updated, err := uc.saveImportedAnnotations(ctx, document, userID, annotations)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "ANNOTATIONS_IMPORT", map[string]interface{}{
+		"version": document.Version,
+		"created": created,
+		"updated": updated,
+	})
+
+	logger.Logger.Info("Anotações importadas de XFDF",
+		zap.String("document_id", documentID.String()),
+		zap.Int("created", created),
+		zap.Int("updated", updated),
+	)
+
+	return &dto.ImportAnnotationsResponse{Created: created, Updated: updated}, nil
+}
+
+// importPDFAnnotations lê as anotações existentes no PDF de um documento recém-criado para o modelo
+// Falhas são apenas registradas: o documento continua válido sem as anotações importadas
+func (uc *DocumentUseCase) importPDFAnnotations(ctx context.Context, document *model.Document) {
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	annotations, err := uc.pdfProcessor.ExtractAnnotations(ctx, fullPath)
+	if err == nil && len(annotations) > 0 {
+		_, _, err = uc.saveImportedAnnotations(ctx, document, document.UserID, annotations)
+	}
+	if err != nil {
+		logger.Logger.Warn("Erro ao importar anotações do PDF",
+			zap.String("document_id", document.ID.String()),
+			zap.Error(err),
+		)
+	}
+}
+
+// saveImportedAnnotations grava anotações lidas de um PDF ou XFDF, atualizando as que já pertencem ao documento
+func (uc *DocumentUseCase) saveImportedAnnotations(ctx context.Context, document *model.Document, userID uuid.UUID, annotations []model.Annotation) (int, int, error) {
+	created, updated := 0, 0
+	for i := range annotations {
+		annotation := &annotations[i]
+		annotation.DocumentID = document.ID
+		annotation.UserID = userID
+		if annotation.Color == "" {
+			annotation.Color = defaultAnnotationColors[annotation.Type]
+		}
+
+		if annotation.ID != uuid.Nil {
+			existing, err := uc.annotationRepo.FindByID(ctx, annotation.ID)
+			if err != nil {
+				return created, updated, fmt.Errorf("erro ao buscar anotação: %w", err)
+			}
+			if existing != nil && existing.DocumentID == document.ID {
+				annotation.CreatedAt = existing.CreatedAt
+				if err := uc.annotationRepo.Update(ctx, annotation); err != nil {
+					return created, updated, fmt.Errorf("erro ao atualizar anotação: %w", err)
+				}
+				updated++
+				continue
+			}
+			if existing != nil {
+				// O ID pertence a outro documento (ex.: PDF gerado a partir de um documento anotado)
+				annotation.ID = uuid.New()
+			}
+		}
+
+		if err := uc.annotationRepo.Create(ctx, annotation); err != nil {
+			return created, updated, fmt.Errorf("erro ao criar anotação: %w", err)
+		}
+		created++
+	}
+
+	return created, updated, nil
+}
+
+// remapAnnotations ajusta as páginas das anotações do documento após a reorganização das páginas
+// order lista, para cada página resultante, a página original (0 = página nova): anotações de páginas
+// removidas são excluídas e as de páginas duplicadas são copiadas para cada cópia
+func (uc *DocumentUseCase) remapAnnotations(ctx context.Context, documentID uuid.UUID, order []int) error {
+	newPages := make(map[int][]int)
+	for i, original := range order {
+		if original > 0 {
+			newPages[original] = append(newPages[original], i+1)
+		}
+	}
+
+	annotations, err := uc.annotationRepo.FindByDocumentID(ctx, documentID, 0)
+	if err != nil {
+		return fmt.Errorf("erro ao listar anotações: %w", err)
+	}
+
+	for _, annotation := range annotations {
+		pages := newPages[annotation.Page]
+		if len(pages) == 0 {
+			if err := uc.annotationRepo.Delete(ctx, annotation.ID); err != nil {
+				return fmt.Errorf("erro ao remover anotação: %w", err)
+			}
+			continue
+		}
+
+		for _, page := range pages[1:] {
+			duplicate := *annotation
+			duplicate.ID = uuid.New()
+			duplicate.Page = page
+			if err := uc.annotationRepo.Create(ctx, &duplicate); err != nil {
+				return fmt.Errorf("erro ao copiar anotação: %w", err)
+			}
+		}
+
+		if pages[0] != annotation.Page {
+			annotation.Page = pages[0]
+			if err := uc.annotationRepo.Update(ctx, annotation); err != nil {
+				return fmt.Errorf("erro ao atualizar anotação: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// documentAnnotations retorna as anotações do documento na ordem das páginas
+func (uc *DocumentUseCase) documentAnnotations(ctx context.Context, documentID uuid.UUID) ([]model.Annotation, error) {
+	stored, err := uc.annotationRepo.FindByDocumentID(ctx, documentID, 0)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar anotações: %w", err)
+	}
+
+	annotations := make([]model.Annotation, 0, len(stored))
+	for _, annotation := range stored {
+		annotations = append(annotations, *annotation)
+	}
+	return annotations, nil
+}
+
+// findDocumentAnnotation busca uma anotação e verifica se pertence ao documento
+func (uc *DocumentUseCase) findDocumentAnnotation(ctx context.Context, documentID, annotationID uuid.UUID) (*model.Annotation, error) {
+	annotation, err := uc.annotationRepo.FindByID(ctx, annotationID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar anotação: %w", err)
+	}
+
+	if annotation == nil || annotation.DocumentID != documentID {
+		return nil, errors.New("anotação não encontrada")
+	}
+
+	return annotation, nil
+}
+
+// buildAnnotation valida a requisição e preenche a anotação, calculando o retângulo que contém
+// os quadriláteros ou traços e aplicando os valores padrão do tipo
+func buildAnnotation(annotation *model.Annotation, req dto.AnnotationRequest, pageCount int) error {
+	if pageCount > 0 && req.Page > pageCount {
+		return fmt.Errorf("página inválida: %d (documento tem %d páginas)", req.Page, pageCount)
+	}
+
+	annotationType := model.AnnotationType(req.Type)
+	annotation.Page = req.Page
+	annotation.Type = annotationType
+	annotation.Author = req.Author
+	annotation.Contents = req.Contents
+	annotation.Quads = nil
+	annotation.InkList = nil
+	annotation.LineWidth = 0
+	annotation.FontSize = 0
+
+	annotation.Color = req.Color
+	if annotation.Color == "" {
+		annotation.Color = defaultAnnotationColors[annotationType]
+	}
+	annotation.Opacity = 1
+	if req.Opacity != nil {
+		annotation.Opacity = *req.Opacity
+	}
+
+	switch {
+	case annotationType.IsTextMarkup():
+		if len(req.Quads) == 0 {
+			return errors.New("informe os quadriláteros (quads) do trecho anotado")
+		}
+		annotation.Quads = req.Quads
+
+		var points []model.Point
+		for _, quad := range req.Quads {
+			points = append(points, quad[:]...)
+		}
+		setAnnotationBounds(annotation, points, 0)
+
+	case annotationType == model.AnnotationNote:
+		annotation.X, annotation.Y = req.X, req.Y
+		annotation.Width, annotation.Height = defaultNoteSize, defaultNoteSize
+
+	case annotationType == model.AnnotationFreeText:
+		if req.Width <= 0 || req.Height <= 0 {
+			return errors.New("informe largura e altura do texto livre")
+		}
+		if req.Contents == "" {
+			return errors.New("informe o texto (contents) do texto livre")
+		}
+		// A aparição do texto livre usa a Helvetica padrão do PDF (WinAnsiEncoding)
+		if _, err := charmap.Windows1252.NewEncoder().String(req.Contents); err != nil {
+			return errors.New("texto livre contém caracteres não suportados pelas fontes padrão do PDF")
+		}
+		annotation.X, annotation.Y = req.X, req.Y
+		annotation.Width, annotation.Height = req.Width, req.Height
+		annotation.FontSize = defaultAnnotationFontSize
+		if req.FontSize != nil {
+			annotation.FontSize = *req.FontSize
+		}
+
+	case annotationType == model.AnnotationInk:
+		if len(req.InkList) == 0 {
+			return errors.New("informe os traços (inkList) da anotação de tinta")
+		}
+		annotation.LineWidth = defaultAnnotationLineWidth
+		if req.LineWidth != nil {
+			annotation.LineWidth = *req.LineWidth
+		}
+
+		var points []model.Point
+		for _, stroke := range req.InkList {
+			path := make([]model.Point, 0, len(stroke))
+			for _, point := range stroke {
+				path = append(path, model.Point{X: point.X, Y: point.Y})
+			}
+			annotation.InkList = append(annotation.InkList, path)
+			points = append(points, path...)
+		}
+		setAnnotationBounds(annotation, points, annotation.LineWidth/2)
+	}
+
+	return nil
+}
+
+// setAnnotationBounds define o retângulo da anotação como o menor que contém os pontos, com margem
+func setAnnotationBounds(annotation *model.Annotation, points []model.Point, margin float64) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, point := range points {
+		minX, maxX = math.Min(minX, point.X), math.Max(maxX, point.X)
+		minY, maxY = math.Min(minY, point.Y), math.Max(maxY, point.Y)
+	}
+
+	annotation.X = minX - margin
+	annotation.Y = minY - margin
+	annotation.Width = maxX - minX + 2*margin
+	annotation.Height = maxY - minY + 2*margin
+}
@@ -0,0 +1,464 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Tipos de operação aceitos por EditBookmarks
+const (
+	bookmarkOpAdd       = "add"
+	bookmarkOpRename    = "rename"
+	bookmarkOpMove      = "move"
+	bookmarkOpDelete    = "delete"
+	bookmarkOpSetOpen   = "set_open"
+	bookmarkOpSetTarget = "set_target"
+)
+
+// defaultHeadingLevels é a quantidade de níveis de títulos usada ao gerar marcadores
+const defaultHeadingLevels = 3
+
+// ListBookmarks retorna a árvore de marcadores da versão atual do documento, com o caminho de cada item
+func (uc *DocumentUseCase) ListBookmarks(ctx context.Context, documentID, userID uuid.UUID) (*dto.BookmarksResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks, err := uc.documentBookmarks(ctx, document)
+	if err != nil {
+		return nil, err
+	}
+	assignBookmarkPaths(bookmarks, "")
+
+	return &dto.BookmarksResponse{
+		DocumentID: document.ID.String(),
+		Version:    document.Version,
+		Bookmarks:  bookmarks,
+		Total:      countBookmarks(bookmarks),
+	}, nil
+}
+
+// ReplaceBookmarks substitui toda a árvore de marcadores, gerando uma nova versão
+func (uc *DocumentUseCase) ReplaceBookmarks(ctx context.Context, documentID, userID uuid.UUID, req dto.ReplaceBookmarksRequest) (*dto.DocumentResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks := make([]model.Bookmark, 0, len(req.Bookmarks))
+	for _, item := range req.Bookmarks {
+		bookmark, err := toBookmark(item, document.PageCount)
+		if err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+
+	return uc.saveBookmarks(ctx, documentID, userID, "BOOKMARKS_REPLACE", bookmarks, map[string]interface{}{})
+}
+
+// EditBookmarks aplica as operações em ordem sobre a árvore de marcadores atual e grava o resultado
+// em uma única nova versão; qualquer operação inválida cancela todas
+func (uc *DocumentUseCase) EditBookmarks(ctx context.Context, documentID, userID uuid.UUID, req dto.EditBookmarksRequest) (*dto.DocumentResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks, err := uc.documentBookmarks(ctx, document)
+	if err != nil {
+		return nil, err
+	}
+
+	operations := make([]string, 0, len(req.Operations))
+	for i, operation := range req.Operations {
+		if bookmarks, err = applyBookmarkOperation(bookmarks, operation, document.PageCount); err != nil {
+			return nil, fmt.Errorf("operação %d (%s): %w", i+1, operation.Type, err)
+		}
+		operations = append(operations, operation.Type)
+	}
+
+	return uc.saveBookmarks(ctx, documentID, userID, "BOOKMARKS_EDIT", bookmarks, map[string]interface{}{
+		"operations": operations,
+	})
+}
+
+// GenerateBookmarks gera marcadores a partir dos títulos detectados pelo tamanho da fonte, substituindo
+// os marcadores atuais; com req.Preview apenas retorna os marcadores propostos
+func (uc *DocumentUseCase) GenerateBookmarks(ctx context.Context, documentID, userID uuid.UUID, req dto.GenerateBookmarksRequest) (*dto.GenerateBookmarksResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	options := model.HeadingOptions{MaxLevel: req.MaxLevel, MinSizeRatio: req.MinSizeRatio}
+	if options.MaxLevel == 0 {
+		options.MaxLevel = defaultHeadingLevels
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	headings, err := uc.pdfProcessor.DetectHeadings(ctx, fullPath, options)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao detectar títulos: %w", err)
+	}
+	if len(headings) == 0 {
+		return nil, errors.New("nenhum título encontrado no texto do documento")
+	}
+
+	bookmarks := bookmarksFromHeadings(headings)
+	result := &dto.GenerateBookmarksResponse{Headings: headings}
+
+	if !req.Preview {
+		response, err := uc.saveBookmarks(ctx, documentID, userID, "BOOKMARKS_GENERATE", bookmarks, map[string]interface{}{
+			"headings_count": len(headings),
+			"max_level":      options.MaxLevel,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.Document = response
+	}
+
+	assignBookmarkPaths(bookmarks, "")
+	result.Bookmarks = bookmarks
+	return result, nil
+}
+
+// saveBookmarks grava a árvore de marcadores em uma nova versão do documento e registra a auditoria
+func (uc *DocumentUseCase) saveBookmarks(ctx context.Context, documentID, userID uuid.UUID, action string, bookmarks []model.Bookmark, details map[string]interface{}) (*dto.DocumentResponse, error) {
+	document, err := uc.transformDocument(ctx, documentID, userID, "bookmarks", func(filePath string) error {
+		return uc.pdfProcessor.WriteBookmarks(ctx, filePath, bookmarks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	total := countBookmarks(bookmarks)
+	details["version"] = document.Version
+	details["bookmarks_count"] = total
+	uc.createAuditLog(ctx, documentID, userID, action, details)
+
+	logger.Logger.Info("Marcadores gravados no documento",
+		zap.String("document_id", documentID.String()),
+		zap.String("action", action),
+		zap.Int("version", document.Version),
+		zap.Int("bookmarks_count", total),
+	)
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+	return uc.toDocumentResponse(document, fileURL), nil
+}
+
+// documentBookmarks lê os marcadores da versão atual do documento
+func (uc *DocumentUseCase) documentBookmarks(ctx context.Context, document *model.Document) ([]model.Bookmark, error) {
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	bookmarks, err := uc.pdfProcessor.ExtractBookmarks(ctx, fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler marcadores: %w", err)
+	}
+	return bookmarks, nil
+}
+
+// applyBookmarkOperation aplica uma operação de edição e retorna a árvore resultante
+func applyBookmarkOperation(bookmarks []model.Bookmark, operation dto.BookmarkOperation, pageCount int) ([]model.Bookmark, error) {
+	if operation.Type == bookmarkOpAdd {
+		if operation.Bookmark == nil {
+			return nil, errors.New("informe o marcador (bookmark) a adicionar")
+		}
+		bookmark, err := toBookmark(*operation.Bookmark, pageCount)
+		if err != nil {
+			return nil, err
+		}
+		parent, err := parseBookmarkPath(operation.Parent, true)
+		if err != nil {
+			return nil, err
+		}
+		return insertBookmark(bookmarks, parent, operation.Position, bookmark)
+	}
+
+	path, err := parseBookmarkPath(operation.Path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Type {
+	case bookmarkOpDelete:
+		bookmarks, _, err = removeBookmark(bookmarks, path)
+		return bookmarks, err
+
+	case bookmarkOpMove:
+		parent, err := parseBookmarkPath(operation.Parent, true)
+		if err != nil {
+			return nil, err
+		}
+		if isBookmarkPathPrefix(path, parent) {
+			return nil, errors.New("um marcador não pode ser movido para dentro dele mesmo")
+		}
+
+		var bookmark model.Bookmark
+		if bookmarks, bookmark, err = removeBookmark(bookmarks, path); err != nil {
+			return nil, err
+		}
+		// A remoção desloca os irmãos seguintes do item movido, inclusive um possível ancestral do destino
+		depth := len(path) - 1
+		if len(parent) > depth && isBookmarkPathPrefix(path[:depth], parent) && parent[depth] > path[depth] {
+			parent[depth]--
+		}
+		return insertBookmark(bookmarks, parent, operation.Position, bookmark)
+	}
+
+	bookmark, err := findBookmark(bookmarks, path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Type {
+	case bookmarkOpRename:
+		title := strings.TrimSpace(operation.Title)
+		if title == "" {
+			return nil, errors.New("informe o novo título (title) do marcador")
+		}
+		bookmark.Title = title
+
+	case bookmarkOpSetOpen:
+		if operation.Open == nil {
+			return nil, errors.New("informe o estado (open) do marcador")
+		}
+		bookmark.Open = *operation.Open
+
+	case bookmarkOpSetTarget:
+		if operation.Target == nil {
+			return nil, errors.New("informe o destino (target) do marcador")
+		}
+		if err := setBookmarkTarget(bookmark, *operation.Target, pageCount); err != nil {
+			return nil, err
+		}
+	}
+
+	return bookmarks, nil
+}
+
+// toBookmark converte um marcador da requisição (e seus filhos) para o modelo
+func toBookmark(req dto.BookmarkRequest, pageCount int) (model.Bookmark, error) {
+	bookmark := model.Bookmark{
+		Title:  strings.TrimSpace(req.Title),
+		Open:   req.Open,
+		Bold:   req.Bold,
+		Italic: req.Italic,
+		Color:  req.Color,
+	}
+	if bookmark.Title == "" {
+		return bookmark, errors.New("informe o título (title) do marcador")
+	}
+	if err := setBookmarkTarget(&bookmark, req.BookmarkTarget, pageCount); err != nil {
+		return bookmark, err
+	}
+
+	for _, child := range req.Children {
+		childBookmark, err := toBookmark(child, pageCount)
+		if err != nil {
+			return bookmark, err
+		}
+		bookmark.Children = append(bookmark.Children, childBookmark)
+	}
+
+	return bookmark, nil
+}
+
+// setBookmarkTarget valida e define o destino do marcador; sem visualização informada usa xyz quando
+// há posição e fit caso contrário
+func setBookmarkTarget(bookmark *model.Bookmark, target dto.BookmarkTarget, pageCount int) error {
+	if pageCount > 0 && target.Page > pageCount {
+		return fmt.Errorf("página inválida: %d (documento tem %d páginas)", target.Page, pageCount)
+	}
+
+	view := model.BookmarkView(target.View)
+	if view == "" {
+		view = model.BookmarkViewFit
+		if target.Left != nil || target.Top != nil || target.Zoom > 0 {
+			view = model.BookmarkViewXYZ
+		}
+	}
+
+	bookmark.Page = target.Page
+	bookmark.View = view
+	bookmark.Left = target.Left
+	bookmark.Top = target.Top
+	bookmark.Zoom = target.Zoom
+	return nil
+}
+
+// bookmarksFromHeadings monta a árvore de marcadores pelos níveis dos títulos: cada título é filho
+// do último título de nível menor que o seu
+func bookmarksFromHeadings(headings []model.Heading) []model.Bookmark {
+	var roots []model.Bookmark
+	// path guarda os índices do último marcador de cada nível aberto
+	var path []int
+	var levels []int
+
+	for _, heading := range headings {
+		top := heading.Top
+		bookmark := model.Bookmark{
+			Title: heading.Title,
+			Page:  heading.Page,
+			View:  model.BookmarkViewXYZ,
+			Top:   &top,
+		}
+
+		for len(levels) > 0 && levels[len(levels)-1] >= heading.Level {
+			levels = levels[:len(levels)-1]
+			path = path[:len(path)-1]
+		}
+
+		siblings := &roots
+		for _, index := range path {
+			siblings = &(*siblings)[index].Children
+		}
+		*siblings = append(*siblings, bookmark)
+
+		path = append(path, len(*siblings)-1)
+		levels = append(levels, heading.Level)
+	}
+
+	return roots
+}
+
+// parseBookmarkPath converte um caminho ("2.1") em índices a partir de zero
+// Caminho vazio só é aceito quando indica a raiz (allowRoot)
+func parseBookmarkPath(path string, allowRoot bool) ([]int, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		if allowRoot {
+			return nil, nil
+		}
+		return nil, errors.New("informe o caminho (path) do marcador")
+	}
+
+	parts := strings.Split(path, ".")
+	indexes := make([]int, 0, len(parts))
+	for _, part := range parts {
+		index, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || index < 1 {
+			return nil, fmt.Errorf("caminho de marcador inválido: %s", path)
+		}
+		indexes = append(indexes, index-1)
+	}
+
+	return indexes, nil
+}
+
+// isBookmarkPathPrefix verifica se prefix é o próprio caminho ou um ancestral de path
+func isBookmarkPathPrefix(prefix, path []int) bool {
+	if len(prefix) > len(path) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// formatBookmarkPath converte índices a partir de zero no caminho exibido ao usuário
+func formatBookmarkPath(path []int) string {
+	parts := make([]string, 0, len(path))
+	for _, index := range path {
+		parts = append(parts, strconv.Itoa(index+1))
+	}
+	return strings.Join(parts, ".")
+}
+
+// bookmarkChildren retorna a lista de filhos do marcador no caminho (a raiz quando vazio)
+func bookmarkChildren(bookmarks *[]model.Bookmark, path []int) (*[]model.Bookmark, error) {
+	list := bookmarks
+	for depth, index := range path {
+		if index >= len(*list) {
+			return nil, fmt.Errorf("marcador não encontrado: %s", formatBookmarkPath(path[:depth+1]))
+		}
+		list = &(*list)[index].Children
+	}
+	return list, nil
+}
+
+// findBookmark retorna o marcador do caminho para edição
+func findBookmark(bookmarks []model.Bookmark, path []int) (*model.Bookmark, error) {
+	siblings, err := bookmarkChildren(&bookmarks, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	index := path[len(path)-1]
+	if index >= len(*siblings) {
+		return nil, fmt.Errorf("marcador não encontrado: %s", formatBookmarkPath(path))
+	}
+	return &(*siblings)[index], nil
+}
+
+// removeBookmark remove o marcador do caminho (com seus filhos) e o retorna
+func removeBookmark(bookmarks []model.Bookmark, path []int) ([]model.Bookmark, model.Bookmark, error) {
+	siblings, err := bookmarkChildren(&bookmarks, path[:len(path)-1])
+	if err != nil {
+		return nil, model.Bookmark{}, err
+	}
+
+	index := path[len(path)-1]
+	if index >= len(*siblings) {
+		return nil, model.Bookmark{}, fmt.Errorf("marcador não encontrado: %s", formatBookmarkPath(path))
+	}
+
+	removed := (*siblings)[index]
+	*siblings = append((*siblings)[:index:index], (*siblings)[index+1:]...)
+	return bookmarks, removed, nil
+}
+
+// insertBookmark insere o marcador entre os filhos de parent na posição informada (1 = primeiro;
+// nula = último)
+func insertBookmark(bookmarks []model.Bookmark, parent []int, position *int, bookmark model.Bookmark) ([]model.Bookmark, error) {
+	siblings, err := bookmarkChildren(&bookmarks, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	index := len(*siblings)
+	if position != nil {
+		if *position < 1 || *position > len(*siblings)+1 {
+			return nil, fmt.Errorf("posição inválida: %d (o nível tem %d marcadores)", *position, len(*siblings))
+		}
+		index = *position - 1
+	}
+
+	*siblings = append((*siblings)[:index:index], append([]model.Bookmark{bookmark}, (*siblings)[index:]...)...)
+	return bookmarks, nil
+}
+
+// assignBookmarkPaths preenche o caminho de cada marcador na árvore
+func assignBookmarkPaths(bookmarks []model.Bookmark, prefix string) {
+	for i := range bookmarks {
+		bookmarks[i].Path = strconv.Itoa(i + 1)
+		if prefix != "" {
+			bookmarks[i].Path = prefix + "." + bookmarks[i].Path
+		}
+		assignBookmarkPaths(bookmarks[i].Children, bookmarks[i].Path)
+	}
+}
+
+// countBookmarks conta os marcadores de todos os níveis
+func countBookmarks(bookmarks []model.Bookmark) int {
+	count := len(bookmarks)
+	for _, bookmark := range bookmarks {
+		count += countBookmarks(bookmark.Children)
+	}
+	return count
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// EncryptDocument protege o documento com AES-256, gerando uma nova versão
+// As senhas nunca são registradas em logs ou na auditoria
+func (uc *DocumentUseCase) EncryptDocument(ctx context.Context, documentID, userID uuid.UUID, req dto.EncryptDocumentRequest) (*dto.DocumentResponse, error) {
+	options := model.EncryptionOptions{
+		UserPassword:  req.UserPassword,
+		OwnerPassword: req.OwnerPassword,
+		Permissions:   req.Permissions,
+	}
+
+	document, err := uc.transformDocument(ctx, documentID, userID, "encrypt", func(filePath string) error {
+		return uc.pdfProcessor.EncryptPDF(ctx, filePath, options)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "ENCRYPT", map[string]interface{}{
+		"version":       document.Version,
+		"algorithm":     "AES-256",
+		"user_password": options.UserPassword != "",
+		"permissions":   options.Permissions,
+	})
+
+	logger.Logger.Info("Documento protegido por senha",
+		zap.String("document_id", documentID.String()),
+		zap.Int("new_version", document.Version),
+	)
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+
+	return uc.toDocumentResponse(document, fileURL), nil
+}
+
+// DecryptDocument remove a proteção por senha do documento, gerando uma nova versão
+func (uc *DocumentUseCase) DecryptDocument(ctx context.Context, documentID, userID uuid.UUID, req dto.DecryptDocumentRequest) (*dto.DocumentResponse, error) {
+	document, err := uc.transformDocument(ctx, documentID, userID, "decrypt", func(filePath string) error {
+		decrypted, err := uc.pdfProcessor.DecryptPDF(ctx, filePath, req.Password)
+		if err != nil {
+			return err
+		}
+		if !decrypted {
+			return errors.New("documento não está protegido por senha")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "DECRYPT", map[string]interface{}{
+		"version": document.Version,
+	})
+
+	logger.Logger.Info("Proteção por senha removida",
+		zap.String("document_id", documentID.String()),
+		zap.Int("new_version", document.Version),
+	)
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+
+	return uc.toDocumentResponse(document, fileURL), nil
+}
+
+// transformDocument aplica uma transformação a uma cópia temporária da versão atual do documento
+// e salva o resultado como nova versão
+func (uc *DocumentUseCase) transformDocument(ctx context.Context, documentID, userID uuid.UUID, operation string, transform func(filePath string) error) (*model.Document, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	pdfData, err := uc.fileStorage.Read(ctx, document.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF original: %w", err)
+	}
+
+	tempPath, err := uc.fileStorage.Save(ctx, pdfData, fmt.Sprintf("temp_%s_%s.pdf", documentID.String(), operation))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar PDF temporário: %w", err)
+	}
+	defer uc.fileStorage.Delete(ctx, tempPath)
+
+	fullTempPath := filepath.Join(uc.storageBasePath, tempPath)
+	if err := transform(fullTempPath); err != nil {
+		return nil, err
+	}
+
+	outputData, err := os.ReadFile(fullTempPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF processado: %w", err)
+	}
+
+	if err := uc.storeNewVersion(ctx, document, outputData); err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxExportPages limita a quantidade de páginas renderizadas por exportação
+	maxExportPages = 500
+
+	// defaultExportJPEGQuality é a qualidade JPEG usada quando não informada
+	defaultExportJPEGQuality = 85
+)
+
+// ExportImages renderiza as páginas selecionadas do documento como imagens. Retorna o conteúdo, o nome sugerido
+// para o arquivo e o MIME type: a imagem, quando há um único arquivo, ou um ZIP com uma imagem por página
+func (uc *DocumentUseCase) ExportImages(ctx context.Context, documentID, userID uuid.UUID, req dto.ExportImagesRequest) ([]byte, string, string, error) {
+	options := model.ImageExportOptions{
+		Format:      model.ImageFormat(req.Format),
+		DPI:         req.DPI,
+		Width:       req.Width,
+		JPEGQuality: req.Quality,
+		Grayscale:   req.Grayscale,
+		Transparent: req.Transparent,
+		MultiPage:   req.MultiPage && !req.Zip,
+	}
+	if options.DPI > 0 && options.Width > 0 {
+		return nil, "", "", errors.New("exportação inválida: informe dpi ou width, não ambos")
+	}
+	if options.Transparent && options.Format == model.ImageFormatJPEG {
+		return nil, "", "", errors.New("exportação inválida: fundo transparente não é suportado em JPEG")
+	}
+	if options.JPEGQuality == 0 {
+		options.JPEGQuality = defaultExportJPEGQuality
+	}
+
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	pages, err := parsePageRanges(req.Pages, document.PageCount)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("exportação inválida: %w", err)
+	}
+	if len(pages) > maxExportPages {
+		return nil, "", "", fmt.Errorf("exportação inválida: no máximo %d páginas por exportação", maxExportPages)
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	images, err := uc.pdfProcessor.ExportPageImages(ctx, fullPath, pages, options)
+	if err != nil {
+		if errors.Is(err, domain.ErrRenderLimit) {
+			return nil, "", "", fmt.Errorf("exportação inválida: %w", err)
+		}
+		return nil, "", "", fmt.Errorf("erro ao exportar imagens: %w", err)
+	}
+
+	// Nomes dos arquivos: <documento>_p<página>.<extensão>
+	name := documentName(document)
+	extension := options.Format.Extension()
+	imageFilename := func(image model.PageImage) string {
+		if len(image.Pages) == 1 {
+			return fmt.Sprintf("%s_p%d.%s", name, image.Pages[0], extension)
+		}
+		return fmt.Sprintf("%s.%s", name, extension)
+	}
+
+	var data []byte
+	var filename, contentType string
+	if len(images) == 1 && !req.Zip {
+		data = images[0].Data
+		filename = imageFilename(images[0])
+		contentType = options.Format.ContentType()
+	} else {
+		if data, err = zipImages(images, imageFilename); err != nil {
+			return nil, "", "", err
+		}
+		filename = name + "_imagens.zip"
+		contentType = "application/zip"
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "EXPORT_IMAGES", map[string]interface{}{
+		"version":     document.Version,
+		"format":      options.Format,
+		"pages_count": len(pages),
+		"dpi":         options.DPI,
+		"width":       options.Width,
+		"zip":         contentType == "application/zip",
+	})
+
+	logger.Logger.Info("Páginas exportadas como imagens",
+		zap.String("document_id", documentID.String()),
+		zap.String("format", req.Format),
+		zap.Int("pages_count", len(pages)),
+		zap.Int("size_bytes", len(data)),
+	)
+
+	return data, filename, contentType, nil
+}
+
+// zipImages empacota as imagens em um arquivo ZIP. As imagens já são comprimidas e são armazenadas sem
+// recompressão
+func zipImages(images []model.PageImage, filename func(model.PageImage) string) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	now := time.Now()
+	for _, image := range images {
+		entry, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     filename(image),
+			Method:   zip.Store,
+			Modified: now,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("erro ao criar arquivo ZIP: %w", err)
+		}
+		if _, err := entry.Write(image.Data); err != nil {
+			return nil, fmt.Errorf("erro ao criar arquivo ZIP: %w", err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("erro ao criar arquivo ZIP: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ListFormFields lista os campos do formulário do documento com valores, opções e widgets
+func (uc *DocumentUseCase) ListFormFields(ctx context.Context, documentID, userID uuid.UUID) (*dto.FormFieldsResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	fields, err := uc.pdfProcessor.ListFormFields(ctx, fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler campos do formulário: %w", err)
+	}
+
+	return &dto.FormFieldsResponse{
+		DocumentID: document.ID.String(),
+		Version:    document.Version,
+		Fields:     fields,
+	}, nil
+}
+
+// ExportFormData exporta os valores do formulário do documento em FDF ou XFDF
+// Retorna o conteúdo e o nome sugerido para o arquivo
+func (uc *DocumentUseCase) ExportFormData(ctx context.Context, documentID, userID uuid.UUID, format string) ([]byte, string, error) {
+	dataFormat := model.FormDataFormat(format)
+	if dataFormat != model.FormDataFDF && dataFormat != model.FormDataXFDF {
+		return nil, "", errors.New("formato de dados de formulário inválido")
+	}
+
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	data, err := uc.pdfProcessor.ExportFormData(ctx, fullPath, dataFormat)
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao exportar dados do formulário: %w", err)
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "FORM_EXPORT", map[string]interface{}{
+		"version": document.Version,
+		"format":  dataFormat,
+	})
+
+	filename := fmt.Sprintf("%s_v%d.%s", document.ID.String(), document.Version, dataFormat)
+	return data, filename, nil
+}
+
+// ImportFormData preenche o formulário do documento com os valores de um arquivo FDF ou XFDF,
+// gerando uma nova versão
+func (uc *DocumentUseCase) ImportFormData(ctx context.Context, documentID, userID uuid.UUID, data []byte) (*dto.DocumentResponse, error) {
+	if _, err := uc.findOwnedDocument(ctx, documentID, userID); err != nil {
+		return nil, err
+	}
+
+	values, format, err := uc.pdfProcessor.ParseFormData(ctx, data)
+	if err != nil {
+		logger.Logger.Warn("Dados de formulário inválidos",
+			zap.String("document_id", documentID.String()),
+			zap.Error(err),
+		)
+		return nil, errors.New("dados de formulário inválidos")
+	}
+
+	response, err := uc.ProcessDocument(ctx, documentID, userID, []dto.EditInstruction{
+		{Type: "form_fill", Fields: values},
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "FORM_IMPORT", map[string]interface{}{
+		"version":      response.Version,
+		"format":       format,
+		"fields_count": len(values),
+	})
+
+	logger.Logger.Info("Dados de formulário importados",
+		zap.String("document_id", documentID.String()),
+		zap.String("format", string(format)),
+		zap.Int("fields_count", len(values)),
+	)
+
+	return response, nil
+}
+
+// findOwnedDocument busca o documento e verifica se pertence ao usuário
+func (uc *DocumentUseCase) findOwnedDocument(ctx context.Context, documentID, userID uuid.UUID) (*model.Document, error) {
+	document, err := uc.documentRepo.FindByID(ctx, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	if document == nil {
+		return nil, errors.New("documento não encontrado")
+	}
+
+	if document.UserID != userID {
+		return nil, errors.New("acesso negado")
+	}
+
+	return document, nil
+}
@@ -0,0 +1,246 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Valores padrão dos cabeçalhos e rodapés, em PDF points
+const (
+	defaultHeaderFooterFontSize = 10.0
+	defaultHeaderFooterMarginX  = 36.0
+	defaultHeaderFooterMarginY  = 24.0
+	defaultBatesDigits          = 6
+)
+
+// templatePlaceholderPattern localiza os marcadores dos modelos de cabeçalho e rodapé (ex.: {page})
+var templatePlaceholderPattern = regexp.MustCompile(`\{([a-z]+)\}`)
+
+// templatePlaceholders lista os marcadores aceitos nos modelos
+var templatePlaceholders = map[string]bool{
+	"page":  true, // Número da página
+	"total": true, // Total de páginas do documento
+	"date":  true, // Data da aplicação (DD/MM/AAAA)
+	"name":  true, // Nome original do documento, sem extensão
+	"bates": true, // Número Bates da página
+}
+
+// batesCounter gera a numeração Bates contínua de um conjunto de documentos
+type batesCounter struct {
+	prefix string
+	digits int
+	next   int
+}
+
+// format formata um número Bates com prefixo e zeros à esquerda
+func (b *batesCounter) format(number int) string {
+	return fmt.Sprintf("%s%0*d", b.prefix, b.digits, number)
+}
+
+// ApplyHeaderFooter aplica cabeçalhos e rodapés aos documentos na ordem informada, gerando uma nova versão de cada um
+// A numeração Bates continua de um documento para o seguinte; as páginas fora da seleção não recebem número.
+// Todos os documentos são verificados antes da aplicação, mas uma falha no meio do conjunto mantém as versões
+// já geradas para os documentos anteriores
+func (uc *DocumentUseCase) ApplyHeaderFooter(ctx context.Context, userID uuid.UUID, sources []dto.HeaderFooterSource, options dto.HeaderFooterOptions) (*dto.HeaderFooterResponse, error) {
+	if options.Header.IsEmpty() && options.Footer.IsEmpty() {
+		return nil, errors.New("informe ao menos um texto de cabeçalho ou rodapé")
+	}
+
+	usesBates, err := validateHeaderFooterTemplates(options.Header, options.Footer)
+	if err != nil {
+		return nil, err
+	}
+
+	var bates *batesCounter
+	switch {
+	case usesBates:
+		bates = &batesCounter{digits: defaultBatesDigits, next: 1}
+		if options.Bates != nil {
+			bates.prefix = options.Bates.Prefix
+			if options.Bates.Digits > 0 {
+				bates.digits = options.Bates.Digits
+			}
+			if options.Bates.Start != nil {
+				bates.next = *options.Bates.Start
+			}
+		}
+	case options.Bates != nil:
+		return nil, errors.New("numeração Bates informada sem o marcador {bates} no modelo")
+	}
+
+	// Verifica todos os documentos antes de alterar qualquer um
+	documents := make([]*model.Document, 0, len(sources))
+	seen := make(map[uuid.UUID]bool, len(sources))
+	for _, source := range sources {
+		documentID, err := uuid.Parse(source.DocumentID)
+		if err != nil {
+			return nil, fmt.Errorf("ID de documento inválido: %s", source.DocumentID)
+		}
+		if seen[documentID] {
+			return nil, fmt.Errorf("documento repetido no conjunto: %s", source.DocumentID)
+		}
+		seen[documentID] = true
+
+		document, err := uc.findOwnedDocument(ctx, documentID, userID)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, document)
+	}
+
+	style := model.HeaderFooterStyle{
+		FontFamily: options.FontFamily,
+		FontWeight: options.FontWeight,
+		FontSize:   defaultHeaderFooterFontSize,
+		Color:      options.Color,
+		MarginX:    defaultHeaderFooterMarginX,
+		MarginY:    defaultHeaderFooterMarginY,
+	}
+	if options.FontSize != nil {
+		style.FontSize = *options.FontSize
+	}
+	if options.MarginX != nil {
+		style.MarginX = *options.MarginX
+	}
+	if options.MarginY != nil {
+		style.MarginY = *options.MarginY
+	}
+
+	date := time.Now().Format("02/01/2006")
+
+	results := make([]dto.HeaderFooterDocument, 0, len(sources))
+	for i, original := range documents {
+		documentID := original.ID
+		name := sources[i].Name
+		if name == "" {
+			name = documentName(original)
+		}
+
+		var stamped []model.PageHeaderFooter
+		batesStart := 0
+		if bates != nil {
+			batesStart = bates.next
+		}
+
+		document, err := uc.transformDocument(ctx, documentID, userID, "header_footer", func(filePath string) error {
+			pages, err := uc.pdfProcessor.ExtractPages(ctx, filePath)
+			if err != nil {
+				return fmt.Errorf("erro ao obter páginas: %w", err)
+			}
+
+			selection, err := parsePageRanges(options.Pages, len(pages))
+			if err != nil {
+				return err
+			}
+
+			values := map[string]string{
+				"total": strconv.Itoa(len(pages)),
+				"date":  date,
+				"name":  name,
+			}
+
+			number := batesStart
+			for _, pageNum := range selection {
+				if (options.Parity == "odd" && pageNum%2 == 0) || (options.Parity == "even" && pageNum%2 != 0) {
+					continue
+				}
+
+				values["page"] = strconv.Itoa(pageNum)
+				if bates != nil {
+					values["bates"] = bates.format(number)
+					number++
+				}
+
+				stamped = append(stamped, model.PageHeaderFooter{
+					Page:   pageNum,
+					Header: renderHeaderFooterSlots(options.Header, values),
+					Footer: renderHeaderFooterSlots(options.Footer, values),
+				})
+			}
+
+			return uc.pdfProcessor.AddHeaderFooter(ctx, filePath, stamped, style)
+		})
+		if err != nil {
+			if errors.Is(err, domain.ErrUnsupportedCharacters) {
+				return nil, fmt.Errorf("texto inválido: %w", err)
+			}
+			return nil, err
+		}
+
+		fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+		result := dto.HeaderFooterDocument{Document: *uc.toDocumentResponse(document, fileURL)}
+
+		auditMetadata := map[string]interface{}{
+			"version": document.Version,
+			"pages":   len(stamped),
+		}
+		if bates != nil && len(stamped) > 0 {
+			bates.next = batesStart + len(stamped)
+			result.BatesFirst = bates.format(batesStart)
+			result.BatesLast = bates.format(bates.next - 1)
+			auditMetadata["bates_first"] = result.BatesFirst
+			auditMetadata["bates_last"] = result.BatesLast
+		}
+
+		uc.createAuditLog(ctx, documentID, userID, "HEADER_FOOTER", auditMetadata)
+		results = append(results, result)
+	}
+
+	logger.Logger.Info("Cabeçalhos e rodapés aplicados",
+		zap.Int("documents_count", len(results)),
+		zap.Bool("bates", bates != nil),
+	)
+
+	response := &dto.HeaderFooterResponse{Documents: results}
+	if bates != nil {
+		next := bates.next
+		response.NextBatesNumber = &next
+	}
+
+	return response, nil
+}
+
+// validateHeaderFooterTemplates rejeita marcadores desconhecidos e indica se algum modelo usa {bates}
+func validateHeaderFooterTemplates(slots ...model.HeaderFooterSlots) (bool, error) {
+	usesBates := false
+	for _, slot := range slots {
+		for _, template := range []string{slot.Left, slot.Center, slot.Right} {
+			for _, match := range templatePlaceholderPattern.FindAllStringSubmatch(template, -1) {
+				if !templatePlaceholders[match[1]] {
+					return false, fmt.Errorf("marcador desconhecido no modelo: %s", match[0])
+				}
+				if match[1] == "bates" {
+					usesBates = true
+				}
+			}
+		}
+	}
+	return usesBates, nil
+}
+
+// renderHeaderFooterSlots substitui os marcadores das três posições pelos valores da página
+func renderHeaderFooterSlots(slots model.HeaderFooterSlots, values map[string]string) model.HeaderFooterSlots {
+	render := func(template string) string {
+		return templatePlaceholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+			return values[strings.Trim(placeholder, "{}")]
+		})
+	}
+
+	return model.HeaderFooterSlots{
+		Left:   render(slots.Left),
+		Center: render(slots.Center),
+		Right:  render(slots.Right),
+	}
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// UploadImages cria um documento PDF a partir de imagens enviadas (fotos, digitalizações e faxes TIFF),
+// com uma página por imagem na ordem de envio
+func (uc *DocumentUseCase) UploadImages(ctx context.Context, userID uuid.UUID, images []model.ImageFile, options model.ImageConversionOptions) (*dto.DocumentResponse, error) {
+	documentID := uuid.New()
+
+	outputTempPath := fmt.Sprintf("temp_images_%s.pdf", documentID.String())
+	defer uc.fileStorage.Delete(ctx, outputTempPath)
+
+	if err := uc.pdfProcessor.ImagesToPDF(ctx, filepath.Join(uc.storageBasePath, outputTempPath), images, options); err != nil {
+		// Apenas erros da entrada (formato, arquivo corrompido, dimensões) são do cliente
+		if errors.Is(err, domain.ErrInvalidImage) {
+			return nil, fmt.Errorf("imagens inválidas: %w", err)
+		}
+		return nil, fmt.Errorf("erro ao converter imagens: %w", err)
+	}
+
+	pdfData, err := uc.fileStorage.Read(ctx, outputTempPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF gerado: %w", err)
+	}
+
+	// O documento recebe o nome da primeira imagem com a extensão .pdf
+	name := images[0].Name
+	name = strings.TrimSuffix(name, filepath.Ext(name)) + ".pdf"
+
+	document, err := uc.storeNewDocument(ctx, documentID, userID, name, pdfData)
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := make([]string, 0, len(images))
+	size := 0
+	for _, img := range images {
+		filenames = append(filenames, img.Name)
+		size += len(img.Data)
+	}
+
+	uc.createAuditLog(ctx, document.ID, userID, "UPLOAD", map[string]interface{}{
+		"filenames":        filenames,
+		"size":             size,
+		"images":           len(images),
+		"page_count":       document.PageCount,
+		"page_size":        options.PageSize,
+		"auto_rotate":      options.AutoRotate,
+		"jpeg_passthrough": options.JPEGPassthrough,
+	})
+
+	logger.Logger.Info("Documento criado a partir de imagens",
+		zap.String("document_id", document.ID.String()),
+		zap.Int("images", len(images)),
+		zap.Int("page_count", document.PageCount),
+	)
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+
+	return uc.toDocumentResponse(document, fileURL), nil
+}
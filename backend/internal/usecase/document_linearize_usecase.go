@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// LinearizeDocument lineariza o documento (visualização rápida na web), gerando uma nova versão que o
+// visualizador pode exibir a partir da primeira página antes de baixar o arquivo inteiro
+func (uc *DocumentUseCase) LinearizeDocument(ctx context.Context, documentID, userID uuid.UUID) (*dto.DocumentResponse, error) {
+	document, err := uc.transformDocument(ctx, documentID, userID, "linearize", func(filePath string) error {
+		return uc.pdfProcessor.LinearizePDF(ctx, filePath)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "LINEARIZE", map[string]interface{}{
+		"version": document.Version,
+	})
+
+	logger.Logger.Info("Documento linearizado",
+		zap.String("document_id", documentID.String()),
+		zap.Int("new_version", document.Version),
+	)
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+
+	return uc.toDocumentResponse(document, fileURL), nil
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxOriginalFilename é o tamanho máximo (em caracteres) do nome original armazenado
+const maxOriginalFilename = 255
+
+// metadataPropertyName restringe os nomes de propriedades personalizadas a nomes válidos no dicionário Info
+// e como elementos XML no pacote XMP
+var metadataPropertyName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_-]{0,63}$`)
+
+// reservedMetadataProperties são as entradas padrão do dicionário Info, editadas pelos campos próprios
+var reservedMetadataProperties = map[string]bool{
+	"Title": true, "Author": true, "Subject": true, "Keywords": true, "Creator": true,
+	"Producer": true, "CreationDate": true, "ModDate": true, "Trapped": true,
+}
+
+// GetMetadata lê os metadados da versão atual do documento: os valores consolidados, os do dicionário Info
+// e os do pacote XMP, indicando se ambos estão sincronizados
+func (uc *DocumentUseCase) GetMetadata(ctx context.Context, documentID, userID uuid.UUID) (*dto.MetadataResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	metadata, err := uc.pdfProcessor.ReadMetadata(ctx, fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler metadados: %w", err)
+	}
+
+	return &dto.MetadataResponse{
+		DocumentID:       document.ID.String(),
+		Version:          document.Version,
+		OriginalFilename: document.OriginalFilename,
+		Metadata:         metadata.Merged(),
+		Info:             metadata.Info,
+		XMP:              metadata.XMP,
+		InSync:           metadata.InSync(),
+	}, nil
+}
+
+// UpdateMetadata altera os metadados informados, grava o dicionário Info e o pacote XMP sincronizados em uma
+// nova versão e atualiza os metadados pesquisáveis do documento. Campos omitidos mantêm o valor atual;
+// propriedades personalizadas com valor nulo são removidas
+func (uc *DocumentUseCase) UpdateMetadata(ctx context.Context, documentID, userID uuid.UUID, req dto.UpdateMetadataRequest) (*dto.DocumentResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	current, err := uc.pdfProcessor.ReadMetadata(ctx, fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler metadados: %w", err)
+	}
+
+	metadata := current.Merged()
+	changed := []string{}
+	for _, field := range []struct {
+		name   string
+		target *string
+		value  *string
+	}{
+		{"title", &metadata.Title, req.Title},
+		{"author", &metadata.Author, req.Author},
+		{"subject", &metadata.Subject, req.Subject},
+		{"keywords", &metadata.Keywords, req.Keywords},
+		{"creator", &metadata.Creator, req.Creator},
+	} {
+		if field.value != nil {
+			*field.target = strings.TrimSpace(*field.value)
+			changed = append(changed, field.name)
+		}
+	}
+
+	if len(req.Custom) > 0 {
+		custom := make(model.MetadataProperties, len(metadata.Custom)+len(req.Custom))
+		for name, value := range metadata.Custom {
+			custom[name] = value
+		}
+		for name, value := range req.Custom {
+			if !metadataPropertyName.MatchString(name) || reservedMetadataProperties[name] {
+				return nil, fmt.Errorf("nome de propriedade inválido: %s (use letras, dígitos, _ ou -, iniciando por letra)", name)
+			}
+			if value == nil || strings.TrimSpace(*value) == "" {
+				delete(custom, name)
+			} else {
+				custom[name] = strings.TrimSpace(*value)
+			}
+			changed = append(changed, "custom."+name)
+		}
+		metadata.Custom = custom
+	}
+
+	if len(changed) > 0 {
+		document, err = uc.transformDocument(ctx, documentID, userID, "metadata", func(filePath string) error {
+			return uc.pdfProcessor.WriteMetadata(ctx, filePath, metadata)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// O Producer é mantido no arquivo; os demais valores são os gravados
+		document.SetMetadata(uc.readPDFMetadata(ctx, filepath.Join(uc.storageBasePath, document.FilePath)))
+	}
+
+	if req.OriginalFilename != nil {
+		document.OriginalFilename = originalFilename(*req.OriginalFilename)
+		if document.OriginalFilename == "" {
+			return nil, errors.New("informe um nome de arquivo válido")
+		}
+		changed = append(changed, "original_filename")
+	}
+
+	if len(changed) == 0 {
+		return nil, errors.New("informe ao menos um metadado a alterar")
+	}
+
+	if err := uc.documentRepo.Update(ctx, document); err != nil {
+		return nil, fmt.Errorf("erro ao atualizar documento: %w", err)
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "METADATA_UPDATE", map[string]interface{}{
+		"version": document.Version,
+		"fields":  changed,
+	})
+
+	logger.Logger.Info("Metadados do documento atualizados",
+		zap.String("document_id", documentID.String()),
+		zap.Int("version", document.Version),
+		zap.Strings("fields", changed),
+	)
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+	return uc.toDocumentResponse(document, fileURL), nil
+}
+
+// readPDFMetadata lê os metadados consolidados (Info completado pelo XMP) de um PDF
+// Falhas são apenas registradas: o documento continua válido sem metadados pesquisáveis
+func (uc *DocumentUseCase) readPDFMetadata(ctx context.Context, fullPath string) model.DocumentMetadata {
+	metadata, err := uc.pdfProcessor.ReadMetadata(ctx, fullPath)
+	if err != nil {
+		logger.Logger.Warn("Erro ao ler metadados do PDF",
+			zap.String("file", filepath.Base(fullPath)),
+			zap.Error(err),
+		)
+		return model.DocumentMetadata{}
+	}
+	return metadata.Merged()
+}
+
+// originalFilename normaliza o nome de arquivo enviado: sem diretórios e limitado ao tamanho da coluna
+func originalFilename(filename string) string {
+	name := strings.TrimSpace(filepath.Base(strings.ReplaceAll(filename, "\\", "/")))
+	if name == "." || name == "/" {
+		return ""
+	}
+	if runes := []rune(name); len(runes) > maxOriginalFilename {
+		ext := []rune(filepath.Ext(name))
+		if len(ext) >= maxOriginalFilename {
+			ext = nil
+		}
+		name = string(runes[:maxOriginalFilename-len(ext)]) + string(ext)
+	}
+	return name
+}
+
+// documentName retorna o nome do documento sem extensão: o nome original ou, em documentos anteriores
+// ao armazenamento do nome, o nome do arquivo da versão atual
+func documentName(document *model.Document) string {
+	name := document.OriginalFilename
+	if name == "" {
+		name = filepath.Base(document.FilePath)
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// errNotReduced interrompe a criação da versão quando a otimização não reduz o tamanho do documento
+var errNotReduced = errors.New("otimização não reduziu o documento")
+
+// OptimizeDocument reduz o tamanho do documento com o perfil informado (screen, ebook ou print), gerando
+// uma nova versão quando o resultado é menor; caso contrário, a versão atual é mantida
+func (uc *DocumentUseCase) OptimizeDocument(ctx context.Context, documentID, userID uuid.UUID, req dto.OptimizeDocumentRequest) (*dto.OptimizeDocumentResponse, error) {
+	options, ok := model.OptimizePreset(req.Preset).Options()
+	if !ok {
+		return nil, fmt.Errorf("perfil de otimização inválido: %s", req.Preset)
+	}
+
+	var report *model.OptimizeReport
+	document, err := uc.transformDocument(ctx, documentID, userID, "optimize", func(filePath string) error {
+		var err error
+		if report, err = uc.pdfProcessor.OptimizePDF(ctx, filePath, options); err != nil {
+			return err
+		}
+		if report.SizeAfter >= report.SizeBefore {
+			return errNotReduced
+		}
+		return nil
+	})
+
+	optimized := err == nil
+	if errors.Is(err, errNotReduced) {
+		document, err = uc.findOwnedDocument(ctx, documentID, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	message := "Documento otimizado com sucesso"
+	if optimized {
+		uc.createAuditLog(ctx, documentID, userID, "OPTIMIZE", map[string]interface{}{
+			"version":            document.Version,
+			"preset":             req.Preset,
+			"size_before":        report.SizeBefore,
+			"size_after":         report.SizeAfter,
+			"images_downsampled": report.ImagesDownsampled,
+		})
+
+		logger.Logger.Info("Documento otimizado",
+			zap.String("document_id", documentID.String()),
+			zap.String("preset", req.Preset),
+			zap.Int64("size_before", report.SizeBefore),
+			zap.Int64("size_after", report.SizeAfter),
+			zap.Int("new_version", document.Version),
+		)
+	} else {
+		message = "O documento já está otimizado para este perfil; nenhuma versão foi criada"
+	}
+
+	savedPercent := 0.0
+	if report.SizeBefore > 0 {
+		savedPercent = math.Round(float64(report.SizeBefore-report.SizeAfter)*1000/float64(report.SizeBefore)) / 10
+	}
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+
+	return &dto.OptimizeDocumentResponse{
+		Document:     *uc.toDocumentResponse(document, fileURL),
+		Preset:       req.Preset,
+		Optimized:    optimized,
+		Report:       *report,
+		SavedPercent: savedPercent,
+		Message:      message,
+	}, nil
+}
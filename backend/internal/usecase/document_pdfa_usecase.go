@@ -0,0 +1,148 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// errNotConformant interrompe a criação da versão quando a conversão deixa violações PDF/A não corrigíveis
+var errNotConformant = errors.New("conversão não atingiu a conformidade PDF/A")
+
+// ConvertToPDFA converte o documento para o nível PDF/A informado (1b, 2b ou 3b), gerando uma nova versão
+// e registrando o nível no documento. Quando restam violações que não podem ser corrigidas automaticamente
+// (ex.: cores CMYK sem OutputIntent CMYK), a versão atual é mantida e as violações são retornadas
+func (uc *DocumentUseCase) ConvertToPDFA(ctx context.Context, documentID, userID uuid.UUID, req dto.ConvertPDFARequest) (*dto.PDFAConversionResponse, error) {
+	conformance := model.PDFAConformance(req.Conformance)
+	if !conformance.Valid() {
+		return nil, fmt.Errorf("nível PDF/A inválido: %s", req.Conformance)
+	}
+
+	var report *model.PDFAConversionReport
+	document, err := uc.transformDocument(ctx, documentID, userID, "pdfa", func(filePath string) error {
+		var err error
+		if report, err = uc.pdfProcessor.ConvertToPDFA(ctx, filePath, conformance, req.Password); err != nil {
+			return err
+		}
+		if len(report.Remaining) > 0 {
+			return errNotConformant
+		}
+		return nil
+	})
+
+	converted := err == nil
+	if errors.Is(err, errNotConformant) {
+		document, err = uc.findOwnedDocument(ctx, documentID, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Documento convertido para PDF/A-%s com sucesso", conformance)
+	if converted {
+		document.PDFAConformance = conformance
+		if err := uc.documentRepo.Update(ctx, document); err != nil {
+			return nil, fmt.Errorf("erro ao atualizar documento: %w", err)
+		}
+
+		uc.createAuditLog(ctx, documentID, userID, "PDFA_CONVERT", map[string]interface{}{
+			"version":        document.Version,
+			"conformance":    conformance,
+			"fixed":          len(report.Fixed),
+			"embedded_fonts": report.EmbeddedFonts,
+		})
+
+		logger.Logger.Info("Documento convertido para PDF/A",
+			zap.String("document_id", documentID.String()),
+			zap.String("conformance", string(conformance)),
+			zap.Int("fixed", len(report.Fixed)),
+			zap.Int("new_version", document.Version),
+		)
+	} else {
+		message = fmt.Sprintf("O documento tem %d violações PDF/A que não podem ser corrigidas automaticamente; nenhuma versão foi criada", len(report.Remaining))
+	}
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+
+	return &dto.PDFAConversionResponse{
+		Document:  *uc.toDocumentResponse(document, fileURL),
+		Converted: converted,
+		Report:    *report,
+		Message:   message,
+	}, nil
+}
+
+// ValidatePDFA valida a versão atual do documento contra um nível PDF/A, listando cada violação com a
+// cláusula da norma. Sem nível informado, usa o declarado no XMP (ou 2b). O nível registrado no documento
+// é atualizado conforme o resultado
+func (uc *DocumentUseCase) ValidatePDFA(ctx context.Context, documentID, userID uuid.UUID, conformance string) (*dto.PDFAValidationResponse, error) {
+	level := model.PDFAConformance(conformance)
+	if conformance != "" && !level.Valid() {
+		return nil, fmt.Errorf("nível PDF/A inválido: %s", conformance)
+	}
+
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	if level == "" {
+		level = model.PDFA2B
+		if declared := uc.readPDFMetadata(ctx, fullPath).PDFA; declared.Valid() {
+			level = declared
+		}
+	}
+
+	validation, err := uc.pdfProcessor.ValidatePDFA(ctx, fullPath, level)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao validar PDF/A: %w", err)
+	}
+
+	// Registra o nível validado; uma falha no nível registrado o remove do documento
+	previous := document.PDFAConformance
+	if validation.Compliant {
+		document.PDFAConformance = level
+	} else if document.PDFAConformance == level {
+		document.PDFAConformance = ""
+	}
+	if document.PDFAConformance != previous {
+		if err := uc.documentRepo.Update(ctx, document); err != nil {
+			return nil, fmt.Errorf("erro ao atualizar documento: %w", err)
+		}
+	}
+
+	return &dto.PDFAValidationResponse{
+		DocumentID: document.ID.String(),
+		Version:    document.Version,
+		Validation: *validation,
+	}, nil
+}
+
+// validatedPDFA valida o nível PDF/A declarado no XMP de um PDF recebido, retornando-o quando o arquivo
+// está conforme; falhas são apenas registradas e o documento fica sem nível
+func (uc *DocumentUseCase) validatedPDFA(ctx context.Context, fullPath string, declared model.PDFAConformance) model.PDFAConformance {
+	if !declared.Valid() {
+		return ""
+	}
+
+	validation, err := uc.pdfProcessor.ValidatePDFA(ctx, fullPath, declared)
+	if err != nil {
+		logger.Logger.Warn("Erro ao validar PDF/A declarado",
+			zap.String("file", filepath.Base(fullPath)),
+			zap.Error(err),
+		)
+		return ""
+	}
+	if !validation.Compliant {
+		return ""
+	}
+	return declared
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// SignDocument assina digitalmente o documento com o certificado do usuário (ou o da organização),
+// gerando uma nova versão. A senha do certificado nunca é registrada em logs ou na auditoria
+func (uc *DocumentUseCase) SignDocument(ctx context.Context, documentID, userID uuid.UUID, req dto.SignDocumentRequest) (*dto.DocumentResponse, error) {
+	if uc.certificateStore == nil {
+		return nil, errors.New("assinatura digital não configurada")
+	}
+
+	// Por padrão usa o carimbo do tempo quando há TSA configurado
+	var tsa domain.TimestampAuthority
+	if req.Timestamp == nil || *req.Timestamp {
+		if uc.timestampAuthority == nil && req.Timestamp != nil {
+			return nil, errors.New("carimbo do tempo não configurado")
+		}
+		tsa = uc.timestampAuthority
+	}
+
+	if _, err := uc.findOwnedDocument(ctx, documentID, userID); err != nil {
+		return nil, err
+	}
+
+	identity, err := uc.certificateStore.Identity(ctx, userID, req.CertificatePassword)
+	if err != nil {
+		return nil, err
+	}
+
+	options := model.SignatureOptions{
+		FieldName:   req.FieldName,
+		Reason:      req.Reason,
+		Location:    req.Location,
+		ContactInfo: req.ContactInfo,
+		Page:        req.Page,
+		X:           req.X,
+		Y:           req.Y,
+	}
+	if req.Width != nil && req.Height != nil {
+		options.Width = *req.Width
+		options.Height = *req.Height
+	}
+
+	var fieldName string
+	document, err := uc.transformDocument(ctx, documentID, userID, "sign", func(filePath string) error {
+		var signErr error
+		fieldName, signErr = uc.pdfProcessor.SignPDF(ctx, filePath, identity, options, tsa)
+		return signErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	uc.createAuditLog(ctx, documentID, userID, "SIGN", map[string]interface{}{
+		"version":           document.Version,
+		"field":             fieldName,
+		"signer":            identity.Certificate.Subject.CommonName,
+		"certificate_owner": identity.Owner,
+		"timestamped":       tsa != nil,
+		"visible":           options.Width > 0 && options.Height > 0,
+	})
+
+	logger.Logger.Info("Documento assinado",
+		zap.String("document_id", documentID.String()),
+		zap.String("field", fieldName),
+		zap.String("certificate_owner", string(identity.Owner)),
+		zap.Int("new_version", document.Version),
+	)
+
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+
+	return uc.toDocumentResponse(document, fileURL), nil
+}
+
+// VerifySignatures verifica as assinaturas digitais da versão atual do documento
+func (uc *DocumentUseCase) VerifySignatures(ctx context.Context, documentID, userID uuid.UUID) (*dto.SignatureVerificationResponse, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem repositório configurado nenhuma cadeia é confiável (as raízes do sistema não são usadas)
+	roots := x509.NewCertPool()
+	if uc.certificateStore != nil {
+		roots = uc.certificateStore.TrustedRoots()
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	signatures, err := uc.pdfProcessor.VerifySignatures(ctx, fullPath, roots)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao verificar assinaturas: %w", err)
+	}
+
+	return &dto.SignatureVerificationResponse{
+		DocumentID: document.ID.String(),
+		Version:    document.Version,
+		Signatures: signatures,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
+	"github.com/editor-pdf/backend/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// maxSearchHits limita a quantidade de ocorrências retornadas por busca
+const maxSearchHits = 500
+
+// ExtractText extrai o texto das páginas selecionadas (ex.: "1-3,5"; vazio = todas)
+// Com withWords, inclui as linhas e palavras com suas caixas no sistema de coordenadas de AddText
+func (uc *DocumentUseCase) ExtractText(ctx context.Context, documentID, userID uuid.UUID, pagesSpec string, withWords bool) (*dto.DocumentTextResponse, error) {
+	// Busca o documento
+	document, err := uc.documentRepo.FindByID(ctx, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	if document == nil {
+		return nil, errors.New("documento não encontrado")
+	}
+
+	if document.UserID != userID {
+		return nil, errors.New("acesso negado")
+	}
+
+	pages, err := parsePageRanges(pagesSpec, document.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	pageTexts, err := uc.pdfProcessor.ExtractText(ctx, fullPath, pages)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao extrair texto: %w", err)
+	}
+
+	if !withWords {
+		for i := range pageTexts {
+			pageTexts[i].Lines = nil
+		}
+	}
+
+	logger.Logger.Debug("Texto extraído do documento",
+		zap.String("document_id", documentID.String()),
+		zap.Int("pages_count", len(pageTexts)),
+		zap.Bool("with_words", withWords),
+	)
+
+	return &dto.DocumentTextResponse{
+		DocumentID: document.ID.String(),
+		Version:    document.Version,
+		Pages:      pageTexts,
+	}, nil
+}
+
+// SearchText busca uma frase (ou expressão regular) nas páginas selecionadas do documento,
+// ignorando maiúsculas/minúsculas e acentos
+func (uc *DocumentUseCase) SearchText(ctx context.Context, documentID, userID uuid.UUID, query string, isRegex bool, pagesSpec string) (*dto.SearchResponse, error) {
+	if strings.TrimSpace(query) == "" {
+		return nil, errors.New("consulta de busca vazia")
+	}
+	if isRegex {
+		if _, err := regexp.Compile(query); err != nil {
+			return nil, errors.New("expressão regular inválida")
+		}
+	}
+
+	// Busca o documento
+	document, err := uc.documentRepo.FindByID(ctx, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	if document == nil {
+		return nil, errors.New("documento não encontrado")
+	}
+
+	if document.UserID != userID {
+		return nil, errors.New("acesso negado")
+	}
+
+	pages, err := parsePageRanges(pagesSpec, document.PageCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Busca uma ocorrência além do limite para indicar que o resultado foi truncado
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	hits, err := uc.pdfProcessor.SearchText(ctx, fullPath, model.TextSearch{
+		Query: query,
+		Regex: isRegex,
+		Pages: pages,
+		Limit: maxSearchHits + 1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar texto: %w", err)
+	}
+
+	truncated := len(hits) > maxSearchHits
+	if truncated {
+		hits = hits[:maxSearchHits]
+	}
+
+	logger.Logger.Debug("Busca realizada no documento",
+		zap.String("document_id", documentID.String()),
+		zap.Int("hits_count", len(hits)),
+		zap.Bool("truncated", truncated),
+	)
+
+	return &dto.SearchResponse{
+		DocumentID: document.ID.String(),
+		Version:    document.Version,
+		Query:      query,
+		Regex:      isRegex,
+		Hits:       hits,
+		Total:      len(hits),
+		Truncated:  truncated,
+	}, nil
+}
@@ -7,8 +7,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/editor-pdf/backend/internal/domain"
@@ -21,41 +24,47 @@ import (
 
 // DocumentUseCase contém os casos de uso de documentos
 type DocumentUseCase struct {
-	documentRepo    domain.DocumentRepository
-	auditLogRepo    domain.AuditLogRepository
-	fileStorage     domain.FileStorage
-	pdfProcessor    domain.PDFProcessor
-	storageBasePath string
+	documentRepo       domain.DocumentRepository
+	auditLogRepo       domain.AuditLogRepository
+	annotationRepo     domain.AnnotationRepository
+	fileStorage        domain.FileStorage
+	pdfProcessor       domain.PDFProcessor
+	certificateStore   domain.CertificateStore   // Nulo quando a assinatura digital não está configurada
+	timestampAuthority domain.TimestampAuthority // Nulo quando não há TSA configurado
+	storageBasePath    string
 }
 
 // NewDocumentUseCase cria uma nova instância de DocumentUseCase
 func NewDocumentUseCase(
 	documentRepo domain.DocumentRepository,
 	auditLogRepo domain.AuditLogRepository,
+	annotationRepo domain.AnnotationRepository,
 	fileStorage domain.FileStorage,
 	pdfProcessor domain.PDFProcessor,
+	certificateStore domain.CertificateStore,
+	timestampAuthority domain.TimestampAuthority,
 	storageBasePath string,
 ) *DocumentUseCase {
 	return &DocumentUseCase{
-		documentRepo:    documentRepo,
-		auditLogRepo:    auditLogRepo,
-		fileStorage:     fileStorage,
-		pdfProcessor:    pdfProcessor,
-		storageBasePath: storageBasePath,
+		documentRepo:       documentRepo,
+		auditLogRepo:       auditLogRepo,
+		annotationRepo:     annotationRepo,
+		fileStorage:        fileStorage,
+		pdfProcessor:       pdfProcessor,
+		certificateStore:   certificateStore,
+		timestampAuthority: timestampAuthority,
+		storageBasePath:    storageBasePath,
 	}
 }
 
 // UploadDocument faz upload de um documento PDF
-func (uc *DocumentUseCase) UploadDocument(ctx context.Context, userID uuid.UUID, fileData []byte, filename string) (*dto.DocumentResponse, error) {
+// Documentos protegidos por senha são armazenados sem a proteção, decifrados com a senha informada
+func (uc *DocumentUseCase) UploadDocument(ctx context.Context, userID uuid.UUID, fileData []byte, filename, password string) (*dto.DocumentResponse, error) {
 	// Valida o PDF usando magic bytes
-	if err := uc.pdfProcessor.ValidatePDF(ctx, fileData); err != nil {
+	if err := uc.pdfProcessor.ValidatePDF(ctx, fileData); err != nil && err.Error() != "PDF protegido por senha" {
 		return nil, fmt.Errorf("arquivo PDF inválido: %w", err)
 	}
 
-	// Calcula checksum
-	hash := sha256.Sum256(fileData)
-	checksum := hex.EncodeToString(hash[:])
-
 	// Gera nome único para o arquivo
 	fileID := uuid.New()
 	ext := filepath.Ext(filename)
@@ -70,8 +79,30 @@ func (uc *DocumentUseCase) UploadDocument(ctx context.Context, userID uuid.UUID,
 		return nil, fmt.Errorf("erro ao salvar arquivo temporário: %w", err)
 	}
 
-	// Extrai informações das páginas
 	fullPath := filepath.Join(uc.storageBasePath, tempPath)
+
+	// Remove a proteção por senha; as edições exigem o conteúdo decifrado
+	decrypted, err := uc.pdfProcessor.DecryptPDF(ctx, fullPath, password)
+	if err != nil {
+		_ = uc.fileStorage.Delete(ctx, tempPath)
+		return nil, err
+	}
+	if decrypted {
+		if fileData, err = os.ReadFile(fullPath); err != nil {
+			_ = uc.fileStorage.Delete(ctx, tempPath)
+			return nil, fmt.Errorf("erro ao ler PDF decifrado: %w", err)
+		}
+		if err := uc.pdfProcessor.ValidatePDF(ctx, fileData); err != nil {
+			_ = uc.fileStorage.Delete(ctx, tempPath)
+			return nil, fmt.Errorf("arquivo PDF inválido: %w", err)
+		}
+	}
+
+	// Calcula checksum
+	hash := sha256.Sum256(fileData)
+	checksum := hex.EncodeToString(hash[:])
+
+	// Extrai informações das páginas
 	pages, err := uc.pdfProcessor.ExtractPages(ctx, fullPath)
 	if err != nil {
 		// Se não conseguir extrair páginas, continua com 0
@@ -79,6 +110,10 @@ func (uc *DocumentUseCase) UploadDocument(ctx context.Context, userID uuid.UUID,
 		pages = []model.Page{}
 	}
 
+	// Lê os metadados (Info e XMP) para permitir buscas
+	metadata := uc.readPDFMetadata(ctx, fullPath)
+	pdfa := uc.validatedPDFA(ctx, fullPath, metadata.PDFA)
+
 	// Remove arquivo temporário
 	_ = uc.fileStorage.Delete(ctx, tempPath)
 
@@ -90,14 +125,17 @@ func (uc *DocumentUseCase) UploadDocument(ctx context.Context, userID uuid.UUID,
 
 	// Cria registro no banco
 	document := &model.Document{
-		ID:        fileID,
-		UserID:    userID,
-		FilePath:  filePath,
-		Checksum:  checksum,
-		Version:   1,
-		Status:    model.DocumentStatusReady,
-		PageCount: len(pages),
+		ID:               fileID,
+		UserID:           userID,
+		FilePath:         filePath,
+		OriginalFilename: originalFilename(filename),
+		Checksum:         checksum,
+		Version:          1,
+		Status:           model.DocumentStatusReady,
+		PageCount:        len(pages),
+		PDFAConformance:  pdfa,
 	}
+	document.SetMetadata(metadata)
 
 	if err := uc.documentRepo.Create(ctx, document); err != nil {
 		// Tenta remover o arquivo se falhar ao criar registro
@@ -105,10 +143,14 @@ func (uc *DocumentUseCase) UploadDocument(ctx context.Context, userID uuid.UUID,
 		return nil, fmt.Errorf("erro ao criar registro do documento: %w", err)
 	}
 
+	// Importa as anotações existentes no PDF para a camada de anotações
+	uc.importPDFAnnotations(ctx, document)
+
 	// Cria log de auditoria
 	uc.createAuditLog(ctx, document.ID, userID, "UPLOAD", map[string]interface{}{
-		"filename": filename,
-		"size":     len(fileData),
+		"filename":  filename,
+		"size":      len(fileData),
+		"decrypted": decrypted,
 	})
 
 	// Obtém URL do arquivo
@@ -134,8 +176,30 @@ func (uc *DocumentUseCase) GetDocument(ctx context.Context, documentID, userID u
 	return uc.toDocumentResponse(document, fileURL), nil
 }
 
+// OpenDocumentFile abre o arquivo da versão atual do documento para download
+// O arquivo retornado permite posicionamento, para atender requisições de faixas de bytes (HTTP Range)
+func (uc *DocumentUseCase) OpenDocumentFile(ctx context.Context, documentID, userID uuid.UUID) (io.ReadSeekCloser, *model.Document, error) {
+	document, err := uc.findOwnedDocument(ctx, documentID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := uc.fileStorage.Open(ctx, document.FilePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao abrir PDF: %w", err)
+	}
+
+	return file, document, nil
+}
+
 // ListDocuments lista documentos de um usuário
-func (uc *DocumentUseCase) ListDocuments(ctx context.Context, userID uuid.UUID, limit, offset int) (*dto.DocumentListResponse, error) {
+// search filtra por palavras do nome original e dos metadados (título, autor, assunto e palavras-chave)
+// pdfa filtra pelo nível PDF/A validado (1b, 2b ou 3b), por qualquer nível (any) ou pelos não conformes (none)
+func (uc *DocumentUseCase) ListDocuments(ctx context.Context, userID uuid.UUID, search, pdfa string, limit, offset int) (*dto.DocumentListResponse, error) {
+	if pdfa != "" && pdfa != "any" && pdfa != "none" && !model.PDFAConformance(pdfa).Valid() {
+		return nil, fmt.Errorf("filtro PDF/A inválido: %s", pdfa)
+	}
+
 	if limit <= 0 {
 		limit = 20
 	}
@@ -143,7 +207,7 @@ func (uc *DocumentUseCase) ListDocuments(ctx context.Context, userID uuid.UUID,
 		limit = 100
 	}
 
-	documents, total, err := uc.documentRepo.FindByUserID(ctx, userID, limit, offset)
+	documents, total, err := uc.documentRepo.FindByUserID(ctx, userID, model.DocumentFilter{Search: search, PDFA: pdfa}, limit, offset)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao listar documentos: %w", err)
 	}
@@ -163,7 +227,8 @@ func (uc *DocumentUseCase) ListDocuments(ctx context.Context, userID uuid.UUID,
 }
 
 // ProcessDocument processa edições em um documento
-func (uc *DocumentUseCase) ProcessDocument(ctx context.Context, documentID, userID uuid.UUID, instructions []dto.EditInstruction) (*dto.DocumentResponse, error) {
+// Com linearize a versão gerada é linearizada (visualização rápida na web) após as edições
+func (uc *DocumentUseCase) ProcessDocument(ctx context.Context, documentID, userID uuid.UUID, instructions []dto.EditInstruction, linearize bool) (*dto.DocumentResponse, error) {
 	// Busca o documento
 	document, err := uc.documentRepo.FindByID(ctx, documentID)
 	if err != nil {
@@ -182,6 +247,9 @@ func (uc *DocumentUseCase) ProcessDocument(ctx context.Context, documentID, user
 
 	// Valida o PDF
 	if err := uc.pdfProcessor.ValidatePDF(ctx, pdfData); err != nil {
+		if err.Error() == "PDF protegido por senha" {
+			return nil, errors.New("documento protegido por senha; remova a proteção antes de editar")
+		}
 		return nil, fmt.Errorf("PDF inválido: %w", err)
 	}
 
@@ -206,60 +274,21 @@ func (uc *DocumentUseCase) ProcessDocument(ctx context.Context, documentID, user
 	fullOutputPath := filepath.Join(uc.storageBasePath, outputPath)
 
 	// Processa cada edição sequencialmente
+	result := &editResult{pageOrder: pageSequence(document.PageCount)}
 	for i, instruction := range instructions {
-		switch instruction.Type {
-		case "text":
-			// Valida campos obrigatórios
-			if instruction.Content == "" {
-				_ = uc.fileStorage.Delete(ctx, outputPath)
-				return nil, fmt.Errorf("edição %d: conteúdo de texto não pode ser vazio", i+1)
-			}
-
-			fontSize := 12.0 // Tamanho padrão
-			if instruction.FontSize != nil && *instruction.FontSize > 0 {
-				fontSize = *instruction.FontSize
-			}
-
-			// Aplica a edição de texto
-			if err := uc.pdfProcessor.AddText(ctx, fullTempPath, instruction.Page, instruction.X, instruction.Y, instruction.Content, fontSize); err != nil {
-				_ = uc.fileStorage.Delete(ctx, outputPath)
-				return nil, fmt.Errorf("erro ao adicionar texto na edição %d: %w", i+1, err)
-			}
-
-		case "image":
-			// Valida campos obrigatórios
-			if instruction.Content == "" {
-				_ = uc.fileStorage.Delete(ctx, outputPath)
-				return nil, fmt.Errorf("edição %d: caminho da imagem não pode ser vazio", i+1)
-			}
-			if instruction.Width == nil || *instruction.Width <= 0 {
-				_ = uc.fileStorage.Delete(ctx, outputPath)
-				return nil, fmt.Errorf("edição %d: largura da imagem deve ser maior que zero", i+1)
-			}
-			if instruction.Height == nil || *instruction.Height <= 0 {
-				_ = uc.fileStorage.Delete(ctx, outputPath)
-				return nil, fmt.Errorf("edição %d: altura da imagem deve ser maior que zero", i+1)
-			}
-
-			// Resolve caminho da imagem (pode ser relativo ou absoluto)
-			imagePath := instruction.Content
-			if !filepath.IsAbs(imagePath) {
-				imagePath = filepath.Join(uc.storageBasePath, imagePath)
-			}
-
-			// Aplica a edição de imagem
-			if err := uc.pdfProcessor.AddImage(ctx, fullTempPath, instruction.Page, instruction.X, instruction.Y, *instruction.Width, *instruction.Height, imagePath); err != nil {
-				_ = uc.fileStorage.Delete(ctx, outputPath)
-				return nil, fmt.Errorf("erro ao adicionar imagem na edição %d: %w", i+1, err)
-			}
-
-		case "drawing":
+		if err := uc.applyInstruction(ctx, fullTempPath, i, instruction, result); err != nil {
 			_ = uc.fileStorage.Delete(ctx, outputPath)
-			return nil, fmt.Errorf("edição %d: tipo 'drawing' ainda não está implementado", i+1)
+			if errors.Is(err, domain.ErrUnsupportedCharacters) {
+				return nil, fmt.Errorf("texto inválido: %w", err)
+			}
+			return nil, err
+		}
+	}
 
-		default:
+	if linearize {
+		if err := uc.pdfProcessor.LinearizePDF(ctx, fullTempPath); err != nil {
 			_ = uc.fileStorage.Delete(ctx, outputPath)
-			return nil, fmt.Errorf("edição %d: tipo de edição desconhecido: %s", i+1, instruction.Type)
+			return nil, fmt.Errorf("erro ao linearizar PDF: %w", err)
 		}
 	}
 
@@ -275,6 +304,15 @@ func (uc *DocumentUseCase) ProcessDocument(ctx context.Context, documentID, user
 		return nil, fmt.Errorf("erro ao salvar PDF processado: %w", err)
 	}
 
+	// Operações de página podem alterar a quantidade de páginas
+	pages, err := uc.pdfProcessor.ExtractPages(ctx, fullOutputPath)
+	if err != nil {
+		_ = uc.fileStorage.Delete(ctx, outputPath)
+		return nil, fmt.Errorf("erro ao extrair páginas do PDF processado: %w", err)
+	}
+
+	hash := sha256.Sum256(processedData)
+
 	logger.Logger.Info("Edições processadas com sucesso",
 		zap.String("document_id", documentID.String()),
 		zap.Int("instructions_count", len(instructions)),
@@ -290,23 +328,600 @@ func (uc *DocumentUseCase) ProcessDocument(ctx context.Context, documentID, user
 	// Atualiza caminho do arquivo
 	document.FilePath = outputPath
 	document.Version = newVersion
+	document.Checksum = hex.EncodeToString(hash[:])
+	document.PageCount = len(pages)
+	document.PDFAConformance = "" // Edições podem invalidar a conformidade PDF/A
 	if err := uc.documentRepo.Update(ctx, document); err != nil {
 		_ = uc.fileStorage.Delete(ctx, outputPath)
 		return nil, fmt.Errorf("erro ao atualizar documento: %w", err)
 	}
 
+	// As anotações do documento acompanham as páginas removidas, movidas, duplicadas ou inseridas
+	if result.pagesChanged {
+		if len(result.pageOrder) != len(pages) {
+			logger.Logger.Warn("Ordem das páginas divergente; anotações não ajustadas",
+				zap.String("document_id", documentID.String()),
+				zap.Int("expected_pages", len(result.pageOrder)),
+				zap.Int("page_count", len(pages)),
+			)
+		} else if err := uc.remapAnnotations(ctx, documentID, result.pageOrder); err != nil {
+			// A nova versão já foi registrada; a falha é apenas registrada
+			logger.Logger.Warn("Erro ao ajustar anotações às páginas",
+				zap.String("document_id", documentID.String()),
+				zap.Error(err),
+			)
+		}
+	}
+
 	// Cria log de auditoria
 	uc.createAuditLog(ctx, documentID, userID, "PROCESS", map[string]interface{}{
 		"instructions_count": len(instructions),
 		"new_version":        newVersion,
+		"page_count":         document.PageCount,
+		"linearized":         linearize,
 	})
 
+	// Registra o relatório de redação separadamente (apenas contagens, nunca o conteúdo removido)
+	if len(result.redactions) > 0 {
+		uc.createAuditLog(ctx, documentID, userID, "REDACT", map[string]interface{}{
+			"version":    newVersion,
+			"redactions": result.redactions,
+		})
+	}
+
+	if len(result.createdFields) > 0 {
+		sort.Strings(result.createdFields)
+		uc.createAuditLog(ctx, documentID, userID, "FORM_CREATE", map[string]interface{}{
+			"version": newVersion,
+			"fields":  slices.Compact(result.createdFields),
+		})
+	}
+
+	// Registra apenas os nomes dos campos preenchidos; os valores podem conter dados pessoais
+	if len(result.filledFields) > 0 || result.flattened {
+		sort.Strings(result.filledFields)
+		uc.createAuditLog(ctx, documentID, userID, "FORM_FILL", map[string]interface{}{
+			"version":   newVersion,
+			"fields":    slices.Compact(result.filledFields),
+			"flattened": result.flattened,
+		})
+	}
+
 	// Obtém URL do arquivo
 	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
 
 	return uc.toDocumentResponse(document, fileURL), nil
 }
 
+// editResult acumula informações produzidas pelas instruções para o log de auditoria
+type editResult struct {
+	redactions    []model.RedactionReport
+	createdFields []string
+	filledFields  []string
+	flattened     bool
+
+	// Para cada página resultante, a página original (0 = página nova), usada para ajustar as anotações
+	pageOrder    []int
+	pagesChanged bool
+}
+
+// pageSequence retorna a ordem inicial das páginas (1..count)
+func pageSequence(count int) []int {
+	order := make([]int, count)
+	for i := range order {
+		order[i] = i + 1
+	}
+	return order
+}
+
+// trackPage registra em pageOrder uma operação de página já aplicada ao PDF
+// Posições fora da ordem conhecida (contagem de páginas desatualizada) invalidam o acompanhamento
+func (r *editResult) trackPage(operation string, page, target int) {
+	r.pagesChanged = true
+	order := r.pageOrder
+	if page < 1 || page > len(order)+1 || (operation != "insert_page" && page > len(order)) {
+		r.pageOrder = nil
+		return
+	}
+
+	switch operation {
+	case "delete_page":
+		order = slices.Delete(order, page-1, page)
+	case "move_page":
+		original := order[page-1]
+		order = slices.Delete(order, page-1, page)
+		if target < 1 || target > len(order)+1 {
+			r.pageOrder = nil
+			return
+		}
+		order = slices.Insert(order, target-1, original)
+	case "duplicate_page":
+		order = slices.Insert(order, page, order[page-1])
+	case "insert_page":
+		order = slices.Insert(order, page-1, 0)
+	}
+	r.pageOrder = order
+}
+
+// applyInstruction aplica uma instrução de edição ao PDF de trabalho
+func (uc *DocumentUseCase) applyInstruction(ctx context.Context, filePath string, i int, instruction dto.EditInstruction, result *editResult) error {
+	switch instruction.Type {
+	case "text":
+		// Valida campos obrigatórios
+		if instruction.Content == "" {
+			return fmt.Errorf("edição %d: conteúdo de texto não pode ser vazio", i+1)
+		}
+
+		// Aplica a edição de texto
+		if err := uc.pdfProcessor.AddText(ctx, filePath, instruction.Page, instruction.X, instruction.Y, instruction.Content, toTextOptions(instruction)); err != nil {
+			return fmt.Errorf("erro ao adicionar texto na edição %d: %w", i+1, err)
+		}
+
+	case "image":
+		// Valida campos obrigatórios
+		if instruction.Content == "" {
+			return fmt.Errorf("edição %d: caminho da imagem não pode ser vazio", i+1)
+		}
+		if instruction.Width == nil || *instruction.Width <= 0 {
+			return fmt.Errorf("edição %d: largura da imagem deve ser maior que zero", i+1)
+		}
+		if instruction.Height == nil || *instruction.Height <= 0 {
+			return fmt.Errorf("edição %d: altura da imagem deve ser maior que zero", i+1)
+		}
+
+		// Resolve caminho da imagem (pode ser relativo ou absoluto)
+		imagePath := instruction.Content
+		if !filepath.IsAbs(imagePath) {
+			imagePath = filepath.Join(uc.storageBasePath, imagePath)
+		}
+
+		// Aplica a edição de imagem
+		if err := uc.pdfProcessor.AddImage(ctx, filePath, instruction.Page, instruction.X, instruction.Y, *instruction.Width, *instruction.Height, imagePath); err != nil {
+			return fmt.Errorf("erro ao adicionar imagem na edição %d: %w", i+1, err)
+		}
+
+	case "drawing":
+		// Valida campos obrigatórios
+		if instruction.Shape == "" {
+			return fmt.Errorf("edição %d: forma do desenho não pode ser vazia", i+1)
+		}
+
+		// Aplica a edição de desenho
+		if err := uc.pdfProcessor.AddDrawing(ctx, filePath, instruction.Page, toDrawing(instruction)); err != nil {
+			return fmt.Errorf("erro ao adicionar desenho na edição %d: %w", i+1, err)
+		}
+
+	case "rotate_page":
+		if instruction.Rotation == nil || int(*instruction.Rotation)%90 != 0 || *instruction.Rotation != float64(int(*instruction.Rotation)) {
+			return fmt.Errorf("edição %d: rotação da página deve ser múltipla de 90 graus", i+1)
+		}
+
+		if err := uc.pdfProcessor.RotatePage(ctx, filePath, instruction.Page, int(*instruction.Rotation)); err != nil {
+			return fmt.Errorf("erro ao rotacionar página na edição %d: %w", i+1, err)
+		}
+
+	case "delete_page":
+		if err := uc.pdfProcessor.DeletePage(ctx, filePath, instruction.Page); err != nil {
+			return fmt.Errorf("erro ao remover página na edição %d: %w", i+1, err)
+		}
+		result.trackPage(instruction.Type, instruction.Page, 0)
+
+	case "move_page":
+		if instruction.TargetPage == nil {
+			return fmt.Errorf("edição %d: posição de destino da página é obrigatória", i+1)
+		}
+
+		if err := uc.pdfProcessor.MovePage(ctx, filePath, instruction.Page, *instruction.TargetPage); err != nil {
+			return fmt.Errorf("erro ao mover página na edição %d: %w", i+1, err)
+		}
+		result.trackPage(instruction.Type, instruction.Page, *instruction.TargetPage)
+
+	case "duplicate_page":
+		if err := uc.pdfProcessor.DuplicatePage(ctx, filePath, instruction.Page); err != nil {
+			return fmt.Errorf("erro ao duplicar página na edição %d: %w", i+1, err)
+		}
+		result.trackPage(instruction.Type, instruction.Page, 0)
+
+	case "insert_page":
+		// Tamanho padrão A4 em PDF points
+		width, height := 595.0, 842.0
+		if instruction.Width != nil {
+			width = *instruction.Width
+		}
+		if instruction.Height != nil {
+			height = *instruction.Height
+		}
+
+		if err := uc.pdfProcessor.InsertBlankPage(ctx, filePath, instruction.Page, width, height); err != nil {
+			return fmt.Errorf("erro ao inserir página na edição %d: %w", i+1, err)
+		}
+		result.trackPage(instruction.Type, instruction.Page, 0)
+
+	case "redact":
+		if instruction.Width == nil || *instruction.Width <= 0 {
+			return fmt.Errorf("edição %d: largura da região de redação deve ser maior que zero", i+1)
+		}
+		if instruction.Height == nil || *instruction.Height <= 0 {
+			return fmt.Errorf("edição %d: altura da região de redação deve ser maior que zero", i+1)
+		}
+
+		report, err := uc.pdfProcessor.Redact(ctx, filePath, instruction.Page, toRedaction(instruction))
+		if err != nil {
+			return fmt.Errorf("erro ao redigir região na edição %d: %w", i+1, err)
+		}
+		result.redactions = append(result.redactions, *report)
+
+	case "form_field":
+		if instruction.FieldType == "" {
+			return fmt.Errorf("edição %d: tipo do campo de formulário é obrigatório", i+1)
+		}
+		if instruction.Name == "" {
+			return fmt.Errorf("edição %d: nome do campo de formulário é obrigatório", i+1)
+		}
+		if instruction.Width == nil || *instruction.Width <= 0 || instruction.Height == nil || *instruction.Height <= 0 {
+			return fmt.Errorf("edição %d: campo de formulário requer largura e altura maiores que zero", i+1)
+		}
+
+		if err := uc.pdfProcessor.AddFormField(ctx, filePath, toFormFieldDefinition(instruction)); err != nil {
+			return fmt.Errorf("erro ao criar campo de formulário na edição %d: %w", i+1, err)
+		}
+		result.createdFields = append(result.createdFields, instruction.Name)
+
+	case "form_fill":
+		if len(instruction.Fields) == 0 {
+			return fmt.Errorf("edição %d: nenhum campo de formulário informado", i+1)
+		}
+
+		if err := uc.pdfProcessor.FillForm(ctx, filePath, instruction.Fields); err != nil {
+			return fmt.Errorf("erro ao preencher formulário na edição %d: %w", i+1, err)
+		}
+		for name := range instruction.Fields {
+			result.filledFields = append(result.filledFields, name)
+		}
+
+	case "form_flatten":
+		if err := uc.pdfProcessor.FlattenForm(ctx, filePath); err != nil {
+			return fmt.Errorf("erro ao achatar formulário na edição %d: %w", i+1, err)
+		}
+		result.flattened = true
+
+	case "watermark":
+		if instruction.Content == "" && instruction.ImagePath == "" {
+			return fmt.Errorf("edição %d: marca d'água requer texto ou imagem", i+1)
+		}
+
+		pages, err := uc.pdfProcessor.ExtractPages(ctx, filePath)
+		if err != nil {
+			return fmt.Errorf("erro ao obter páginas na edição %d: %w", i+1, err)
+		}
+		selection, err := parsePageRanges(instruction.Pages, len(pages))
+		if err != nil {
+			return fmt.Errorf("edição %d: %w", i+1, err)
+		}
+
+		if err := uc.pdfProcessor.AddWatermark(ctx, filePath, selection, uc.toWatermark(instruction)); err != nil {
+			return fmt.Errorf("erro ao aplicar marca d'água na edição %d: %w", i+1, err)
+		}
+
+	default:
+		return fmt.Errorf("edição %d: tipo de edição desconhecido: %s", i+1, instruction.Type)
+	}
+
+	return nil
+}
+
+// MergeDocuments combina documentos (ou intervalos de páginas deles) em um novo documento do usuário
+// A ordem das origens é a ordem das páginas no documento gerado
+func (uc *DocumentUseCase) MergeDocuments(ctx context.Context, userID uuid.UUID, sources []dto.MergeSource) (*dto.DocumentResponse, error) {
+	if len(sources) < 2 {
+		return nil, errors.New("informe ao menos dois documentos para mesclar")
+	}
+
+	mergeID := uuid.New()
+
+	// Arquivos temporários são removidos ao final, com sucesso ou erro
+	var tempPaths []string
+	defer func() {
+		for _, tempPath := range tempPaths {
+			_ = uc.fileStorage.Delete(ctx, tempPath)
+		}
+	}()
+
+	var mergedName string
+	inputPaths := make([]string, 0, len(sources))
+	sourcesMetadata := make([]map[string]interface{}, 0, len(sources))
+	for i, source := range sources {
+		documentID, err := uuid.Parse(source.DocumentID)
+		if err != nil {
+			return nil, fmt.Errorf("documento %d: ID de documento inválido: %w", i+1, err)
+		}
+
+		document, err := uc.documentRepo.FindByID(ctx, documentID)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+		}
+
+		if document == nil {
+			return nil, errors.New("documento não encontrado")
+		}
+
+		if document.UserID != userID {
+			return nil, errors.New("acesso negado")
+		}
+
+		pdfData, err := uc.fileStorage.Read(ctx, document.FilePath)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler PDF do documento %d: %w", i+1, err)
+		}
+
+		// Cada origem é copiada para que a seleção de páginas não altere o documento original
+		tempPath, err := uc.fileStorage.Save(ctx, pdfData, fmt.Sprintf("temp_merge_%s_%d.pdf", mergeID.String(), i+1))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao salvar PDF temporário: %w", err)
+		}
+		tempPaths = append(tempPaths, tempPath)
+		fullTempPath := filepath.Join(uc.storageBasePath, tempPath)
+
+		pageInfo, err := uc.pdfProcessor.ExtractPages(ctx, fullTempPath)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao extrair páginas do documento %d: %w", i+1, err)
+		}
+
+		pages, err := parsePageRanges(source.Pages, len(pageInfo))
+		if err != nil {
+			return nil, fmt.Errorf("documento %d: %w", i+1, err)
+		}
+
+		if source.Pages != "" {
+			if err := uc.pdfProcessor.SelectPages(ctx, fullTempPath, pages); err != nil {
+				return nil, fmt.Errorf("erro ao selecionar páginas do documento %d: %w", i+1, err)
+			}
+		}
+
+		if i == 0 {
+			mergedName = documentName(document) + "_mesclado.pdf"
+		}
+		inputPaths = append(inputPaths, fullTempPath)
+		sourcesMetadata = append(sourcesMetadata, map[string]interface{}{
+			"document_id": document.ID.String(),
+			"version":     document.Version,
+			"checksum":    document.Checksum,
+			"pages":       pages,
+		})
+	}
+
+	// Mescla as origens em um arquivo temporário
+	outputTempPath := fmt.Sprintf("temp_merge_%s_output.pdf", mergeID.String())
+	tempPaths = append(tempPaths, outputTempPath)
+	if err := uc.pdfProcessor.MergePDFs(ctx, filepath.Join(uc.storageBasePath, outputTempPath), inputPaths); err != nil {
+		return nil, fmt.Errorf("erro ao mesclar documentos: %w", err)
+	}
+
+	mergedData, err := uc.fileStorage.Read(ctx, outputTempPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF mesclado: %w", err)
+	}
+
+	document, err := uc.storeNewDocument(ctx, mergeID, userID, mergedName, mergedData)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Logger.Info("Documentos mesclados com sucesso",
+		zap.String("document_id", document.ID.String()),
+		zap.Int("sources_count", len(sources)),
+		zap.Int("page_count", document.PageCount),
+	)
+
+	// Cria log de auditoria com a origem de cada trecho
+	uc.createAuditLog(ctx, document.ID, userID, "MERGE", map[string]interface{}{
+		"sources":    sourcesMetadata,
+		"page_count": document.PageCount,
+		"size":       len(mergedData),
+	})
+
+	// Obtém URL do arquivo
+	fileURL, _ := uc.fileStorage.GetURL(ctx, document.FilePath)
+
+	return uc.toDocumentResponse(document, fileURL), nil
+}
+
+// SplitDocument divide um documento em novos documentos do usuário
+// Modos: "ranges" (um documento por intervalo), "every" (a cada N páginas) e "bookmarks" (um por marcador de primeiro nível)
+func (uc *DocumentUseCase) SplitDocument(ctx context.Context, documentID, userID uuid.UUID, req dto.SplitDocumentRequest) ([]dto.DocumentResponse, error) {
+	// Busca o documento
+	document, err := uc.documentRepo.FindByID(ctx, documentID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+	}
+
+	if document == nil {
+		return nil, errors.New("documento não encontrado")
+	}
+
+	if document.UserID != userID {
+		return nil, errors.New("acesso negado")
+	}
+
+	pdfData, err := uc.fileStorage.Read(ctx, document.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler PDF original: %w", err)
+	}
+
+	fullPath := filepath.Join(uc.storageBasePath, document.FilePath)
+	pageInfo, err := uc.pdfProcessor.ExtractPages(ctx, fullPath)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao extrair páginas do PDF: %w", err)
+	}
+
+	// Calcula os trechos conforme o modo
+	var parts []splitPart
+	switch req.Mode {
+	case "ranges":
+		parts, err = splitByRanges(req.Ranges, len(pageInfo))
+	case "every":
+		parts, err = splitEvery(req.Every, len(pageInfo))
+	case "bookmarks":
+		var bookmarks []model.Bookmark
+		bookmarks, err = uc.pdfProcessor.ExtractBookmarks(ctx, fullPath)
+		if err == nil {
+			parts, err = splitByBookmarks(bookmarks, len(pageInfo))
+		}
+	default:
+		err = fmt.Errorf("modo de divisão desconhecido: %s", req.Mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Documentos já criados são removidos se alguma parte falhar
+	var created []*model.Document
+	rollback := func() {
+		for _, doc := range created {
+			_ = uc.documentRepo.Delete(ctx, doc.ID)
+			_ = uc.fileStorage.Delete(ctx, doc.FilePath)
+		}
+	}
+
+	for i, part := range parts {
+		partID := uuid.New()
+
+		// Cada parte é gerada a partir de uma cópia do original
+		tempPath, err := uc.fileStorage.Save(ctx, pdfData, fmt.Sprintf("temp_split_%s.pdf", partID.String()))
+		if err != nil {
+			rollback()
+			return nil, fmt.Errorf("erro ao salvar PDF temporário: %w", err)
+		}
+
+		if err := uc.pdfProcessor.SelectPages(ctx, filepath.Join(uc.storageBasePath, tempPath), part.pages); err != nil {
+			_ = uc.fileStorage.Delete(ctx, tempPath)
+			rollback()
+			return nil, fmt.Errorf("erro ao gerar parte %d: %w", i+1, err)
+		}
+
+		partData, err := uc.fileStorage.Read(ctx, tempPath)
+		_ = uc.fileStorage.Delete(ctx, tempPath)
+		if err != nil {
+			rollback()
+			return nil, fmt.Errorf("erro ao ler parte %d: %w", i+1, err)
+		}
+
+		partName := fmt.Sprintf("%s_parte%d.pdf", documentName(document), i+1)
+		partDocument, err := uc.storeNewDocument(ctx, partID, userID, partName, partData)
+		if err != nil {
+			rollback()
+			return nil, err
+		}
+		created = append(created, partDocument)
+	}
+
+	logger.Logger.Info("Documento dividido com sucesso",
+		zap.String("document_id", documentID.String()),
+		zap.String("mode", req.Mode),
+		zap.Int("parts_count", len(parts)),
+	)
+
+	// Cada parte registra de onde veio; o original registra as partes geradas
+	responses := make([]dto.DocumentResponse, 0, len(created))
+	partIDs := make([]string, 0, len(created))
+	for i, partDocument := range created {
+		metadata := map[string]interface{}{
+			"parent_document_id": document.ID.String(),
+			"parent_version":     document.Version,
+			"parent_checksum":    document.Checksum,
+			"mode":               req.Mode,
+			"part":               i + 1,
+			"pages":              parts[i].pages,
+		}
+		if parts[i].title != "" {
+			metadata["bookmark"] = parts[i].title
+		}
+		uc.createAuditLog(ctx, partDocument.ID, userID, "SPLIT_PART", metadata)
+
+		partIDs = append(partIDs, partDocument.ID.String())
+		fileURL, _ := uc.fileStorage.GetURL(ctx, partDocument.FilePath)
+		responses = append(responses, *uc.toDocumentResponse(partDocument, fileURL))
+	}
+
+	uc.createAuditLog(ctx, document.ID, userID, "SPLIT", map[string]interface{}{
+		"mode":      req.Mode,
+		"version":   document.Version,
+		"documents": partIDs,
+	})
+
+	return responses, nil
+}
+
+// storeNewDocument salva um PDF gerado pelo sistema e cria o registro de um novo documento do usuário
+// name é o nome original atribuído ao documento gerado (ex.: "contrato_parte1.pdf")
+func (uc *DocumentUseCase) storeNewDocument(ctx context.Context, documentID, userID uuid.UUID, name string, pdfData []byte) (*model.Document, error) {
+	hash := sha256.Sum256(pdfData)
+
+	filePath, err := uc.fileStorage.Save(ctx, pdfData, fmt.Sprintf("%s.pdf", documentID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao salvar arquivo: %w", err)
+	}
+
+	pages, err := uc.pdfProcessor.ExtractPages(ctx, filepath.Join(uc.storageBasePath, filePath))
+	if err != nil {
+		_ = uc.fileStorage.Delete(ctx, filePath)
+		return nil, fmt.Errorf("erro ao extrair páginas do PDF: %w", err)
+	}
+
+	document := &model.Document{
+		ID:               documentID,
+		UserID:           userID,
+		FilePath:         filePath,
+		OriginalFilename: originalFilename(name),
+		Checksum:         hex.EncodeToString(hash[:]),
+		Version:          1,
+		Status:           model.DocumentStatusReady,
+		PageCount:        len(pages),
+	}
+	metadata := uc.readPDFMetadata(ctx, filepath.Join(uc.storageBasePath, filePath))
+	document.SetMetadata(metadata)
+	document.PDFAConformance = uc.validatedPDFA(ctx, filepath.Join(uc.storageBasePath, filePath), metadata.PDFA)
+
+	if err := uc.documentRepo.Create(ctx, document); err != nil {
+		// Tenta remover o arquivo se falhar ao criar registro
+		_ = uc.fileStorage.Delete(ctx, filePath)
+		return nil, fmt.Errorf("erro ao criar registro do documento: %w", err)
+	}
+
+	uc.importPDFAnnotations(ctx, document)
+
+	return document, nil
+}
+
+// storeNewVersion salva um PDF gerado a partir do documento como sua próxima versão
+// Usado por transformações que preservam as páginas (assinatura, proteção etc.); a contagem de páginas
+// não é recalculada, pois a versão gerada pode exigir senha para ser lida. A conformidade PDF/A é
+// descartada, pois a transformação pode invalidá-la (a conversão PDF/A a registra novamente)
+func (uc *DocumentUseCase) storeNewVersion(ctx context.Context, document *model.Document, pdfData []byte) error {
+	newVersion := document.Version + 1
+	outputPath, err := uc.fileStorage.Save(ctx, pdfData, fmt.Sprintf("%s_v%d.pdf", document.ID.String(), newVersion))
+	if err != nil {
+		return fmt.Errorf("erro ao criar novo arquivo: %w", err)
+	}
+
+	if err := uc.documentRepo.IncrementVersion(ctx, document.ID); err != nil {
+		_ = uc.fileStorage.Delete(ctx, outputPath)
+		return fmt.Errorf("erro ao incrementar versão: %w", err)
+	}
+
+	hash := sha256.Sum256(pdfData)
+	document.FilePath = outputPath
+	document.Version = newVersion
+	document.Checksum = hex.EncodeToString(hash[:])
+	document.PDFAConformance = ""
+	if err := uc.documentRepo.Update(ctx, document); err != nil {
+		_ = uc.fileStorage.Delete(ctx, outputPath)
+		return fmt.Errorf("erro ao atualizar documento: %w", err)
+	}
+
+	return nil
+}
+
 // DeleteDocument remove um documento
 func (uc *DocumentUseCase) DeleteDocument(ctx context.Context, documentID, userID uuid.UUID) error {
 	// Busca o documento
@@ -338,17 +953,185 @@ func (uc *DocumentUseCase) DeleteDocument(ctx context.Context, documentID, userI
 // toDocumentResponse converte model.Document para dto.DocumentResponse
 func (uc *DocumentUseCase) toDocumentResponse(doc *model.Document, fileURL string) *dto.DocumentResponse {
 	return &dto.DocumentResponse{
-		ID:        doc.ID.String(),
-		UserID:    doc.UserID.String(),
-		FilePath:  doc.FilePath,
-		FileURL:   fileURL,
-		Checksum:  doc.Checksum,
-		Version:   doc.Version,
-		Status:    string(doc.Status),
-		PageCount: doc.PageCount,
-		CreatedAt: doc.CreatedAt,
-		UpdatedAt: doc.UpdatedAt,
+		ID:               doc.ID.String(),
+		UserID:           doc.UserID.String(),
+		FilePath:         doc.FilePath,
+		FileURL:          fileURL,
+		OriginalFilename: doc.OriginalFilename,
+		Title:            doc.Title,
+		Author:           doc.Author,
+		Subject:          doc.Subject,
+		Keywords:         doc.Keywords,
+		Checksum:         doc.Checksum,
+		Version:          doc.Version,
+		Status:           string(doc.Status),
+		PageCount:        doc.PageCount,
+		PDFAConformance:  string(doc.PDFAConformance),
+		CreatedAt:        doc.CreatedAt,
+		UpdatedAt:        doc.UpdatedAt,
+	}
+}
+
+// toTextOptions converte uma instrução de texto em model.TextOptions aplicando os valores padrão
+func toTextOptions(instruction dto.EditInstruction) model.TextOptions {
+	opts := model.TextOptions{
+		FontFamily: instruction.FontFamily,
+		FontWeight: instruction.FontWeight,
+		FontSize:   12.0, // Tamanho padrão
+		Color:      instruction.FillColor,
+		Opacity:    1.0, // Opaco por padrão
+		Align:      model.TextAlign(instruction.Align),
+		Overflow:   model.TextOverflow(instruction.Overflow),
+	}
+
+	if instruction.FontSize != nil && *instruction.FontSize > 0 {
+		opts.FontSize = *instruction.FontSize
+	}
+	if instruction.Rotation != nil {
+		opts.Rotation = *instruction.Rotation
+	}
+	if instruction.Opacity != nil {
+		opts.Opacity = *instruction.Opacity
+	}
+	if instruction.LineHeight != nil {
+		opts.LineHeight = *instruction.LineHeight
+	}
+	if instruction.Width != nil {
+		opts.Width = *instruction.Width
 	}
+	if instruction.Height != nil {
+		opts.Height = *instruction.Height
+	}
+
+	return opts
+}
+
+// toDrawing converte uma instrução de desenho em model.Drawing aplicando os valores padrão
+func toDrawing(instruction dto.EditInstruction) model.Drawing {
+	drawing := model.Drawing{
+		Shape:       model.DrawingShape(instruction.Shape),
+		X:           instruction.X,
+		Y:           instruction.Y,
+		StrokeColor: instruction.StrokeColor,
+		FillColor:   instruction.FillColor,
+		LineWidth:   1.0, // Espessura padrão
+		DashPattern: instruction.DashPattern,
+		Opacity:     1.0, // Opaco por padrão
+	}
+
+	if instruction.Width != nil {
+		drawing.Width = *instruction.Width
+	}
+	if instruction.Height != nil {
+		drawing.Height = *instruction.Height
+	}
+	if instruction.LineWidth != nil {
+		drawing.LineWidth = *instruction.LineWidth
+	}
+	if instruction.Opacity != nil {
+		drawing.Opacity = *instruction.Opacity
+	}
+
+	drawing.Points = make([]model.Point, 0, len(instruction.Points))
+	for _, point := range instruction.Points {
+		drawing.Points = append(drawing.Points, model.Point{X: point.X, Y: point.Y})
+	}
+
+	return drawing
+}
+
+// toRedaction converte uma instrução de redação em model.Redaction aplicando os valores padrão
+func toRedaction(instruction dto.EditInstruction) model.Redaction {
+	redaction := model.Redaction{
+		X:           instruction.X,
+		Y:           instruction.Y,
+		Width:       *instruction.Width,
+		Height:      *instruction.Height,
+		FillColor:   "#000000", // Tarja preta por padrão
+		OverlayText: instruction.Content,
+		TextColor:   "#FFFFFF", // Texto branco sobre a tarja
+		FontFamily:  instruction.FontFamily,
+	}
+
+	if instruction.Fill != nil && !*instruction.Fill {
+		redaction.FillColor = ""
+		redaction.TextColor = "#000000"
+	} else if instruction.FillColor != "" {
+		redaction.FillColor = instruction.FillColor
+	}
+	if instruction.TextColor != "" {
+		redaction.TextColor = instruction.TextColor
+	}
+	if instruction.FontSize != nil && *instruction.FontSize > 0 {
+		redaction.FontSize = *instruction.FontSize
+	}
+
+	return redaction
+}
+
+// toWatermark converte a instrução de edição em marca d'água
+func (uc *DocumentUseCase) toWatermark(instruction dto.EditInstruction) model.Watermark {
+	watermark := model.Watermark{
+		Text:       instruction.Content,
+		Position:   model.WatermarkPosition(instruction.Position),
+		Rotation:   instruction.Rotation,
+		Opacity:    0.3, // Marca d'água discreta por padrão
+		Color:      instruction.FillColor,
+		FontFamily: instruction.FontFamily,
+		FontWeight: instruction.FontWeight,
+		Background: instruction.Background,
+	}
+
+	// Resolve caminho da imagem (pode ser relativo ou absoluto), como na instrução de imagem
+	if instruction.ImagePath != "" {
+		watermark.ImagePath = instruction.ImagePath
+		if !filepath.IsAbs(watermark.ImagePath) {
+			watermark.ImagePath = filepath.Join(uc.storageBasePath, watermark.ImagePath)
+		}
+	}
+	if instruction.Opacity != nil {
+		watermark.Opacity = *instruction.Opacity
+	}
+	if instruction.Scale != nil {
+		watermark.Scale = *instruction.Scale
+	}
+
+	return watermark
+}
+
+// toFormFieldDefinition converte a instrução de edição na definição do campo de formulário
+func toFormFieldDefinition(instruction dto.EditInstruction) model.FormFieldDefinition {
+	definition := model.FormFieldDefinition{
+		Name:         instruction.Name,
+		Type:         model.FormFieldType(instruction.FieldType),
+		Page:         instruction.Page,
+		X:            instruction.X,
+		Y:            instruction.Y,
+		DefaultValue: instruction.DefaultValue,
+		ExportValue:  instruction.ExportValue,
+		Options:      instruction.Options,
+		Editable:     instruction.Editable,
+		Multiline:    instruction.Multiline,
+		ReadOnly:     instruction.ReadOnly,
+		Required:     instruction.Required,
+		FontFamily:   instruction.FontFamily,
+		FontWeight:   instruction.FontWeight,
+	}
+
+	if instruction.Width != nil {
+		definition.Width = *instruction.Width
+	}
+	if instruction.Height != nil {
+		definition.Height = *instruction.Height
+	}
+	if instruction.MaxLength != nil {
+		definition.MaxLength = *instruction.MaxLength
+	}
+	if instruction.FontSize != nil {
+		definition.FontSize = *instruction.FontSize
+	}
+
+	return definition
 }
 
 // createAuditLog cria um log de auditoria
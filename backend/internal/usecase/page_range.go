@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/editor-pdf/backend/internal/model"
+)
+
+// parsePageRanges converte uma seleção de páginas (ex.: "1-3,5,8-") em uma lista ordenada conforme informado
+// Intervalos abertos ("8-") vão até a última página. Seleção vazia retorna todas as páginas
+func parsePageRanges(spec string, pageCount int) ([]int, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		pages := make([]int, 0, pageCount)
+		for i := 1; i <= pageCount; i++ {
+			pages = append(pages, i)
+		}
+		return pages, nil
+	}
+
+	var pages []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("intervalo de páginas vazio em %q", spec)
+		}
+
+		start, end, isRange := strings.Cut(part, "-")
+		first, err := parsePageNumber(start, pageCount)
+		if err != nil {
+			return nil, err
+		}
+
+		last := first
+		if isRange {
+			last = pageCount
+			if strings.TrimSpace(end) != "" {
+				last, err = parsePageNumber(end, pageCount)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if last < first {
+			return nil, fmt.Errorf("intervalo de páginas inválido: %s", part)
+		}
+
+		for i := first; i <= last; i++ {
+			pages = append(pages, i)
+		}
+	}
+
+	return pages, nil
+}
+
+// parsePageNumber converte e valida um número de página
+func parsePageNumber(value string, pageCount int) (int, error) {
+	pageNum, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("número de página inválido: %q", value)
+	}
+
+	if pageNum < 1 || pageNum > pageCount {
+		return 0, fmt.Errorf("página inválida: %d (PDF tem %d páginas)", pageNum, pageCount)
+	}
+
+	return pageNum, nil
+}
+
+// splitPart representa um trecho do documento que se tornará um novo documento
+type splitPart struct {
+	title string // Título do marcador (apenas na divisão por marcadores)
+	pages []int
+}
+
+// splitByRanges cria um trecho para cada intervalo separado por vírgula (ex.: "1-3,4-10")
+func splitByRanges(spec string, pageCount int) ([]splitPart, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, fmt.Errorf("intervalos de páginas não informados")
+	}
+
+	var parts []splitPart
+	for _, rangeSpec := range strings.Split(spec, ",") {
+		if strings.TrimSpace(rangeSpec) == "" {
+			return nil, fmt.Errorf("intervalo de páginas vazio em %q", spec)
+		}
+
+		pages, err := parsePageRanges(rangeSpec, pageCount)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, splitPart{pages: pages})
+	}
+
+	return parts, nil
+}
+
+// splitEvery cria trechos consecutivos de até n páginas
+func splitEvery(n, pageCount int) ([]splitPart, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("quantidade de páginas por documento deve ser maior que zero")
+	}
+
+	var parts []splitPart
+	for start := 1; start <= pageCount; start += n {
+		end := min(start+n-1, pageCount)
+		pages := make([]int, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			pages = append(pages, i)
+		}
+		parts = append(parts, splitPart{pages: pages})
+	}
+
+	return parts, nil
+}
+
+// splitByBookmarks cria um trecho para cada marcador de primeiro nível, indo da página do marcador
+// até a página anterior ao próximo. Páginas antes do primeiro marcador formam um trecho próprio
+func splitByBookmarks(bookmarks []model.Bookmark, pageCount int) ([]splitPart, error) {
+	// Considera apenas marcadores com destino válido, em ordem de página
+	var valid []model.Bookmark
+	for _, bookmark := range bookmarks {
+		if bookmark.Page >= 1 && bookmark.Page <= pageCount {
+			valid = append(valid, bookmark)
+		}
+	}
+	sort.SliceStable(valid, func(i, j int) bool { return valid[i].Page < valid[j].Page })
+
+	// Marcadores que apontam para a mesma página não iniciam um novo trecho
+	var starts []model.Bookmark
+	for _, bookmark := range valid {
+		if len(starts) > 0 && bookmark.Page == starts[len(starts)-1].Page {
+			continue
+		}
+		starts = append(starts, bookmark)
+	}
+
+	if len(starts) == 0 {
+		return nil, fmt.Errorf("documento não possui marcadores de primeiro nível")
+	}
+
+	if starts[0].Page > 1 {
+		starts = append([]model.Bookmark{{Page: 1}}, starts...)
+	}
+
+	parts := make([]splitPart, 0, len(starts))
+	for i, bookmark := range starts {
+		end := pageCount
+		if i+1 < len(starts) {
+			end = starts[i+1].Page - 1
+		}
+
+		pages := make([]int, 0, end-bookmark.Page+1)
+		for page := bookmark.Page; page <= end; page++ {
+			pages = append(pages, page)
+		}
+		parts = append(parts, splitPart{title: bookmark.Title, pages: pages})
+	}
+
+	return parts, nil
+}
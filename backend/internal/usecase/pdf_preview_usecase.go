@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/editor-pdf/backend/internal/domain"
+	"github.com/editor-pdf/backend/internal/dto"
+	"github.com/editor-pdf/backend/internal/model"
 	"github.com/editor-pdf/backend/pkg/logger"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -35,37 +40,119 @@ func NewPDFPreviewUseCase(
 	}
 }
 
-// GeneratePreview gera uma preview (imagem) de uma página específica do PDF
-func (uc *PDFPreviewUseCase) GeneratePreview(ctx context.Context, documentID, userID uuid.UUID, pageNum int) ([]byte, error) {
+const (
+	// defaultThumbnailSize é o lado do quadrado em que as miniaturas cabem quando não informado
+	defaultThumbnailSize = 200
+
+	// defaultPreviewJPEGQuality é a qualidade JPEG usada quando não informada
+	defaultPreviewJPEGQuality = 85
+)
+
+// GeneratePreview gera uma preview (imagem) de uma página específica do PDF com as opções de renderização
+// informadas. Retorna a imagem e o seu MIME type
+func (uc *PDFPreviewUseCase) GeneratePreview(ctx context.Context, documentID, userID uuid.UUID, pageNum int, req dto.PreviewRequest) ([]byte, string, error) {
+	options, err := previewOptions(req)
+	if err != nil {
+		return nil, "", err
+	}
+
 	// Busca o documento
 	document, err := uc.documentRepo.FindByID(ctx, documentID)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao buscar documento: %w", err)
+		return nil, "", fmt.Errorf("erro ao buscar documento: %w", err)
 	}
 
 	if document == nil {
-		return nil, errors.New("documento não encontrado")
+		return nil, "", errors.New("documento não encontrado")
 	}
 
 	// Valida número da página
 	if pageNum < 1 || pageNum > document.PageCount {
-		return nil, fmt.Errorf("página inválida: %d (documento tem %d páginas)", pageNum, document.PageCount)
+		return nil, "", fmt.Errorf("página inválida: %d (documento tem %d páginas)", pageNum, document.PageCount)
 	}
 
 	// Obtém o caminho completo do arquivo PDF
 	fullFilePath := filepath.Join(uc.storageBasePath, document.FilePath)
 
 	// Gera a preview usando o PDFProcessor
-	previewBytes, err := uc.pdfProcessor.GeneratePreview(ctx, fullFilePath, pageNum)
+	previewBytes, err := uc.pdfProcessor.GeneratePreview(ctx, fullFilePath, pageNum, options)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao gerar preview: %w", err)
+		if errors.Is(err, domain.ErrRenderLimit) || errors.Is(err, domain.ErrClipOutsidePage) {
+			return nil, "", fmt.Errorf("preview inválida: %w", err)
+		}
+		return nil, "", fmt.Errorf("erro ao gerar preview: %w", err)
 	}
 
 	logger.Logger.Debug("Preview gerado com sucesso",
 		zap.String("document_id", documentID.String()),
 		zap.Int("page", pageNum),
+		zap.String("format", string(options.Format)),
 		zap.Int("size_bytes", len(previewBytes)),
 	)
 
-	return previewBytes, nil
+	return previewBytes, options.Format.ContentType(), nil
+}
+
+// previewOptions valida os parâmetros da preview e aplica os padrões
+func previewOptions(req dto.PreviewRequest) (model.PreviewOptions, error) {
+	options := model.PreviewOptions{
+		Format:      model.ImageFormat(req.Format),
+		DPI:         req.DPI,
+		Width:       req.Width,
+		JPEGQuality: req.Quality,
+	}
+	if options.JPEGQuality == 0 {
+		options.JPEGQuality = defaultPreviewJPEGQuality
+	}
+
+	if req.Thumbnail {
+		if req.DPI > 0 || req.Width > 0 || req.Clip != "" {
+			return options, errors.New("preview inválida: thumbnail não aceita dpi, width ou clip")
+		}
+		options.ThumbnailSize = req.Size
+		if options.ThumbnailSize == 0 {
+			options.ThumbnailSize = defaultThumbnailSize
+		}
+		return options, nil
+	}
+
+	if req.Size > 0 {
+		return options, errors.New("preview inválida: size é aceito apenas com thumbnail")
+	}
+	if req.DPI > 0 && req.Width > 0 {
+		return options, errors.New("preview inválida: informe dpi ou width, não ambos")
+	}
+
+	if req.Clip != "" {
+		clip, err := parsePreviewClip(req.Clip)
+		if err != nil {
+			return options, err
+		}
+		options.Clip = clip
+	}
+
+	return options, nil
+}
+
+// parsePreviewClip converte uma região "x,y,largura,altura" (PDF points)
+func parsePreviewClip(value string) (*model.PreviewClip, error) {
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("preview inválida: clip deve ter o formato x,y,largura,altura")
+	}
+
+	var numbers [4]float64
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || math.IsNaN(number) || math.IsInf(number, 0) {
+			return nil, fmt.Errorf("preview inválida: valor de clip inválido: %q", part)
+		}
+		numbers[i] = number
+	}
+
+	if numbers[2] <= 0 || numbers[3] <= 0 {
+		return nil, errors.New("preview inválida: largura e altura do clip devem ser maiores que zero")
+	}
+
+	return &model.PreviewClip{X: numbers[0], Y: numbers[1], Width: numbers[2], Height: numbers[3]}, nil
 }
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS annotations (
+    id UUID PRIMARY KEY,
+    document_id UUID NOT NULL REFERENCES documents(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    page INTEGER NOT NULL CHECK (page > 0),
+    type VARCHAR(20) NOT NULL,
+    x DOUBLE PRECISION NOT NULL DEFAULT 0,
+    y DOUBLE PRECISION NOT NULL DEFAULT 0,
+    width DOUBLE PRECISION NOT NULL DEFAULT 0,
+    height DOUBLE PRECISION NOT NULL DEFAULT 0,
+    quads JSONB NOT NULL DEFAULT '[]',
+    ink_list JSONB NOT NULL DEFAULT '[]',
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    contents TEXT NOT NULL DEFAULT '',
+    color VARCHAR(7) NOT NULL DEFAULT '#FFFF00',
+    opacity DOUBLE PRECISION NOT NULL DEFAULT 1,
+    line_width DOUBLE PRECISION NOT NULL DEFAULT 0,
+    font_size DOUBLE PRECISION NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_annotations_document_page ON annotations(document_id, page);
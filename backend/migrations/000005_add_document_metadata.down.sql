@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_documents_metadata_search;
+
+ALTER TABLE documents
+    DROP COLUMN IF EXISTS custom_metadata,
+    DROP COLUMN IF EXISTS producer,
+    DROP COLUMN IF EXISTS creator,
+    DROP COLUMN IF EXISTS keywords,
+    DROP COLUMN IF EXISTS subject,
+    DROP COLUMN IF EXISTS author,
+    DROP COLUMN IF EXISTS title,
+    DROP COLUMN IF EXISTS original_filename;
@@ -0,0 +1,12 @@
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS original_filename VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS title TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS author TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS subject TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS keywords TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS creator TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS producer TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS custom_metadata JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_documents_metadata_search ON documents
+    USING GIN (to_tsvector('simple', original_filename || ' ' || title || ' ' || author || ' ' || subject || ' ' || keywords));
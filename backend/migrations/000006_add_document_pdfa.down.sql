@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_documents_pdfa_conformance;
+
+ALTER TABLE documents
+    DROP COLUMN IF EXISTS pdfa_conformance;
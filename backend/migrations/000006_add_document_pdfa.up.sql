@@ -0,0 +1,4 @@
+ALTER TABLE documents
+    ADD COLUMN IF NOT EXISTS pdfa_conformance VARCHAR(2) NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_documents_pdfa_conformance ON documents (pdfa_conformance);